@@ -78,6 +78,9 @@ func (r ExternalSchedulerRequest) Filter(includedHosts map[string]float64) lib.F
 // Cortex returns an ordered list of hosts that the share should be scheduled on.
 type ExternalSchedulerResponse struct {
 	Hosts []string `json:"hosts"`
+	// Aggregated weights of the returned hosts, by host. Only included
+	// if the request asked for a verbose response (?verbose=1).
+	Weights map[string]float64 `json:"weights,omitempty"`
 }
 
 // TODO add specs
@@ -200,6 +200,9 @@ func (req ExternalSchedulerRequest) GetIntent() (v1alpha1.SchedulingIntent, erro
 // Cortex returns an ordered list of hosts that the VM should be scheduled on.
 type ExternalSchedulerResponse struct {
 	Hosts []string `json:"hosts"`
+	// Aggregated weights of the returned hosts, by host. Only included
+	// if the request asked for a verbose response (?verbose=1).
+	Weights map[string]float64 `json:"weights,omitempty"`
 }
 
 // Wrapped Nova object. Nova returns objects in this format.
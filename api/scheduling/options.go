@@ -5,6 +5,7 @@ package scheduling
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/cobaltcore-dev/cortex/api/v1alpha1"
 )
@@ -35,6 +36,11 @@ type Options struct {
 	// committed resource reservation slot. Set for non-VM-placement runs (capacity checks,
 	// failover scheduling, CR slot scheduling) that must not modify reservation allocations.
 	SkipCommittedResourceTracking bool `json:"skip_committed_resource_tracking,omitempty"`
+
+	// OvercommitOverrides replaces the configured overcommit ratio per resource
+	// (e.g. "cpu", "memory") for this run. Only honored for allowlisted callers,
+	// ignored otherwise.
+	OvercommitOverrides map[string]float64 `json:"overcommit_overrides,omitempty"`
 }
 
 // Validate checks for mutually exclusive or inconsistent option combinations.
@@ -48,5 +54,10 @@ func (o Options) Validate() error {
 	if o.ReadOnly && !o.SkipCommittedResourceTracking {
 		return errors.New("read-only runs must not write CR reservation allocations: set SkipCommittedResourceTracking=true")
 	}
+	for resourceName, ratio := range o.OvercommitOverrides {
+		if ratio < 1.0 {
+			return fmt.Errorf("invalid overcommit override for resource %s: must be >= 1.0, got %f", resourceName, ratio)
+		}
+	}
 	return nil
 }
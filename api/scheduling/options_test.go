@@ -16,6 +16,7 @@ func TestOptions_Validate(t *testing.T) {
 		{"ReadOnly without SkipHistory is invalid", Options{ReadOnly: true}, true},
 		{"ReadOnly without SkipInflight is invalid", Options{ReadOnly: true, SkipHistory: true}, true},
 		{"ReadOnly without SkipCommittedResourceTracking is invalid", Options{ReadOnly: true, SkipHistory: true, SkipInflight: true}, true},
+		{"overcommit override below 1.0 is invalid", Options{OvercommitOverrides: map[string]float64{"cpu": 0.5}}, true},
 	}
 
 	for _, tt := range tests {
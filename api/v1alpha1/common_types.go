@@ -56,3 +56,12 @@ type Parameter struct {
 // multiple parameters for filters, weighers, and other resources that need
 // flexible but limited configuration.
 type Parameters []Parameter
+
+const (
+	// Annotation on the hypervisor resource that announces the start of the
+	// next scheduled maintenance window of the host, formatted as RFC3339.
+	AnnotationMaintenanceWindowStart = "cortex.cloud/maintenance-window-start"
+	// Optional annotation on the hypervisor resource that announces the end
+	// of the next scheduled maintenance window of the host, formatted as RFC3339.
+	AnnotationMaintenanceWindowEnd = "cortex.cloud/maintenance-window-end"
+)
@@ -40,6 +40,7 @@ type PrometheusDatasource struct {
 
 	// Secret containing the following keys:
 	// - "url": The prometheus URL.
+	// - "token" (optional): A bearer token to authenticate with.
 	SecretRef corev1.SecretReference `json:"secretRef"`
 }
 
@@ -61,6 +62,25 @@ type NovaDatasource struct {
 	// Time frame in minutes for the changes-since parameter when fetching
 	// deleted servers. Set if the Type is "deletedServers".
 	DeletedServersChangesSinceMinutes *int `json:"deletedServersChangesSinceMinutes,omitempty"`
+	// If true, only fetch servers changed since the last sync using the
+	// changes-since parameter, instead of listing all servers on every
+	// sync. Set if the Type is "servers".
+	ServersIncrementalSync bool `json:"serversIncrementalSync,omitempty"`
+	// Interval in minutes after which a full sync of all servers is done
+	// even if incremental sync is enabled, to catch missed changes.
+	// Defaults to 24 hours. Set if the Type is "servers".
+	ServersFullSyncEveryMinutes *int `json:"serversFullSyncEveryMinutes,omitempty"`
+	// Number of objects to request per page when listing the resource.
+	// If unset, the default page size of the service is used. Servers,
+	// flavors and images are written to the database page by page.
+	// +kubebuilder:validation:Minimum=1
+	PageSize *int `json:"pageSize,omitempty"`
+	// Maximum number of objects to fetch per sync, as a safety cap on very
+	// large clouds. If a list holds more objects, the sync fails instead of
+	// syncing a partial list.
+	// If unset, all objects are fetched.
+	// +kubebuilder:validation:Minimum=1
+	MaxResults *int `json:"maxResults,omitempty"`
 }
 
 type PlacementDatasourceType string
@@ -216,6 +236,11 @@ type DatasourceSpec struct {
 	// - "host": The database host.
 	// - "port": The database port.
 	// - "database": The database name.
+	// Optionally, read-only queries are routed to a read replica with the
+	// same credentials if the secret contains the following keys:
+	// - "readReplicaHost": The read replica host.
+	// - "readReplicaPort": The read replica port, defaults to "port".
+	// If the read replica can't be reached, queries are read from the primary.
 	DatabaseSecretRef corev1.SecretReference `json:"databaseSecretRef"`
 
 	// Kubernetes secret ref for an optional sso certificate to access the host.
@@ -229,13 +254,23 @@ const (
 	DatasourceConditionReady = "Ready"
 )
 
+// Annotation that forces the datasource to be synced again immediately,
+// regardless of its next sync time. The annotation is removed once the sync
+// was triggered, and its outcome is reflected in the datasource status.
+const AnnotationResync = "datasource.cortex.cloud/resync"
+
 type DatasourceStatus struct {
 	// When the datasource was last successfully synced.
 	LastSynced metav1.Time `json:"lastSynced,omitempty"`
+	// How long the last successful sync took.
+	LastSyncDuration metav1.Duration `json:"lastSyncDuration,omitempty"`
 	// The number of objects currently stored for this datasource.
 	NumberOfObjects int64 `json:"numberOfObjects,omitempty"`
 	// Planned time for the next sync.
 	NextSyncTime metav1.Time `json:"nextSyncTime,omitempty"`
+	// Schema version of the table the datasource was last synced into.
+	// Tables of an older schema version are dropped and synced again.
+	SchemaVersion int `json:"schemaVersion,omitempty"`
 
 	// The current status conditions of the datasource.
 	// +kubebuilder:validation:Optional
@@ -59,13 +59,21 @@ type StepResult struct {
 	StepName string `json:"stepName"`
 	// Activations of the step for each host.
 	Activations map[string]float64 `json:"activations"`
+	// Multiplier applied to the activations of this step when computing
+	// the aggregated output weights. Only set for weigher steps.
+	// +kubebuilder:validation:Optional
+	Multiplier *float64 `json:"multiplier,omitempty"`
+	// Why the step removed hosts, by host. Only set for filter steps that
+	// give a reason for the hosts they removed.
+	// +kubebuilder:validation:Optional
+	Reasons map[string]string `json:"reasons,omitempty"`
 }
 
 type DecisionResult struct {
 	// Raw input weights to the pipeline.
 	// +kubebuilder:validation:Optional
 	RawInWeights map[string]float64 `json:"rawInWeights"`
-	// Normalized input weights to the pipeline.
+	// Normalized input weights of the hosts remaining after the filters.
 	// +kubebuilder:validation:Optional
 	NormalizedInWeights map[string]float64 `json:"normalizedInWeights"`
 	// Outputs of the decision pipeline including the activations used
@@ -81,6 +89,10 @@ type DecisionResult struct {
 	// The first element of the ordered hosts is considered the target host.
 	// +kubebuilder:validation:Optional
 	TargetHost *string `json:"targetHost,omitempty"`
+	// The fallback ordering used because all weighers scored the hosts
+	// identically. Empty if the ordering was determined by the weights.
+	// +kubebuilder:validation:Optional
+	TieBreaker TieBreaker `json:"tieBreaker,omitempty"`
 }
 
 const (
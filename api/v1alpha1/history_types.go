@@ -48,6 +48,31 @@ type HistorySpec struct {
 	AvailabilityZone *string `json:"availabilityZone,omitempty"`
 }
 
+// DecisionSummary holds the structured pieces of the explanation of a
+// scheduling decision, for consumption by dashboards and other tools.
+type DecisionSummary struct {
+	// The aggregated score of the highest-ranked host.
+	// +kubebuilder:validation:Optional
+	TargetHostScore *float64 `json:"targetHostScore,omitempty"`
+	// The score gap between the highest-ranked host and the runner-up.
+	// +kubebuilder:validation:Optional
+	GapToRunnerUp *float64 `json:"gapToRunnerUp,omitempty"`
+	// The number of hosts the pipeline started with.
+	HostsEvaluated int `json:"hostsEvaluated"`
+	// The number of hosts remaining after all steps.
+	HostsRemaining int `json:"hostsRemaining"`
+	// The number of hosts sharing the top score, whose ordering was decided
+	// by host name. Zero if there was no such tie.
+	// +kubebuilder:validation:Optional
+	TiedHosts int `json:"tiedHosts,omitempty"`
+	// The hosts removed by each step (limited to 10 per step).
+	// +kubebuilder:validation:Optional
+	FilteredHosts map[string][]string `json:"filteredHosts,omitempty"`
+	// The weighers without which a different host would have been selected.
+	// +kubebuilder:validation:Optional
+	CriticalSteps []string `json:"criticalSteps,omitempty"`
+}
+
 // CurrentDecision holds the full context of the most recent scheduling
 // decision. When a new decision arrives the previous CurrentDecision is
 // compacted into a SchedulingHistoryEntry and appended to History.
@@ -66,19 +91,36 @@ type CurrentDecision struct {
 	// A human-readable explanation of the scheduling decision.
 	// +kubebuilder:validation:Optional
 	Explanation string `json:"explanation,omitempty"`
+	// The structured pieces the explanation is derived from.
+	// +kubebuilder:validation:Optional
+	Summary *DecisionSummary `json:"summary,omitempty"`
 	// The top hosts ordered by score (limited to 3).
 	// +kubebuilder:validation:Optional
 	// +kubebuilder:validation:MaxItems=3
 	OrderedHosts []string `json:"orderedHosts,omitempty"`
 }
 
+// SummarizedHistory aggregates the history entries that were dropped because
+// the history exceeded its maximum length.
+type SummarizedHistory struct {
+	// The number of dropped history entries.
+	Count int `json:"count"`
+	// The timestamp of the earliest dropped entry.
+	EarliestTimestamp metav1.Time `json:"earliestTimestamp"`
+}
+
 type HistoryStatus struct {
 	// Current represents the latest scheduling decision with full context.
 	// +kubebuilder:validation:Optional
 	Current CurrentDecision `json:"current,omitempty"`
-	// History of past scheduling decisions (limited to last 10).
+	// History of past scheduling decisions (limited to the configured
+	// maximum history length, 10 by default).
 	// +kubebuilder:validation:Optional
 	History []SchedulingHistoryEntry `json:"history,omitempty"`
+	// Summary of the past scheduling decisions that were dropped from the
+	// history because it exceeded its maximum length.
+	// +kubebuilder:validation:Optional
+	Summarized *SummarizedHistory `json:"summarized,omitempty"`
 
 	// Conditions represent the latest available observations of the history's state.
 	// +kubebuilder:validation:Optional
@@ -97,7 +139,7 @@ type HistoryStatus struct {
 // +kubebuilder:printcolumn:name="Created",type="date",JSONPath=".metadata.creationTimestamp"
 
 // The history is a CRD that provides a record of past scheduling decisions for a given resource (e.g., a nova instance).
-// A new history entry is created for each scheduling decision, and the most recent decision is stored in the status.current field. The history is capped at 10 entries to prevent unbounded growth.
+// A new history entry is created for each scheduling decision, and the most recent decision is stored in the status.current field. The history is capped (10 entries by default) to prevent unbounded growth, older entries are only kept as a summary.
 // This CRD is designed to be used by an operations team to troubleshoot scheduling decisions and understand the context around why a particular host was selected (or not selected) for a resource.
 type History struct {
 	metav1.TypeMeta `json:",inline"`
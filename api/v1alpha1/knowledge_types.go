@@ -46,6 +46,15 @@ type KnowledgeSpec struct {
 	// +kubebuilder:default="60s"
 	Recency metav1.Duration `json:"recency"`
 
+	// The maximum age of the data behind this knowledge until it is considered
+	// stale, e.g. because a datasource stopped syncing. The age is measured
+	// from the last sync of the oldest datasource this knowledge depends on.
+	// Stale knowledge is marked as not ready, so that steps depending on it
+	// fail or fall back.
+	// If unset, the knowledge never becomes stale.
+	// +kubebuilder:validation:Optional
+	MaxAge *metav1.Duration `json:"maxAge,omitempty"`
+
 	// A human-readable description of the knowledge to be extracted.
 	// +kubebuilder:validation:Optional
 	Description string `json:"description,omitempty"`
@@ -86,8 +95,17 @@ func BoxFeatureList[T any](features []T) (runtime.RawExtension, error) {
 const (
 	// The knowledge is ready to be used.
 	KnowledgeConditionReady = "Ready"
+	// The knowledge dependencies are extracted and ready. If a dependency
+	// becomes unready after the knowledge was extracted, only this condition
+	// turns false and the knowledge keeps serving its previous data.
+	KnowledgeConditionDependenciesReady = "DependenciesReady"
 )
 
+// Annotation that forces the knowledge to be extracted again immediately,
+// regardless of its recency. The annotation is removed once the extraction
+// was triggered, and its outcome is reflected in the knowledge status.
+const AnnotationReextract = "knowledge.cortex.cloud/reextract"
+
 type KnowledgeStatus struct {
 	// When the knowledge was last successfully extracted.
 	// +kubebuilder:validation:Optional
@@ -104,6 +122,10 @@ type KnowledgeStatus struct {
 	// The number of features extracted, or 1 if the knowledge is not a list.
 	// +kubebuilder:validation:Optional
 	RawLength int `json:"rawLength,omitempty"`
+	// The schema version of the extracted features, so that consumers
+	// can detect when the feature model changes.
+	// +kubebuilder:validation:Optional
+	SchemaVersion int `json:"schemaVersion,omitempty"`
 
 	// The current status conditions of the knowledge.
 	// +kubebuilder:validation:Optional
@@ -40,6 +40,11 @@ type KPISpec struct {
 	// and decisions made by it.
 	// +kubebuilder:validation:Optional
 	Description string `json:"description,omitempty"`
+	// If set, the kpi is computed at most once per interval and scrapes
+	// in between are served the last computed metrics. Changes to the
+	// data of the kpi's dependencies invalidate the cached metrics.
+	// +kubebuilder:validation:Optional
+	CollectInterval *metav1.Duration `json:"collectInterval,omitempty"`
 }
 
 const (
@@ -20,6 +20,12 @@ type FilterSpec struct {
 	// and decisions made by it.
 	// +kubebuilder:validation:Optional
 	Description string `json:"description,omitempty"`
+
+	// If this filter doesn't depend on hosts removed by the filters before
+	// it, so that it can run concurrently with adjacent independent filters
+	// when the pipeline has parallel filters enabled.
+	// +kubebuilder:validation:Optional
+	Independent bool `json:"independent,omitempty"`
 }
 
 type WeigherSpec struct {
@@ -41,6 +47,12 @@ type WeigherSpec struct {
 	// relative to other steps in the same pipeline.
 	// +kubebuilder:validation:Optional
 	Multiplier *float64 `json:"multiplier,omitempty"`
+
+	// Optional activation to assign to all hosts if the step cannot be
+	// initialized, e.g. because its knowledge is not available yet. If
+	// unset, the step is dropped from the pipeline in this case.
+	// +kubebuilder:validation:Optional
+	FallbackActivation *float64 `json:"fallbackActivation,omitempty"`
 }
 
 type DetectorSpec struct {
@@ -67,6 +79,36 @@ const (
 	// Pipeline containing detector steps, e.g. for generating descheduling
 	// recommendations.
 	PipelineTypeDetector PipelineType = "detector"
+	// Pipeline containing weigher steps that score hosts by how safe they
+	// are to evacuate, e.g. to pick the next host to drain for maintenance.
+	PipelineTypeDrain PipelineType = "drain"
+)
+
+type TieBreaker string
+
+const (
+	// Order tied hosts alphabetically by their name.
+	TieBreakerHostName TieBreaker = "hostName"
+	// Order tied hosts by when they were last selected by the pipeline,
+	// so that placements are spread round-robin across the tied hosts.
+	// Selections are read from the histories of the pipeline's decisions, so
+	// they survive restarts and are shared between scheduler replicas.
+	TieBreakerLeastRecentlySelected TieBreaker = "leastRecentlySelected"
+	// Order tied hosts by the weights passed in the scheduling request.
+	TieBreakerInputWeight TieBreaker = "inputWeight"
+)
+
+type InputWeightNormalization string
+
+const (
+	// Squash each input weight into (-1, 1) using tanh.
+	InputWeightNormalizationTanh InputWeightNormalization = "tanh"
+	// Scale the input weights linearly to [0, 1], by their min and max.
+	InputWeightNormalizationMinMax InputWeightNormalization = "minMax"
+	// Center the input weights around their mean, in standard deviations.
+	InputWeightNormalizationZScore InputWeightNormalization = "zScore"
+	// Use the input weights as they are.
+	InputWeightNormalizationNone InputWeightNormalization = "none"
 )
 
 type PipelineSpec struct {
@@ -85,14 +127,15 @@ type PipelineSpec struct {
 	IgnorePreselection bool `json:"ignorePreselection,omitempty"`
 
 	// The type of the pipeline, used to differentiate between
-	// filter-weigher and detector pipelines within the same
+	// filter-weigher, detector, and drain pipelines within the same
 	// scheduling domain.
 	//
 	// If the type is filter-weigher, the filter and weigher attributes
 	// must be set. If the type is detector, the detectors attribute
+	// must be set. If the type is drain, only the weighers attribute
 	// must be set.
 	//
-	// +kubebuilder:validation:Enum=filter-weigher;detector
+	// +kubebuilder:validation:Enum=filter-weigher;detector;drain
 	Type PipelineType `json:"type"`
 
 	// Ordered list of filters to apply in a scheduling pipeline.
@@ -105,8 +148,8 @@ type PipelineSpec struct {
 
 	// Ordered list of weighers to apply in a scheduling pipeline.
 	//
-	// This attribute is set only if the pipeline type is filter-weigher.
-	// These weighers are run after filters are applied.
+	// This attribute is set only if the pipeline type is filter-weigher
+	// or drain. These weighers are run after filters are applied.
 	// +kubebuilder:validation:Optional
 	Weighers []WeigherSpec `json:"weighers,omitempty"`
 
@@ -117,6 +160,41 @@ type PipelineSpec struct {
 	// These detectors are run after weighers are applied.
 	// +kubebuilder:validation:Optional
 	Detectors []DetectorSpec `json:"detectors,omitempty"`
+
+	// Ordering to fall back to for hosts that end up with identical weights,
+	// e.g. because all knowledges are stale. Hosts with different weights
+	// are still ordered by their weights. If unset, hosts are ordered by the
+	// combined weights only.
+	//
+	// This attribute is only used if the pipeline type is filter-weigher.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=hostName;leastRecentlySelected;inputWeight
+	TieBreaker TieBreaker `json:"tieBreaker,omitempty"`
+
+	// How the weights passed in the scheduling request are normalized before
+	// the weighers' activations are added to them. This decides how much the
+	// input weights count against the weighers. Only the weights of the hosts
+	// remaining after the filters are normalized. If unset, tanh is used.
+	//
+	// This attribute is only used if the pipeline type is filter-weigher.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=tanh;minMax;zScore;none
+	InputWeightNormalization InputWeightNormalization `json:"inputWeightNormalization,omitempty"`
+
+	// If adjacent filters marked as independent should run concurrently over
+	// the same hosts. A host removed by any of them is removed, and the
+	// results are merged in the configured filter order, so the outcome is
+	// the same as when running the filters one after the other.
+	//
+	// This attribute is only used if the pipeline type is filter-weigher.
+	// +kubebuilder:default=false
+	ParallelFilters bool `json:"parallelFilters,omitempty"`
+
+	// If an audit record should be logged for each decision made by this
+	// pipeline, independently of the decision resource, so that it survives
+	// the garbage collection of decisions.
+	// +kubebuilder:default=false
+	Audit bool `json:"audit,omitempty"`
 }
 
 const (
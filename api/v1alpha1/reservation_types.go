@@ -307,6 +307,12 @@ func (s *CommittedResourceReservationSpec) MatchesGroup(projectID, resourceGroup
 	return s.ProjectID == projectID && s.ResourceGroup == resourceGroup
 }
 
+// MatchesDomain reports whether the reservation targets the given domain.
+// Reservations that don't specify a domain match any domain.
+func (s *CommittedResourceReservationSpec) MatchesDomain(domainID string) bool {
+	return s.DomainID == "" || s.DomainID == domainID
+}
+
 // IsReady returns true if the reservation has the Ready condition set to True.
 func (r *Reservation) IsReady() bool {
 	return meta.IsStatusConditionTrue(r.Status.Conditions, ReservationConditionReady)
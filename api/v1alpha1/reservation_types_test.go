@@ -26,3 +26,23 @@ func TestCommittedResourceReservationSpec_MatchesGroup(t *testing.T) {
 		}
 	}
 }
+
+func TestCommittedResourceReservationSpec_MatchesDomain(t *testing.T) {
+	tests := []struct {
+		specDomain string
+		domain     string
+		want       bool
+	}{
+		{"", "domain-1", true},
+		{"", "", true},
+		{"domain-1", "domain-1", true},
+		{"domain-1", "domain-2", false},
+		{"domain-1", "", false},
+	}
+	for _, tt := range tests {
+		spec := CommittedResourceReservationSpec{DomainID: tt.specDomain}
+		if got := spec.MatchesDomain(tt.domain); got != tt.want {
+			t.Errorf("MatchesDomain(%q) with spec domain %q = %v, want %v", tt.domain, tt.specDomain, got, tt.want)
+		}
+	}
+}
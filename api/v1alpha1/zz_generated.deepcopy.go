@@ -250,6 +250,11 @@ func (in *CurrentDecision) DeepCopyInto(out *CurrentDecision) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.Summary != nil {
+		in, out := &in.Summary, &out.Summary
+		*out = new(DecisionSummary)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.OrderedHosts != nil {
 		in, out := &in.OrderedHosts, &out.OrderedHosts
 		*out = make([]string, len(*in))
@@ -567,6 +572,52 @@ func (in *DecisionStatus) DeepCopy() *DecisionStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DecisionSummary) DeepCopyInto(out *DecisionSummary) {
+	*out = *in
+	if in.TargetHostScore != nil {
+		in, out := &in.TargetHostScore, &out.TargetHostScore
+		*out = new(float64)
+		**out = **in
+	}
+	if in.GapToRunnerUp != nil {
+		in, out := &in.GapToRunnerUp, &out.GapToRunnerUp
+		*out = new(float64)
+		**out = **in
+	}
+	if in.FilteredHosts != nil {
+		in, out := &in.FilteredHosts, &out.FilteredHosts
+		*out = make(map[string][]string, len(*in))
+		for key, val := range *in {
+			var outVal []string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				inVal := (*in)[key]
+				in, out := &inVal, &outVal
+				*out = make([]string, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.CriticalSteps != nil {
+		in, out := &in.CriticalSteps, &out.CriticalSteps
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DecisionSummary.
+func (in *DecisionSummary) DeepCopy() *DecisionSummary {
+	if in == nil {
+		return nil
+	}
+	out := new(DecisionSummary)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Descheduling) DeepCopyInto(out *Descheduling) {
 	*out = *in
@@ -987,6 +1038,11 @@ func (in *HistoryStatus) DeepCopyInto(out *HistoryStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Summarized != nil {
+		in, out := &in.Summarized, &out.Summarized
+		*out = new(SummarizedHistory)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]v1.Condition, len(*in))
@@ -1140,6 +1196,11 @@ func (in *KPISpec) DeepCopyInto(out *KPISpec) {
 	*out = *in
 	in.Opts.DeepCopyInto(&out.Opts)
 	in.Dependencies.DeepCopyInto(&out.Dependencies)
+	if in.CollectInterval != nil {
+		in, out := &in.CollectInterval, &out.CollectInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KPISpec.
@@ -1279,6 +1340,11 @@ func (in *KnowledgeSpec) DeepCopyInto(out *KnowledgeSpec) {
 	*out = *in
 	in.Extractor.DeepCopyInto(&out.Extractor)
 	out.Recency = in.Recency
+	if in.MaxAge != nil {
+		in, out := &in.MaxAge, &out.MaxAge
+		*out = new(v1.Duration)
+		**out = **in
+	}
 	in.Dependencies.DeepCopyInto(&out.Dependencies)
 }
 
@@ -1355,6 +1421,21 @@ func (in *NovaDatasource) DeepCopyInto(out *NovaDatasource) {
 		*out = new(int)
 		**out = **in
 	}
+	if in.ServersFullSyncEveryMinutes != nil {
+		in, out := &in.ServersFullSyncEveryMinutes, &out.ServersFullSyncEveryMinutes
+		*out = new(int)
+		**out = **in
+	}
+	if in.PageSize != nil {
+		in, out := &in.PageSize, &out.PageSize
+		*out = new(int)
+		**out = **in
+	}
+	if in.MaxResults != nil {
+		in, out := &in.MaxResults, &out.MaxResults
+		*out = new(int)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NovaDatasource.
@@ -1915,6 +1996,18 @@ func (in *StepResult) DeepCopyInto(out *StepResult) {
 			(*out)[key] = val
 		}
 	}
+	if in.Multiplier != nil {
+		in, out := &in.Multiplier, &out.Multiplier
+		*out = new(float64)
+		**out = **in
+	}
+	if in.Reasons != nil {
+		in, out := &in.Reasons, &out.Reasons
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StepResult.
@@ -1927,6 +2020,22 @@ func (in *StepResult) DeepCopy() *StepResult {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SummarizedHistory) DeepCopyInto(out *SummarizedHistory) {
+	*out = *in
+	in.EarliestTimestamp.DeepCopyInto(&out.EarliestTimestamp)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SummarizedHistory.
+func (in *SummarizedHistory) DeepCopy() *SummarizedHistory {
+	if in == nil {
+		return nil
+	}
+	out := new(SummarizedHistory)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WeigherSpec) DeepCopyInto(out *WeigherSpec) {
 	*out = *in
@@ -1942,6 +2051,11 @@ func (in *WeigherSpec) DeepCopyInto(out *WeigherSpec) {
 		*out = new(float64)
 		**out = **in
 	}
+	if in.FallbackActivation != nil {
+		in, out := &in.FallbackActivation, &out.FallbackActivation
+		*out = new(float64)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WeigherSpec.
@@ -211,13 +211,17 @@ func main() {
 				"supported", []string{"debug", "info", "warn", "warning", "error"})
 		}
 	}
-	slog.SetDefault(slog.New(monitoring.NewMetricsSlogHandler(
-		&logMetricsMonitor,
-		slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
-			Level: slogLevel,
-		}),
-	)))
-	slog.Info("slog configured", "level", slogLevel.Level().String())
+	var slogHandler slog.Handler = slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
+		Level: slogLevel,
+	})
+	// Optionally drop repeated messages, e.g. per-request logs at high rates.
+	loggingConfig := conf.GetConfigOrDie[monitoring.LoggingConfig]()
+	if sampling := loggingConfig.Logging.Sampling; sampling != nil {
+		slogHandler = monitoring.NewSamplingSlogHandler(*sampling, slogHandler)
+	}
+	slog.SetDefault(slog.New(monitoring.NewMetricsSlogHandler(&logMetricsMonitor, slogHandler)))
+	slog.Info("slog configured", "level", slogLevel.Level().String(),
+		"sampling", loggingConfig.Logging.Sampling != nil)
 
 	// Log the main configuration
 	setupLog.Info("loaded main configuration",
@@ -380,10 +384,43 @@ func main() {
 
 	// TODO: Remove me after scheduling pipeline steps don't require DB connections anymore.
 	metrics.Registry.MustRegister(&db.Monitor)
+	db.ConfigurePool(conf.GetConfigOrDie[db.PoolConfig]())
+	db.ConfigureBulkInsert(conf.GetConfigOrDie[db.BulkInsertConfig]())
+	dbLivenessConfig := conf.GetConfigOrDie[db.LivenessConfig]()
+	if err := mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+		db.CheckLivenessPeriodically(ctx, dbLivenessConfig.Interval())
+		return nil
+	})); err != nil {
+		setupLog.Error(err, "unable to add database liveness check to manager")
+		os.Exit(1)
+	}
+
+	// Optionally export spans of scheduling requests.
+	tracingConfig := conf.GetConfigOrDie[schedulinglib.TracingConfig]()
+	shutdownTracing, err := schedulinglib.InitTracing(ctx, tracingConfig)
+	if err != nil {
+		setupLog.Error(err, "unable to set up tracing")
+		os.Exit(1)
+	}
+	if err := mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+		<-ctx.Done()
+		// Flush the remaining spans with a fresh context, since ctx is done.
+		return shutdownTracing(context.Background())
+	})); err != nil {
+		setupLog.Error(err, "unable to add tracing shutdown to manager")
+		os.Exit(1)
+	}
+	setupLog.Info("tracing configured", "enabled", tracingConfig.TracingEnabled)
 
 	// API endpoint.
 	mux := http.NewServeMux()
 
+	// Expose the explanations of past scheduling decisions.
+	(&schedulinglib.HistoryAPI{Client: multiclusterClient}).Init(mux)
+	// Expose the pipelines loaded by the pipeline controllers set up below.
+	pipelineAPI := &schedulinglib.PipelineAPI{}
+	pipelineAPI.Init(mux)
+
 	// The pipeline monitor is a bucket for all metrics produced during the
 	// execution of individual steps (see step monitor below) and the overall
 	// pipeline.
@@ -410,14 +447,27 @@ func main() {
 		commitmentsAPI.Init(mux, metrics.Registry, ctrl.Log.WithName("commitments-api"))
 	}
 
+	// Shared configuration of the pipeline admission webhooks of all domains.
+	pipelineWebhookConfig := conf.GetConfigOrDie[schedulinglib.PipelineWebhookConfig]()
+	// Shared configuration of the History CRDs written by all domains.
+	historyConfig := conf.GetConfigOrDie[schedulinglib.HistoryConfig]()
+	if err := historyConfig.Validate(); err != nil {
+		setupLog.Error(err, "invalid history config")
+		os.Exit(1)
+	}
+
 	if slices.Contains(mainConfig.EnabledControllers, "nova-pipeline-controllers") {
 		featureGates := conf.GetConfigOrDie[nova.FeatureGates]()
 		noHostFoundCounter := crs.NewNoHostFoundCounter()
 		placementCounter := crs.NewPlacementCounter()
+		candidateCacheCounter := nova.NewCandidateCacheCounter()
 		// Filter-weigher pipeline controller setup.
 		filterWeigherController := &nova.FilterWeigherPipelineController{
-			Monitor:      filterWeigherPipelineMonitor,
-			FeatureGates: featureGates,
+			Monitor:               filterWeigherPipelineMonitor.SubDomain(v1alpha1.SchedulingDomainNova),
+			FeatureGates:          featureGates,
+			HistoryConfig:         historyConfig,
+			CandidateCache:        conf.GetConfigOrDie[nova.CandidateCacheConfig](),
+			CandidateCacheCounter: candidateCacheCounter,
 			CRRecorder: crs.Recorder{
 				NoHostFoundCounter: noHostFoundCounter,
 				PlacementCounter:   placementCounter,
@@ -425,6 +475,7 @@ func main() {
 		}
 		metrics.Registry.MustRegister(noHostFoundCounter)
 		metrics.Registry.MustRegister(placementCounter)
+		metrics.Registry.MustRegister(candidateCacheCounter)
 		// Inferred through the base controller.
 		filterWeigherController.Client = multiclusterClient
 		filterWeigherController.CRRecorder.Client = multiclusterClient
@@ -432,11 +483,27 @@ func main() {
 			setupLog.Error(err, "unable to create controller", "controller", "nova FilterWeigherPipelineController")
 			os.Exit(1)
 		}
+		pipelineAPI.Describers = append(pipelineAPI.Describers, filterWeigherController)
+
+		// Drain pipeline controller setup.
+		drainController := &nova.DrainPipelineController{
+			Monitor: filterWeigherPipelineMonitor.SubDomain(v1alpha1.SchedulingDomainNova),
+		}
+		// Inferred through the base controller.
+		drainController.Client = multiclusterClient
+		if err := drainController.SetupWithManager(mgr, multiclusterClient); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "nova DrainPipelineController")
+			os.Exit(1)
+		}
+		pipelineAPI.Describers = append(pipelineAPI.Describers, drainController)
+
 		novaAPIConfig := conf.GetConfigOrDie[nova.HTTPAPIConfig]()
 		setupLog.Info("loaded nova API config",
 			"evacuationShuffleK", novaAPIConfig.EvacuationShuffleK,
-			"novaLimitHostsToRequest", novaAPIConfig.NovaLimitHostsToRequest)
-		nova.NewAPI(novaAPIConfig, filterWeigherController).Init(mux)
+			"novaLimitHostsToRequest", novaAPIConfig.NovaLimitHostsToRequest,
+			"requestTimeout", novaAPIConfig.RequestTimeout.Duration,
+			"novaDefaultPipeline", novaAPIConfig.NovaDefaultPipeline)
+		nova.NewAPI(novaAPIConfig, filterWeigherController, drainController).Init(mux)
 
 		// Detector pipeline controller setup.
 		novaClient := nova.NewNovaClient()
@@ -458,6 +525,7 @@ func main() {
 			setupLog.Error(err, "unable to create controller", "controller", "nova DetectorPipelineController")
 			os.Exit(1)
 		}
+		pipelineAPI.Describers = append(pipelineAPI.Describers, deschedulingsController)
 		go deschedulingsController.CreateDeschedulingsPeriodically(ctx)
 		// Deschedulings cleanup on startup
 		if err := (&nova.DeschedulingsCleanup{
@@ -470,6 +538,7 @@ func main() {
 
 		// Webhook that validates all pipelines.
 		novaPipelineWebhook := nova.NewPipelineWebhook()
+		novaPipelineWebhook.RejectUnknownSteps = pipelineWebhookConfig.RejectUnknownSteps
 		if err := novaPipelineWebhook.SetupWebhookWithManager(mgr); err != nil {
 			setupLog.Error(err, "unable to setup nova pipeline webhook")
 			os.Exit(1)
@@ -508,7 +577,8 @@ func main() {
 	if slices.Contains(mainConfig.EnabledControllers, "manila-decisions-pipeline-controller") {
 		setupLog.Info("enabling controller", "controller", "manila-decisions-pipeline-controller")
 		controller := &manila.FilterWeigherPipelineController{
-			Monitor: filterWeigherPipelineMonitor,
+			Monitor:       filterWeigherPipelineMonitor.SubDomain(v1alpha1.SchedulingDomainManila),
+			HistoryConfig: historyConfig,
 		}
 		// Inferred through the base controller.
 		controller.Client = multiclusterClient
@@ -516,10 +586,16 @@ func main() {
 			setupLog.Error(err, "unable to create controller", "controller", "DecisionReconciler")
 			os.Exit(1)
 		}
-		manila.NewAPI(controller).Init(mux)
+		pipelineAPI.Describers = append(pipelineAPI.Describers, controller)
+		manilaAPIConfig := conf.GetConfigOrDie[manila.HTTPAPIConfig]()
+		setupLog.Info("loaded manila API config",
+			"requestTimeout", manilaAPIConfig.RequestTimeout.Duration,
+			"manilaDefaultPipeline", manilaAPIConfig.ManilaDefaultPipeline)
+		manila.NewAPI(manilaAPIConfig, controller).Init(mux)
 
 		// Webhook that validates all pipelines.
 		manilaPipelineWebhook := manila.NewPipelineWebhook()
+		manilaPipelineWebhook.RejectUnknownSteps = pipelineWebhookConfig.RejectUnknownSteps
 		if err := manilaPipelineWebhook.SetupWebhookWithManager(mgr); err != nil {
 			setupLog.Error(err, "unable to setup manila pipeline webhook")
 			os.Exit(1)
@@ -528,7 +604,8 @@ func main() {
 	if slices.Contains(mainConfig.EnabledControllers, "cinder-decisions-pipeline-controller") {
 		setupLog.Info("enabling controller", "controller", "cinder-decisions-pipeline-controller")
 		controller := &cinder.FilterWeigherPipelineController{
-			Monitor: filterWeigherPipelineMonitor,
+			Monitor:       filterWeigherPipelineMonitor.SubDomain(v1alpha1.SchedulingDomainCinder),
+			HistoryConfig: historyConfig,
 		}
 		// Inferred through the base controller.
 		controller.Client = multiclusterClient
@@ -536,10 +613,16 @@ func main() {
 			setupLog.Error(err, "unable to create controller", "controller", "DecisionReconciler")
 			os.Exit(1)
 		}
-		cinder.NewAPI(controller).Init(mux)
+		pipelineAPI.Describers = append(pipelineAPI.Describers, controller)
+		cinderAPIConfig := conf.GetConfigOrDie[cinder.HTTPAPIConfig]()
+		setupLog.Info("loaded cinder API config",
+			"requestTimeout", cinderAPIConfig.RequestTimeout.Duration,
+			"cinderDefaultPipeline", cinderAPIConfig.CinderDefaultPipeline)
+		cinder.NewAPI(cinderAPIConfig, controller).Init(mux)
 
 		// Webhook that validates all pipelines.
 		cinderPipelineWebhook := cinder.NewPipelineWebhook()
+		cinderPipelineWebhook.RejectUnknownSteps = pipelineWebhookConfig.RejectUnknownSteps
 		if err := cinderPipelineWebhook.SetupWebhookWithManager(mgr); err != nil {
 			setupLog.Error(err, "unable to setup cinder pipeline webhook")
 			os.Exit(1)
@@ -548,7 +631,8 @@ func main() {
 	if slices.Contains(mainConfig.EnabledControllers, "ironcore-decisions-pipeline-controller") {
 		setupLog.Info("enabling controller", "controller", "ironcore-decisions-pipeline-controller")
 		controller := &machines.FilterWeigherPipelineController{
-			Monitor: filterWeigherPipelineMonitor,
+			Monitor:       filterWeigherPipelineMonitor.SubDomain(v1alpha1.SchedulingDomainMachines),
+			HistoryConfig: historyConfig,
 		}
 		// Inferred through the base controller.
 		controller.Client = multiclusterClient
@@ -556,9 +640,11 @@ func main() {
 			setupLog.Error(err, "unable to create controller", "controller", "DecisionReconciler")
 			os.Exit(1)
 		}
+		pipelineAPI.Describers = append(pipelineAPI.Describers, controller)
 
 		// Webhook that validates all pipelines.
 		ironcorePipelineWebhook := machines.NewPipelineWebhook()
+		ironcorePipelineWebhook.RejectUnknownSteps = pipelineWebhookConfig.RejectUnknownSteps
 		if err := ironcorePipelineWebhook.SetupWebhookWithManager(mgr); err != nil {
 			setupLog.Error(err, "unable to setup ironcore pipeline webhook")
 			os.Exit(1)
@@ -567,7 +653,8 @@ func main() {
 	if slices.Contains(mainConfig.EnabledControllers, "pods-decisions-pipeline-controller") {
 		setupLog.Info("enabling controller", "controller", "pods-decisions-pipeline-controller")
 		controller := &pods.FilterWeigherPipelineController{
-			Monitor: filterWeigherPipelineMonitor,
+			Monitor:       filterWeigherPipelineMonitor.SubDomain(v1alpha1.SchedulingDomainPods),
+			HistoryConfig: historyConfig,
 		}
 		// Inferred through the base controller.
 		controller.Client = multiclusterClient
@@ -575,9 +662,11 @@ func main() {
 			setupLog.Error(err, "unable to create controller", "controller", "DecisionReconciler")
 			os.Exit(1)
 		}
+		pipelineAPI.Describers = append(pipelineAPI.Describers, controller)
 
 		// Webhook that validates all pipelines.
 		podsPipelineWebhook := pods.NewPipelineWebhook()
+		podsPipelineWebhook.RejectUnknownSteps = pipelineWebhookConfig.RejectUnknownSteps
 		if err := podsPipelineWebhook.SetupWebhookWithManager(mgr); err != nil {
 			setupLog.Error(err, "unable to setup pods pipeline webhook")
 			os.Exit(1)
@@ -636,6 +725,7 @@ func main() {
 		setupLog.Info("enabling controller", "controller", "datasource-controllers")
 		monitor := datasources.NewMonitor()
 		metrics.Registry.MustRegister(&monitor)
+		(&datasources.SyncStatusAPI{Client: multiclusterClient}).Init(mux)
 		if err := (&openstack.OpenStackDatasourceReconciler{
 			Client:  multiclusterClient,
 			Scheme:  mgr.GetScheme(),
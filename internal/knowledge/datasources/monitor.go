@@ -15,6 +15,12 @@ type Monitor struct {
 	RequestTimer *prometheus.HistogramVec
 	// A counter to observe the number of processed sync requests.
 	RequestProcessedCounter *prometheus.CounterVec
+	// A counter to observe failed syncs, by datasource and failure reason.
+	SyncFailedCounter *prometheus.CounterVec
+	// A gauge to observe the circuit breaker state of upstream services by
+	// the secret they are reached with, where 0 is closed, 1 is half-open,
+	// and 2 is open.
+	CircuitBreakerStateGauge *prometheus.GaugeVec
 }
 
 // NewSyncMonitor creates a new sync monitor and registers the necessary Prometheus metrics.
@@ -33,6 +39,14 @@ func NewMonitor() Monitor {
 			Name: "cortex_sync_request_processed_total",
 			Help: "Number of processed sync requests",
 		}, []string{"datasource"}),
+		SyncFailedCounter: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "cortex_sync_failed_total",
+			Help: "Number of failed datasource syncs",
+		}, []string{"datasource", "reason"}),
+		CircuitBreakerStateGauge: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "cortex_sync_circuit_breaker_state",
+			Help: "Circuit breaker state of upstream services (0=closed, 1=half-open, 2=open)",
+		}, []string{"service", "secret"}),
 	}
 }
 
@@ -40,10 +54,21 @@ func (m *Monitor) Describe(ch chan<- *prometheus.Desc) {
 	m.ObjectsGauge.Describe(ch)
 	m.RequestTimer.Describe(ch)
 	m.RequestProcessedCounter.Describe(ch)
+	m.SyncFailedCounter.Describe(ch)
+	m.CircuitBreakerStateGauge.Describe(ch)
+}
+
+// Observe a failed sync of the given datasource.
+func (m *Monitor) ObserveSyncFailed(datasource, reason string) {
+	if m.SyncFailedCounter != nil {
+		m.SyncFailedCounter.WithLabelValues(datasource, reason).Inc()
+	}
 }
 
 func (m *Monitor) Collect(ch chan<- prometheus.Metric) {
 	m.ObjectsGauge.Collect(ch)
 	m.RequestTimer.Collect(ch)
 	m.RequestProcessedCounter.Collect(ch)
+	m.SyncFailedCounter.Collect(ch)
+	m.CircuitBreakerStateGauge.Collect(ch)
 }
@@ -391,3 +391,24 @@ func TestMonitorLabels(t *testing.T) {
 		}
 	}
 }
+
+func TestObserveSyncFailed(t *testing.T) {
+	monitor := NewMonitor()
+	monitor.ObserveSyncFailed("nova-servers", "OpenStackDatasourceSyncFailed")
+	monitor.ObserveSyncFailed("nova-servers", "OpenStackDatasourceSyncFailed")
+	monitor.ObserveSyncFailed("glance-images", "OpenStackDatasourceInitFailed")
+
+	expected := `
+		# HELP cortex_sync_failed_total Number of failed datasource syncs
+		# TYPE cortex_sync_failed_total counter
+		cortex_sync_failed_total{datasource="glance-images",reason="OpenStackDatasourceInitFailed"} 1
+		cortex_sync_failed_total{datasource="nova-servers",reason="OpenStackDatasourceSyncFailed"} 2
+	`
+	if err := testutil.CollectAndCompare(monitor.SyncFailedCounter, strings.NewReader(expected)); err != nil {
+		t.Errorf("Unexpected metric output: %v", err)
+	}
+
+	// A monitor without metrics should not panic.
+	empty := Monitor{}
+	empty.ObserveSyncFailed("nova-servers", "OpenStackDatasourceSyncFailed")
+}
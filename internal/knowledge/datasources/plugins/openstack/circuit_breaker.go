@@ -0,0 +1,165 @@
+// Copyright SAP SE
+// SPDX-License-Identifier: Apache-2.0
+
+package openstack
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/gophercloud/gophercloud/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+)
+
+type circuitState int
+
+const (
+	// Calls to the service are made as usual.
+	circuitClosed circuitState = iota
+	// The cooldown has passed, a single call is let through to test if
+	// the service recovered. Its recorded outcome closes or reopens it.
+	circuitHalfOpen
+	// Calls to the service are short-circuited until the cooldown passed.
+	circuitOpen
+)
+
+type circuit struct {
+	state circuitState
+	// Number of consecutive failed calls.
+	failures int
+	// When the circuit was last opened.
+	openedAt time.Time
+	// When the call testing recovery was let through, while half-open.
+	// Zero if no such call is in flight.
+	probeStartedAt time.Time
+}
+
+// Key of a circuit, i.e. an upstream service reached with the keystone
+// credentials of a secret. Datasources sharing the secret call the same
+// endpoints and share the circuit, while datasources of other regions or
+// accounts are not affected by it.
+type circuitKey struct {
+	// The upstream service, such as keystone or nova.
+	service string
+	// The secret with the keystone credentials, as namespace/name.
+	secret string
+}
+
+func newCircuitKey(service string, secretRef corev1.SecretReference) circuitKey {
+	return circuitKey{service: service, secret: secretRef.Namespace + "/" + secretRef.Name}
+}
+
+// Check if the error tells that the upstream service is unhealthy, i.e. it
+// couldn't be reached, or responded with a server error or rate limit.
+// Other errors, such as failed database writes, say nothing about the service.
+func isUpstreamError(err error) bool {
+	var codeErr gophercloud.ErrUnexpectedResponseCode
+	if errors.As(err, &codeErr) {
+		code := codeErr.GetStatusCode()
+		return code >= http.StatusInternalServerError || code == http.StatusTooManyRequests
+	}
+	var urlErr *url.Error
+	return errors.As(err, &urlErr)
+}
+
+// Circuit breaker for the calls to upstream openstack services, such as
+// keystone or nova. After a number of consecutive failed calls to a service,
+// the circuit of this service opens and further calls are short-circuited,
+// so that a flapping service isn't hammered with retries. Once the cooldown
+// has passed, the circuit half-opens and a single call is let through to test
+// if the service recovered, while all other calls are still short-circuited.
+// Only upstream errors count as failed calls, see isUpstreamError.
+//
+// A nil circuit breaker lets all calls through.
+type circuitBreaker struct {
+	// Number of consecutive failed calls after which the circuit opens.
+	failureThreshold int
+	// How long the circuit stays open before calls are let through again.
+	cooldown time.Duration
+	// Gauge to publish the state of the circuits on, by service and secret.
+	stateGauge *prometheus.GaugeVec
+
+	mu       sync.Mutex
+	circuits map[circuitKey]*circuit
+}
+
+func newCircuitBreaker(failureThreshold int, cooldown time.Duration, stateGauge *prometheus.GaugeVec) *circuitBreaker {
+	return &circuitBreaker{
+		failureThreshold: failureThreshold,
+		cooldown:         cooldown,
+		stateGauge:       stateGauge,
+		circuits:         make(map[circuitKey]*circuit),
+	}
+}
+
+// Check if a call to the given service may be made. If the circuit of this
+// service is open, or another call is testing its recovery, return how long
+// until the call should be tried again.
+func (b *circuitBreaker) allow(key circuitKey, now time.Time) (time.Duration, bool) {
+	if b == nil {
+		return 0, true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	c, ok := b.circuits[key]
+	if !ok || c.state == circuitClosed {
+		return 0, true
+	}
+	if c.state == circuitOpen {
+		if remaining := c.openedAt.Add(b.cooldown).Sub(now); remaining > 0 {
+			return remaining, false
+		}
+		b.setState(key, c, circuitHalfOpen)
+	}
+	// A test call whose outcome wasn't recorded within the cooldown is
+	// considered lost, so that the circuit doesn't stay half-open forever.
+	if !c.probeStartedAt.IsZero() {
+		if remaining := c.probeStartedAt.Add(b.cooldown).Sub(now); remaining > 0 {
+			return remaining, false
+		}
+	}
+	c.probeStartedAt = now
+	return 0, true
+}
+
+// Record the outcome of a call to the given service. Errors that aren't
+// caused by the service only let the next call test its recovery.
+func (b *circuitBreaker) record(key circuitKey, err error, now time.Time) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	c, ok := b.circuits[key]
+	if !ok {
+		c = &circuit{}
+		b.circuits[key] = c
+	}
+	c.probeStartedAt = time.Time{}
+	if err != nil && !isUpstreamError(err) {
+		return
+	}
+	if err == nil {
+		c.failures = 0
+		b.setState(key, c, circuitClosed)
+		return
+	}
+	c.failures++
+	// A failed call while testing recovery reopens the circuit right away.
+	if c.state == circuitHalfOpen || c.failures >= b.failureThreshold {
+		c.openedAt = now
+		b.setState(key, c, circuitOpen)
+	}
+}
+
+// Set the state of the circuit and publish it on the gauge.
+func (b *circuitBreaker) setState(key circuitKey, c *circuit, state circuitState) {
+	c.state = state
+	if b.stateGauge != nil {
+		b.stateGauge.WithLabelValues(key.service, key.secret).Set(float64(state))
+	}
+}
@@ -0,0 +1,171 @@
+// Copyright SAP SE
+// SPDX-License-Identifier: Apache-2.0
+
+package openstack
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gophercloud/gophercloud/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	gauge := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "test_circuit_state"}, []string{"service", "secret"})
+	breaker := newCircuitBreaker(3, time.Minute, gauge)
+	errUpstream := gophercloud.ErrUnexpectedResponseCode{Actual: http.StatusServiceUnavailable}
+	nova := newCircuitKey("nova", corev1.SecretReference{Namespace: "default", Name: "keystone-a"})
+	now := time.Now()
+
+	// Failures below the threshold keep the circuit closed.
+	for range 2 {
+		if _, ok := breaker.allow(nova, now); !ok {
+			t.Fatal("expected call to be allowed while circuit is closed")
+		}
+		breaker.record(nova, errUpstream, now)
+	}
+	// A success resets the consecutive failures.
+	breaker.record(nova, nil, now)
+	for range 2 {
+		breaker.record(nova, errUpstream, now)
+	}
+	if _, ok := breaker.allow(nova, now); !ok {
+		t.Fatal("expected call to be allowed after failures were reset")
+	}
+
+	// The third consecutive failure opens the circuit.
+	breaker.record(nova, errUpstream, now)
+	retryAfter, ok := breaker.allow(nova, now.Add(10*time.Second))
+	if ok {
+		t.Fatal("expected call to be short-circuited while circuit is open")
+	}
+	if retryAfter != 50*time.Second {
+		t.Errorf("expected retry after 50s, got %s", retryAfter)
+	}
+	if state := testutil.ToFloat64(gauge.WithLabelValues("nova", "default/keystone-a")); state != float64(circuitOpen) {
+		t.Errorf("expected open state on gauge, got %v", state)
+	}
+	// Other services, or the same service reached with other credentials,
+	// are not affected.
+	keystone := newCircuitKey("keystone", corev1.SecretReference{Namespace: "default", Name: "keystone-a"})
+	if _, ok := breaker.allow(keystone, now); !ok {
+		t.Fatal("expected calls to other services to be allowed")
+	}
+	otherNova := newCircuitKey("nova", corev1.SecretReference{Namespace: "default", Name: "keystone-b"})
+	if _, ok := breaker.allow(otherNova, now); !ok {
+		t.Fatal("expected calls with other credentials to be allowed")
+	}
+
+	// After the cooldown, the circuit half-opens and a failure reopens it.
+	now = now.Add(time.Minute)
+	if _, ok := breaker.allow(nova, now); !ok {
+		t.Fatal("expected call to be allowed once cooldown passed")
+	}
+	if state := testutil.ToFloat64(gauge.WithLabelValues("nova", "default/keystone-a")); state != float64(circuitHalfOpen) {
+		t.Errorf("expected half-open state on gauge, got %v", state)
+	}
+	// Only the single test call is let through while half-open.
+	if _, ok := breaker.allow(nova, now); ok {
+		t.Fatal("expected further calls to be short-circuited while testing recovery")
+	}
+	breaker.record(nova, errUpstream, now)
+	if _, ok := breaker.allow(nova, now); ok {
+		t.Fatal("expected failed test call to reopen the circuit")
+	}
+
+	// A successful test call closes the circuit again.
+	now = now.Add(time.Minute)
+	if _, ok := breaker.allow(nova, now); !ok {
+		t.Fatal("expected call to be allowed once cooldown passed")
+	}
+	breaker.record(nova, nil, now)
+	if _, ok := breaker.allow(nova, now); !ok {
+		t.Fatal("expected call to be allowed after circuit closed")
+	}
+	if state := testutil.ToFloat64(gauge.WithLabelValues("nova", "default/keystone-a")); state != float64(circuitClosed) {
+		t.Errorf("expected closed state on gauge, got %v", state)
+	}
+}
+
+func TestCircuitBreaker_HalfOpenProbe(t *testing.T) {
+	breaker := newCircuitBreaker(1, time.Minute, nil)
+	nova := newCircuitKey("nova", corev1.SecretReference{Namespace: "default", Name: "keystone"})
+	now := time.Now()
+	breaker.record(nova, gophercloud.ErrUnexpectedResponseCode{Actual: http.StatusBadGateway}, now)
+
+	// Once the cooldown passed, the first call tests the recovery.
+	now = now.Add(time.Minute)
+	if _, ok := breaker.allow(nova, now); !ok {
+		t.Fatal("expected test call to be allowed once cooldown passed")
+	}
+	retryAfter, ok := breaker.allow(nova, now.Add(10*time.Second))
+	if ok {
+		t.Fatal("expected other calls to be short-circuited while testing recovery")
+	}
+	if retryAfter != 50*time.Second {
+		t.Errorf("expected retry after 50s, got %s", retryAfter)
+	}
+
+	// Errors not caused by the service let the next call test the recovery.
+	breaker.record(nova, errors.New("failed to insert into database"), now)
+	if _, ok := breaker.allow(nova, now); !ok {
+		t.Fatal("expected next test call to be allowed")
+	}
+
+	// A test call whose outcome is never recorded is considered lost.
+	if _, ok := breaker.allow(nova, now.Add(time.Minute)); !ok {
+		t.Fatal("expected test call to be allowed after the previous one was lost")
+	}
+}
+
+func TestCircuitBreaker_Nil(t *testing.T) {
+	var breaker *circuitBreaker
+	nova := newCircuitKey("nova", corev1.SecretReference{Namespace: "default", Name: "keystone"})
+	breaker.record(nova, errors.New("service unavailable"), time.Now())
+	if _, ok := breaker.allow(nova, time.Now()); !ok {
+		t.Fatal("expected nil circuit breaker to allow all calls")
+	}
+}
+
+func TestCircuitBreaker_IgnoresNonUpstreamErrors(t *testing.T) {
+	breaker := newCircuitBreaker(1, time.Minute, nil)
+	nova := newCircuitKey("nova", corev1.SecretReference{Namespace: "default", Name: "keystone"})
+	now := time.Now()
+	breaker.record(nova, errors.New("failed to insert into database"), now)
+	breaker.record(nova, gophercloud.ErrUnexpectedResponseCode{Actual: http.StatusNotFound}, now)
+	if _, ok := breaker.allow(nova, now); !ok {
+		t.Fatal("expected errors not caused by the service to be ignored")
+	}
+	breaker.record(nova, fmt.Errorf("sync failed: %w", gophercloud.ErrUnexpectedResponseCode{Actual: http.StatusBadGateway}), now)
+	if _, ok := breaker.allow(nova, now); ok {
+		t.Fatal("expected wrapped upstream error to open the circuit")
+	}
+}
+
+func TestIsUpstreamError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"server error", gophercloud.ErrUnexpectedResponseCode{Actual: http.StatusInternalServerError}, true},
+		{"rate limited", gophercloud.ErrUnexpectedResponseCode{Actual: http.StatusTooManyRequests}, true},
+		{"client error", gophercloud.ErrUnexpectedResponseCode{Actual: http.StatusForbidden}, false},
+		{"unreachable", &url.Error{Op: "Get", URL: "http://nova", Err: errors.New("connection refused")}, true},
+		{"other error", errors.New("database unavailable"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isUpstreamError(tt.err); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
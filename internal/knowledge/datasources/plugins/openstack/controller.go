@@ -45,6 +45,12 @@ type config struct {
 	// The number of parallel reconciles to allow for the controller.
 	// By default, this will be set to 1.
 	ParallelReconciles *int `json:"openstackDatasourceControllerParallelReconciles,omitempty"`
+	// The number of consecutive failed calls to an openstack service after
+	// which further calls are short-circuited. By default, this will be set to 5.
+	CircuitBreakerFailureThreshold *int `json:"openstackCircuitBreakerFailureThreshold,omitempty"`
+	// How long calls to a failing openstack service are short-circuited before
+	// they are let through again. By default, this will be set to 5 minutes.
+	CircuitBreakerCooldown *metav1.Duration `json:"openstackCircuitBreakerCooldown,omitempty"`
 }
 
 type Syncer interface {
@@ -68,6 +74,36 @@ type OpenStackDatasourceReconciler struct {
 	// On first reconcile the timestamp skip is bypassed, so a DB wipe + operator restart
 	// forces an immediate re-sync of all datasources.
 	reconciledOnce sync.Map
+	// Circuit breaker for the calls to the upstream openstack services.
+	breaker *circuitBreaker
+}
+
+// Set the datasource to not ready because calls to the given service are
+// short-circuited, and check again once the circuit breaker lets calls through.
+func (r *OpenStackDatasourceReconciler) shortCircuit(
+	ctx context.Context,
+	datasource *v1alpha1.Datasource,
+	key circuitKey,
+	retryAfter time.Duration,
+) (ctrl.Result, error) {
+
+	log := logf.FromContext(ctx)
+	log.Info("skipping datasource sync, circuit breaker is open",
+		"name", datasource.Name, "service", key.service, "secret", key.secret, "retryAfter", retryAfter)
+	r.Monitor.ObserveSyncFailed(datasource.Name, "CircuitBreakerOpen")
+	old := datasource.DeepCopy()
+	meta.SetStatusCondition(&datasource.Status.Conditions, metav1.Condition{
+		Type:    v1alpha1.DatasourceConditionReady,
+		Status:  metav1.ConditionFalse,
+		Reason:  "CircuitBreakerOpen",
+		Message: "calls to " + key.service + " are paused after repeated failures",
+	})
+	patch := client.MergeFrom(old)
+	if err := r.Status().Patch(ctx, datasource, patch); err != nil {
+		log.Error(err, "failed to patch datasource status", "name", datasource.Name)
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: retryAfter}, nil
 }
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
@@ -95,13 +131,25 @@ func (r *OpenStackDatasourceReconciler) Reconcile(ctx context.Context, req ctrl.
 		log.Info("skipping datasource, not an openstack datasource", "name", datasource.Name)
 		return ctrl.Result{}, nil
 	}
-	if datasource.Status.NextSyncTime.After(time.Now()) && datasource.Status.NumberOfObjects != 0 {
+	if datasources.ResyncRequested(datasource) {
+		log.Info("forcing datasource sync, requested by annotation", "name", datasource.Name)
+	} else if datasource.Status.NextSyncTime.After(time.Now()) && datasource.Status.NumberOfObjects != 0 {
 		if _, seen := r.reconciledOnce.Load(req.NamespacedName); seen {
 			log.Info("skipping datasource sync, not yet time", "name", datasource.Name)
 			return ctrl.Result{RequeueAfter: time.Until(datasource.Status.NextSyncTime.Time)}, nil
 		}
 		log.Info("first reconcile this process lifetime, forcing sync despite timestamp", "name", datasource.Name)
 	}
+	if err := datasources.ConsumeResync(ctx, r.Client, datasource); err != nil {
+		log.Error(err, "failed to remove datasource resync annotation")
+		return ctrl.Result{}, err
+	}
+	// Mark the sync as in progress until it finished, whatever its outcome.
+	if !datasources.StartSync(datasource.Name) {
+		log.Info("skipping datasource sync, already in progress", "name", datasource.Name)
+		return ctrl.Result{}, nil
+	}
+	defer datasources.FinishSync(datasource.Name)
 
 	// Authenticate with the database based on the secret provided in the datasource.
 	log.Info("Connecting to database")
@@ -149,9 +197,14 @@ func (r *OpenStackDatasourceReconciler) Reconcile(ctx context.Context, req ctrl.
 	}
 
 	// Authenticate with keystone.
+	keystoneCircuit := newCircuitKey("keystone", datasource.Spec.OpenStack.SecretRef)
+	if retryAfter, ok := r.breaker.allow(keystoneCircuit, time.Now()); !ok {
+		return r.shortCircuit(ctx, datasource, keystoneCircuit, retryAfter)
+	}
 	log.Info("Authenticating with keystone")
 	authenticatedKeystone, err := keystone.Connector{Client: r.Client, HTTPClient: authenticatedHTTP}.
 		FromSecretRef(ctx, datasource.Spec.OpenStack.SecretRef)
+	r.breaker.record(keystoneCircuit, err, time.Now())
 	if err != nil {
 		log.Error(err, "failed to authenticate with keystone", "secretRef", datasource.Spec.OpenStack.SecretRef)
 		old := datasource.DeepCopy()
@@ -197,6 +250,7 @@ func (r *OpenStackDatasourceReconciler) Reconcile(ctx context.Context, req ctrl.
 	log.Info("Initializing syncer for datasource")
 	if err := syncer.Init(ctx); err != nil {
 		log.Error(err, "failed to init openstack datasource", "name", datasource.Name)
+		r.Monitor.ObserveSyncFailed(datasource.Name, "OpenStackDatasourceInitFailed")
 		old := datasource.DeepCopy()
 		meta.SetStatusCondition(&datasource.Status.Conditions, metav1.Condition{
 			Type:    v1alpha1.DatasourceConditionReady,
@@ -212,9 +266,15 @@ func (r *OpenStackDatasourceReconciler) Reconcile(ctx context.Context, req ctrl.
 		return ctrl.Result{}, err
 	}
 
+	serviceCircuit := newCircuitKey(string(datasource.Spec.OpenStack.Type), datasource.Spec.OpenStack.SecretRef)
+	if retryAfter, ok := r.breaker.allow(serviceCircuit, time.Now()); !ok {
+		return r.shortCircuit(ctx, datasource, serviceCircuit, retryAfter)
+	}
 	log.Info("Syncing datasource")
+	syncStart := time.Now()
 	nResults, err := syncer.Sync(ctx)
 	log.Info("Finished syncing datasource", "name", datasource.Name, "numberOfResults", nResults)
+	r.breaker.record(serviceCircuit, err, time.Now())
 	if errors.Is(err, v1alpha1.ErrWaitingForDependencyDatasource) {
 		log.Info("datasource sync waiting for dependency datasource", "name", datasource.Name)
 		old := datasource.DeepCopy()
@@ -235,6 +295,7 @@ func (r *OpenStackDatasourceReconciler) Reconcile(ctx context.Context, req ctrl.
 	// Other error
 	if err != nil {
 		log.Error(err, "failed to sync openstack datasource", "name", datasource.Name)
+		r.Monitor.ObserveSyncFailed(datasource.Name, "OpenStackDatasourceSyncFailed")
 		old := datasource.DeepCopy()
 		meta.SetStatusCondition(&datasource.Status.Conditions, metav1.Condition{
 			Type:    v1alpha1.DatasourceConditionReady,
@@ -260,6 +321,7 @@ func (r *OpenStackDatasourceReconciler) Reconcile(ctx context.Context, req ctrl.
 		Message: "openstack datasource synced successfully",
 	})
 	datasource.Status.LastSynced = metav1.NewTime(time.Now())
+	datasource.Status.LastSyncDuration = metav1.Duration{Duration: time.Since(syncStart)}
 	nextTime := time.Now().Add(datasource.Spec.OpenStack.SyncInterval.Duration)
 	datasource.Status.NextSyncTime = metav1.NewTime(nextTime)
 	datasource.Status.NumberOfObjects = nResults
@@ -302,6 +364,14 @@ func (r *OpenStackDatasourceReconciler) SetupWithManager(mgr manager.Manager, mc
 	if err != nil {
 		return err
 	}
+	failureThreshold, cooldown := 5, 5*time.Minute
+	if r.conf.CircuitBreakerFailureThreshold != nil {
+		failureThreshold = *r.conf.CircuitBreakerFailureThreshold
+	}
+	if r.conf.CircuitBreakerCooldown != nil {
+		cooldown = r.conf.CircuitBreakerCooldown.Duration
+	}
+	r.breaker = newCircuitBreaker(failureThreshold, cooldown, r.Monitor.CircuitBreakerStateGauge)
 	bldr := multicluster.BuildController(mcl, mgr)
 	// Watch datasource changes across all clusters.
 	bldr, err = bldr.WatchesMulticluster(
@@ -321,6 +391,7 @@ func (r *OpenStackDatasourceReconciler) SetupWithManager(mgr manager.Manager, mc
 			return true
 		}),
 		predicateIgnoreStatusConditions(),
+		datasources.PredicateIgnoreResyncConsumed(),
 	)
 	if err != nil {
 		return err
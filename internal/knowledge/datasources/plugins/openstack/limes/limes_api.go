@@ -6,7 +6,6 @@ package limes
 import (
 	"context"
 	"encoding/json"
-	"fmt"
 	"log/slog"
 	"net/http"
 	"sync"
@@ -146,7 +145,12 @@ func (api *limesAPI) getCommitments(ctx context.Context, project identity.Projec
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		return nil, gophercloud.ErrUnexpectedResponseCode{
+			URL:      req.URL.String(),
+			Method:   req.Method,
+			Expected: []int{http.StatusOK},
+			Actual:   resp.StatusCode,
+		}
 	}
 	var list struct {
 		Commitments []Commitment `json:"commitments"`
@@ -5,6 +5,8 @@ package manila
 
 import (
 	"encoding/json"
+	"fmt"
+	"strconv"
 )
 
 // OpenStack Manila storage pool.
@@ -27,6 +29,10 @@ type StoragePool struct {
 	CapabilitiesReplicationDomain           *string `json:"-" db:"capabilities_replication_domain"`
 	CapabilitiesSGConsistentSnapshotSupport string  `json:"-" db:"capabilities_sg_consistent_snapshot_support"`
 	CapabilitiesTimestamp                   string  `json:"-" db:"capabilities_timestamp"`
+	// Ratio by which thin provisioned pools may be oversubscribed.
+	CapabilitiesMaxOverSubscriptionRatio float64 `json:"-" db:"capabilities_max_over_subscription_ratio"`
+	// Capacity provisioned for the shares of the pool, if reported.
+	CapabilitiesProvisionedCapacityGB *float64 `json:"-" db:"capabilities_provisioned_capacity_gb"`
 
 	// Fields that may be lists or single values -> json strings.
 
@@ -41,6 +47,7 @@ type StoragePool struct {
 	CapabilitiesCompression                    *string `json:"-" db:"capabilities_compression"`
 	CapabilitiesIPv4Support                    *string `json:"-" db:"capabilities_ipv4_support"`
 	CapabilitiesIPv6Support                    *string `json:"-" db:"capabilities_ipv6_support"`
+	CapabilitiesThinProvisioning               *string `json:"-" db:"capabilities_thin_provisioning"`
 }
 
 // Custom unmarshaler for StoragePool to handle nested JSON.
@@ -57,16 +64,21 @@ func (sp *StoragePool) UnmarshalJSON(data []byte) error {
 	}
 
 	var capabilities struct {
-		TotalCapacityGB             float64 `json:"total_capacity_gb"`
-		FreeCapacityGB              float64 `json:"free_capacity_gb"`
-		ReservedPercentage          int     `json:"reserved_percentage"`
-		PoolName                    string  `json:"pool_name"`
-		ShareBackendName            string  `json:"share_backend_name"`
-		StorageProtocol             string  `json:"storage_protocol"`
-		VendorName                  string  `json:"vendor_name"`
-		ReplicationDomain           *string `json:"replication_domain"`
-		SGConsistentSnapshotSupport string  `json:"sg_consistent_snapshot_support"`
-		Timestamp                   string  `json:"timestamp"`
+		TotalCapacityGB             float64  `json:"total_capacity_gb"`
+		FreeCapacityGB              float64  `json:"free_capacity_gb"`
+		ReservedPercentage          int      `json:"reserved_percentage"`
+		PoolName                    string   `json:"pool_name"`
+		ShareBackendName            string   `json:"share_backend_name"`
+		StorageProtocol             string   `json:"storage_protocol"`
+		VendorName                  string   `json:"vendor_name"`
+		ReplicationDomain           *string  `json:"replication_domain"`
+		SGConsistentSnapshotSupport string   `json:"sg_consistent_snapshot_support"`
+		Timestamp                   string   `json:"timestamp"`
+		ProvisionedCapacityGB       *float64 `json:"provisioned_capacity_gb"`
+
+		// Reported as number or string depending on the driver.
+
+		MaxOverSubscriptionRatio any `json:"max_over_subscription_ratio"`
 
 		// Fields that may be lists or single values.
 
@@ -81,6 +93,7 @@ func (sp *StoragePool) UnmarshalJSON(data []byte) error {
 		Compression                    any `json:"compression"`
 		IPv4Support                    any `json:"ipv4_support"`
 		IPv6Support                    any `json:"ipv6_support"`
+		ThinProvisioning               any `json:"thin_provisioning"`
 	}
 	if err := json.Unmarshal(aux.Capabilities, &capabilities); err != nil {
 		return err
@@ -95,6 +108,17 @@ func (sp *StoragePool) UnmarshalJSON(data []byte) error {
 	sp.CapabilitiesTimestamp = capabilities.Timestamp
 	sp.CapabilitiesReplicationDomain = capabilities.ReplicationDomain
 	sp.CapabilitiesSGConsistentSnapshotSupport = capabilities.SGConsistentSnapshotSupport
+	sp.CapabilitiesProvisionedCapacityGB = capabilities.ProvisionedCapacityGB
+	switch ratio := capabilities.MaxOverSubscriptionRatio.(type) {
+	case float64:
+		sp.CapabilitiesMaxOverSubscriptionRatio = ratio
+	case string:
+		parsed, err := strconv.ParseFloat(ratio, 64)
+		if err != nil {
+			return fmt.Errorf("invalid max_over_subscription_ratio %q: %w", ratio, err)
+		}
+		sp.CapabilitiesMaxOverSubscriptionRatio = parsed
+	}
 
 	parse := func(field **string, value any) error {
 		if value == nil {
@@ -124,6 +148,7 @@ func (sp *StoragePool) UnmarshalJSON(data []byte) error {
 		{&sp.CapabilitiesCompression, capabilities.Compression},
 		{&sp.CapabilitiesIPv4Support, capabilities.IPv4Support},
 		{&sp.CapabilitiesIPv6Support, capabilities.IPv6Support},
+		{&sp.CapabilitiesThinProvisioning, capabilities.ThinProvisioning},
 	}
 	for _, f := range fields {
 		if err := parse(f.field, f.value); err != nil {
@@ -192,6 +217,10 @@ func (sp *StoragePool) MarshalJSON() ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
+	thinProvisioning, err := parseJSONString(sp.CapabilitiesThinProvisioning)
+	if err != nil {
+		return nil, err
+	}
 
 	// Reconstruct the capabilities object
 	capabilities := map[string]any{
@@ -216,6 +245,9 @@ func (sp *StoragePool) MarshalJSON() ([]byte, error) {
 		"compression":                        compression,
 		"ipv4_support":                       ipv4Support,
 		"ipv6_support":                       ipv6Support,
+		"thin_provisioning":                  thinProvisioning,
+		"max_over_subscription_ratio":        sp.CapabilitiesMaxOverSubscriptionRatio,
+		"provisioned_capacity_gb":            sp.CapabilitiesProvisionedCapacityGB,
 	}
 
 	// Create the final structure with capabilities nested
@@ -235,3 +267,6 @@ func (StoragePool) TableName() string { return "openstack_manila_storage_pools"
 
 // Index for the openstack model.
 func (StoragePool) Indexes() map[string][]string { return nil }
+
+// Version 2 added the thin provisioning capabilities.
+func (StoragePool) SchemaVersion() int { return 2 }
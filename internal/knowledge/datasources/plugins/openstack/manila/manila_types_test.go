@@ -36,7 +36,10 @@ func TestStoragePoolUnmarshalJSON(t *testing.T) {
 			"dedupe": ["on", "off"],
 			"compression": "lz4",
 			"ipv4_support": true,
-			"ipv6_support": [false]
+			"ipv6_support": [false],
+			"thin_provisioning": [true, false],
+			"max_over_subscription_ratio": "20.0",
+			"provisioned_capacity_gb": 150
 		}
 	}`
 
@@ -108,6 +111,13 @@ func TestStoragePoolUnmarshalJSON(t *testing.T) {
 	checkJSON(sp.CapabilitiesCompression, "lz4", "Compression")
 	checkJSON(sp.CapabilitiesIPv4Support, true, "IPv4Support")
 	checkJSON(sp.CapabilitiesIPv6Support, []any{false}, "IPv6Support")
+	checkJSON(sp.CapabilitiesThinProvisioning, []any{true, false}, "ThinProvisioning")
+	if sp.CapabilitiesMaxOverSubscriptionRatio != 20 {
+		t.Errorf("MaxOverSubscriptionRatio: got %v, want 20", sp.CapabilitiesMaxOverSubscriptionRatio)
+	}
+	if sp.CapabilitiesProvisionedCapacityGB == nil || *sp.CapabilitiesProvisionedCapacityGB != 150 {
+		t.Errorf("ProvisionedCapacityGB: got %v, want 150", sp.CapabilitiesProvisionedCapacityGB)
+	}
 }
 
 func TestStoragePoolMarshalJSON(t *testing.T) {
@@ -6,10 +6,12 @@ package nova
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
@@ -32,19 +34,24 @@ type NovaAPI interface {
 	Init(ctx context.Context) error
 	// Get all nova servers that are NOT deleted. (Includes ERROR, SHUTOFF etc)
 	// For KVM flavors, os_type is probed concurrently using the OSTypeProber.
-	GetAllServers(ctx context.Context) ([]Server, error)
+	// The servers are passed to onPage page by page, as they are fetched.
+	GetAllServers(ctx context.Context, onPage func([]Server) error) error
+	// Get all nova servers that changed since the timestamp, including
+	// servers that were deleted in the meantime (with status DELETED).
+	GetChangedServers(ctx context.Context, since time.Time) ([]Server, error)
 	// Get all deleted nova servers since the timestamp.
 	GetDeletedServers(ctx context.Context, since time.Time) ([]DeletedServer, error)
 	// Get all nova hypervisors.
 	GetAllHypervisors(ctx context.Context) ([]Hypervisor, error)
-	// Get all nova flavors.
-	GetAllFlavors(ctx context.Context) ([]Flavor, error)
+	// Get all nova flavors, passed to onPage page by page.
+	GetAllFlavors(ctx context.Context, onPage func([]Flavor) error) error
 	// Get all nova migrations.
 	GetAllMigrations(ctx context.Context) ([]Migration, error)
 	// Get all aggregates.
 	GetAllAggregates(ctx context.Context) ([]Aggregate, error)
-	// Get all Glance images with pre-computed os_type.
-	GetAllImages(ctx context.Context) ([]Image, error)
+	// Get all Glance images with pre-computed os_type, passed to onPage
+	// page by page.
+	GetAllImages(ctx context.Context, onPage func([]Image) error) error
 }
 
 // API for OpenStack Nova.
@@ -108,8 +115,34 @@ func (api *novaAPI) Init(ctx context.Context) error {
 	return nil
 }
 
+// Add the configured page size to the given list url, if any.
+func (api *novaAPI) withPageSize(listURL string) string {
+	if api.conf.PageSize == nil {
+		return listURL
+	}
+	sep := "?"
+	if strings.Contains(listURL, "?") {
+		sep = "&"
+	}
+	return listURL + sep + "limit=" + strconv.Itoa(*api.conf.PageSize)
+}
+
+// Returned if a list holds more objects than the configured max results.
+var ErrMaxResultsExceeded = errors.New("max results exceeded")
+
+// Check the number of fetched objects against the configured max results,
+// if any. Syncing a partial list would drop the objects past the cap from
+// the database or skip their changes, so the sync fails instead.
+func checkMaxResults(count int, maxResults *int, label string) error {
+	if maxResults == nil || count <= *maxResults {
+		return nil
+	}
+	slog.Error("reached max results, aborting sync", "label", label, "maxResults", *maxResults)
+	return fmt.Errorf("%w: more than %d objects in %s", ErrMaxResultsExceeded, *maxResults, label)
+}
+
 // Get all Nova servers that are NOT deleted. (Includes ERROR, SHUTOFF etc)
-func (api *novaAPI) GetAllServers(ctx context.Context) ([]Server, error) {
+func (api *novaAPI) GetAllServers(ctx context.Context, onPage func([]Server) error) error {
 	label := Server{}.TableName()
 	slog.Info("fetching nova data", "label", label)
 
@@ -119,25 +152,77 @@ func (api *novaAPI) GetAllServers(ctx context.Context) ([]Server, error) {
 		defer timer.ObserveDuration()
 	}
 
-	initialURL := api.sc.Endpoint + "servers/detail?all_tenants=true"
+	count := 0
+	err := api.listServers(ctx, api.withPageSize(api.sc.Endpoint+"servers/detail?all_tenants=true"), func(page []Server) error {
+		// Probe OS type concurrently for KVM servers.
+		api.probeOSTypes(ctx, page)
+		count += len(page)
+		return onPage(page)
+	})
+	if err != nil {
+		return err
+	}
+
+	slog.Info("fetched", "label", label, "count", count)
+	return nil
+}
+
+// Get all Nova servers that changed since the timestamp. Since the
+// changes-since parameter is given, nova also returns deleted servers.
+func (api *novaAPI) GetChangedServers(ctx context.Context, since time.Time) ([]Server, error) {
+	label := Server{}.TableName()
+	slog.Info("fetching nova data", "label", label, "changedSince", since)
+
+	if api.mon.RequestTimer != nil {
+		hist := api.mon.RequestTimer.WithLabelValues(label)
+		timer := prometheus.NewTimer(hist)
+		defer timer.ObserveDuration()
+	}
+
+	initialURL := api.sc.Endpoint + "servers/detail?all_tenants=true&changes-since=" + url.QueryEscape(since.Format(time.RFC3339))
+	var changedServers []Server
+	err := api.listServers(ctx, api.withPageSize(initialURL), func(page []Server) error {
+		changedServers = append(changedServers, page...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Probe OS type concurrently for KVM servers.
+	api.probeOSTypes(ctx, changedServers)
+
+	slog.Info("fetched", "label", label, "count", len(changedServers))
+	return changedServers, nil
+}
+
+// List the servers returned by the given url, following pagination links.
+// The servers of each page are passed to onPage before the next page is
+// fetched, so that callers don't need to hold all servers in memory.
+func (api *novaAPI) listServers(ctx context.Context, initialURL string, onPage func([]Server) error) error {
 	var nextURL = &initialURL
-	var allServers []Server
+	count := 0
 	seen := make(map[string]struct{})
 
 	for nextURL != nil {
 		req, err := http.NewRequestWithContext(ctx, http.MethodGet, *nextURL, http.NoBody)
 		if err != nil {
-			return nil, err
+			return err
 		}
 		req.Header.Set("X-Auth-Token", api.sc.Token())
 		req.Header.Set("X-OpenStack-Nova-API-Version", api.sc.Microversion)
 		resp, err := api.sc.HTTPClient.Do(req)
 		if err != nil {
-			return nil, err
+			return err
 		}
 		defer resp.Body.Close()
 		if resp.StatusCode != http.StatusOK {
-			return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+			return gophercloud.ErrUnexpectedResponseCode{
+				URL:      req.URL.String(),
+				Method:   req.Method,
+				Expected: []int{http.StatusOK},
+				Actual:   resp.StatusCode,
+			}
 		}
 		var list struct {
 			Servers []Server `json:"servers"`
@@ -148,15 +233,23 @@ func (api *novaAPI) GetAllServers(ctx context.Context) ([]Server, error) {
 		}
 		err = json.NewDecoder(resp.Body).Decode(&list)
 		if err != nil {
-			return nil, err
+			return err
 		}
+		page := make([]Server, 0, len(list.Servers))
 		for _, s := range list.Servers {
 			if _, ok := seen[s.ID]; ok {
 				slog.Warn("skipping duplicate server", "id", s.ID)
 				continue
 			}
 			seen[s.ID] = struct{}{}
-			allServers = append(allServers, s)
+			page = append(page, s)
+		}
+		count += len(page)
+		if err := checkMaxResults(count, api.conf.MaxResults, Server{}.TableName()); err != nil {
+			return err
+		}
+		if err := onPage(page); err != nil {
+			return err
 		}
 		nextURL = nil
 		for _, link := range list.Links {
@@ -167,11 +260,7 @@ func (api *novaAPI) GetAllServers(ctx context.Context) ([]Server, error) {
 		}
 	}
 
-	// Probe OS type concurrently for KVM servers.
-	api.probeOSTypes(ctx, allServers)
-
-	slog.Info("fetched", "label", label, "count", len(allServers))
-	return allServers, nil
+	return nil
 }
 
 // probeOSTypes determines the OS type for all KVM servers sequentially.
@@ -233,7 +322,7 @@ func (api *novaAPI) GetDeletedServers(ctx context.Context, since time.Time) ([]D
 		defer timer.ObserveDuration()
 	}
 
-	initialURL := api.sc.Endpoint + "servers/detail?status=DELETED&all_tenants=true&changes-since=" + url.QueryEscape(since.Format(time.RFC3339))
+	initialURL := api.withPageSize(api.sc.Endpoint + "servers/detail?status=DELETED&all_tenants=true&changes-since=" + url.QueryEscape(since.Format(time.RFC3339)))
 	var nextURL = &initialURL
 	var deletedServers []DeletedServer
 	seen := make(map[string]struct{})
@@ -251,7 +340,12 @@ func (api *novaAPI) GetDeletedServers(ctx context.Context, since time.Time) ([]D
 		}
 		defer resp.Body.Close()
 		if resp.StatusCode != http.StatusOK {
-			return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+			return nil, gophercloud.ErrUnexpectedResponseCode{
+				URL:      req.URL.String(),
+				Method:   req.Method,
+				Expected: []int{http.StatusOK},
+				Actual:   resp.StatusCode,
+			}
 		}
 		var list struct {
 			Servers []DeletedServer `json:"servers"`
@@ -272,6 +366,9 @@ func (api *novaAPI) GetDeletedServers(ctx context.Context, since time.Time) ([]D
 			seen[s.ID] = struct{}{}
 			deletedServers = append(deletedServers, s)
 		}
+		if err := checkMaxResults(len(deletedServers), api.conf.MaxResults, label); err != nil {
+			return nil, err
+		}
 		nextURL = nil
 		for _, link := range list.Links {
 			if link.Rel == "next" {
@@ -297,7 +394,7 @@ func (api *novaAPI) GetAllHypervisors(ctx context.Context) ([]Hypervisor, error)
 		timer := prometheus.NewTimer(hist)
 		defer timer.ObserveDuration()
 	}
-	initialURL := api.sc.Endpoint + "os-hypervisors/detail"
+	initialURL := api.withPageSize(api.sc.Endpoint + "os-hypervisors/detail")
 	var nextURL = &initialURL
 	var hypervisors []Hypervisor
 	seen := make(map[string]struct{})
@@ -314,7 +411,12 @@ func (api *novaAPI) GetAllHypervisors(ctx context.Context) ([]Hypervisor, error)
 		}
 		defer resp.Body.Close()
 		if resp.StatusCode != http.StatusOK {
-			return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+			return nil, gophercloud.ErrUnexpectedResponseCode{
+				URL:      req.URL.String(),
+				Method:   req.Method,
+				Expected: []int{http.StatusOK},
+				Actual:   resp.StatusCode,
+			}
 		}
 		var list struct {
 			Hypervisors []Hypervisor `json:"hypervisors"`
@@ -335,6 +437,9 @@ func (api *novaAPI) GetAllHypervisors(ctx context.Context) ([]Hypervisor, error)
 			seen[h.ID] = struct{}{}
 			hypervisors = append(hypervisors, h)
 		}
+		if err := checkMaxResults(len(hypervisors), api.conf.MaxResults, label); err != nil {
+			return nil, err
+		}
 		nextURL = nil
 		for _, link := range list.Links {
 			if link.Rel == "next" {
@@ -348,31 +453,42 @@ func (api *novaAPI) GetAllHypervisors(ctx context.Context) ([]Hypervisor, error)
 }
 
 // Get all Nova flavors.
-func (api *novaAPI) GetAllFlavors(ctx context.Context) ([]Flavor, error) {
+func (api *novaAPI) GetAllFlavors(ctx context.Context, onPage func([]Flavor) error) error {
 	label := Flavor{}.TableName()
 	slog.Info("fetching nova data", "label", label)
-	// Fetch all pages.
-	pages, err := func() (pagination.Page, error) {
-		if api.mon.RequestTimer != nil {
-			hist := api.mon.RequestTimer.WithLabelValues(label)
-			timer := prometheus.NewTimer(hist)
-			defer timer.ObserveDuration()
+	if api.mon.RequestTimer != nil {
+		hist := api.mon.RequestTimer.WithLabelValues(label)
+		timer := prometheus.NewTimer(hist)
+		defer timer.ObserveDuration()
+	}
+	lo := flavors.ListOpts{AccessType: flavors.AllAccess} // Also private flavors.
+	if api.conf.PageSize != nil {
+		lo.Limit = *api.conf.PageSize
+	}
+	// Pass on page by page, and stop once the max results are exceeded.
+	count := 0
+	err := flavors.ListDetail(api.sc, lo).EachPage(ctx, func(_ context.Context, page pagination.Page) (bool, error) {
+		// Parse the json data into our custom model.
+		var data = &struct {
+			Flavors []Flavor `json:"flavors"`
+		}{}
+		if err := page.(flavors.FlavorPage).ExtractInto(data); err != nil {
+			return false, err
 		}
-		lo := flavors.ListOpts{AccessType: flavors.AllAccess} // Also private flavors.
-		return flavors.ListDetail(api.sc, lo).AllPages(ctx)
-	}()
+		count += len(data.Flavors)
+		if err := checkMaxResults(count, api.conf.MaxResults, label); err != nil {
+			return false, err
+		}
+		if err := onPage(data.Flavors); err != nil {
+			return false, err
+		}
+		return true, nil
+	})
 	if err != nil {
-		return nil, err
-	}
-	// Parse the json data into our custom model.
-	var data = &struct {
-		Flavors []Flavor `json:"flavors"`
-	}{}
-	if err := pages.(flavors.FlavorPage).ExtractInto(data); err != nil {
-		return nil, err
+		return err
 	}
-	slog.Info("fetched", "label", label, "count", len(data.Flavors))
-	return data.Flavors, nil
+	slog.Info("fetched", "label", label, "count", count)
+	return nil
 }
 
 // Get all Nova migrations from the OpenStack API.
@@ -395,7 +511,7 @@ func (api *novaAPI) GetAllMigrations(ctx context.Context) ([]Migration, error) {
 		timer := prometheus.NewTimer(hist)
 		defer timer.ObserveDuration()
 	}
-	initialURL := api.sc.Endpoint + "os-migrations"
+	initialURL := api.withPageSize(api.sc.Endpoint + "os-migrations")
 	var nextURL = &initialURL
 	var migrations []Migration
 	seen := make(map[int]struct{})
@@ -413,7 +529,12 @@ func (api *novaAPI) GetAllMigrations(ctx context.Context) ([]Migration, error) {
 		}
 		defer resp.Body.Close()
 		if resp.StatusCode != http.StatusOK {
-			return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+			return nil, gophercloud.ErrUnexpectedResponseCode{
+				URL:      req.URL.String(),
+				Method:   req.Method,
+				Expected: []int{http.StatusOK},
+				Actual:   resp.StatusCode,
+			}
 		}
 		var list struct {
 			Migrations []Migration `json:"migrations"`
@@ -434,6 +555,9 @@ func (api *novaAPI) GetAllMigrations(ctx context.Context) ([]Migration, error) {
 			seen[m.ID] = struct{}{}
 			migrations = append(migrations, m)
 		}
+		if err := checkMaxResults(len(migrations), api.conf.MaxResults, label); err != nil {
+			return nil, err
+		}
 		nextURL = nil
 		for _, link := range list.Links {
 			if link.Rel == "next" {
@@ -512,9 +636,9 @@ func (api *novaAPI) GetAllAggregates(ctx context.Context) ([]Aggregate, error) {
 
 // GetAllImages fetches all Glance images and returns them with pre-computed os_type.
 // See deriveOSType for the derivation logic.
-func (api *novaAPI) GetAllImages(ctx context.Context) ([]Image, error) {
+func (api *novaAPI) GetAllImages(ctx context.Context, onPage func([]Image) error) error {
 	if api.glance == nil {
-		return nil, fmt.Errorf("glance client not initialized: datasource type must be %q", v1alpha1.NovaDatasourceTypeImages)
+		return fmt.Errorf("glance client not initialized: datasource type must be %q", v1alpha1.NovaDatasourceTypeImages)
 	}
 
 	label := Image{}.TableName()
@@ -525,26 +649,37 @@ func (api *novaAPI) GetAllImages(ctx context.Context) ([]Image, error) {
 		defer timer.ObserveDuration()
 	}
 
-	var result []Image
+	count := 0
 	opts := glanceimages.ListOpts{Limit: 1000}
+	if api.conf.PageSize != nil {
+		opts.Limit = *api.conf.PageSize
+	}
 	err := glanceimages.List(api.glance, opts).EachPage(ctx, func(_ context.Context, page pagination.Page) (bool, error) {
 		imgs, err := glanceimages.ExtractImages(page)
 		if err != nil {
 			return false, err
 		}
+		images := make([]Image, 0, len(imgs))
 		for _, img := range imgs {
-			result = append(result, Image{
+			images = append(images, Image{
 				ID:     img.ID,
 				OSType: deriveOSType(img.Properties, img.Tags),
 			})
 		}
+		count += len(images)
+		if err := checkMaxResults(count, api.conf.MaxResults, label); err != nil {
+			return false, err
+		}
+		if err := onPage(images); err != nil {
+			return false, err
+		}
 		return true, nil
 	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to list Glance images: %w", err)
+		return fmt.Errorf("failed to list Glance images: %w", err)
 	}
-	slog.Info("fetched", "label", label, "count", len(result))
-	return result, nil
+	slog.Info("fetched", "label", label, "count", count)
+	return nil
 }
 
 // deriveOSType computes os_type from image properties and tags.
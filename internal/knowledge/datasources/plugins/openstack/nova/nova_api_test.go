@@ -5,6 +5,7 @@ package nova
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -114,7 +115,11 @@ func TestNovaAPI_GetAllServers(t *testing.T) {
 	}
 
 	ctx := t.Context()
-	servers, err := api.GetAllServers(ctx)
+	var servers []Server
+	err := api.GetAllServers(ctx, func(page []Server) error {
+		servers = append(servers, page...)
+		return nil
+	})
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
@@ -123,6 +128,44 @@ func TestNovaAPI_GetAllServers(t *testing.T) {
 	}
 }
 
+func TestNovaAPI_GetChangedServers(t *testing.T) {
+	since := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("changes-since"); got != since.Format(time.RFC3339) {
+			t.Errorf("expected changes-since %s, got %s", since.Format(time.RFC3339), got)
+		}
+		if got := r.URL.Query().Get("all_tenants"); got != "true" {
+			t.Errorf("expected all_tenants to be set, got %s", got)
+		}
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if _, err := w.Write([]byte(`{"servers": [
+			{"id": "1", "name": "server1", "status": "DELETED", "flavor": {"id": "1", "name": "flavor1"}},
+			{"id": "2", "name": "server2", "status": "ACTIVE", "flavor": {"id": "1", "name": "flavor1"}}
+		]}`)); err != nil {
+			t.Fatalf("failed to write response: %v", err)
+		}
+	}
+	server, k := setupNovaMockServer(handler)
+	defer server.Close()
+
+	conf := v1alpha1.NovaDatasource{Type: v1alpha1.NovaDatasourceTypeServers}
+	api := NewNovaAPI(datasources.Monitor{}, k, conf).(*novaAPI)
+	if err := api.Init(t.Context()); err != nil {
+		t.Fatalf("failed to init nova api: %v", err)
+	}
+	servers, err := api.GetChangedServers(t.Context(), since)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(servers) != 2 {
+		t.Fatalf("expected 2 servers, got %d", len(servers))
+	}
+	if servers[0].Status != "DELETED" {
+		t.Errorf("expected deleted servers to be included, got status %s", servers[0].Status)
+	}
+}
+
 func TestNovaAPI_GetAllServers_DeduplicatesServers(t *testing.T) {
 	tests := []struct {
 		name            string
@@ -205,7 +248,11 @@ func TestNovaAPI_GetAllServers_DeduplicatesServers(t *testing.T) {
 				t.Fatalf("failed to init nova api: %v", err)
 			}
 
-			servers, err := api.GetAllServers(t.Context())
+			var servers []Server
+			err := api.GetAllServers(t.Context(), func(page []Server) error {
+				servers = append(servers, page...)
+				return nil
+			})
 			if err != nil {
 				t.Fatalf("expected no error, got %v", err)
 			}
@@ -290,7 +337,11 @@ func TestNovaAPI_GetAllFlavors(t *testing.T) {
 	}
 
 	ctx := t.Context()
-	flavors, err := api.GetAllFlavors(ctx)
+	var flavors []Flavor
+	err := api.GetAllFlavors(ctx, func(page []Flavor) error {
+		flavors = append(flavors, page...)
+		return nil
+	})
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
@@ -538,6 +589,70 @@ func TestNovaAPI_GetAllHypervisors_DeduplicatesHypervisors(t *testing.T) {
 	}
 }
 
+func TestNovaAPI_GetAllHypervisors_PageSizeAndMaxResults(t *testing.T) {
+	responses := []string{
+		`{"hypervisors": [
+			{"id": "aaa", "hypervisor_hostname": "h1", "cpu_info": {}, "service": {"id": "s1", "host": "h1"}},
+			{"id": "bbb", "hypervisor_hostname": "h2", "cpu_info": {}, "service": {"id": "s2", "host": "h2"}}
+		], "hypervisors_links": [{"rel": "next", "href": "NEXT_URL"}]}`,
+		`{"hypervisors": [
+			{"id": "ccc", "hypervisor_hostname": "h3", "cpu_info": {}, "service": {"id": "s3", "host": "h3"}},
+			{"id": "ddd", "hypervisor_hostname": "h4", "cpu_info": {}, "service": {"id": "s4", "host": "h4"}}
+		], "hypervisors_links": [{"rel": "next", "href": "NEXT_URL"}]}`,
+		`{"hypervisors": [
+			{"id": "eee", "hypervisor_hostname": "h5", "cpu_info": {}, "service": {"id": "s5", "host": "h5"}}
+		]}`,
+	}
+	callCount := 0
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if callCount == 0 && r.URL.Query().Get("limit") != "2" {
+			t.Errorf("expected limit=2 query parameter, got %q", r.URL.RawQuery)
+		}
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if _, err := w.Write([]byte(responses[callCount])); err != nil {
+			t.Fatalf("failed to write response: %v", err)
+		}
+		callCount++
+	}
+	srv, k := setupNovaMockServer(handler)
+	defer srv.Close()
+	for i := range responses {
+		responses[i] = strings.ReplaceAll(responses[i], "NEXT_URL", srv.URL+"/os-hypervisors/detail?limit=2&marker=x")
+	}
+
+	pageSize, maxResults := 2, 3
+	conf := v1alpha1.NovaDatasource{PageSize: &pageSize, MaxResults: &maxResults}
+	api := NewNovaAPI(datasources.Monitor{}, k, conf).(*novaAPI)
+	if err := api.Init(t.Context()); err != nil {
+		t.Fatalf("failed to init nova api: %v", err)
+	}
+
+	// A partial list must not be synced, since it would drop the remaining
+	// hypervisors from the database.
+	hypervisors, err := api.GetAllHypervisors(t.Context())
+	if !errors.Is(err, ErrMaxResultsExceeded) {
+		t.Fatalf("expected max results exceeded error, got %v", err)
+	}
+	if hypervisors != nil {
+		t.Errorf("expected no hypervisors, got %v", hypervisors)
+	}
+	if callCount != 2 {
+		t.Errorf("expected remaining pages to be skipped after 2 requests, got %d requests", callCount)
+	}
+
+	// Exactly as many objects as allowed are fine.
+	callCount = 0
+	maxResults = 5
+	hypervisors, err = api.GetAllHypervisors(t.Context())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(hypervisors) != 5 {
+		t.Fatalf("expected 5 hypervisors, got %d", len(hypervisors))
+	}
+}
+
 func TestDeriveOSType(t *testing.T) {
 	tests := []struct {
 		name       string
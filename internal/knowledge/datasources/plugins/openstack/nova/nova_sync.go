@@ -5,6 +5,8 @@ package nova
 
 import (
 	"context"
+	"fmt"
+	"log/slog"
 	"strings"
 	"time"
 
@@ -36,6 +38,9 @@ func (s *NovaSyncer) Init(ctx context.Context) error {
 	switch s.Conf.Type {
 	case v1alpha1.NovaDatasourceTypeServers:
 		tables = append(tables, s.DB.AddTable(Server{}))
+		if s.Conf.ServersIncrementalSync {
+			tables = append(tables, s.DB.AddTable(ServersSyncWatermark{}))
+		}
 	case v1alpha1.NovaDatasourceTypeDeletedServers:
 		tables = append(tables, s.DB.AddTable(DeletedServer{}))
 	case v1alpha1.NovaDatasourceTypeHypervisors:
@@ -82,25 +87,140 @@ func isKVMFlavor(flavorName string) bool {
 }
 
 // Sync all the active OpenStack servers into the database. (Includes ERROR, SHUTOFF, etc. state)
+// If incremental sync is configured, only servers changed since the last sync are fetched.
 func (s *NovaSyncer) SyncAllServers(ctx context.Context) (int64, error) {
-	allServers, err := s.API.GetAllServers(ctx)
+	if s.Conf.ServersIncrementalSync {
+		return s.syncChangedServers(ctx)
+	}
+	nServers, err := s.replaceAllServers(ctx)
+	if err != nil {
+		return 0, err
+	}
+	label := Server{}.TableName()
+	if s.Mon.ObjectsGauge != nil {
+		gauge := s.Mon.ObjectsGauge.WithLabelValues(label)
+		gauge.Set(float64(nServers))
+	}
+	if s.Mon.RequestProcessedCounter != nil {
+		counter := s.Mon.RequestProcessedCounter.WithLabelValues(label)
+		counter.Inc()
+	}
+	return int64(nServers), nil
+}
+
+// Replace all servers in the database, inserting them page by page as
+// they are fetched from nova.
+func (s *NovaSyncer) replaceAllServers(ctx context.Context) (int, error) {
+	return db.ReplaceAllInPages(s.DB, func(insert func([]Server) error) error {
+		return s.API.GetAllServers(ctx, insert)
+	})
+}
+
+// Sync only the servers changed since the last sync into the database.
+// Falls back to a full sync if no watermark exists yet or the configured
+// full sync interval has elapsed, to catch changes that were missed.
+func (s *NovaSyncer) syncChangedServers(ctx context.Context) (int64, error) {
+	// Take the timestamp before fetching, so that changes made while
+	// fetching are picked up again by the next sync.
+	syncStart := time.Now()
+	fullSyncEvery := 24 * time.Hour
+	if s.Conf.ServersFullSyncEveryMinutes != nil {
+		fullSyncEvery = time.Duration(*s.Conf.ServersFullSyncEveryMinutes) * time.Minute
+	}
+	watermark, err := s.getServersSyncWatermark()
 	if err != nil {
 		return 0, err
 	}
-	err = db.ReplaceAll(s.DB, allServers...)
+	if watermark == nil || syncStart.Sub(watermark.LastFullSync) >= fullSyncEvery {
+		slog.Info("running full sync of nova servers", "watermark", watermark)
+		if _, err := s.replaceAllServers(ctx); err != nil {
+			return 0, err
+		}
+		watermark = &ServersSyncWatermark{Table: Server{}.TableName(), LastFullSync: syncStart}
+	} else {
+		changedServers, err := s.API.GetChangedServers(ctx, watermark.LastSync)
+		if err != nil {
+			return 0, err
+		}
+		if err := s.applyServerChanges(changedServers); err != nil {
+			return 0, err
+		}
+	}
+	watermark.LastSync = syncStart
+	if err := s.setServersSyncWatermark(*watermark); err != nil {
+		return 0, err
+	}
+	nServers, err := s.DB.SelectInt("SELECT COUNT(*) FROM " + Server{}.TableName())
 	if err != nil {
 		return 0, err
 	}
 	label := Server{}.TableName()
 	if s.Mon.ObjectsGauge != nil {
 		gauge := s.Mon.ObjectsGauge.WithLabelValues(label)
-		gauge.Set(float64(len(allServers)))
+		gauge.Set(float64(nServers))
 	}
 	if s.Mon.RequestProcessedCounter != nil {
 		counter := s.Mon.RequestProcessedCounter.WithLabelValues(label)
 		counter.Inc()
 	}
-	return int64(len(allServers)), nil
+	return nServers, nil
+}
+
+// Upsert the changed servers and remove the ones that were deleted.
+func (s *NovaSyncer) applyServerChanges(changedServers []Server) error {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	rollback := func() {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			slog.Error("failed to rollback transaction", "error", rbErr)
+		}
+	}
+	var upserts []Server
+	for _, server := range changedServers {
+		if server.Status != "DELETED" {
+			upserts = append(upserts, server)
+			continue
+		}
+		query := "DELETE FROM " + Server{}.TableName() + " WHERE id = :id"
+		if _, err := tx.Exec(query, map[string]any{"id": server.ID}); err != nil {
+			rollback()
+			return fmt.Errorf("failed to delete server %s: %w", server.ID, err)
+		}
+	}
+	if err := db.Upsert(tx, s.DB, []string{"id"}, upserts...); err != nil {
+		rollback()
+		return fmt.Errorf("failed to upsert changed servers: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	slog.Info("applied nova server changes",
+		"upserted", len(upserts), "deleted", len(changedServers)-len(upserts))
+	return nil
+}
+
+// Get the watermark of the last servers sync, or nil if there is none.
+func (s *NovaSyncer) getServersSyncWatermark() (*ServersSyncWatermark, error) {
+	var watermarks []ServersSyncWatermark
+	query := "SELECT * FROM " + ServersSyncWatermark{}.TableName() + " WHERE table_name = :table_name"
+	if _, err := s.DB.Select(&watermarks, query, map[string]any{"table_name": Server{}.TableName()}); err != nil {
+		return nil, err
+	}
+	if len(watermarks) == 0 {
+		return nil, nil
+	}
+	return &watermarks[0], nil
+}
+
+// Replace the watermark of the last servers sync.
+func (s *NovaSyncer) setServersSyncWatermark(watermark ServersSyncWatermark) error {
+	query := "DELETE FROM " + ServersSyncWatermark{}.TableName() + " WHERE table_name = :table_name"
+	if _, err := s.DB.Exec(query, map[string]any{"table_name": watermark.Table}); err != nil {
+		return err
+	}
+	return s.DB.Insert(&watermark)
 }
 
 // Sync all the deleted OpenStack servers into the database.
@@ -160,24 +280,22 @@ func (s *NovaSyncer) SyncAllHypervisors(ctx context.Context) (int64, error) {
 
 // Sync the OpenStack flavors into the database.
 func (s *NovaSyncer) SyncAllFlavors(ctx context.Context) (int64, error) {
-	allFlavors, err := s.API.GetAllFlavors(ctx)
-	if err != nil {
-		return 0, err
-	}
-	err = db.ReplaceAll(s.DB, allFlavors...)
+	nFlavors, err := db.ReplaceAllInPages(s.DB, func(insert func([]Flavor) error) error {
+		return s.API.GetAllFlavors(ctx, insert)
+	})
 	if err != nil {
 		return 0, err
 	}
 	label := Flavor{}.TableName()
 	if s.Mon.ObjectsGauge != nil {
 		gauge := s.Mon.ObjectsGauge.WithLabelValues(label)
-		gauge.Set(float64(len(allFlavors)))
+		gauge.Set(float64(nFlavors))
 	}
 	if s.Mon.RequestProcessedCounter != nil {
 		counter := s.Mon.RequestProcessedCounter.WithLabelValues(label)
 		counter.Inc()
 	}
-	return int64(len(allFlavors)), nil
+	return int64(nFlavors), nil
 }
 
 // Sync the OpenStack migrations into the database.
@@ -204,22 +322,20 @@ func (s *NovaSyncer) SyncAllMigrations(ctx context.Context) (int64, error) {
 
 // Sync all Glance images into the database with pre-computed os_type.
 func (s *NovaSyncer) SyncAllImages(ctx context.Context) (int64, error) {
-	allImages, err := s.API.GetAllImages(ctx)
-	if err != nil {
-		return 0, err
-	}
-	err = db.ReplaceAll(s.DB, allImages...)
+	nImages, err := db.ReplaceAllInPages(s.DB, func(insert func([]Image) error) error {
+		return s.API.GetAllImages(ctx, insert)
+	})
 	if err != nil {
 		return 0, err
 	}
 	label := Image{}.TableName()
 	if s.Mon.ObjectsGauge != nil {
-		s.Mon.ObjectsGauge.WithLabelValues(label).Set(float64(len(allImages)))
+		s.Mon.ObjectsGauge.WithLabelValues(label).Set(float64(nImages))
 	}
 	if s.Mon.RequestProcessedCounter != nil {
 		s.Mon.RequestProcessedCounter.WithLabelValues(label).Inc()
 	}
-	return int64(len(allImages)), nil
+	return int64(nImages), nil
 }
 
 // Sync the OpenStack aggregates into the database.
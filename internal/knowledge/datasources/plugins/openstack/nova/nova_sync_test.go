@@ -5,6 +5,7 @@ package nova
 
 import (
 	"context"
+	"slices"
 	"testing"
 	"time"
 
@@ -18,8 +19,15 @@ type mockNovaAPI struct{}
 
 func (m *mockNovaAPI) Init(ctx context.Context) error { return nil }
 
-func (m *mockNovaAPI) GetAllServers(ctx context.Context) ([]Server, error) {
-	return []Server{{ID: "1", Name: "server1"}}, nil
+func (m *mockNovaAPI) GetAllServers(ctx context.Context, onPage func([]Server) error) error {
+	return onPage([]Server{{ID: "1", Name: "server1"}})
+}
+
+func (m *mockNovaAPI) GetChangedServers(ctx context.Context, since time.Time) ([]Server, error) {
+	return []Server{
+		{ID: "1", Name: "server1", Status: "DELETED"},
+		{ID: "2", Name: "server2", Status: "ACTIVE"},
+	}, nil
 }
 
 func (m *mockNovaAPI) GetDeletedServers(ctx context.Context, t time.Time) ([]DeletedServer, error) {
@@ -43,8 +51,8 @@ func (m *mockNovaAPI) GetAllHypervisors(ctx context.Context) ([]Hypervisor, erro
 	return []Hypervisor{{ID: "1", Hostname: "hypervisor1"}}, nil
 }
 
-func (m *mockNovaAPI) GetAllFlavors(ctx context.Context) ([]Flavor, error) {
-	return []Flavor{{ID: "1", Name: "flavor1"}}, nil
+func (m *mockNovaAPI) GetAllFlavors(ctx context.Context, onPage func([]Flavor) error) error {
+	return onPage([]Flavor{{ID: "1", Name: "flavor1"}})
 }
 
 func (m *mockNovaAPI) GetAllMigrations(ctx context.Context) ([]Migration, error) {
@@ -55,8 +63,8 @@ func (m *mockNovaAPI) GetAllAggregates(ctx context.Context) ([]Aggregate, error)
 	return []Aggregate{{Name: "aggregate1"}}, nil
 }
 
-func (m *mockNovaAPI) GetAllImages(ctx context.Context) ([]Image, error) {
-	return []Image{{ID: "img-1", OSType: "windows8Server64Guest"}}, nil
+func (m *mockNovaAPI) GetAllImages(ctx context.Context, onPage func([]Image) error) error {
+	return onPage([]Image{{ID: "img-1", OSType: "windows8Server64Guest"}})
 }
 
 func TestNovaSyncer_Init(t *testing.T) {
@@ -123,6 +131,77 @@ func TestNovaSyncer_SyncServers(t *testing.T) {
 	}
 }
 
+func TestNovaSyncer_SyncServersIncrementally(t *testing.T) {
+	dbEnv := testlibDB.SetupDBEnv(t)
+	testDB := db.DB{DbMap: dbEnv.DbMap}
+	defer dbEnv.Close()
+	syncer := &NovaSyncer{
+		DB:  testDB,
+		Mon: datasources.Monitor{},
+		Conf: v1alpha1.NovaDatasource{
+			Type:                   v1alpha1.NovaDatasourceTypeServers,
+			ServersIncrementalSync: true,
+		},
+		API: &mockNovaAPI{},
+	}
+	ctx := t.Context()
+	if err := syncer.Init(ctx); err != nil {
+		t.Fatalf("failed to init nova syncer: %v", err)
+	}
+	serverIDs := func() []string {
+		var ids []string
+		if _, err := testDB.Select(&ids, "SELECT id FROM "+Server{}.TableName()+" ORDER BY id"); err != nil {
+			t.Fatalf("failed to select servers: %v", err)
+		}
+		return ids
+	}
+
+	// Without a watermark, all servers are synced.
+	if _, err := syncer.SyncAllServers(ctx); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if ids := serverIDs(); !slices.Equal(ids, []string{"1"}) {
+		t.Fatalf("expected full sync to store server 1, got %v", ids)
+	}
+	firstWatermark, err := syncer.getServersSyncWatermark()
+	if err != nil || firstWatermark == nil {
+		t.Fatalf("expected a watermark after the full sync, got %v (err: %v)", firstWatermark, err)
+	}
+
+	// With a watermark, only changes are applied: server 1 was deleted
+	// and server 2 was created in the meantime.
+	n, err := syncer.SyncAllServers(ctx)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if n != 1 {
+		t.Errorf("expected 1 server, got %d", n)
+	}
+	if ids := serverIDs(); !slices.Equal(ids, []string{"2"}) {
+		t.Fatalf("expected incremental sync to store server 2, got %v", ids)
+	}
+	secondWatermark, err := syncer.getServersSyncWatermark()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !secondWatermark.LastFullSync.Equal(firstWatermark.LastFullSync) {
+		t.Error("expected the incremental sync to keep the last full sync time")
+	}
+	if secondWatermark.LastSync.Before(firstWatermark.LastSync) {
+		t.Error("expected the incremental sync to advance the last sync time")
+	}
+
+	// Once the full sync interval elapsed, all servers are synced again.
+	fullSyncEvery := 0
+	syncer.Conf.ServersFullSyncEveryMinutes = &fullSyncEvery
+	if _, err := syncer.SyncAllServers(ctx); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if ids := serverIDs(); !slices.Equal(ids, []string{"1"}) {
+		t.Fatalf("expected full sync to store server 1, got %v", ids)
+	}
+}
+
 func TestNovaSyncer_SyncDeletedServers(t *testing.T) {
 	tests := []struct {
 		Name                              string
@@ -6,6 +6,7 @@ package nova
 import (
 	"encoding/json"
 	"log/slog"
+	"time"
 )
 
 // OpenStack server model as returned by the Nova API under /servers/detail.
@@ -243,6 +244,22 @@ func (Server) TableName() string { return "openstack_servers_v4" }
 // Index for the openstack model.
 func (Server) Indexes() map[string][]string { return nil }
 
+// Watermark of the last incremental and full sync of the servers table.
+type ServersSyncWatermark struct {
+	// Name of the synced table.
+	Table string `db:"table_name,primarykey"`
+	// When the last (incremental or full) sync was started.
+	LastSync time.Time `db:"last_sync"`
+	// When the last full sync was started.
+	LastFullSync time.Time `db:"last_full_sync"`
+}
+
+// Table in which the openstack servers sync watermarks are stored.
+func (ServersSyncWatermark) TableName() string { return "openstack_servers_sync_watermarks" }
+
+// Index for the openstack model.
+func (ServersSyncWatermark) Indexes() map[string][]string { return nil }
+
 // OpenStack hypervisor model as returned by the Nova API under /os-hypervisors/detail.
 // See: https://docs.openstack.org/api-ref/compute/#list-hypervisors-details
 type Hypervisor struct {
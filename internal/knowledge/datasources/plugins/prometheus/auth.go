@@ -0,0 +1,36 @@
+// Copyright SAP SE
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheus
+
+import (
+	"net/http"
+)
+
+// Round tripper which adds a bearer token to each request.
+type bearerTokenTransport struct {
+	// The token to send in the Authorization header.
+	token string
+	// The wrapped round tripper which performs the actual request.
+	base http.RoundTripper
+}
+
+// RoundTrip sets the Authorization header before making the request.
+func (t *bearerTokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// Requests must not be modified by round trippers, so clone it first.
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+t.token)
+	return t.base.RoundTrip(req)
+}
+
+// Wrap the given http client so that it authenticates with a bearer token.
+// The given client is not modified.
+func withBearerToken(httpClient *http.Client, token string) *http.Client {
+	base := httpClient.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	wrapped := *httpClient
+	wrapped.Transport = &bearerTokenTransport{token: token, base: base}
+	return &wrapped
+}
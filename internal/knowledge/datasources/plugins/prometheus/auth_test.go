@@ -0,0 +1,41 @@
+// Copyright SAP SE
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheus
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWithBearerToken(t *testing.T) {
+	var gotHeader string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHeader = r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	base := &http.Client{}
+	authenticated := withBearerToken(base, "secret-token")
+	if base.Transport != nil {
+		t.Fatal("expected the base client to be left unmodified")
+	}
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, server.URL, http.NoBody)
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+	resp, err := authenticated.Do(req)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	resp.Body.Close()
+	if gotHeader != "Bearer secret-token" {
+		t.Errorf("expected bearer token header, got %q", gotHeader)
+	}
+	if req.Header.Get("Authorization") != "" {
+		t.Error("expected the original request to be left unmodified")
+	}
+}
@@ -6,6 +6,7 @@ package prometheus
 import (
 	"context"
 	"net/http"
+	"strings"
 	"sync"
 	"time"
 
@@ -71,13 +72,25 @@ func (r *PrometheusDatasourceReconciler) Reconcile(ctx context.Context, req ctrl
 		log.Info("skipping datasource, not a prometheus datasource", "name", datasource.Name)
 		return ctrl.Result{}, nil
 	}
-	if datasource.Status.NextSyncTime.After(time.Now()) && datasource.Status.NumberOfObjects != 0 {
+	if datasources.ResyncRequested(datasource) {
+		log.Info("forcing datasource sync, requested by annotation", "name", datasource.Name)
+	} else if datasource.Status.NextSyncTime.After(time.Now()) && datasource.Status.NumberOfObjects != 0 {
 		if _, seen := r.reconciledOnce.Load(req.NamespacedName); seen {
 			log.Info("skipping datasource sync, not yet time", "name", datasource.Name)
 			return ctrl.Result{RequeueAfter: time.Until(datasource.Status.NextSyncTime.Time)}, nil
 		}
 		log.Info("first reconcile this process lifetime, forcing sync despite timestamp", "name", datasource.Name)
 	}
+	if err := datasources.ConsumeResync(ctx, r.Client, datasource); err != nil {
+		log.Error(err, "failed to remove datasource resync annotation")
+		return ctrl.Result{}, err
+	}
+	// Mark the sync as in progress until it finished, whatever its outcome.
+	if !datasources.StartSync(datasource.Name) {
+		log.Info("skipping datasource sync, already in progress", "name", datasource.Name)
+		return ctrl.Result{}, nil
+	}
+	defer datasources.FinishSync(datasource.Name)
 
 	newSyncerFunc, ok := supportedMetricSyncers[datasource.Spec.Prometheus.Type]
 	if !ok {
@@ -166,6 +179,13 @@ func (r *PrometheusDatasourceReconciler) Reconcile(ctx context.Context, req ctrl
 		return ctrl.Result{}, err
 	}
 
+	// Authenticate with a bearer token if one is given in the secret. The
+	// secret is read on every sync, so rotated tokens are picked up without
+	// a restart. Client certificates are configured through the sso secret.
+	if token, ok := secret.Data["token"]; ok && len(token) > 0 {
+		authenticatedHTTP = withBearerToken(authenticatedHTTP, strings.TrimSpace(string(token)))
+	}
+
 	syncer := newSyncerFunc(
 		*datasource,
 		authenticatedDB,
@@ -173,9 +193,11 @@ func (r *PrometheusDatasourceReconciler) Reconcile(ctx context.Context, req ctrl
 		string(prometheusURL),
 		r.Monitor,
 	)
+	syncStart := time.Now()
 	nResults, nextSync, err := syncer.Sync(ctx)
 	if err != nil {
 		log.Error(err, "failed to sync prometheus datasource", "name", datasource.Name)
+		r.Monitor.ObserveSyncFailed(datasource.Name, "PrometheusDatasourceSyncFailed")
 		old := datasource.DeepCopy()
 		meta.SetStatusCondition(&datasource.Status.Conditions, metav1.Condition{
 			Type:    v1alpha1.DatasourceConditionReady,
@@ -200,8 +222,10 @@ func (r *PrometheusDatasourceReconciler) Reconcile(ctx context.Context, req ctrl
 		Message: "prometheus datasource synced successfully",
 	})
 	datasource.Status.LastSynced = metav1.NewTime(time.Now())
+	datasource.Status.LastSyncDuration = metav1.Duration{Duration: time.Since(syncStart)}
 	datasource.Status.NextSyncTime = metav1.NewTime(nextSync)
 	datasource.Status.NumberOfObjects = nResults
+	datasource.Status.SchemaVersion = syncer.SchemaVersion()
 	patch := client.MergeFrom(old)
 	if err := r.Status().Patch(ctx, datasource, patch); err != nil {
 		log.Error(err, "failed to patch datasource status", "name", datasource.Name)
@@ -233,6 +257,7 @@ func (r *PrometheusDatasourceReconciler) SetupWithManager(mgr manager.Manager, m
 			// Only react to prometheus datasources.
 			return ds.Spec.Type == v1alpha1.DatasourceTypePrometheus
 		}),
+		datasources.PredicateIgnoreResyncConsumed(),
 	)
 	if err != nil {
 		return err
@@ -171,6 +171,8 @@ func TestMetricTypeMapping(t *testing.T) {
 		"netapp_node_metric",
 		"netapp_volume_aggregate_labels_metric",
 		"kvm_libvirt_domain_metric",
+		"cooling_zone_metric",
+		"pipeline_run_duration_metric",
 	}
 
 	for _, metricType := range knownMetricTypes {
@@ -25,6 +25,8 @@ import (
 
 type typedSyncer interface {
 	Sync(context.Context) (nResults int64, nextSync time.Time, err error)
+	// Schema version of the table the metrics are synced into.
+	SchemaVersion() int
 }
 
 // Create a new prometheus metric syncer with a connected database
@@ -291,9 +293,25 @@ func (s *syncer[M]) sync(start time.Time) {
 	s.sync(end)
 }
 
+// Get the schema version of the table the metrics are synced into.
+func (s *syncer[M]) SchemaVersion() int {
+	var model M
+	return db.SchemaVersionOf(model)
+}
+
 // Sync the Prometheus metrics with the database.
 func (s *syncer[M]) Sync(context.Context) (nResults int64, nextSync time.Time, err error) {
 	var model M
+	if err := s.db.CreateTable(s.db.AddTable(db.TableSchemaVersion{})); err != nil {
+		return 0, time.Time{}, err
+	}
+	// The metrics table is shared by all aliases of the metric type, so it is
+	// recreated at most once per process, by the first alias synced. Since
+	// each datasource is synced on its first reconcile after a restart, all
+	// aliases are synced again into the recreated table.
+	if _, err := s.db.MigrateSchema(model); err != nil {
+		return 0, time.Time{}, err
+	}
 	if err := s.db.CreateTable(s.db.AddTable(model)); err != nil {
 		return 0, time.Time{}, err
 	}
@@ -307,6 +325,9 @@ func (s *syncer[M]) Sync(context.Context) (nResults int64, nextSync time.Time, e
 	}
 	s.sync(start)
 	slog.Info("synced metrics", "metricAlias", s.alias)
+	if err := db.StampSchemaVersion(s.db, model); err != nil {
+		return 0, time.Time{}, err
+	}
 
 	nResults, err = s.db.SelectInt(
 		"SELECT COUNT(*) FROM "+model.TableName()+" WHERE name = :name",
@@ -482,6 +482,17 @@ func TestSyncerSyncMethod(t *testing.T) {
 			if nextSync.Before(time.Now()) {
 				t.Error("next sync time should be in the future")
 			}
+
+			version, err := testDB.GetSchemaVersion(mockMetric{})
+			if err != nil {
+				t.Fatalf("expected schema version to be stamped, got %v", err)
+			}
+			if version != db.DefaultSchemaVersion {
+				t.Errorf("expected schema version %d, got %d", db.DefaultSchemaVersion, version)
+			}
+			if got := s.SchemaVersion(); got != version {
+				t.Errorf("expected syncer to report schema version %d, got %d", version, got)
+			}
 		})
 	}
 }
@@ -51,6 +51,60 @@ func (m KVMDomainMetric) With(n string, t time.Time, v float64) PrometheusMetric
 	return m
 }
 
+// Metric describing the thermal load of the cooling zone a host is placed in,
+// e.g. exported by the datacenter infrastructure monitoring.
+type CoolingZoneMetric struct {
+	// The name of the metric.
+	Name string `db:"name"`
+	// Compute host located in the cooling zone.
+	Host string `json:"host" db:"host"`
+	// Name of the cooling zone (e.g. a row or rack group).
+	CoolingZone string `json:"cooling_zone" db:"cooling_zone"`
+	// Timestamp of the metric value.
+	Timestamp time.Time `json:"timestamp" db:"timestamp"`
+	// The value of the metric.
+	Value float64 `json:"value" db:"value"`
+}
+
+func (m CoolingZoneMetric) TableName() string            { return "cooling_zone_metrics" }
+func (m CoolingZoneMetric) Indexes() map[string][]string { return nil }
+func (m CoolingZoneMetric) GetName() string              { return m.Name }
+func (m CoolingZoneMetric) GetTimestamp() time.Time      { return m.Timestamp }
+func (m CoolingZoneMetric) GetValue() float64            { return m.Value }
+func (m CoolingZoneMetric) With(n string, t time.Time, v float64) PrometheusMetric {
+	m.Name = n
+	m.Timestamp = t
+	m.Value = v
+	return m
+}
+
+// Bucket of the pipeline run duration histogram exported by the cortex
+// scheduler, e.g. the result of a sum by (pipeline, le) (increase(...)).
+type PipelineRunDurationMetric struct {
+	// The name of the metric.
+	Name string `db:"name"`
+	// Name of the pipeline that was run.
+	Pipeline string `json:"pipeline" db:"pipeline"`
+	// Upper bound of the histogram bucket, e.g. "0.5" or "+Inf".
+	Le string `json:"le" db:"le"`
+	// Timestamp of the metric value.
+	Timestamp time.Time `json:"timestamp" db:"timestamp"`
+	// The value of the metric.
+	Value float64 `json:"value" db:"value"`
+}
+
+func (m PipelineRunDurationMetric) TableName() string            { return "cortex_pipeline_run_duration_metrics" }
+func (m PipelineRunDurationMetric) Indexes() map[string][]string { return nil }
+func (m PipelineRunDurationMetric) GetName() string              { return m.Name }
+func (m PipelineRunDurationMetric) GetTimestamp() time.Time      { return m.Timestamp }
+func (m PipelineRunDurationMetric) GetValue() float64            { return m.Value }
+func (m PipelineRunDurationMetric) With(n string, t time.Time, v float64) PrometheusMetric {
+	m.Name = n
+	m.Timestamp = t
+	m.Value = v
+	return m
+}
+
 // VROpsHostMetric represents a single metric value from Prometheus
 // that was generated the VMware vROps exporter for a specific hostsystem.
 // See: https://github.com/sapcc/vrops-exporter
@@ -0,0 +1,76 @@
+// Copyright SAP SE
+// SPDX-License-Identifier: Apache-2.0
+
+package datasources
+
+import (
+	"context"
+	"sync"
+
+	"github.com/cobaltcore-dev/cortex/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// Names of the datasources whose sync is currently running in this process.
+// This state is kept in memory, so that it doesn't outlive a crashed sync,
+// and tracking it doesn't trigger reconciles of the datasource.
+var syncsInProgress sync.Map
+
+// Check if a resync of the datasource was requested through the annotation.
+func ResyncRequested(datasource *v1alpha1.Datasource) bool {
+	_, ok := datasource.Annotations[v1alpha1.AnnotationResync]
+	return ok
+}
+
+// Remove the resync annotation of the datasource, if any, so that the resync
+// is only forced once.
+func ConsumeResync(ctx context.Context, cl client.Client, datasource *v1alpha1.Datasource) error {
+	if !ResyncRequested(datasource) {
+		return nil
+	}
+	old := datasource.DeepCopy()
+	delete(datasource.Annotations, v1alpha1.AnnotationResync)
+	return cl.Patch(ctx, datasource, client.MergeFrom(old))
+}
+
+// Mark the sync of the named datasource as in progress. Returns false if a
+// sync of the datasource is already running.
+func StartSync(name string) bool {
+	_, running := syncsInProgress.LoadOrStore(name, struct{}{})
+	return !running
+}
+
+// Mark the sync of the named datasource as finished, whatever its outcome.
+func FinishSync(name string) {
+	syncsInProgress.Delete(name)
+}
+
+// Check if a sync of the named datasource is currently running.
+func SyncInProgress(name string) bool {
+	_, ok := syncsInProgress.Load(name)
+	return ok
+}
+
+// Predicate that ignores updates which only consume the resync annotation,
+// so that controllers aren't triggered again by their own patch.
+func PredicateIgnoreResyncConsumed() predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldObj, ok := e.ObjectOld.(*v1alpha1.Datasource)
+			if !ok {
+				return true
+			}
+			newObj, ok := e.ObjectNew.(*v1alpha1.Datasource)
+			if !ok {
+				return true
+			}
+			if !ResyncRequested(oldObj) || ResyncRequested(newObj) {
+				return true
+			}
+			return !equality.Semantic.DeepEqual(oldObj.Spec, newObj.Spec)
+		},
+	}
+}
@@ -0,0 +1,39 @@
+// Copyright SAP SE
+// SPDX-License-Identifier: Apache-2.0
+
+package datasources
+
+import (
+	"testing"
+
+	"github.com/cobaltcore-dev/cortex/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+func TestPredicateIgnoreResyncConsumed(t *testing.T) {
+	plain := &v1alpha1.Datasource{ObjectMeta: metav1.ObjectMeta{Name: "nova-servers"}}
+	requested := plain.DeepCopy()
+	requested.Annotations = map[string]string{v1alpha1.AnnotationResync: "now"}
+	changed := plain.DeepCopy()
+	changed.Spec.Type = v1alpha1.DatasourceTypeOpenStack
+
+	tests := []struct {
+		name     string
+		old, new *v1alpha1.Datasource
+		expected bool
+	}{
+		{"resync requested", plain, requested, true},
+		{"resync consumed", requested, plain, false},
+		{"resync consumed with spec change", requested, changed, true},
+		{"spec changed", plain, changed, true},
+	}
+	p := PredicateIgnoreResyncConsumed()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := p.Update(event.UpdateEvent{ObjectOld: tt.old, ObjectNew: tt.new}); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
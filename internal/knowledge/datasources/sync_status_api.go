@@ -0,0 +1,155 @@
+// Copyright SAP SE
+// SPDX-License-Identifier: Apache-2.0
+
+package datasources
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/cobaltcore-dev/cortex/api/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Sync status of a datasource, as returned by the sync status API.
+type SyncStatus struct {
+	// The name of the datasource.
+	Name string `json:"name"`
+	// The scheduling domain of the datasource.
+	SchedulingDomain v1alpha1.SchedulingDomain `json:"schedulingDomain"`
+	// The type of the datasource, e.g. openstack or prometheus.
+	Type v1alpha1.DatasourceType `json:"type"`
+	// Whether the last sync of the datasource succeeded.
+	Ready bool `json:"ready"`
+	// When the datasource was last successfully synced.
+	LastSynced *metav1.Time `json:"lastSynced,omitempty"`
+	// How long the last successful sync took.
+	LastSyncDuration string `json:"lastSyncDuration,omitempty"`
+	// The number of objects currently stored for this datasource.
+	NumberOfObjects int64 `json:"numberOfObjects"`
+	// Planned time for the next sync.
+	NextSyncTime *metav1.Time `json:"nextSyncTime,omitempty"`
+	// The error of the last sync, if it failed.
+	LastError string `json:"lastError,omitempty"`
+}
+
+// SyncStatusAPI exposes the sync status of all datasources over http, so
+// operators can quickly check which datasource is behind, and lets them
+// request an immediate resync of a datasource.
+type SyncStatusAPI struct {
+	// Client to list the datasources.
+	Client client.Client
+}
+
+// Init the API mux and bind the handlers.
+func (api *SyncStatusAPI) Init(mux *http.ServeMux) {
+	mux.HandleFunc("GET /sync/status", api.HandleSyncStatus)
+	mux.HandleFunc("POST /sync/{datasource}/resync", api.HandleResync)
+}
+
+// Build the sync status of the given datasource.
+func newSyncStatus(ds v1alpha1.Datasource) SyncStatus {
+	status := SyncStatus{
+		Name:             ds.Name,
+		SchedulingDomain: ds.Spec.SchedulingDomain,
+		Type:             ds.Spec.Type,
+		Ready:            meta.IsStatusConditionTrue(ds.Status.Conditions, v1alpha1.DatasourceConditionReady),
+		NumberOfObjects:  ds.Status.NumberOfObjects,
+	}
+	if !ds.Status.LastSynced.IsZero() {
+		status.LastSynced = &ds.Status.LastSynced
+		status.LastSyncDuration = ds.Status.LastSyncDuration.Duration.String()
+	}
+	if !ds.Status.NextSyncTime.IsZero() {
+		status.NextSyncTime = &ds.Status.NextSyncTime
+	}
+	cond := meta.FindStatusCondition(ds.Status.Conditions, v1alpha1.DatasourceConditionReady)
+	if cond != nil && cond.Status == metav1.ConditionFalse {
+		status.LastError = cond.Message
+	}
+	return status
+}
+
+// Return the sync status of all datasources. Use ?domain=<domain> to only
+// return the datasources of one scheduling domain.
+func (api *SyncStatusAPI) HandleSyncStatus(w http.ResponseWriter, r *http.Request) {
+	domain := v1alpha1.SchedulingDomain(r.URL.Query().Get("domain"))
+	var datasources v1alpha1.DatasourceList
+	if err := api.Client.List(r.Context(), &datasources); err != nil {
+		slog.Error("failed to list datasources", "error", err)
+		http.Error(w, "failed to list datasources", http.StatusInternalServerError)
+		return
+	}
+	statuses := []SyncStatus{}
+	for _, ds := range datasources.Items {
+		if domain != "" && ds.Spec.SchedulingDomain != domain {
+			continue
+		}
+		statuses = append(statuses, newSyncStatus(ds))
+	}
+	slices.SortFunc(statuses, func(a, b SyncStatus) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(statuses); err != nil {
+		slog.Error("failed to encode sync status", "error", err)
+	}
+}
+
+// Request an immediate sync of the named datasource, regardless of its next
+// sync time. The sync is run asynchronously by the datasource controller, so
+// this responds with 202 Accepted and the current sync status. The outcome of
+// the sync is reflected in the sync status once finished, i.e. callers poll
+// GET /sync until lastSynced or lastError changes. Responds with a conflict
+// if a resync of the datasource was already requested, or a sync is running
+// in this process.
+func (api *SyncStatusAPI) HandleResync(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("datasource")
+	ds := &v1alpha1.Datasource{}
+	if err := api.Client.Get(r.Context(), client.ObjectKey{Name: name}, ds); err != nil {
+		if apierrors.IsNotFound(err) {
+			http.Error(w, "datasource not found", http.StatusNotFound)
+			return
+		}
+		slog.Error("failed to get datasource", "name", name, "error", err)
+		http.Error(w, "failed to get datasource", http.StatusInternalServerError)
+		return
+	}
+	if _, ok := ds.Annotations[v1alpha1.AnnotationResync]; ok {
+		http.Error(w, "resync of datasource already requested", http.StatusConflict)
+		return
+	}
+	if SyncInProgress(ds.Name) {
+		http.Error(w, "sync of datasource already in progress", http.StatusConflict)
+		return
+	}
+	old := ds.DeepCopy()
+	if ds.Annotations == nil {
+		ds.Annotations = map[string]string{}
+	}
+	ds.Annotations[v1alpha1.AnnotationResync] = time.Now().UTC().Format(time.RFC3339)
+	// Optimistic locking, so that concurrent requests can't both trigger a resync.
+	patch := client.MergeFromWithOptions(old, client.MergeFromWithOptimisticLock{})
+	if err := api.Client.Patch(r.Context(), ds, patch); err != nil {
+		if apierrors.IsConflict(err) {
+			http.Error(w, "resync of datasource already in progress", http.StatusConflict)
+			return
+		}
+		slog.Error("failed to request datasource resync", "name", name, "error", err)
+		http.Error(w, "failed to request datasource resync", http.StatusInternalServerError)
+		return
+	}
+	slog.Info("requested datasource resync", "name", name)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	if err := json.NewEncoder(w).Encode(newSyncStatus(*ds)); err != nil {
+		slog.Error("failed to encode sync status", "error", err)
+	}
+}
@@ -0,0 +1,171 @@
+// Copyright SAP SE
+// SPDX-License-Identifier: Apache-2.0
+
+package datasources
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cobaltcore-dev/cortex/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestSyncStatusAPI_HandleSyncStatus(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add scheme: %v", err)
+	}
+	lastSynced := metav1.NewTime(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+	synced := &v1alpha1.Datasource{
+		ObjectMeta: metav1.ObjectMeta{Name: "nova-servers"},
+		Spec: v1alpha1.DatasourceSpec{
+			SchedulingDomain: v1alpha1.SchedulingDomainNova,
+			Type:             v1alpha1.DatasourceTypeOpenStack,
+		},
+		Status: v1alpha1.DatasourceStatus{
+			LastSynced:       lastSynced,
+			LastSyncDuration: metav1.Duration{Duration: 3 * time.Second},
+			NumberOfObjects:  42,
+			Conditions: []metav1.Condition{{
+				Type:   v1alpha1.DatasourceConditionReady,
+				Status: metav1.ConditionTrue,
+				Reason: "OpenStackDatasourceSynced",
+			}},
+		},
+	}
+	failed := &v1alpha1.Datasource{
+		ObjectMeta: metav1.ObjectMeta{Name: "host-cpu-usage"},
+		Spec: v1alpha1.DatasourceSpec{
+			SchedulingDomain: v1alpha1.SchedulingDomainNova,
+			Type:             v1alpha1.DatasourceTypePrometheus,
+		},
+		Status: v1alpha1.DatasourceStatus{
+			Conditions: []metav1.Condition{{
+				Type:    v1alpha1.DatasourceConditionReady,
+				Status:  metav1.ConditionFalse,
+				Reason:  "PrometheusDatasourceSyncFailed",
+				Message: "failed to sync prometheus datasource: timeout",
+			}},
+		},
+	}
+	other := &v1alpha1.Datasource{
+		ObjectMeta: metav1.ObjectMeta{Name: "manila-storage-pools"},
+		Spec: v1alpha1.DatasourceSpec{
+			SchedulingDomain: v1alpha1.SchedulingDomainManila,
+			Type:             v1alpha1.DatasourceTypeOpenStack,
+		},
+	}
+	api := &SyncStatusAPI{Client: fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(synced, failed, other).
+		Build()}
+	mux := http.NewServeMux()
+	api.Init(mux)
+
+	req := httptest.NewRequest(http.MethodGet, "/sync/status?domain=nova", http.NoBody)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	var statuses []SyncStatus
+	if err := json.NewDecoder(w.Body).Decode(&statuses); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(statuses) != 2 {
+		t.Fatalf("expected 2 datasources, got %d", len(statuses))
+	}
+	// Sorted by name.
+	if statuses[0].Name != "host-cpu-usage" || statuses[1].Name != "nova-servers" {
+		t.Fatalf("unexpected datasource order: %s, %s", statuses[0].Name, statuses[1].Name)
+	}
+	if statuses[0].Ready || statuses[0].LastError != "failed to sync prometheus datasource: timeout" {
+		t.Errorf("expected failed datasource with last error, got %+v", statuses[0])
+	}
+	if statuses[0].LastSynced != nil {
+		t.Errorf("expected no last synced time for never synced datasource, got %v", statuses[0].LastSynced)
+	}
+	if !statuses[1].Ready || statuses[1].LastError != "" {
+		t.Errorf("expected ready datasource without error, got %+v", statuses[1])
+	}
+	if statuses[1].LastSynced == nil || !statuses[1].LastSynced.Equal(&lastSynced) {
+		t.Errorf("expected last synced %v, got %v", lastSynced, statuses[1].LastSynced)
+	}
+	if statuses[1].LastSyncDuration != "3s" || statuses[1].NumberOfObjects != 42 {
+		t.Errorf("unexpected sync duration or object count: %+v", statuses[1])
+	}
+}
+
+func TestSyncStatusAPI_HandleResync(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add scheme: %v", err)
+	}
+	ds := &v1alpha1.Datasource{
+		ObjectMeta: metav1.ObjectMeta{Name: "nova-servers"},
+		Spec: v1alpha1.DatasourceSpec{
+			SchedulingDomain: v1alpha1.SchedulingDomainNova,
+			Type:             v1alpha1.DatasourceTypeOpenStack,
+		},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(ds).Build()
+	api := &SyncStatusAPI{Client: fakeClient}
+	mux := http.NewServeMux()
+	api.Init(mux)
+
+	resync := func(name string) int {
+		req := httptest.NewRequest(http.MethodPost, "/sync/"+name+"/resync", http.NoBody)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	if code := resync("unknown"); code != http.StatusNotFound {
+		t.Errorf("expected status 404 for unknown datasource, got %d", code)
+	}
+	if code := resync("nova-servers"); code != http.StatusAccepted {
+		t.Fatalf("expected status 202, got %d", code)
+	}
+	updated := &v1alpha1.Datasource{}
+	if err := fakeClient.Get(t.Context(), client.ObjectKey{Name: "nova-servers"}, updated); err != nil {
+		t.Fatalf("failed to get datasource: %v", err)
+	}
+	if _, ok := updated.Annotations[v1alpha1.AnnotationResync]; !ok {
+		t.Fatal("expected resync annotation to be set")
+	}
+	// A second request while the resync is pending is rejected.
+	if code := resync("nova-servers"); code != http.StatusConflict {
+		t.Errorf("expected status 409 for pending resync, got %d", code)
+	}
+
+	// While the controller syncs the datasource, no resync can be requested.
+	if err := ConsumeResync(t.Context(), fakeClient, updated); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if ResyncRequested(updated) {
+		t.Fatal("expected resync annotation to be consumed")
+	}
+	if !StartSync(updated.Name) {
+		t.Fatal("expected sync to start")
+	}
+	if StartSync(updated.Name) {
+		t.Error("expected concurrent sync not to start")
+	}
+	if code := resync("nova-servers"); code != http.StatusConflict {
+		t.Errorf("expected status 409 for running sync, got %d", code)
+	}
+
+	// Once the sync finished, a resync can be requested again.
+	FinishSync(updated.Name)
+	if code := resync("nova-servers"); code != http.StatusAccepted {
+		t.Errorf("expected status 202 after sync finished, got %d", code)
+	}
+}
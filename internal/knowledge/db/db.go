@@ -28,6 +28,10 @@ type DB struct {
 	*gorp.DbMap
 	host         string
 	databaseName string
+	// Result of the periodic liveness checks, shared by all copies.
+	liveness *liveness
+	// Optional read replica to route read-only queries to.
+	reader *DB
 }
 
 type Table interface {
@@ -77,20 +81,52 @@ func (c Connector) FromSecretRef(ctx context.Context, ref corev1.SecretReference
 		return nil, errors.New("missing port in secret data")
 	}
 	strip := func(s string) string { return strings.ReplaceAll(s, "\n", "") }
-	dbURL, err := easypg.URLFrom(easypg.URLParts{
-		HostName:          strip(string(host)),
-		Port:              strip(string(port)),
-		UserName:          strip(string(user)),
-		Password:          strip(string(password)),
-		DatabaseName:      strip(string(database)),
-		ConnectionOptions: "sslmode=disable",
-	})
+	connectTo := func(host, port string) (*DB, error) {
+		dbURL, err := easypg.URLFrom(easypg.URLParts{
+			HostName:          strip(host),
+			Port:              strip(port),
+			UserName:          strip(string(user)),
+			Password:          strip(string(password)),
+			DatabaseName:      strip(string(database)),
+			ConnectionOptions: "sslmode=disable",
+		})
+		if err != nil {
+			return nil, err
+		}
+		dbUrlStr := dbURL.String()
+		// Strip the password from the URL for logging.
+		urlForLog := strings.ReplaceAll(dbUrlStr, strip(string(password)), "****")
+		return connect(ctx, dbUrlStr, urlForLog, host, string(database))
+	}
+
+	primary, err := connectTo(string(host), string(port))
 	if err != nil {
 		return nil, err
 	}
-	dbUrlStr := dbURL.String()
-	// Strip the password from the URL for logging.
-	urlForLog := strings.ReplaceAll(dbUrlStr, strip(string(password)), "****")
+	// Read queries can optionally be routed to a read replica.
+	replicaHost, ok := authSecret.Data["readReplicaHost"]
+	if !ok {
+		return primary, nil
+	}
+	replicaPort := port
+	if p, ok := authSecret.Data["readReplicaPort"]; ok {
+		replicaPort = p
+	}
+	reader, err := connectTo(string(replicaHost), string(replicaPort))
+	if err != nil {
+		// An unavailable replica shouldn't take down the primary's users.
+		slog.Error("failed to connect to read replica, reading from primary", "error", err)
+		return primary, nil
+	}
+	// The cached connection is shared by all secrets pointing to the same
+	// database, so attach the replica to a copy instead of the cached entry.
+	withReader := *primary
+	withReader.reader = reader
+	return &withReader, nil
+}
+
+// Connect to the database at the given url, or reuse an existing connection.
+func connect(ctx context.Context, dbUrlStr, urlForLog, host, database string) (*DB, error) {
 	slog.Info("connecting to database", "url", urlForLog)
 
 	// Check if we already have a connection for this url.
@@ -99,7 +135,7 @@ func (c Connector) FromSecretRef(ctx context.Context, ref corev1.SecretReference
 		return conn.(*DB), nil
 	}
 
-	Monitor.connectionAttempts.WithLabelValues(string(host), string(database)).Inc()
+	Monitor.connectionAttempts.WithLabelValues(host, database).Inc()
 
 	db, err := sql.Open("postgres", dbUrlStr)
 	if err != nil {
@@ -121,14 +157,33 @@ func (c Connector) FromSecretRef(ctx context.Context, ref corev1.SecretReference
 		time.Sleep(1 * time.Second)
 	}
 
-	db.SetMaxOpenConns(16)
+	poolConfigMu.Lock()
+	poolConfig.apply(db)
+	poolConfigMu.Unlock()
 	dbMap := &gorp.DbMap{Db: db, Dialect: gorp.PostgresDialect{}}
 	slog.Info("database is ready")
-	wrapped := &DB{DbMap: dbMap, host: string(host), databaseName: string(database)}
+	wrapped := &DB{
+		DbMap:        dbMap,
+		host:         host,
+		databaseName: database,
+		liveness:     &liveness{},
+	}
 	connections.Store(dbUrlStr, wrapped)
 	return wrapped, nil
 }
 
+// Get the database to run read-only queries against. This is the read
+// replica if one is configured, and the database itself otherwise.
+//
+// Note: The read replica may lag behind, so queries that need to see
+// the writes just made should run against the database itself.
+func (d *DB) Reader() *DB {
+	if d.reader == nil {
+		return d
+	}
+	return d.reader
+}
+
 // Executes a select query while monitoring its execution time.
 func (d *DB) SelectTimed(group string, i any, query string, args ...any) ([]any, error) {
 	queryURL := url.QueryEscape(query)
@@ -197,106 +252,284 @@ func (d *DB) TableExists(t Table) bool {
 	return exists
 }
 
-// Replace all old objects of a table with new objects.
+// Replace all old objects of a table with new objects and stamp the
+// schema version of the table into the metadata table.
 func ReplaceAll[T Table](db DB, objs ...T) error {
+	_, err := ReplaceAllInPages(db, func(insert func([]T) error) error {
+		return insert(objs)
+	})
+	return err
+}
+
+// Replace all old objects of a table with the objects passed page by page
+// to the insert function given to fetch, and stamp the schema version of the
+// table into the metadata table. Each page is inserted into the transaction
+// right away, so the objects don't need to be held in memory all at once.
+// If fetch fails, the transaction is rolled back and the old objects are
+// kept. Returns the number of inserted objects.
+func ReplaceAllInPages[T Table](db DB, fetch func(insert func([]T) error) error) (int, error) {
 	var model T
 	tableName := model.TableName()
+	if err := db.CreateTable(db.AddTable(TableSchemaVersion{})); err != nil {
+		return 0, err
+	}
+	if err := db.CreateTable(db.AddTable(model)); err != nil {
+		return 0, err
+	}
+	// Tables of an older schema are recreated once per process, in the same
+	// transaction that replaces all rows.
+	checkSchema := !isSchemaChecked(tableName)
 	tx, err := db.Begin()
 	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
 	}
-	if _, err = tx.Exec("DELETE FROM " + tableName); err != nil {
+	rollback := func() {
 		if rbErr := tx.Rollback(); rbErr != nil {
 			slog.Error("failed to rollback transaction", "error", rbErr)
 		}
-		return fmt.Errorf("failed to delete old objects from %s: %w", tableName, err)
 	}
-	if err = BulkInsert(tx, db, objs...); err != nil {
-		if rbErr := tx.Rollback(); rbErr != nil {
-			slog.Error("failed to rollback transaction", "error", rbErr)
+	if checkSchema {
+		if _, err := migrateSchema(tx, db, model); err != nil {
+			rollback()
+			return 0, err
 		}
-		return fmt.Errorf("failed to insert new objects into %s: %w", tableName, err)
+	}
+	if _, err = tx.Exec("DELETE FROM " + tableName); err != nil {
+		rollback()
+		return 0, fmt.Errorf("failed to delete old objects from %s: %w", tableName, err)
+	}
+	inserted := 0
+	insert := func(page []T) error {
+		if err := BulkInsert(tx, db, page...); err != nil {
+			return fmt.Errorf("failed to insert new objects into %s: %w", tableName, err)
+		}
+		inserted += len(page)
+		return nil
+	}
+	if err = fetch(insert); err != nil {
+		rollback()
+		return 0, err
+	}
+	if err = StampSchemaVersion(tx, model); err != nil {
+		rollback()
+		return 0, fmt.Errorf("failed to stamp schema version of %s: %w", tableName, err)
 	}
 	if err = tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
 	}
-	return nil
+	if checkSchema {
+		markSchemaChecked(tableName)
+	}
+	return inserted, nil
+}
+
+// Default number of objects inserted per statement by BulkInsert.
+const DefaultBulkInsertBatchSize = 1000
+
+// Settings for the bulk writes of the datasource syncers.
+type BulkInsertConfig struct {
+	// Number of objects inserted per statement when writing synced objects.
+	// Defaults to DefaultBulkInsertBatchSize. Clamped per table, so that a
+	// statement stays within the bind parameter limit of postgres.
+	DBBulkInsertBatchSize int `json:"dbBulkInsertBatchSize,omitempty"`
+}
+
+var (
+	bulkInsertConfigMu sync.Mutex
+	bulkInsertConfig   BulkInsertConfig
+)
+
+// Set the bulk insert settings. Should be called once on startup.
+func ConfigureBulkInsert(c BulkInsertConfig) {
+	bulkInsertConfigMu.Lock()
+	defer bulkInsertConfigMu.Unlock()
+	bulkInsertConfig = c
+}
+
+// Get the configured number of objects to insert per statement, falling
+// back to DefaultBulkInsertBatchSize if unset.
+func BulkInsertBatchSize() int {
+	bulkInsertConfigMu.Lock()
+	defer bulkInsertConfigMu.Unlock()
+	if bulkInsertConfig.DBBulkInsertBatchSize > 0 {
+		return bulkInsertConfig.DBBulkInsertBatchSize
+	}
+	return DefaultBulkInsertBatchSize
+}
+
+// Maximum number of bind parameters in a single postgres statement.
+const maxBindParameters = 65535
+
+// Clamp the batch size so that the multi-row statement for a batch of
+// objects of the given table stays within maxBindParameters.
+func clampBatchSize(table *gorp.TableMap, batchSize int) int {
+	columns := 0
+	for _, col := range table.Columns {
+		if !col.Transient {
+			columns++
+		}
+	}
+	if columns == 0 {
+		return batchSize
+	}
+	return max(1, min(batchSize, maxBindParameters/columns))
 }
 
 // Bulk insert objects into the database, using an executor which
-// can be a transaction or a database connection itself.
+// can be a transaction or a database connection itself. Objects are
+// written in batches of the configured BulkInsertBatchSize.
 //
 // Note: This function does NOT support auto-incrementing primary keys.
 func BulkInsert[T Table](executor gorp.SqlExecutor, db DB, allObjs ...T) error {
+	return BulkInsertWithBatchSize(executor, db, BulkInsertBatchSize(), allObjs...)
+}
+
+// Bulk insert objects into the database with one multi-row INSERT per batch
+// of the given size. The batch size is clamped per table, so that tables with
+// many columns stay within the bind parameter limit of postgres.
+//
+// Note: This function does NOT support auto-incrementing primary keys.
+func BulkInsertWithBatchSize[T Table](executor gorp.SqlExecutor, db DB, batchSize int, allObjs ...T) error {
 	if len(allObjs) == 0 {
 		// Nothing to do.
 		return nil
 	}
+	if batchSize <= 0 {
+		return fmt.Errorf("invalid bulk insert batch size: %d", batchSize)
+	}
+	table, err := db.TableFor(reflect.TypeFor[T](), false)
+	if err != nil {
+		return err
+	}
+	batchSize = clampBatchSize(table, batchSize)
 
-	// Commit every n objects to avoid running out of memory and avoid
-	// hitting the database parameter limit.
-	const batchSize = 1000
+	for i := 0; i < len(allObjs); i += batchSize {
+		end := min(i+batchSize, len(allObjs))
+		if err := insertBatch(executor, db, allObjs[i:end], ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Insert or update objects keyed on the given conflict columns, which must
+// be covered by a unique index or the primary key of the table. Existing
+// rows are updated in place instead of being deleted and reinserted, which
+// avoids churning the whole table on repeated syncs. Objects are written
+// in batches of the configured BulkInsertBatchSize, clamped per table like
+// in BulkInsertWithBatchSize.
+//
+// Note: Objects in the same call must not share the same conflict key.
+func Upsert[T Table](executor gorp.SqlExecutor, db DB, conflictColumns []string, allObjs ...T) error {
+	if len(allObjs) == 0 {
+		// Nothing to do.
+		return nil
+	}
+	if len(conflictColumns) == 0 {
+		return errors.New("upsert requires at least one conflict column")
+	}
+	table, err := db.TableFor(reflect.TypeFor[T](), false)
+	if err != nil {
+		return err
+	}
+	columns := make(map[string]bool, len(table.Columns))
+	for _, col := range table.Columns {
+		columns[col.ColumnName] = true
+	}
+	isConflictColumn := make(map[string]bool, len(conflictColumns))
+	quotedConflictColumns := make([]string, 0, len(conflictColumns))
+	for _, col := range conflictColumns {
+		if !columns[col] {
+			return fmt.Errorf("unknown conflict column %s in table %s", col, table.TableName)
+		}
+		isConflictColumn[col] = true
+		quotedConflictColumns = append(quotedConflictColumns, db.Dialect.QuoteField(col))
+	}
+	var updates []string
+	for _, col := range table.Columns {
+		if col.Transient || isConflictColumn[col.ColumnName] {
+			continue
+		}
+		quoted := db.Dialect.QuoteField(col.ColumnName)
+		updates = append(updates, quoted+" = excluded."+quoted)
+	}
+	onConflict := " ON CONFLICT (" + strings.Join(quotedConflictColumns, ", ") + ") "
+	if len(updates) == 0 {
+		onConflict += "DO NOTHING"
+	} else {
+		onConflict += "DO UPDATE SET " + strings.Join(updates, ", ")
+	}
 
+	batchSize := clampBatchSize(table, BulkInsertBatchSize())
 	for i := 0; i < len(allObjs); i += batchSize {
 		end := min(i+batchSize, len(allObjs))
-		objs := allObjs[i:end]
-		// Detect the table based on the first object.
-		objType := reflect.ValueOf(objs).Index(0).Type()
-		table, err := db.TableFor(objType, false)
-		if err != nil {
-			slog.Error("failed to get table for object", "error", err)
+		if err := insertBatch(executor, db, allObjs[i:end], onConflict); err != nil {
 			return err
 		}
+	}
+	return nil
+}
+
+// Insert the given objects with a single multi-row INSERT statement.
+// The suffix is appended to the statement, e.g. an ON CONFLICT clause.
+func insertBatch[T Table](executor gorp.SqlExecutor, db DB, objs []T, suffix string) error {
+	// Detect the table based on the first object.
+	objType := reflect.ValueOf(objs).Index(0).Type()
+	table, err := db.TableFor(objType, false)
+	if err != nil {
+		slog.Error("failed to get table for object", "error", err)
+		return err
+	}
 
-		// Using a strings.Builder is much faster than string concatenation.
-		var builder strings.Builder
-		builder.WriteString("INSERT INTO ")
-		builder.WriteString(db.Dialect.QuotedTableForQuery(table.SchemaName, table.TableName))
-		builder.WriteString(" (")
+	// Using a strings.Builder is much faster than string concatenation.
+	var builder strings.Builder
+	builder.WriteString("INSERT INTO ")
+	builder.WriteString(db.Dialect.QuotedTableForQuery(table.SchemaName, table.TableName))
+	builder.WriteString(" (")
 
-		// Build the column names.
-		for idx, col := range table.Columns {
+	// Build the column names.
+	for idx, col := range table.Columns {
+		if col.Transient {
+			continue
+		}
+		builder.WriteString(db.Dialect.QuoteField(col.ColumnName))
+		if idx < len(table.Columns)-1 {
+			builder.WriteString(", ")
+		}
+	}
+	builder.WriteString(") VALUES ")
+
+	var params []any
+	// Build the values.
+	paramIdx := 0
+	for i, obj := range objs {
+		if i > 0 {
+			builder.WriteString(", ")
+		}
+		builder.WriteString("(")
+		for j, col := range table.Columns {
 			if col.Transient {
 				continue
 			}
-			builder.WriteString(db.Dialect.QuoteField(col.ColumnName))
-			if idx < len(table.Columns)-1 {
-				builder.WriteString(", ")
-			}
-		}
-		builder.WriteString(") VALUES ")
-
-		var params []any
-		// Build the values.
-		paramIdx := 0
-		for i, obj := range objs {
-			if i > 0 {
+			val := reflect.ValueOf(obj).FieldByIndex([]int{j}).Interface()
+			params = append(params, val)
+			builder.WriteString(db.Dialect.BindVar(paramIdx))
+			if j < len(table.Columns)-1 {
 				builder.WriteString(", ")
 			}
-			builder.WriteString("(")
-			for j, col := range table.Columns {
-				if col.Transient {
-					continue
-				}
-				val := reflect.ValueOf(obj).FieldByIndex([]int{j}).Interface()
-				params = append(params, val)
-				builder.WriteString(db.Dialect.BindVar(paramIdx))
-				if j < len(table.Columns)-1 {
-					builder.WriteString(", ")
-				}
-				paramIdx++
-			}
-			builder.WriteString(")")
+			paramIdx++
 		}
+		builder.WriteString(")")
+	}
 
-		builder.WriteString(db.Dialect.QuerySuffix())
-		query := builder.String()
+	builder.WriteString(suffix)
+	builder.WriteString(db.Dialect.QuerySuffix())
+	query := builder.String()
 
-		slog.Debug("bulk inserting objects", "n", len(objs))
-		if _, err = executor.Exec(query, params...); err != nil {
-			slog.Error("failed to execute bulk insert", "error", err)
-			return err
-		}
+	slog.Debug("bulk inserting objects", "n", len(objs))
+	if _, err = executor.Exec(query, params...); err != nil {
+		slog.Error("failed to execute bulk insert", "error", err)
+		return err
 	}
 	return nil
 }
@@ -4,6 +4,7 @@
 package db
 
 import (
+	"errors"
 	"strings"
 	"testing"
 	"time"
@@ -170,6 +171,56 @@ func TestReplaceAllWithDuplicateKeys(t *testing.T) {
 	}
 }
 
+func TestReplaceAllInPages(t *testing.T) {
+	dbEnv := testlibDB.SetupDBEnv(t)
+	db := DB{DbMap: dbEnv.DbMap}
+	defer dbEnv.Close()
+
+	if err := ReplaceAll(db, MockTable{ID: 1, Name: "old"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// A failing fetch must keep the old objects, even if pages were inserted.
+	errFetch := errors.New("fetch failed")
+	_, err := ReplaceAllInPages(db, func(insert func([]MockTable) error) error {
+		if err := insert([]MockTable{{ID: 2, Name: "page1"}}); err != nil {
+			return err
+		}
+		return errFetch
+	})
+	if !errors.Is(err, errFetch) {
+		t.Fatalf("expected fetch error, got %v", err)
+	}
+	var names []string
+	if _, err := db.Select(&names, "SELECT name FROM mock_table ORDER BY id"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(names) != 1 || names[0] != "old" {
+		t.Fatalf("expected old data to be intact, got %v", names)
+	}
+
+	// All pages replace the old objects.
+	n, err := ReplaceAllInPages(db, func(insert func([]MockTable) error) error {
+		if err := insert([]MockTable{{ID: 2, Name: "page1"}, {ID: 3, Name: "page1"}}); err != nil {
+			return err
+		}
+		return insert([]MockTable{{ID: 4, Name: "page2"}})
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if n != 3 {
+		t.Errorf("expected 3 inserted objects, got %d", n)
+	}
+	names = nil
+	if _, err := db.Select(&names, "SELECT name FROM mock_table ORDER BY id"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if strings.Join(names, ",") != "page1,page1,page2" {
+		t.Fatalf("expected the paged objects, got %v", names)
+	}
+}
+
 // Test all sorts of data types.
 type BulkMockTable struct {
 	A int        `db:"a,primarykey"`
@@ -280,3 +331,319 @@ func TestBulkInsert(t *testing.T) {
 		}
 	}
 }
+
+type VersionedMockTable struct {
+	ID int `db:"id,primarykey"`
+}
+
+func (VersionedMockTable) TableName() string            { return "versioned_mock_table" }
+func (VersionedMockTable) Indexes() map[string][]string { return nil }
+func (VersionedMockTable) SchemaVersion() int           { return 3 }
+
+func TestReplaceAll_StampsSchemaVersion(t *testing.T) {
+	dbEnv := testlibDB.SetupDBEnv(t)
+	db := DB{DbMap: dbEnv.DbMap}
+	defer dbEnv.Close()
+
+	if err := db.CreateTable(db.AddTable(MockTable{}), db.AddTable(VersionedMockTable{})); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, err := db.GetSchemaVersion(MockTable{}); err == nil {
+		t.Fatal("expected error before the table was written")
+	}
+
+	if err := ReplaceAll(db, MockTable{ID: 1, Name: "record1"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := ReplaceAll(db, VersionedMockTable{ID: 1}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	// Writing twice should keep a single stamp per table.
+	if err := ReplaceAll(db, VersionedMockTable{ID: 2}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	tests := []struct {
+		table    Table
+		expected int
+	}{
+		{MockTable{}, DefaultSchemaVersion},
+		{VersionedMockTable{}, 3},
+	}
+	for _, tt := range tests {
+		version, err := db.GetSchemaVersion(tt.table)
+		if err != nil {
+			t.Fatalf("expected no error for %s, got %v", tt.table.TableName(), err)
+		}
+		if version != tt.expected {
+			t.Errorf("expected schema version %d for %s, got %d", tt.expected, tt.table.TableName(), version)
+		}
+	}
+}
+
+func TestDB_MigrateSchema(t *testing.T) {
+	dbEnv := testlibDB.SetupDBEnv(t)
+	db := DB{DbMap: dbEnv.DbMap}
+	defer dbEnv.Close()
+
+	if err := db.CreateTable(db.AddTable(TableSchemaVersion{})); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if migrated, err := db.MigrateSchema(VersionedMockTable{}); err != nil || migrated {
+		t.Fatalf("expected missing table not to be migrated, got %v, %v", migrated, err)
+	}
+
+	// The table was last written with an older schema version.
+	resetSchemaChecked()
+	if err := db.CreateTable(db.AddTable(VersionedMockTable{})); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := db.Insert(&VersionedMockTable{ID: 1}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := db.Insert(&TableSchemaVersion{Table: VersionedMockTable{}.TableName(), SchemaVersion: 2}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	migrated, err := db.MigrateSchema(VersionedMockTable{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !migrated {
+		t.Fatal("expected table with outdated schema to be recreated")
+	}
+	if n, err := db.SelectInt("SELECT COUNT(*) FROM " + VersionedMockTable{}.TableName()); err != nil || n != 0 {
+		t.Errorf("expected recreated table to be empty, got %d rows, %v", n, err)
+	}
+	if _, err := db.GetSchemaVersion(VersionedMockTable{}); err == nil {
+		t.Error("expected outdated schema version to be removed")
+	}
+
+	// Tables are only checked once per process.
+	if err := db.Insert(&VersionedMockTable{ID: 1}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if migrated, err := db.MigrateSchema(VersionedMockTable{}); err != nil || migrated {
+		t.Errorf("expected table to be checked only once, got %v, %v", migrated, err)
+	}
+
+	// Tables written with the current schema version are kept.
+	resetSchemaChecked()
+	if err := ReplaceAll(db, VersionedMockTable{ID: 1}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	resetSchemaChecked()
+	if migrated, err := db.MigrateSchema(VersionedMockTable{}); err != nil || migrated {
+		t.Errorf("expected current table not to be migrated, got %v, %v", migrated, err)
+	}
+
+	// Tables written with a newer schema version, e.g. before a rollback, are kept.
+	resetSchemaChecked()
+	if _, err := db.Exec("UPDATE "+TableSchemaVersion{}.TableName()+" SET schema_version = 4 WHERE table_name = :table_name",
+		map[string]any{"table_name": VersionedMockTable{}.TableName()}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if migrated, err := db.MigrateSchema(VersionedMockTable{}); err != nil || migrated {
+		t.Errorf("expected newer table not to be migrated, got %v, %v", migrated, err)
+	}
+	if n, err := db.SelectInt("SELECT COUNT(*) FROM " + VersionedMockTable{}.TableName()); err != nil || n != 1 {
+		t.Errorf("expected newer table to keep its rows, got %d rows, %v", n, err)
+	}
+}
+
+// Forget which tables had their schema version checked by this process.
+func resetSchemaChecked() {
+	schemaCheckedMu.Lock()
+	defer schemaCheckedMu.Unlock()
+	schemaChecked = make(map[string]bool)
+}
+
+func TestBulkInsertWithBatchSize(t *testing.T) {
+	dbEnv := testlibDB.SetupDBEnv(t)
+	db := DB{DbMap: dbEnv.DbMap}
+	defer dbEnv.Close()
+
+	if err := db.CreateTable(db.AddTable(BulkMockTable{})); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	records := make([]BulkMockTable, 10)
+	for i := range records {
+		records[i] = BulkMockTable{A: i, B: "test"}
+	}
+	// A batch size not dividing the number of records leaves a partial batch.
+	if err := BulkInsertWithBatchSize(db, db, 3, records...); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	var count int
+	if err := db.SelectOne(&count, "SELECT COUNT(*) FROM bulk_mock_table"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if count != len(records) {
+		t.Errorf("expected %d records, got %d", len(records), count)
+	}
+
+	if err := BulkInsertWithBatchSize(db, db, 0, records...); err == nil {
+		t.Error("expected error for invalid batch size, got nil")
+	}
+}
+
+func TestClampBatchSize(t *testing.T) {
+	dbEnv := testlibDB.SetupDBEnv(t)
+	db := DB{DbMap: dbEnv.DbMap}
+	defer dbEnv.Close()
+
+	// The mock table has two columns, so at most 32767 rows fit a statement.
+	table := db.AddTable(MockTable{})
+	if got := clampBatchSize(table, 100); got != 100 {
+		t.Errorf("expected batch size 100 to be kept, got %d", got)
+	}
+	if got := clampBatchSize(table, 100000); got != maxBindParameters/2 {
+		t.Errorf("expected batch size to be clamped to %d, got %d", maxBindParameters/2, got)
+	}
+}
+
+func TestBulkInsertBatchSize(t *testing.T) {
+	defer ConfigureBulkInsert(BulkInsertConfig{})
+
+	if got := BulkInsertBatchSize(); got != DefaultBulkInsertBatchSize {
+		t.Errorf("expected default batch size %d, got %d", DefaultBulkInsertBatchSize, got)
+	}
+	ConfigureBulkInsert(BulkInsertConfig{DBBulkInsertBatchSize: 2})
+	if got := BulkInsertBatchSize(); got != 2 {
+		t.Errorf("expected configured batch size 2, got %d", got)
+	}
+
+	// ReplaceAll writes the objects in batches of the configured size.
+	dbEnv := testlibDB.SetupDBEnv(t)
+	db := DB{DbMap: dbEnv.DbMap}
+	defer dbEnv.Close()
+	if err := db.CreateTable(db.AddTable(BulkMockTable{})); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	records := make([]BulkMockTable, 5)
+	for i := range records {
+		records[i] = BulkMockTable{A: i, B: "test"}
+	}
+	if err := ReplaceAll(db, records...); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	var count int
+	if err := db.SelectOne(&count, "SELECT COUNT(*) FROM bulk_mock_table"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if count != len(records) {
+		t.Errorf("expected %d records, got %d", len(records), count)
+	}
+}
+
+// Compare inserting rows one by one against inserting them in batches.
+// Run with: go test -run=^$ -bench=Insert ./internal/knowledge/db/
+func BenchmarkInsert(b *testing.B) {
+	const n = 10_000
+	records := make([]BulkMockTable, n)
+	for i := range records {
+		records[i] = BulkMockTable{A: i, B: "test"}
+	}
+
+	setup := func(b *testing.B) DB {
+		dbEnv := testlibDB.SetupDBEnv(b)
+		b.Cleanup(dbEnv.Close)
+		dbEnv.TraceOff()
+		db := DB{DbMap: dbEnv.DbMap}
+		if err := db.CreateTable(db.AddTable(BulkMockTable{})); err != nil {
+			b.Fatalf("expected no error, got %v", err)
+		}
+		return db
+	}
+	reset := func(b *testing.B, db DB) {
+		b.StopTimer()
+		if _, err := db.Exec("DELETE FROM bulk_mock_table"); err != nil {
+			b.Fatalf("expected no error, got %v", err)
+		}
+		b.StartTimer()
+	}
+
+	b.Run("SingleRow", func(b *testing.B) {
+		db := setup(b)
+		for b.Loop() {
+			reset(b, db)
+			tx, err := db.Begin()
+			if err != nil {
+				b.Fatalf("expected no error, got %v", err)
+			}
+			for i := range records {
+				if err := tx.Insert(&records[i]); err != nil {
+					b.Fatalf("expected no error, got %v", err)
+				}
+			}
+			if err := tx.Commit(); err != nil {
+				b.Fatalf("expected no error, got %v", err)
+			}
+		}
+	})
+	b.Run("Batched", func(b *testing.B) {
+		db := setup(b)
+		for b.Loop() {
+			reset(b, db)
+			tx, err := db.Begin()
+			if err != nil {
+				b.Fatalf("expected no error, got %v", err)
+			}
+			if err := BulkInsert(tx, db, records...); err != nil {
+				b.Fatalf("expected no error, got %v", err)
+			}
+			if err := tx.Commit(); err != nil {
+				b.Fatalf("expected no error, got %v", err)
+			}
+		}
+	})
+}
+
+func TestUpsert(t *testing.T) {
+	dbEnv := testlibDB.SetupDBEnv(t)
+	db := DB{DbMap: dbEnv.DbMap}
+	defer dbEnv.Close()
+
+	if err := db.CreateTable(db.AddTable(BulkMockTable{})); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := BulkInsert(db, db, BulkMockTable{A: 1, B: "old"}, BulkMockTable{A: 2, B: "old"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// Existing rows are updated in place, new rows are inserted.
+	if err := Upsert(db, db, []string{"a"}, BulkMockTable{A: 2, B: "new"}, BulkMockTable{A: 3, B: "new"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	var records []BulkMockTable
+	if _, err := db.Select(&records, "SELECT * FROM bulk_mock_table ORDER BY a"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	expected := map[int]string{1: "old", 2: "new", 3: "new"}
+	if len(records) != len(expected) {
+		t.Fatalf("expected %d records, got %d", len(expected), len(records))
+	}
+	for _, record := range records {
+		if record.B != expected[record.A] {
+			t.Errorf("record %d: expected B %s, got %s", record.A, expected[record.A], record.B)
+		}
+	}
+
+	if err := Upsert(db, db, nil, BulkMockTable{A: 1}); err == nil {
+		t.Error("expected error without conflict columns, got nil")
+	}
+	if err := Upsert(db, db, []string{"unknown"}, BulkMockTable{A: 1}); err == nil {
+		t.Error("expected error for unknown conflict column, got nil")
+	}
+}
+
+func TestDB_Reader(t *testing.T) {
+	primary := &DB{}
+	if primary.Reader() != primary {
+		t.Error("expected primary as reader without read replica")
+	}
+	replica := &DB{}
+	primary.reader = replica
+	if primary.Reader() != replica {
+		t.Error("expected read replica as reader")
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright SAP SE
+// SPDX-License-Identifier: Apache-2.0
+
+package db
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Configuration for the periodic liveness check of the database connections.
+type LivenessConfig struct {
+	// Interval between two liveness checks of all open database connections.
+	// Defaults to 30s.
+	DBLivenessCheckInterval metav1.Duration `json:"dbLivenessCheckInterval,omitempty"`
+}
+
+// Get the configured liveness check interval or the default.
+func (c LivenessConfig) Interval() time.Duration {
+	if c.DBLivenessCheckInterval.Duration <= 0 {
+		return 30 * time.Second
+	}
+	return c.DBLivenessCheckInterval.Duration
+}
+
+// Consecutive failed liveness checks after which failures are logged as errors.
+const livenessFailuresUntilError = 3
+
+// Result of the last liveness check of a database connection.
+type LivenessCheck struct {
+	// Whether the database responded to the last check.
+	Alive bool
+	// Error of the last check, if it failed.
+	Err error
+	// When the last check happened, zero if the database was never checked.
+	CheckedAt time.Time
+}
+
+// Liveness state shared by all copies of a database connection.
+type liveness struct {
+	mu                  sync.Mutex
+	last                LivenessCheck
+	consecutiveFailures int
+}
+
+// Get the result of the last liveness check of this database.
+func (d *DB) LastLivenessCheck() LivenessCheck {
+	if d.liveness == nil {
+		return LivenessCheck{}
+	}
+	d.liveness.mu.Lock()
+	defer d.liveness.mu.Unlock()
+	return d.liveness.last
+}
+
+// Ping the database and record the result.
+func (d *DB) checkLiveness(ctx context.Context, timeout time.Duration) {
+	if d.liveness == nil {
+		return
+	}
+	pingCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	err := d.Db.PingContext(pingCtx)
+
+	d.liveness.mu.Lock()
+	defer d.liveness.mu.Unlock()
+	d.liveness.last = LivenessCheck{Alive: err == nil, Err: err, CheckedAt: time.Now()}
+	gauge := Monitor.alive.WithLabelValues(d.host, d.databaseName)
+	if err == nil {
+		gauge.Set(1)
+		if d.liveness.consecutiveFailures > 0 {
+			slog.Info("database is alive again", "host", d.host, "database", d.databaseName,
+				"failures", d.liveness.consecutiveFailures)
+		}
+		d.liveness.consecutiveFailures = 0
+		return
+	}
+	gauge.Set(0)
+	d.liveness.consecutiveFailures++
+	if d.liveness.consecutiveFailures >= livenessFailuresUntilError {
+		slog.Error("database liveness check failed", "host", d.host, "database", d.databaseName,
+			"failures", d.liveness.consecutiveFailures, "error", err)
+		return
+	}
+	slog.Warn("database liveness check failed", "host", d.host, "database", d.databaseName,
+		"failures", d.liveness.consecutiveFailures, "error", err)
+}
+
+// Check the liveness of all open database connections in the given interval
+// until the context is done.
+func CheckLivenessPeriodically(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			connections.Range(func(_, value any) bool {
+				value.(*DB).checkLiveness(ctx, interval)
+				return true
+			})
+		}
+	}
+}
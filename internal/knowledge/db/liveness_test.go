@@ -0,0 +1,62 @@
+// Copyright SAP SE
+// SPDX-License-Identifier: Apache-2.0
+
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	testlibDB "github.com/cobaltcore-dev/cortex/internal/knowledge/db/testing"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestLivenessConfig_Interval(t *testing.T) {
+	if got := (LivenessConfig{}).Interval(); got != 30*time.Second {
+		t.Errorf("expected default interval of 30s, got %s", got)
+	}
+	conf := LivenessConfig{DBLivenessCheckInterval: metav1.Duration{Duration: time.Minute}}
+	if got := conf.Interval(); got != time.Minute {
+		t.Errorf("expected configured interval of 1m, got %s", got)
+	}
+}
+
+func TestDB_checkLiveness(t *testing.T) {
+	dbEnv := testlibDB.SetupDBEnv(t)
+	defer dbEnv.Close()
+	db := DB{DbMap: dbEnv.DbMap, liveness: &liveness{}}
+
+	if check := db.LastLivenessCheck(); !check.CheckedAt.IsZero() {
+		t.Fatalf("expected no check yet, got %+v", check)
+	}
+
+	db.checkLiveness(context.Background(), time.Second)
+	check := db.LastLivenessCheck()
+	if !check.Alive || check.Err != nil || check.CheckedAt.IsZero() {
+		t.Errorf("expected alive database, got %+v", check)
+	}
+
+	// A closed database fails the liveness check.
+	if err := dbEnv.Db.Close(); err != nil {
+		t.Fatalf("failed to close database: %v", err)
+	}
+	for range livenessFailuresUntilError {
+		db.checkLiveness(context.Background(), time.Second)
+	}
+	check = db.LastLivenessCheck()
+	if check.Alive || check.Err == nil {
+		t.Errorf("expected failed check, got %+v", check)
+	}
+	if db.liveness.consecutiveFailures != livenessFailuresUntilError {
+		t.Errorf("expected %d consecutive failures, got %d",
+			livenessFailuresUntilError, db.liveness.consecutiveFailures)
+	}
+}
+
+func TestDB_LastLivenessCheck_WithoutState(t *testing.T) {
+	db := DB{}
+	if check := db.LastLivenessCheck(); check.Alive || !check.CheckedAt.IsZero() {
+		t.Errorf("expected zero check, got %+v", check)
+	}
+}
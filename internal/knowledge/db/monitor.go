@@ -10,12 +10,15 @@ import (
 type monitor struct {
 	connectionAttempts *prometheus.CounterVec
 	selectTimer        *prometheus.HistogramVec
+	alive              *prometheus.GaugeVec
 
 	// See the sqlstats package for reference: https://github.com/dlmiddlecote/sqlstats
 	maxOpenDesc *prometheus.Desc
 	openDesc    *prometheus.Desc
 	inUseDesc   *prometheus.Desc
 	idleDesc    *prometheus.Desc
+	waitCount   *prometheus.Desc
+	waitTime    *prometheus.Desc
 }
 
 func newMonitor() monitor {
@@ -33,6 +36,11 @@ func newMonitor() monitor {
 			Buckets: prometheus.DefBuckets,
 		}, []string{"group", "query"}),
 
+		alive: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: prometheus.BuildFQName(namespace, subsystem, "alive"),
+			Help: "Whether the last liveness check of the database succeeded (1) or failed (0)",
+		}, []string{"host", "database"}),
+
 		maxOpenDesc: prometheus.NewDesc(
 			prometheus.BuildFQName(namespace, subsystem, "connections_max_open"),
 			"Maximum number of open connections to the database.",
@@ -57,22 +65,38 @@ func newMonitor() monitor {
 			[]string{"host", "database"},
 			nil,
 		),
+		waitCount: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subsystem, "connections_wait_count_total"),
+			"The total number of connections waited for.",
+			[]string{"host", "database"},
+			nil,
+		),
+		waitTime: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subsystem, "connections_wait_seconds_total"),
+			"The total time blocked waiting for a new connection.",
+			[]string{"host", "database"},
+			nil,
+		),
 	}
 }
 
 func (m *monitor) Describe(ch chan<- *prometheus.Desc) {
 	m.connectionAttempts.Describe(ch)
 	m.selectTimer.Describe(ch)
+	m.alive.Describe(ch)
 
 	ch <- m.maxOpenDesc
 	ch <- m.openDesc
 	ch <- m.inUseDesc
 	ch <- m.idleDesc
+	ch <- m.waitCount
+	ch <- m.waitTime
 }
 
 func (m *monitor) Collect(ch chan<- prometheus.Metric) {
 	m.connectionAttempts.Collect(ch)
 	m.selectTimer.Collect(ch)
+	m.alive.Collect(ch)
 
 	connections.Range(func(key, value any) bool {
 		db := value.(*DB)
@@ -103,6 +127,18 @@ func (m *monitor) Collect(ch chan<- prometheus.Metric) {
 			float64(stats.Idle),
 			host, database,
 		)
+		ch <- prometheus.MustNewConstMetric(
+			m.waitCount,
+			prometheus.CounterValue,
+			float64(stats.WaitCount),
+			host, database,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			m.waitTime,
+			prometheus.CounterValue,
+			stats.WaitDuration.Seconds(),
+			host, database,
+		)
 		return true
 	})
 }
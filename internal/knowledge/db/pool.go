@@ -0,0 +1,55 @@
+// Copyright SAP SE
+// SPDX-License-Identifier: Apache-2.0
+
+package db
+
+import (
+	"database/sql"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Connection pool settings applied to all database connections.
+type PoolConfig struct {
+	// Maximum number of open connections per database. Defaults to 16.
+	DBMaxOpenConns int `json:"dbMaxOpenConns,omitempty"`
+	// Maximum number of idle connections per database. Defaults to 2,
+	// the default of database/sql.
+	DBMaxIdleConns int `json:"dbMaxIdleConns,omitempty"`
+	// Maximum time a connection may be reused. Defaults to no limit.
+	DBConnMaxLifetime metav1.Duration `json:"dbConnMaxLifetime,omitempty"`
+}
+
+var (
+	poolConfigMu sync.Mutex
+	poolConfig   PoolConfig
+)
+
+// Set the pool settings for database connections opened from now on.
+// Should be called once on startup, before any connection is opened.
+func ConfigurePool(c PoolConfig) {
+	poolConfigMu.Lock()
+	defer poolConfigMu.Unlock()
+	poolConfig = c
+}
+
+// Apply the pool settings, falling back to the defaults, to the database.
+func (c PoolConfig) apply(db *sql.DB) {
+	maxOpenConns := 16
+	if c.DBMaxOpenConns > 0 {
+		maxOpenConns = c.DBMaxOpenConns
+	}
+	maxIdleConns := 2
+	if c.DBMaxIdleConns > 0 {
+		maxIdleConns = c.DBMaxIdleConns
+	}
+	var connMaxLifetime time.Duration
+	if c.DBConnMaxLifetime.Duration > 0 {
+		connMaxLifetime = c.DBConnMaxLifetime.Duration
+	}
+	db.SetMaxOpenConns(maxOpenConns)
+	db.SetMaxIdleConns(maxIdleConns)
+	db.SetConnMaxLifetime(connMaxLifetime)
+}
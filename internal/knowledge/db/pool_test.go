@@ -0,0 +1,45 @@
+// Copyright SAP SE
+// SPDX-License-Identifier: Apache-2.0
+
+package db
+
+import (
+	"testing"
+	"time"
+
+	testlibDB "github.com/cobaltcore-dev/cortex/internal/knowledge/db/testing"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPoolConfig_apply(t *testing.T) {
+	tests := []struct {
+		name            string
+		config          PoolConfig
+		expectedMaxOpen int
+	}{
+		{
+			name:            "defaults",
+			config:          PoolConfig{},
+			expectedMaxOpen: 16,
+		},
+		{
+			name: "configured",
+			config: PoolConfig{
+				DBMaxOpenConns:    32,
+				DBMaxIdleConns:    8,
+				DBConnMaxLifetime: metav1.Duration{Duration: time.Minute},
+			},
+			expectedMaxOpen: 32,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dbEnv := testlibDB.SetupDBEnv(t)
+			defer dbEnv.Close()
+			tt.config.apply(dbEnv.Db)
+			if got := dbEnv.Db.Stats().MaxOpenConnections; got != tt.expectedMaxOpen {
+				t.Errorf("expected %d max open connections, got %d", tt.expectedMaxOpen, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+// Copyright SAP SE
+// SPDX-License-Identifier: Apache-2.0
+
+package db
+
+import (
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/go-gorp/gorp"
+)
+
+// Schema version assumed for tables that don't declare one.
+const DefaultSchemaVersion = 1
+
+// Tables can implement this interface to declare the version of their
+// schema. The version should be bumped whenever columns are added, removed,
+// or change their meaning, so that consumers can detect the change.
+type VersionedTable interface {
+	Table
+	SchemaVersion() int
+}
+
+// Metadata row stamped by the syncers each time they write a table.
+type TableSchemaVersion struct {
+	// Name of the table the version belongs to.
+	Table string `db:"table_name,primarykey"`
+	// Schema version of the table at the time it was written.
+	SchemaVersion int `db:"schema_version"`
+	// When the table was last written.
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+func (TableSchemaVersion) TableName() string            { return "feature_schema_versions" }
+func (TableSchemaVersion) Indexes() map[string][]string { return nil }
+
+// Get the declared schema version of a table, or the default version.
+func SchemaVersionOf(t Table) int {
+	if v, ok := t.(VersionedTable); ok {
+		return v.SchemaVersion()
+	}
+	return DefaultSchemaVersion
+}
+
+// Stamp the schema version of the given table into the metadata table,
+// using an executor which can be a transaction or the database itself.
+//
+// Note: the metadata table must have been created beforehand.
+func StampSchemaVersion(executor gorp.SqlExecutor, t Table) error {
+	query := "DELETE FROM " + TableSchemaVersion{}.TableName() + " WHERE table_name = :table_name"
+	if _, err := executor.Exec(query, map[string]any{"table_name": t.TableName()}); err != nil {
+		return fmt.Errorf("failed to delete old schema version of %s: %w", t.TableName(), err)
+	}
+	return executor.Insert(&TableSchemaVersion{
+		Table:         t.TableName(),
+		SchemaVersion: SchemaVersionOf(t),
+		UpdatedAt:     time.Now(),
+	})
+}
+
+// Get the schema version stamped for the given table when it was last written.
+func (d *DB) GetSchemaVersion(t Table) (int, error) {
+	if !d.TableExists(TableSchemaVersion{}) {
+		return 0, fmt.Errorf("no schema version stamped for %s", t.TableName())
+	}
+	var stamped []TableSchemaVersion
+	query := "SELECT * FROM " + TableSchemaVersion{}.TableName() + " WHERE table_name = :table_name"
+	if _, err := d.Select(&stamped, query, map[string]any{"table_name": t.TableName()}); err != nil {
+		return 0, err
+	}
+	if len(stamped) == 0 {
+		return 0, fmt.Errorf("no schema version stamped for %s", t.TableName())
+	}
+	return stamped[0].SchemaVersion, nil
+}
+
+// Tables whose schema version was already checked by this process, so that
+// tables are migrated at most once per process.
+var (
+	schemaCheckedMu sync.Mutex
+	schemaChecked   = make(map[string]bool)
+)
+
+// Check if the schema version of the table was already checked.
+func isSchemaChecked(tableName string) bool {
+	schemaCheckedMu.Lock()
+	defer schemaCheckedMu.Unlock()
+	return schemaChecked[tableName]
+}
+
+// Remember that the schema version of the table was checked.
+func markSchemaChecked(tableName string) {
+	schemaCheckedMu.Lock()
+	defer schemaCheckedMu.Unlock()
+	schemaChecked[tableName] = true
+}
+
+// Recreate the given table in a transaction if it was last written with an
+// older schema version than the one it declares. This is done at most once
+// per process, so tables shared by several syncers are only recreated by the
+// first of them. Returns whether the table was recreated.
+//
+// Note: the metadata table must have been created beforehand.
+func (d *DB) MigrateSchema(t Table) (bool, error) {
+	// Hold the lock during the migration, so that concurrent syncers of
+	// the same table wait for it instead of writing to the old table.
+	schemaCheckedMu.Lock()
+	defer schemaCheckedMu.Unlock()
+	if schemaChecked[t.TableName()] {
+		return false, nil
+	}
+	tx, err := d.Begin()
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	migrated, err := migrateSchema(tx, *d, t)
+	if err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			slog.Error("failed to rollback transaction", "error", rbErr)
+		}
+		return false, err
+	}
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	schemaChecked[t.TableName()] = true
+	return migrated, nil
+}
+
+// Drop and recreate the given table using the executor, which can be a
+// transaction, if it was last written with an older schema version than the
+// one it declares. Tables written before their version was stamped count as
+// the default version. A newer stamp, e.g. written by a newer version during
+// a rollout or before a rollback, leaves the table untouched.
+func migrateSchema(executor gorp.SqlExecutor, d DB, t Table) (bool, error) {
+	if !d.TableExists(t) {
+		return false, nil
+	}
+	var stamped []TableSchemaVersion
+	query := "SELECT * FROM " + TableSchemaVersion{}.TableName() + " WHERE table_name = :table_name"
+	if _, err := executor.Select(&stamped, query, map[string]any{"table_name": t.TableName()}); err != nil {
+		return false, fmt.Errorf("failed to get schema version of %s: %w", t.TableName(), err)
+	}
+	version := DefaultSchemaVersion
+	if len(stamped) > 0 {
+		version = stamped[0].SchemaVersion
+	}
+	current := SchemaVersionOf(t)
+	if version > current {
+		slog.Warn("table was written with a newer schema, keeping it", "table", t.TableName(),
+			"stampedVersion", version, "schemaVersion", current)
+	}
+	if version >= current {
+		return false, nil
+	}
+	slog.Info("recreating table with outdated schema", "table", t.TableName(),
+		"stampedVersion", version, "schemaVersion", current)
+	if _, err := executor.Exec("DROP TABLE " + t.TableName()); err != nil {
+		return false, fmt.Errorf("failed to drop table %s: %w", t.TableName(), err)
+	}
+	if _, err := executor.Exec(d.AddTable(t).SqlForCreate(false)); err != nil {
+		return false, fmt.Errorf("failed to recreate table %s: %w", t.TableName(), err)
+	}
+	query = "DELETE FROM " + TableSchemaVersion{}.TableName() + " WHERE table_name = :table_name"
+	if _, err := executor.Exec(query, map[string]any{"table_name": t.TableName()}); err != nil {
+		return false, fmt.Errorf("failed to delete old schema version of %s: %w", t.TableName(), err)
+	}
+	return true, nil
+}
@@ -22,7 +22,7 @@ func (c PostgresContainer) GetPort() string {
 	return c.resource.GetPort("5432/tcp")
 }
 
-func (c *PostgresContainer) Init(t *testing.T) {
+func (c *PostgresContainer) Init(t testing.TB) {
 	pool, err := dockertest.NewPool("")
 	if err != nil {
 		log.Fatalf("could not construct pool: %s", err)
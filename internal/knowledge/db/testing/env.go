@@ -21,7 +21,7 @@ type DBEnv struct {
 	Close func()
 }
 
-func SetupDBEnv(t *testing.T) DBEnv {
+func SetupDBEnv(t testing.TB) DBEnv {
 	var env DBEnv
 	// To run tests faster, the default is running with sqlite.
 	if os.Getenv("POSTGRES_CONTAINER") == "1" {
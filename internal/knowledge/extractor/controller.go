@@ -6,12 +6,15 @@ package extractor
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"reflect"
+	"strings"
 	"time"
 
 	"github.com/cobaltcore-dev/cortex/api/v1alpha1"
 
 	"github.com/cobaltcore-dev/cortex/internal/knowledge/db"
+	"github.com/cobaltcore-dev/cortex/internal/knowledge/extractor/plugins"
 	"github.com/cobaltcore-dev/cortex/pkg/multicluster"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -39,6 +42,9 @@ type KnowledgeReconciler struct {
 	Monitor Monitor
 	// Config for the reconciler.
 	Conf KnowledgeReconcilerConfig
+	// Connect to the database behind the datasources, can be overridden
+	// for tests. Defaults to connecting through db.Connector.
+	connectDatabase func(ctx context.Context, ref corev1.SecretReference) (*db.DB, error)
 }
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
@@ -53,7 +59,16 @@ func (r *KnowledgeReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 	// Sanity checks.
 	lastExtracted := knowledge.Status.LastExtracted.Time
 	recency := knowledge.Spec.Recency.Duration
-	if lastExtracted.Add(recency).After(time.Now()) {
+	if _, ok := knowledge.Annotations[v1alpha1.AnnotationReextract]; ok {
+		// Consume the annotation so that the extraction is only forced once.
+		log.Info("forcing knowledge extraction, requested by annotation", "name", knowledge.Name)
+		old := knowledge.DeepCopy()
+		delete(knowledge.Annotations, v1alpha1.AnnotationReextract)
+		if err := r.Patch(ctx, knowledge, client.MergeFrom(old)); err != nil {
+			log.Error(err, "failed to remove re-extract annotation")
+			return ctrl.Result{}, err
+		}
+	} else if lastExtracted.Add(recency).After(time.Now()) {
 		waitFor := time.Until(lastExtracted.Add(recency))
 		log.Info("skipping knowledge extraction, not yet time", "name", knowledge.Name, "waitFor", waitFor)
 		return ctrl.Result{RequeueAfter: waitFor}, nil
@@ -77,8 +92,38 @@ func (r *KnowledgeReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		return ctrl.Result{}, nil
 	}
 
+	// Knowledges are extracted in dependency order. If a dependency isn't
+	// extracted yet, the trigger reconciler will enqueue this knowledge again
+	// once the dependency changes.
+	notReady, err := r.checkKnowledgeDependencies(ctx, *knowledge)
+	if err != nil {
+		log.Error(err, "failed to check knowledge dependencies", "name", knowledge.Name)
+		message := "failed to check knowledge dependencies: " + err.Error()
+		if errors.Is(err, errKnowledgeDependencyCycle) {
+			// Retrying won't resolve the cycle, the spec needs to be fixed.
+			if err := r.patchDependenciesNotReady(ctx, knowledge, "DependencyCycle", message, true); err != nil {
+				return ctrl.Result{}, err
+			}
+			return ctrl.Result{}, nil
+		}
+		if err := r.patchDependenciesNotReady(ctx, knowledge, "DependencyCheckFailed", message, false); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, err
+	}
+	if len(notReady) > 0 {
+		log.Info("skipping knowledge extraction, waiting for dependencies", "name", knowledge.Name, "dependencies", notReady)
+		message := "waiting for knowledge dependencies: " + strings.Join(notReady, ", ")
+		if err := r.patchDependenciesNotReady(ctx, knowledge, "DependenciesNotReady", message, false); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+
 	// Check if all datasources configured share the same database secret ref.
 	var databaseSecretRef *corev1.SecretReference
+	// The data behind the knowledge is only as recent as its oldest datasource.
+	var oldestDatasource *v1alpha1.Datasource
 	for _, dsRef := range knowledge.Spec.Dependencies.Datasources {
 		ds := &v1alpha1.Datasource{}
 		if err := r.Get(ctx, client.ObjectKey{
@@ -100,6 +145,9 @@ func (r *KnowledgeReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 			}
 			return ctrl.Result{}, err
 		}
+		if oldestDatasource == nil || ds.Status.LastSynced.Before(&oldestDatasource.Status.LastSynced) {
+			oldestDatasource = ds
+		}
 		if databaseSecretRef == nil {
 			databaseSecretRef = &ds.Spec.DatabaseSecretRef
 		} else if databaseSecretRef.Name != ds.Spec.DatabaseSecretRef.Name ||
@@ -123,9 +171,12 @@ func (r *KnowledgeReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 	// When we have datasources reading from a database, connect to it.
 	var authenticatedDatasourceDB *db.DB
 	if databaseSecretRef != nil {
+		connect := r.connectDatabase
+		if connect == nil {
+			connect = db.Connector{Client: r.Client}.FromSecretRef
+		}
 		var err error
-		authenticatedDatasourceDB, err = db.Connector{Client: r.Client}.
-			FromSecretRef(ctx, *databaseSecretRef)
+		authenticatedDatasourceDB, err = connect(ctx, *databaseSecretRef)
 		if err != nil {
 			log.Error(err, "failed to authenticate with database", "secretRef", *databaseSecretRef)
 			old := knowledge.DeepCopy()
@@ -163,7 +214,7 @@ func (r *KnowledgeReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		return ctrl.Result{}, err
 	}
 
-	features, err := extractor.Extract()
+	features, err := wrapped.Extract()
 	if err != nil {
 		log.Error(err, "failed to extract features", "name", knowledge.Spec.Extractor.Name)
 		old := knowledge.DeepCopy()
@@ -199,12 +250,34 @@ func (r *KnowledgeReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		}
 		return ctrl.Result{}, err
 	}
-	meta.SetStatusCondition(&knowledge.Status.Conditions, metav1.Condition{
-		Type:    v1alpha1.KnowledgeConditionReady,
-		Status:  metav1.ConditionTrue,
-		Reason:  "KnowledgeExtracted",
-		Message: "knowledge extracted successfully",
-	})
+	staleAt, expires := knowledgeStaleAt(*knowledge, oldestDatasource)
+	if expires && !staleAt.After(time.Now()) {
+		// Keep the extracted features, but don't let steps use them.
+		log.Info("extracted knowledge is stale", "name", knowledge.Name, "datasource", oldestDatasource.Name)
+		meta.SetStatusCondition(&knowledge.Status.Conditions, metav1.Condition{
+			Type:   v1alpha1.KnowledgeConditionReady,
+			Status: metav1.ConditionFalse,
+			Reason: "KnowledgeStale",
+			Message: "datasource " + oldestDatasource.Name + " was last synced at " +
+				oldestDatasource.Status.LastSynced.String() + ", longer ago than max age " + knowledge.Spec.MaxAge.Duration.String(),
+		})
+	} else {
+		meta.SetStatusCondition(&knowledge.Status.Conditions, metav1.Condition{
+			Type:    v1alpha1.KnowledgeConditionReady,
+			Status:  metav1.ConditionTrue,
+			Reason:  "KnowledgeExtracted",
+			Message: "knowledge extracted successfully",
+		})
+	}
+
+	if len(knowledge.Spec.Dependencies.Knowledges) > 0 {
+		meta.SetStatusCondition(&knowledge.Status.Conditions, metav1.Condition{
+			Type:    v1alpha1.KnowledgeConditionDependenciesReady,
+			Status:  metav1.ConditionTrue,
+			Reason:  "DependenciesReady",
+			Message: "all knowledge dependencies are ready",
+		})
+	}
 
 	// Check if content actually changed by comparing deserialized data structures.
 	// This avoids false positives from JSON serialization non-determinism (e.g., map key ordering).
@@ -221,6 +294,9 @@ func (r *KnowledgeReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 	knowledge.Status.Raw = raw
 	knowledge.Status.LastExtracted = metav1.NewTime(time.Now())
 	knowledge.Status.RawLength = len(features)
+	if versioned, ok := extractor.(plugins.VersionedExtractor); ok {
+		knowledge.Status.SchemaVersion = versioned.SchemaVersion()
+	}
 
 	if contentChanged {
 		log.Info("content of knowledge has changed", "name", knowledge.Name)
@@ -232,9 +308,53 @@ func (r *KnowledgeReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		return ctrl.Result{}, err
 	}
 	log.Info("successfully extracted knowledge", "name", knowledge.Name)
+	// Come back once the data gets stale, in case the datasource stops syncing.
+	if expires && staleAt.After(time.Now()) {
+		return ctrl.Result{RequeueAfter: time.Until(staleAt)}, nil
+	}
 	return ctrl.Result{}, nil
 }
 
+// Report that the knowledge dependencies are not ready in the status. A
+// knowledge which already holds extracted data keeps its previous ready
+// state, so that a transient dependency problem doesn't make the pipelines
+// using it unready. Only if the knowledge has no data yet, or the problem
+// can't resolve by itself (force), the knowledge is marked as not ready.
+func (r *KnowledgeReconciler) patchDependenciesNotReady(ctx context.Context, knowledge *v1alpha1.Knowledge, reason, message string, force bool) error {
+	log := logf.FromContext(ctx)
+	old := knowledge.DeepCopy()
+	meta.SetStatusCondition(&knowledge.Status.Conditions, metav1.Condition{
+		Type:    v1alpha1.KnowledgeConditionDependenciesReady,
+		Status:  metav1.ConditionFalse,
+		Reason:  reason,
+		Message: message,
+	})
+	if force || len(knowledge.Status.Raw.Raw) == 0 {
+		meta.SetStatusCondition(&knowledge.Status.Conditions, metav1.Condition{
+			Type:    v1alpha1.KnowledgeConditionReady,
+			Status:  metav1.ConditionFalse,
+			Reason:  reason,
+			Message: message,
+		})
+	}
+	patch := client.MergeFrom(old)
+	if err := r.Status().Patch(ctx, knowledge, patch); err != nil {
+		log.Error(err, "failed to patch knowledge status")
+		return err
+	}
+	return nil
+}
+
+// Get the time at which the knowledge becomes stale, which is when the data of
+// its oldest datasource exceeds the knowledge's max age. Returns false if the
+// knowledge has no max age or no datasources, i.e. never becomes stale.
+func knowledgeStaleAt(knowledge v1alpha1.Knowledge, oldestDatasource *v1alpha1.Datasource) (time.Time, bool) {
+	if knowledge.Spec.MaxAge == nil || oldestDatasource == nil {
+		return time.Time{}, false
+	}
+	return oldestDatasource.Status.LastSynced.Add(knowledge.Spec.MaxAge.Duration), true
+}
+
 func (r *KnowledgeReconciler) SetupWithManager(mgr manager.Manager, mcl *multicluster.Client) error {
 	bldr := multicluster.BuildController(mcl, mgr)
 	// Watch knowledge changes across all clusters.
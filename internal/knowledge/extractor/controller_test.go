@@ -10,6 +10,8 @@ import (
 	"time"
 
 	"github.com/cobaltcore-dev/cortex/api/v1alpha1"
+	"github.com/cobaltcore-dev/cortex/internal/knowledge/db"
+	"github.com/cobaltcore-dev/cortex/internal/knowledge/extractor/plugins"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -104,6 +106,65 @@ func TestKnowledgeReconciler_Reconcile_SkipRecentExtraction(t *testing.T) {
 	}
 }
 
+func TestKnowledgeReconciler_Reconcile_ReextractAnnotation(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	// Knowledge that was extracted recently, but re-extraction is requested.
+	knowledge := &v1alpha1.Knowledge{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "recent-knowledge",
+			Annotations: map[string]string{v1alpha1.AnnotationReextract: "true"},
+		},
+		Spec: v1alpha1.KnowledgeSpec{
+			SchedulingDomain: "test-operator",
+			Recency:          metav1.Duration{Duration: time.Hour},
+			Extractor: v1alpha1.KnowledgeExtractorSpec{
+				Name: "host_utilization_extractor",
+			},
+		},
+		Status: v1alpha1.KnowledgeStatus{
+			LastExtracted: metav1.NewTime(time.Now().Add(-30 * time.Second)),
+		},
+	}
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(knowledge).
+		WithStatusSubresource(&v1alpha1.Knowledge{}).
+		Build()
+	reconciler := &KnowledgeReconciler{
+		Client:  fakeClient,
+		Scheme:  scheme,
+		Monitor: NewMonitor(),
+		Conf:    KnowledgeReconcilerConfig{SchedulingDomain: "test-operator"},
+	}
+
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "recent-knowledge"}}
+	result, err := reconciler.Reconcile(ctx, req)
+	// The extractor fails without database, which shows that it was run.
+	if err == nil {
+		t.Error("Expected extraction error without database, got nil")
+	}
+	if result.RequeueAfter > 0 {
+		t.Errorf("Expected no requeue for recency, got %v", result.RequeueAfter)
+	}
+
+	var updated v1alpha1.Knowledge
+	if err := fakeClient.Get(ctx, req.NamespacedName, &updated); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := updated.Annotations[v1alpha1.AnnotationReextract]; ok {
+		t.Error("Expected re-extract annotation to be removed")
+	}
+	condition := meta.FindStatusCondition(updated.Status.Conditions, v1alpha1.KnowledgeConditionReady)
+	if condition == nil || condition.Reason != "FeatureExtractionFailed" {
+		t.Errorf("Expected extraction to be attempted, got condition %v", condition)
+	}
+}
+
 func TestKnowledgeReconciler_Reconcile_UnsupportedExtractor(t *testing.T) {
 	ctx := context.Background()
 	scheme := runtime.NewScheme()
@@ -440,6 +501,7 @@ func TestKnowledgeReconciler_Reconcile_SupportedExtractors(t *testing.T) {
 	supportedExtractors := []string{
 		"vrops_hostsystem_resolver",
 		"vrops_project_noisiness_extractor",
+		"vrops_host_noisiness_extractor",
 		"vrops_hostsystem_contention_long_term_extractor",
 		"vrops_hostsystem_contention_short_term_extractor",
 		"kvm_libvirt_domain_cpu_steal_pct_extractor",
@@ -451,6 +513,7 @@ func TestKnowledgeReconciler_Reconcile_SupportedExtractors(t *testing.T) {
 		"host_az_extractor",
 		"host_pinned_projects_extractor",
 		"sap_host_details_extractor",
+		"cooling_zone_load_extractor",
 	}
 
 	for _, extractorName := range supportedExtractors {
@@ -546,3 +609,140 @@ func TestKnowledgeReconciler_OperatorFiltering(t *testing.T) {
 		t.Error("Expected knowledge2 to fail predicate filter")
 	}
 }
+
+func TestKnowledgeStaleAt(t *testing.T) {
+	lastSynced := time.Now().Add(-time.Hour)
+	datasource := &v1alpha1.Datasource{
+		Status: v1alpha1.DatasourceStatus{LastSynced: metav1.NewTime(lastSynced)},
+	}
+	withMaxAge := v1alpha1.Knowledge{
+		Spec: v1alpha1.KnowledgeSpec{MaxAge: &metav1.Duration{Duration: 2 * time.Hour}},
+	}
+
+	staleAt, expires := knowledgeStaleAt(withMaxAge, datasource)
+	if !expires {
+		t.Fatal("expected knowledge with max age to expire")
+	}
+	if expected := lastSynced.Add(2 * time.Hour); !staleAt.Equal(expected) {
+		t.Errorf("expected knowledge to be stale at %s, got %s", expected, staleAt)
+	}
+	if _, expires := knowledgeStaleAt(withMaxAge, nil); expires {
+		t.Error("expected knowledge without datasources to never expire")
+	}
+	if _, expires := knowledgeStaleAt(v1alpha1.Knowledge{}, datasource); expires {
+		t.Error("expected knowledge without max age to never expire")
+	}
+}
+
+// Extractor returning fixed features without reading from a database.
+type staticFeatureExtractor struct{}
+
+func (staticFeatureExtractor) Init(*db.DB, client.Client, v1alpha1.KnowledgeSpec) error {
+	return nil
+}
+
+func (staticFeatureExtractor) Extract() ([]plugins.Feature, error) {
+	return []plugins.Feature{map[string]any{"host": "host-1"}}, nil
+}
+
+func TestKnowledgeReconciler_Reconcile_Staleness(t *testing.T) {
+	supportedExtractors["static_extractor"] = staticFeatureExtractor{}
+	defer delete(supportedExtractors, "static_extractor")
+
+	tests := []struct {
+		name            string
+		lastSynced      time.Duration
+		expectedStatus  metav1.ConditionStatus
+		expectedReason  string
+		expectedRequeue time.Duration
+	}{
+		{
+			name:       "stale datasource",
+			lastSynced: 2 * time.Hour,
+			// Stale knowledge is marked unready and waits for the datasource.
+			expectedStatus: metav1.ConditionFalse,
+			expectedReason: "KnowledgeStale",
+		},
+		{
+			name:           "recent datasource",
+			lastSynced:     10 * time.Minute,
+			expectedStatus: metav1.ConditionTrue,
+			expectedReason: "KnowledgeExtracted",
+			// Come back once the data exceeds the max age.
+			expectedRequeue: 50 * time.Minute,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			scheme := runtime.NewScheme()
+			if err := v1alpha1.AddToScheme(scheme); err != nil {
+				t.Fatal(err)
+			}
+			datasource := &v1alpha1.Datasource{
+				ObjectMeta: metav1.ObjectMeta{Name: "nova-servers"},
+				Spec: v1alpha1.DatasourceSpec{
+					DatabaseSecretRef: corev1.SecretReference{Name: "db-secret"},
+				},
+				Status: v1alpha1.DatasourceStatus{
+					LastSynced: metav1.NewTime(time.Now().Add(-tt.lastSynced)),
+				},
+			}
+			knowledge := &v1alpha1.Knowledge{
+				ObjectMeta: metav1.ObjectMeta{Name: "static"},
+				Spec: v1alpha1.KnowledgeSpec{
+					SchedulingDomain: "test-operator",
+					Recency:          metav1.Duration{Duration: time.Minute},
+					MaxAge:           &metav1.Duration{Duration: time.Hour},
+					Extractor:        v1alpha1.KnowledgeExtractorSpec{Name: "static_extractor"},
+				},
+			}
+			knowledge.Spec.Dependencies.Datasources = []corev1.ObjectReference{{Name: datasource.Name}}
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(knowledge, datasource).
+				WithStatusSubresource(&v1alpha1.Knowledge{}).
+				Build()
+			reconciler := &KnowledgeReconciler{
+				Client:  fakeClient,
+				Scheme:  scheme,
+				Monitor: NewMonitor(),
+				Conf:    KnowledgeReconcilerConfig{SchedulingDomain: "test-operator"},
+				connectDatabase: func(context.Context, corev1.SecretReference) (*db.DB, error) {
+					return &db.DB{}, nil
+				},
+			}
+
+			req := ctrl.Request{NamespacedName: types.NamespacedName{Name: knowledge.Name}}
+			result, err := reconciler.Reconcile(ctx, req)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if tt.expectedRequeue == 0 && result.RequeueAfter != 0 {
+				t.Errorf("expected no requeue, got %s", result.RequeueAfter)
+			}
+			if tt.expectedRequeue != 0 &&
+				(result.RequeueAfter <= tt.expectedRequeue-time.Minute || result.RequeueAfter > tt.expectedRequeue) {
+
+				t.Errorf("expected requeue after about %s, got %s", tt.expectedRequeue, result.RequeueAfter)
+			}
+
+			var updated v1alpha1.Knowledge
+			if err := fakeClient.Get(ctx, req.NamespacedName, &updated); err != nil {
+				t.Fatal(err)
+			}
+			condition := meta.FindStatusCondition(updated.Status.Conditions, v1alpha1.KnowledgeConditionReady)
+			if condition == nil {
+				t.Fatal("expected ready condition to be set")
+			}
+			if condition.Status != tt.expectedStatus || condition.Reason != tt.expectedReason {
+				t.Errorf("expected ready %s with reason %s, got %s with reason %s",
+					tt.expectedStatus, tt.expectedReason, condition.Status, condition.Reason)
+			}
+			// The extracted features are kept, even if they are stale.
+			if updated.Status.RawLength != 1 {
+				t.Errorf("expected 1 extracted feature, got %d", updated.Status.RawLength)
+			}
+		})
+	}
+}
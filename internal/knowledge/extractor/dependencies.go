@@ -0,0 +1,98 @@
+// Copyright SAP SE
+// SPDX-License-Identifier: Apache-2.0
+
+package extractor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/cobaltcore-dev/cortex/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/meta"
+)
+
+var errKnowledgeDependencyCycle = errors.New("knowledge dependency cycle")
+
+// Find a cycle in the knowledge dependency graph which is reachable from the
+// given knowledge. Knowledges are referenced by name, like the trigger
+// reconciler resolves them. Returns the names along the cycle, starting and
+// ending with the same knowledge, or nil if there is no cycle.
+func findKnowledgeDependencyCycle(knowledge v1alpha1.Knowledge, all []v1alpha1.Knowledge) []string {
+	byName := make(map[string]v1alpha1.Knowledge, len(all)+1)
+	for _, k := range all {
+		byName[k.Name] = k
+	}
+	byName[knowledge.Name] = knowledge
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[string]int, len(byName))
+	var path []string
+	var visit func(name string) []string
+	visit = func(name string) []string {
+		switch state[name] {
+		case visited:
+			return nil
+		case visiting:
+			// Cut the path to the part that forms the cycle.
+			for i, n := range path {
+				if n == name {
+					return append(append([]string{}, path[i:]...), name)
+				}
+			}
+		}
+		k, ok := byName[name]
+		if !ok {
+			// Missing dependencies can't form a cycle.
+			return nil
+		}
+		state[name] = visiting
+		path = append(path, name)
+		for _, ref := range k.Spec.Dependencies.Knowledges {
+			if cycle := visit(ref.Name); cycle != nil {
+				return cycle
+			}
+		}
+		path = path[:len(path)-1]
+		state[name] = visited
+		return nil
+	}
+	return visit(knowledge.Name)
+}
+
+// Check that the knowledge can be extracted in dependency order, i.e. that
+// its knowledge dependencies don't form a cycle and all of them were already
+// extracted. Returns the names of the dependencies that are not ready yet,
+// or an error wrapping errKnowledgeDependencyCycle if there is a cycle.
+func (r *KnowledgeReconciler) checkKnowledgeDependencies(ctx context.Context, knowledge v1alpha1.Knowledge) ([]string, error) {
+	if len(knowledge.Spec.Dependencies.Knowledges) == 0 {
+		return nil, nil
+	}
+	knowledgeList := &v1alpha1.KnowledgeList{}
+	if err := r.List(ctx, knowledgeList); err != nil {
+		return nil, err
+	}
+	if cycle := findKnowledgeDependencyCycle(knowledge, knowledgeList.Items); cycle != nil {
+		return nil, fmt.Errorf("%w: %s", errKnowledgeDependencyCycle, strings.Join(cycle, " -> "))
+	}
+	byName := make(map[string]v1alpha1.Knowledge, len(knowledgeList.Items))
+	for _, k := range knowledgeList.Items {
+		byName[k.Name] = k
+	}
+	var notReady []string
+	for _, ref := range knowledge.Spec.Dependencies.Knowledges {
+		dependency, ok := byName[ref.Name]
+		if !ok ||
+			dependency.Status.LastExtracted.IsZero() ||
+			!meta.IsStatusConditionTrue(dependency.Status.Conditions, v1alpha1.KnowledgeConditionReady) {
+
+			notReady = append(notReady, ref.Name)
+		}
+	}
+	return notReady, nil
+}
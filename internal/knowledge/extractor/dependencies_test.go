@@ -0,0 +1,211 @@
+// Copyright SAP SE
+// SPDX-License-Identifier: Apache-2.0
+
+package extractor
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cobaltcore-dev/cortex/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func knowledgeDependingOn(name string, dependencies ...string) v1alpha1.Knowledge {
+	k := v1alpha1.Knowledge{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: v1alpha1.KnowledgeSpec{
+			SchedulingDomain: "test-operator",
+			Recency:          metav1.Duration{Duration: time.Minute},
+			Extractor:        v1alpha1.KnowledgeExtractorSpec{Name: "host_utilization_extractor"},
+		},
+	}
+	for _, dependency := range dependencies {
+		k.Spec.Dependencies.Knowledges = append(k.Spec.Dependencies.Knowledges, corev1.ObjectReference{Name: dependency})
+	}
+	return k
+}
+
+func TestFindKnowledgeDependencyCycle(t *testing.T) {
+	tests := []struct {
+		name     string
+		root     string
+		all      []v1alpha1.Knowledge
+		expected []string
+	}{
+		{
+			name: "no dependencies",
+			root: "a",
+			all:  []v1alpha1.Knowledge{knowledgeDependingOn("a")},
+		},
+		{
+			name: "diamond without cycle",
+			root: "a",
+			all: []v1alpha1.Knowledge{
+				knowledgeDependingOn("a", "b", "c"),
+				knowledgeDependingOn("b", "d"),
+				knowledgeDependingOn("c", "d"),
+				knowledgeDependingOn("d"),
+			},
+		},
+		{
+			name: "missing dependency",
+			root: "a",
+			all:  []v1alpha1.Knowledge{knowledgeDependingOn("a", "missing")},
+		},
+		{
+			name:     "self dependency",
+			root:     "a",
+			all:      []v1alpha1.Knowledge{knowledgeDependingOn("a", "a")},
+			expected: []string{"a", "a"},
+		},
+		{
+			name: "indirect cycle",
+			root: "a",
+			all: []v1alpha1.Knowledge{
+				knowledgeDependingOn("a", "b"),
+				knowledgeDependingOn("b", "c"),
+				knowledgeDependingOn("c", "b"),
+			},
+			expected: []string{"b", "c", "b"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var root v1alpha1.Knowledge
+			for _, k := range tt.all {
+				if k.Name == tt.root {
+					root = k
+				}
+			}
+			cycle := findKnowledgeDependencyCycle(root, tt.all)
+			if !reflect.DeepEqual(cycle, tt.expected) {
+				t.Errorf("expected cycle %v, got %v", tt.expected, cycle)
+			}
+		})
+	}
+}
+
+func TestKnowledgeReconciler_Reconcile_Dependencies(t *testing.T) {
+	extracted := func(k v1alpha1.Knowledge) v1alpha1.Knowledge {
+		k.Status.LastExtracted = metav1.NewTime(time.Now().Add(-time.Hour))
+		k.Status.Conditions = []metav1.Condition{{
+			Type:               v1alpha1.KnowledgeConditionReady,
+			Status:             metav1.ConditionTrue,
+			Reason:             "KnowledgeExtracted",
+			LastTransitionTime: metav1.Now(),
+		}}
+		return k
+	}
+	withData := func(k v1alpha1.Knowledge) v1alpha1.Knowledge {
+		k = extracted(k)
+		k.Status.Raw = runtime.RawExtension{Raw: []byte(`{"features":[]}`)}
+		return k
+	}
+	tests := []struct {
+		name                       string
+		objects                    []v1alpha1.Knowledge
+		expectedReason             string
+		expectedMessage            string
+		expectedDependenciesReason string
+	}{
+		{
+			name: "dependency not yet extracted",
+			objects: []v1alpha1.Knowledge{
+				knowledgeDependingOn("derived", "raw"),
+				knowledgeDependingOn("raw"),
+			},
+			expectedReason:             "DependenciesNotReady",
+			expectedMessage:            "raw",
+			expectedDependenciesReason: "DependenciesNotReady",
+		},
+		{
+			name: "dependency unready after extraction",
+			objects: []v1alpha1.Knowledge{
+				withData(knowledgeDependingOn("derived", "raw")),
+				knowledgeDependingOn("raw"),
+			},
+			// The knowledge keeps serving its previous data.
+			expectedReason:             "KnowledgeExtracted",
+			expectedDependenciesReason: "DependenciesNotReady",
+		},
+		{
+			name: "dependency cycle",
+			objects: []v1alpha1.Knowledge{
+				knowledgeDependingOn("derived", "raw"),
+				extracted(knowledgeDependingOn("raw", "derived")),
+			},
+			expectedReason:             "DependencyCycle",
+			expectedMessage:            "derived -> raw -> derived",
+			expectedDependenciesReason: "DependencyCycle",
+		},
+		{
+			name: "dependency extracted",
+			objects: []v1alpha1.Knowledge{
+				knowledgeDependingOn("derived", "raw"),
+				extracted(knowledgeDependingOn("raw")),
+			},
+			// The extraction itself fails without database.
+			expectedReason: "FeatureExtractionFailed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			scheme := runtime.NewScheme()
+			if err := v1alpha1.AddToScheme(scheme); err != nil {
+				t.Fatal(err)
+			}
+			builder := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithStatusSubresource(&v1alpha1.Knowledge{})
+			for i := range tt.objects {
+				builder = builder.WithObjects(&tt.objects[i])
+			}
+			fakeClient := builder.Build()
+			reconciler := &KnowledgeReconciler{
+				Client:  fakeClient,
+				Scheme:  scheme,
+				Monitor: NewMonitor(),
+				Conf:    KnowledgeReconcilerConfig{SchedulingDomain: "test-operator"},
+			}
+
+			req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "derived"}}
+			reconciler.Reconcile(ctx, req) //nolint:errcheck
+
+			var updated v1alpha1.Knowledge
+			if err := fakeClient.Get(ctx, req.NamespacedName, &updated); err != nil {
+				t.Fatal(err)
+			}
+			condition := meta.FindStatusCondition(updated.Status.Conditions, v1alpha1.KnowledgeConditionReady)
+			if condition == nil {
+				t.Fatal("expected ready condition to be set")
+			}
+			if condition.Reason != tt.expectedReason {
+				t.Errorf("expected reason %q, got %q", tt.expectedReason, condition.Reason)
+			}
+			if !strings.Contains(condition.Message, tt.expectedMessage) {
+				t.Errorf("expected message to contain %q, got %q", tt.expectedMessage, condition.Message)
+			}
+			if tt.expectedDependenciesReason == "" {
+				return
+			}
+			dependencies := meta.FindStatusCondition(updated.Status.Conditions, v1alpha1.KnowledgeConditionDependenciesReady)
+			if dependencies == nil || dependencies.Status != metav1.ConditionFalse {
+				t.Fatalf("expected dependencies ready condition to be false, got %v", dependencies)
+			}
+			if dependencies.Reason != tt.expectedDependenciesReason {
+				t.Errorf("expected dependencies reason %q, got %q", tt.expectedDependenciesReason, dependencies.Reason)
+			}
+		})
+	}
+}
@@ -21,6 +21,8 @@ type Monitor struct {
 	stepFeatureCounter *prometheus.GaugeVec
 	// A counter to measure how many steps are skipped.
 	stepSkipCounter *prometheus.CounterVec
+	// A gauge with the unix time of the last successful run of each step.
+	stepLastSuccess *prometheus.GaugeVec
 }
 
 func NewMonitor() Monitor {
@@ -38,6 +40,10 @@ func NewMonitor() Monitor {
 			Name: "cortex_feature_pipeline_step_skipped",
 			Help: "Number of times a feature pipeline step was skipped",
 		}, []string{"step"}),
+		stepLastSuccess: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "cortex_feature_pipeline_step_last_success_timestamp_seconds",
+			Help: "Unix time of the last successful run of a feature pipeline step",
+		}, []string{"step"}),
 	}
 }
 
@@ -45,12 +51,14 @@ func (m *Monitor) Describe(ch chan<- *prometheus.Desc) {
 	m.stepRunTimer.Describe(ch)
 	m.stepFeatureCounter.Describe(ch)
 	m.stepSkipCounter.Describe(ch)
+	m.stepLastSuccess.Describe(ch)
 }
 
 func (m *Monitor) Collect(ch chan<- prometheus.Metric) {
 	m.stepRunTimer.Collect(ch)
 	m.stepFeatureCounter.Collect(ch)
 	m.stepSkipCounter.Collect(ch)
+	m.stepLastSuccess.Collect(ch)
 }
 
 // Wrapper for a feature extraction step that monitors the step's execution.
@@ -63,6 +71,8 @@ type FeatureExtractorMonitor[F plugins.FeatureExtractor] struct {
 	runTimer prometheus.Observer
 	// A counter to measure how many features are extracted by the step.
 	featureCounter prometheus.Gauge
+	// A gauge with the unix time of the last successful run of the step.
+	lastSuccess prometheus.Gauge
 }
 
 // Initialize the wrapped feature extractor with the database and options.
@@ -84,12 +94,17 @@ func monitorFeatureExtractor[F plugins.FeatureExtractor](label string, f F, m Mo
 	if m.stepFeatureCounter != nil {
 		featureCounter = m.stepFeatureCounter.WithLabelValues(label)
 	}
+	var lastSuccess prometheus.Gauge
+	if m.stepLastSuccess != nil {
+		lastSuccess = m.stepLastSuccess.WithLabelValues(label)
+	}
 
 	return FeatureExtractorMonitor[F]{
 		FeatureExtractor: f,
 		label:            label,
 		runTimer:         runTimer,
 		featureCounter:   featureCounter,
+		lastSuccess:      lastSuccess,
 	}
 }
 
@@ -104,6 +119,9 @@ func (m FeatureExtractorMonitor[F]) Extract() ([]plugins.Feature, error) {
 	if m.featureCounter != nil {
 		m.featureCounter.Set(float64(len(features)))
 	}
+	if m.lastSuccess != nil {
+		m.lastSuccess.SetToCurrentTime()
+	}
 
 	return features, nil
 }
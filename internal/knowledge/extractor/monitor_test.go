@@ -45,6 +45,9 @@ func TestNewMonitor(t *testing.T) {
 	if monitor.stepSkipCounter == nil {
 		t.Error("stepSkipCounter should not be nil")
 	}
+	if monitor.stepLastSuccess == nil {
+		t.Error("stepLastSuccess should not be nil")
+	}
 }
 
 func TestMonitor_DescribeAndCollect(t *testing.T) {
@@ -143,6 +146,15 @@ func TestMonitorFeatureExtractor_Extract_Success(t *testing.T) {
 			t.Errorf("Expected feature count %d, got %f", len(expectedFeatures), metric.GetGauge().GetValue())
 		}
 	}
+
+	// Verify that the time of the successful run was recorded
+	metric := &dto.Metric{}
+	if err := wrappedExtractor.lastSuccess.Write(metric); err != nil {
+		t.Errorf("Failed to write metric: %v", err)
+	}
+	if metric.GetGauge().GetValue() == 0 {
+		t.Error("Expected last success timestamp to be set")
+	}
 }
 
 func TestMonitorFeatureExtractor_Extract_Error(t *testing.T) {
@@ -161,6 +173,15 @@ func TestMonitorFeatureExtractor_Extract_Error(t *testing.T) {
 	if features != nil {
 		t.Error("Expected nil features on error")
 	}
+
+	// Failed runs must not update the time of the last successful run
+	metric := &dto.Metric{}
+	if err := wrappedExtractor.lastSuccess.Write(metric); err != nil {
+		t.Errorf("Failed to write metric: %v", err)
+	}
+	if metric.GetGauge().GetValue() != 0 {
+		t.Errorf("Expected no last success timestamp, got %f", metric.GetGauge().GetValue())
+	}
 }
 
 func TestMonitorFeatureExtractor_Extract_EmptyFeatures(t *testing.T) {
@@ -63,3 +63,14 @@ func (e *BaseExtractor[Opts, F]) Extracted(fs []F) ([]Feature, error) {
 	slog.Info("features: extracted", "count", len(output))
 	return output, nil
 }
+
+// Get the schema version of the extracted features. Features can declare
+// their version by implementing SchemaVersion() int, otherwise the default
+// version is assumed.
+func (e *BaseExtractor[Opts, F]) SchemaVersion() int {
+	var f F
+	if v, ok := any(f).(interface{ SchemaVersion() int }); ok {
+		return v.SchemaVersion()
+	}
+	return db.DefaultSchemaVersion
+}
@@ -149,3 +149,20 @@ func TestBaseExtractor_ExtractSQL(t *testing.T) {
 		}
 	}
 }
+
+type VersionedMockFeature struct {
+	ID int `db:"id,primarykey"`
+}
+
+func (VersionedMockFeature) SchemaVersion() int { return 2 }
+
+func TestBaseExtractor_SchemaVersion(t *testing.T) {
+	unversioned := BaseExtractor[MockOptions, MockFeature]{}
+	if v := unversioned.SchemaVersion(); v != db.DefaultSchemaVersion {
+		t.Errorf("expected default schema version %d, got %d", db.DefaultSchemaVersion, v)
+	}
+	versioned := BaseExtractor[MockOptions, VersionedMockFeature]{}
+	if v := versioned.SchemaVersion(); v != 2 {
+		t.Errorf("expected schema version 2, got %d", v)
+	}
+}
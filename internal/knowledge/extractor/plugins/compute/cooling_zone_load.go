@@ -0,0 +1,37 @@
+// Copyright SAP SE
+// SPDX-License-Identifier: Apache-2.0
+
+package compute
+
+import (
+	_ "embed"
+
+	"github.com/cobaltcore-dev/cortex/internal/knowledge/extractor/plugins"
+)
+
+type CoolingZoneLoad struct {
+	// The compute host located in the cooling zone.
+	ComputeHost string `db:"compute_host"`
+	// The cooling zone of the compute host.
+	CoolingZone string `db:"cooling_zone"`
+	// Average load of the cooling zone relative to its cooling limit.
+	LoadPct float64 `db:"load_pct"`
+}
+
+// Extractor that extracts the thermal load of the cooling zone each compute
+// host is located in and stores it as a feature into the database.
+type CoolingZoneLoadExtractor struct {
+	// Common base for all extractors that provides standard functionality.
+	plugins.BaseExtractor[
+		struct{},        // No options passed through yaml config
+		CoolingZoneLoad, // Feature model
+	]
+}
+
+//go:embed cooling_zone_load.sql
+var coolingZoneLoadSQL string
+
+// Extract the cooling zone load of compute hosts.
+func (e *CoolingZoneLoadExtractor) Extract() ([]plugins.Feature, error) {
+	return e.ExtractSQL(coolingZoneLoadSQL)
+}
@@ -0,0 +1,7 @@
+SELECT
+    host AS compute_host,
+    cooling_zone,
+    AVG(value) AS load_pct
+FROM cooling_zone_metrics
+WHERE name = 'cooling_zone_load_pct'
+GROUP BY host, cooling_zone;
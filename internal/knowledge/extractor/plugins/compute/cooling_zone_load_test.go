@@ -0,0 +1,79 @@
+// Copyright SAP SE
+// SPDX-License-Identifier: Apache-2.0
+
+package compute
+
+import (
+	"testing"
+
+	"github.com/cobaltcore-dev/cortex/api/v1alpha1"
+	"github.com/cobaltcore-dev/cortex/internal/knowledge/datasources/plugins/prometheus"
+	"github.com/cobaltcore-dev/cortex/internal/knowledge/db"
+	testlibDB "github.com/cobaltcore-dev/cortex/internal/knowledge/db/testing"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestCoolingZoneLoadExtractor_Init(t *testing.T) {
+	extractor := &CoolingZoneLoadExtractor{}
+	config := v1alpha1.KnowledgeSpec{
+		Extractor: v1alpha1.KnowledgeExtractorSpec{
+			Name:   "cooling_zone_load_extractor",
+			Config: runtime.RawExtension{Raw: []byte(`{}`)},
+		},
+	}
+	if err := extractor.Init(nil, nil, config); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
+
+func TestCoolingZoneLoadExtractor_Extract(t *testing.T) {
+	dbEnv := testlibDB.SetupDBEnv(t)
+	testDB := db.DB{DbMap: dbEnv.DbMap}
+	defer dbEnv.Close()
+	// Create dependency tables
+	if err := testDB.CreateTable(
+		testDB.AddTable(prometheus.CoolingZoneMetric{}),
+	); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	metrics := []any{
+		&prometheus.CoolingZoneMetric{Host: "host1", CoolingZone: "zone-a", Name: "cooling_zone_load_pct", Value: 80},
+		&prometheus.CoolingZoneMetric{Host: "host1", CoolingZone: "zone-a", Name: "cooling_zone_load_pct", Value: 90},
+		&prometheus.CoolingZoneMetric{Host: "host2", CoolingZone: "zone-b", Name: "cooling_zone_load_pct", Value: 40},
+		// Other metrics should be ignored.
+		&prometheus.CoolingZoneMetric{Host: "host3", CoolingZone: "zone-b", Name: "cooling_zone_inlet_temp", Value: 25},
+	}
+	if err := testDB.Insert(metrics...); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	extractor := &CoolingZoneLoadExtractor{}
+	config := v1alpha1.KnowledgeSpec{
+		Extractor: v1alpha1.KnowledgeExtractorSpec{
+			Name:   "cooling_zone_load_extractor",
+			Config: runtime.RawExtension{Raw: []byte(`{}`)},
+		},
+	}
+	if err := extractor.Init(&testDB, nil, config); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	features, err := extractor.Extract()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(features) != 2 {
+		t.Fatalf("expected 2 rows, got %d", len(features))
+	}
+
+	expected := map[string]CoolingZoneLoad{
+		"host1": {ComputeHost: "host1", CoolingZone: "zone-a", LoadPct: 85}, // Avg of 80 and 90
+		"host2": {ComputeHost: "host2", CoolingZone: "zone-b", LoadPct: 40},
+	}
+	for _, f := range features {
+		s := f.(CoolingZoneLoad)
+		if expected[s.ComputeHost] != s {
+			t.Errorf("expected %v for host %s, got %v", expected[s.ComputeHost], s.ComputeHost, s)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+// Copyright SAP SE
+// SPDX-License-Identifier: Apache-2.0
+
+package compute
+
+import (
+	_ "embed"
+
+	"github.com/cobaltcore-dev/cortex/internal/knowledge/extractor/plugins"
+)
+
+// Feature that describes how noisy the VMs currently running
+// on a compute host are, in terms of their cpu demand.
+type VROpsHostNoisiness struct {
+	ComputeHost string  `db:"compute_host"`
+	AvgCPUOfVMs float64 `db:"avg_cpu_of_vms"`
+	MaxCPUOfVMs float64 `db:"max_cpu_of_vms"`
+}
+
+// Extractor that extracts the noisiness of the VMs on each compute host
+// and stores it as a feature into the database.
+type VROpsHostNoisinessExtractor struct {
+	// Common base for all extractors that provides standard functionality.
+	plugins.BaseExtractor[
+		struct{},           // No options passed through yaml config
+		VROpsHostNoisiness, // Feature model
+	]
+}
+
+//go:embed vrops_host_noisiness.sql
+var vropsHostNoisinessSQL string
+
+func (e *VROpsHostNoisinessExtractor) Extract() ([]plugins.Feature, error) {
+	return e.ExtractSQL(vropsHostNoisinessSQL)
+}
@@ -0,0 +1,23 @@
+-- Extract the noisiness of each compute host with the following steps:
+-- 1. Get the average cpu demand of each VM through the vROps metrics.
+-- 2. Find on which hosts the VMs are currently running through the
+-- OpenStack servers and hypervisors.
+-- 3. Store the average and maximum cpu demand of the VMs per host.
+-- This feature can then be used to draw new VMs away from hosts that
+-- currently run VMs known to cause high cpu usage.
+WITH vms_avg_cpu AS (
+    SELECT
+        m.instance_uuid,
+        AVG(m.value) AS avg_cpu
+    FROM vrops_vm_metrics m
+    WHERE m.name = 'vrops_virtualmachine_cpu_demand_ratio'
+    GROUP BY m.instance_uuid
+)
+SELECT
+    h.service_host AS compute_host,
+    AVG(v.avg_cpu) AS avg_cpu_of_vms,
+    MAX(v.avg_cpu) AS max_cpu_of_vms
+FROM openstack_servers_v4 s
+JOIN vms_avg_cpu v ON s.id = v.instance_uuid
+JOIN openstack_hypervisors h ON s.os_ext_srv_attr_hypervisor_hostname = h.hostname
+GROUP BY h.service_host;
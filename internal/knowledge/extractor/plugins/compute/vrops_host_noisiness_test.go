@@ -0,0 +1,105 @@
+// Copyright SAP SE
+// SPDX-License-Identifier: Apache-2.0
+
+package compute
+
+import (
+	"testing"
+
+	"github.com/cobaltcore-dev/cortex/api/v1alpha1"
+	"github.com/cobaltcore-dev/cortex/internal/knowledge/datasources/plugins/openstack/nova"
+	"github.com/cobaltcore-dev/cortex/internal/knowledge/datasources/plugins/prometheus"
+	"github.com/cobaltcore-dev/cortex/internal/knowledge/db"
+	testlibDB "github.com/cobaltcore-dev/cortex/internal/knowledge/db/testing"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestVROpsHostNoisinessExtractor_Init(t *testing.T) {
+	extractor := &VROpsHostNoisinessExtractor{}
+
+	config := v1alpha1.KnowledgeSpec{
+		Extractor: v1alpha1.KnowledgeExtractorSpec{
+			Name:   "vrops_host_noisiness_extractor",
+			Config: runtime.RawExtension{Raw: []byte(`{}`)},
+		},
+	}
+	if err := extractor.Init(nil, nil, config); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
+
+func TestVROpsHostNoisinessExtractor_Extract(t *testing.T) {
+	dbEnv := testlibDB.SetupDBEnv(t)
+	testDB := db.DB{DbMap: dbEnv.DbMap}
+	defer dbEnv.Close()
+	// Create dependency tables
+	if err := testDB.CreateTable(
+		testDB.AddTable(prometheus.VROpsVMMetric{}),
+		testDB.AddTable(nova.Server{}),
+		testDB.AddTable(nova.Hypervisor{}),
+	); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	vropsVMMetrics := []any{
+		&prometheus.VROpsVMMetric{Name: "vrops_virtualmachine_cpu_demand_ratio", Value: 10, InstanceUUID: "uuid1"},
+		&prometheus.VROpsVMMetric{Name: "vrops_virtualmachine_cpu_demand_ratio", Value: 30, InstanceUUID: "uuid1"},
+		&prometheus.VROpsVMMetric{Name: "vrops_virtualmachine_cpu_demand_ratio", Value: 80, InstanceUUID: "uuid2"},
+		&prometheus.VROpsVMMetric{Name: "vrops_virtualmachine_cpu_demand_ratio", Value: 40, InstanceUUID: "uuid3"},
+		// Other metrics should be ignored.
+		&prometheus.VROpsVMMetric{Name: "vrops_virtualmachine_memory_ratio", Value: 99, InstanceUUID: "uuid3"},
+	}
+	if err := testDB.Insert(vropsVMMetrics...); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	servers := []any{
+		&nova.Server{ID: "uuid1", OSEXTSRVATTRHypervisorHostname: "host1"},
+		&nova.Server{ID: "uuid2", OSEXTSRVATTRHypervisorHostname: "host1"},
+		&nova.Server{ID: "uuid3", OSEXTSRVATTRHypervisorHostname: "host2"},
+		// Servers without metrics should be ignored.
+		&nova.Server{ID: "uuid4", OSEXTSRVATTRHypervisorHostname: "host2"},
+	}
+	if err := testDB.Insert(servers...); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	hypervisors := []any{
+		&nova.Hypervisor{ID: "1", Hostname: "host1", ServiceHost: "service_host1"},
+		&nova.Hypervisor{ID: "2", Hostname: "host2", ServiceHost: "service_host2"},
+	}
+	if err := testDB.Insert(hypervisors...); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	extractor := &VROpsHostNoisinessExtractor{}
+
+	config := v1alpha1.KnowledgeSpec{
+		Extractor: v1alpha1.KnowledgeExtractorSpec{
+			Name:   "vrops_host_noisiness_extractor",
+			Config: runtime.RawExtension{Raw: []byte(`{}`)},
+		},
+	}
+
+	if err := extractor.Init(&testDB, nil, config); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	features, err := extractor.Extract()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expected := map[string]VROpsHostNoisiness{
+		"service_host1": {ComputeHost: "service_host1", AvgCPUOfVMs: 50, MaxCPUOfVMs: 80},
+		"service_host2": {ComputeHost: "service_host2", AvgCPUOfVMs: 40, MaxCPUOfVMs: 40},
+	}
+	if len(features) != len(expected) {
+		t.Fatalf("expected %d rows, got %d", len(expected), len(features))
+	}
+	for _, f := range features {
+		n := f.(VROpsHostNoisiness)
+		if e, ok := expected[n.ComputeHost]; !ok || e != n {
+			t.Errorf("unexpected row: %+v", n)
+		}
+	}
+}
@@ -18,3 +18,10 @@ type FeatureExtractor interface {
 }
 
 type Feature any
+
+// Extractors can implement this interface to report the schema version
+// of the features they extract.
+type VersionedExtractor interface {
+	// Get the schema version of the extracted features.
+	SchemaVersion() int
+}
@@ -0,0 +1,48 @@
+// Copyright SAP SE
+// SPDX-License-Identifier: Apache-2.0
+
+package storage
+
+import (
+	_ "embed"
+
+	"github.com/cobaltcore-dev/cortex/internal/knowledge/extractor/plugins"
+)
+
+// Feature that maps the capacity utilization of a storage pool.
+type StoragePoolUtilization struct {
+	// Name of the OpenStack storage pool.
+	StoragePoolName string `db:"storage_pool_name"`
+	// Total capacity of the storage pool in GB.
+	TotalCapacityGB float64 `db:"total_capacity_gb"`
+	// Free capacity of the storage pool in GB.
+	FreeCapacityGB float64 `db:"free_capacity_gb"`
+	// Percentage of the total capacity reserved by the backend.
+	ReservedPercentage int `db:"reserved_percentage"`
+	// Whether the storage pool is thin provisioned.
+	ThinProvisioning bool `db:"thin_provisioning"`
+	// Ratio by which a thin provisioned pool may be oversubscribed.
+	MaxOverSubscriptionRatio float64 `db:"max_over_subscription_ratio"`
+	// Capacity provisioned for the shares of the storage pool in GB.
+	ProvisionedCapacityGB float64 `db:"provisioned_capacity_gb"`
+}
+
+// Version 2 added the thin provisioning columns.
+func (StoragePoolUtilization) SchemaVersion() int { return 2 }
+
+// Extractor that extracts the capacity utilization of a storage pool.
+type StoragePoolUtilizationExtractor struct {
+	// Common base for all extractors that provides standard functionality.
+	plugins.BaseExtractor[
+		struct{},               // No options passed through yaml config
+		StoragePoolUtilization, // Feature model
+	]
+}
+
+//go:embed storage_pool_utilization.sql
+var storagePoolUtilizationQuery string
+
+// Extract the capacity utilization of a storage pool.
+func (e *StoragePoolUtilizationExtractor) Extract() ([]plugins.Feature, error) {
+	return e.ExtractSQL(storagePoolUtilizationQuery)
+}
@@ -0,0 +1,14 @@
+SELECT
+  osp.name AS storage_pool_name,
+  osp.capabilities_total_capacity_gb AS total_capacity_gb,
+  osp.capabilities_free_capacity_gb AS free_capacity_gb,
+  osp.capabilities_reserved_percentage AS reserved_percentage,
+  -- Reported as boolean or list of booleans, pools supporting it are thin.
+  COALESCE(osp.capabilities_thin_provisioning LIKE '%true%', FALSE) AS thin_provisioning,
+  osp.capabilities_max_over_subscription_ratio AS max_over_subscription_ratio,
+  -- Drivers not reporting the provisioned capacity count the used capacity.
+  COALESCE(
+    osp.capabilities_provisioned_capacity_gb,
+    osp.capabilities_total_capacity_gb - osp.capabilities_free_capacity_gb
+  ) AS provisioned_capacity_gb
+FROM openstack_manila_storage_pools osp;
@@ -0,0 +1,85 @@
+// Copyright SAP SE
+// SPDX-License-Identifier: Apache-2.0
+
+package storage
+
+import (
+	"testing"
+
+	"github.com/cobaltcore-dev/cortex/api/v1alpha1"
+	"github.com/cobaltcore-dev/cortex/internal/knowledge/datasources/plugins/openstack/manila"
+	"github.com/cobaltcore-dev/cortex/internal/knowledge/db"
+	testlibDB "github.com/cobaltcore-dev/cortex/internal/knowledge/db/testing"
+)
+
+func TestStoragePoolUtilizationExtractor_Init(t *testing.T) {
+	extractor := &StoragePoolUtilizationExtractor{}
+	config := v1alpha1.KnowledgeSpec{}
+	if err := extractor.Init(nil, nil, config); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
+
+func TestStoragePoolUtilizationExtractor_Extract(t *testing.T) {
+	dbEnv := testlibDB.SetupDBEnv(t)
+	testDB := db.DB{DbMap: dbEnv.DbMap}
+	defer dbEnv.Close()
+	if err := testDB.CreateTable(testDB.AddTable(manila.StoragePool{})); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	manilaStoragePools := []any{
+		&manila.StoragePool{
+			Name:                           "host1@backend1#pool1",
+			CapabilitiesTotalCapacityGB:    1000,
+			CapabilitiesFreeCapacityGB:     250.5,
+			CapabilitiesReservedPercentage: 10,
+		},
+		&manila.StoragePool{
+			Name:                        "host2@backend2#pool2",
+			CapabilitiesTotalCapacityGB: 500,
+			CapabilitiesFreeCapacityGB:  0,
+		},
+		&manila.StoragePool{
+			Name:                                 "host3@backend3#pool3",
+			CapabilitiesTotalCapacityGB:          100,
+			CapabilitiesFreeCapacityGB:           80,
+			CapabilitiesThinProvisioning:         new("[true, false]"),
+			CapabilitiesMaxOverSubscriptionRatio: 20,
+			CapabilitiesProvisionedCapacityGB:    new(300.0),
+		},
+	}
+	if err := testDB.Insert(manilaStoragePools...); err != nil {
+		t.Fatalf("failed to insert manila storage pools: %v", err)
+	}
+
+	extractor := &StoragePoolUtilizationExtractor{}
+	config := v1alpha1.KnowledgeSpec{}
+	if err := extractor.Init(&testDB, nil, config); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	features, err := extractor.Extract()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	utilizations := make(map[string]StoragePoolUtilization, len(features))
+	for _, f := range features {
+		u := f.(StoragePoolUtilization)
+		utilizations[u.StoragePoolName] = u
+	}
+
+	expected := []StoragePoolUtilization{
+		{StoragePoolName: "host1@backend1#pool1", TotalCapacityGB: 1000, FreeCapacityGB: 250.5, ReservedPercentage: 10, ProvisionedCapacityGB: 749.5},
+		{StoragePoolName: "host2@backend2#pool2", TotalCapacityGB: 500, FreeCapacityGB: 0, ReservedPercentage: 0, ProvisionedCapacityGB: 500},
+		{StoragePoolName: "host3@backend3#pool3", TotalCapacityGB: 100, FreeCapacityGB: 80, ThinProvisioning: true, MaxOverSubscriptionRatio: 20, ProvisionedCapacityGB: 300},
+	}
+	if len(utilizations) != len(expected) {
+		t.Errorf("expected %d rows, got %d", len(expected), len(utilizations))
+	}
+	for _, exp := range expected {
+		if got := utilizations[exp.StoragePoolName]; got != exp {
+			t.Errorf("expected %+v, got %+v", exp, got)
+		}
+	}
+}
@@ -13,6 +13,7 @@ import (
 var supportedExtractors = map[string]plugins.FeatureExtractor{
 	"vrops_hostsystem_resolver":                        &compute.VROpsHostsystemResolver{},
 	"vrops_project_noisiness_extractor":                &compute.VROpsProjectNoisinessExtractor{},
+	"vrops_host_noisiness_extractor":                   &compute.VROpsHostNoisinessExtractor{},
 	"vrops_hostsystem_contention_long_term_extractor":  &compute.VROpsHostsystemContentionLongTermExtractor{},
 	"vrops_hostsystem_contention_short_term_extractor": &compute.VROpsHostsystemContentionShortTermExtractor{},
 	"kvm_libvirt_domain_cpu_steal_pct_extractor":       &compute.LibvirtDomainCPUStealPctExtractor{},
@@ -24,6 +25,8 @@ var supportedExtractors = map[string]plugins.FeatureExtractor{
 	"host_pinned_projects_extractor":                   &compute.HostPinnedProjectsExtractor{},
 	"sap_host_details_extractor":                       &compute.HostDetailsExtractor{},
 	"flavor_groups":                                    &compute.FlavorGroupExtractor{},
+	"cooling_zone_load_extractor":                      &compute.CoolingZoneLoadExtractor{},
 
 	"netapp_storage_pool_cpu_usage_extractor": &storage.StoragePoolCPUUsageExtractor{},
+	"storage_pool_utilization_extractor":      &storage.StoragePoolUtilizationExtractor{},
 }
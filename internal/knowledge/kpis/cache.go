@@ -0,0 +1,98 @@
+// Copyright SAP SE
+// SPDX-License-Identifier: Apache-2.0
+
+package kpis
+
+import (
+	"sync"
+	"time"
+
+	"github.com/cobaltcore-dev/cortex/internal/knowledge/db"
+	"github.com/cobaltcore-dev/cortex/internal/knowledge/kpis/plugins"
+	"github.com/cobaltcore-dev/cortex/pkg/conf"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Wrapper around a kpi which is expensive to compute on every scrape.
+// The wrapped kpi is collected at most once per interval, and scrapes in
+// between are served the metrics of the last collection. If the data of
+// the kpi's dependencies changed, the next scrape recomputes the kpi
+// regardless of the interval.
+type cachedKPI struct {
+	// Wrapped kpi to execute.
+	kpi plugins.KPI
+	// Minimum time between two collections of the wrapped kpi.
+	interval time.Duration
+	// Function returning the current time, can be overridden for tests.
+	now func() time.Time
+
+	mu sync.Mutex
+	// Metrics of the last collection.
+	metrics []prometheus.Metric
+	// When the last collection happened.
+	collectedAt time.Time
+	// Version of the dependency data the cached metrics were computed from.
+	collectedVersion string
+	// Version of the dependency data as last observed by the controller.
+	dependencyVersion string
+}
+
+func newCachedKPI(kpi plugins.KPI, interval time.Duration) *cachedKPI {
+	return &cachedKPI{kpi: kpi, interval: interval, now: time.Now}
+}
+
+// Set the version of the dependency data, e.g. derived from the last
+// update timestamps of the kpi's datasources and knowledges. If it differs
+// from the version of the cached metrics, the next scrape recomputes them.
+func (c *cachedKPI) setDependencyVersion(version string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.dependencyVersion = version
+}
+
+func (c *cachedKPI) Describe(ch chan<- *prometheus.Desc) {
+	c.kpi.Describe(ch)
+}
+
+func (c *cachedKPI) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	stale := c.collectedAt.IsZero() ||
+		now.Sub(c.collectedAt) >= c.interval ||
+		c.collectedVersion != c.dependencyVersion
+	if stale {
+		// Buffer the metrics so that they can be served again later.
+		buf := make(chan prometheus.Metric)
+		done := make(chan struct{})
+		var metrics []prometheus.Metric
+		go func() {
+			defer close(done)
+			for m := range buf {
+				metrics = append(metrics, m)
+			}
+		}()
+		c.kpi.Collect(buf)
+		close(buf)
+		<-done
+		c.metrics = metrics
+		c.collectedAt = now
+		c.collectedVersion = c.dependencyVersion
+	}
+	for _, m := range c.metrics {
+		ch <- m
+	}
+}
+
+func (c *cachedKPI) Init(db *db.DB, client client.Client, opts conf.RawOpts) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.metrics = nil
+	c.collectedAt = time.Time{}
+	return c.kpi.Init(db, client, opts)
+}
+
+func (c *cachedKPI) GetName() string {
+	return c.kpi.GetName()
+}
@@ -0,0 +1,162 @@
+// Copyright SAP SE
+// SPDX-License-Identifier: Apache-2.0
+
+package kpis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cobaltcore-dev/cortex/api/v1alpha1"
+	"github.com/cobaltcore-dev/cortex/internal/knowledge/db"
+	"github.com/cobaltcore-dev/cortex/internal/knowledge/kpis/plugins"
+	"github.com/cobaltcore-dev/cortex/pkg/conf"
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// KPI counting how often it was collected.
+type countingKPI struct {
+	desc        *prometheus.Desc
+	collections int
+}
+
+func (k *countingKPI) Init(db *db.DB, client client.Client, opts conf.RawOpts) error {
+	k.desc = prometheus.NewDesc("cortex_test_counting_kpi", "Test kpi", nil, nil)
+	return nil
+}
+
+func (k *countingKPI) Collect(ch chan<- prometheus.Metric) {
+	k.collections++
+	ch <- prometheus.MustNewConstMetric(k.desc, prometheus.GaugeValue, float64(k.collections))
+}
+
+func (k *countingKPI) Describe(ch chan<- *prometheus.Desc) { ch <- k.desc }
+
+func (k *countingKPI) GetName() string { return "counting_kpi" }
+
+func collectCount(t *testing.T, kpi plugins.KPI) int {
+	t.Helper()
+	ch := make(chan prometheus.Metric, 10)
+	kpi.Collect(ch)
+	close(ch)
+	count := 0
+	for range ch {
+		count++
+	}
+	return count
+}
+
+func TestCachedKPI_Collect(t *testing.T) {
+	inner := &countingKPI{}
+	now := time.Now()
+	cached := newCachedKPI(inner, time.Minute)
+	cached.now = func() time.Time { return now }
+	if err := cached.Init(nil, nil, conf.NewRawOpts("{}")); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// The first scrape computes the kpi.
+	if count := collectCount(t, cached); count != 1 {
+		t.Fatalf("expected 1 metric, got %d", count)
+	}
+	if inner.collections != 1 {
+		t.Fatalf("expected 1 collection, got %d", inner.collections)
+	}
+
+	// Scrapes within the interval are served from the cache.
+	now = now.Add(30 * time.Second)
+	if count := collectCount(t, cached); count != 1 {
+		t.Fatalf("expected cached metric, got %d metrics", count)
+	}
+	if inner.collections != 1 {
+		t.Errorf("expected no recomputation within interval, got %d collections", inner.collections)
+	}
+
+	// Changed dependency data invalidates the cache.
+	cached.setDependencyVersion("v2")
+	collectCount(t, cached)
+	if inner.collections != 2 {
+		t.Errorf("expected recomputation after dependency change, got %d collections", inner.collections)
+	}
+
+	// After the interval, the kpi is recomputed.
+	now = now.Add(time.Minute)
+	collectCount(t, cached)
+	if inner.collections != 3 {
+		t.Errorf("expected recomputation after interval, got %d collections", inner.collections)
+	}
+}
+
+func TestController_handleKPIChange_CollectInterval(t *testing.T) {
+	testScheme := runtime.NewScheme()
+	if err := v1alpha1.AddToScheme(testScheme); err != nil {
+		t.Fatalf("Failed to add v1alpha1 scheme: %v", err)
+	}
+	knowledge := &v1alpha1.Knowledge{
+		ObjectMeta: metav1.ObjectMeta{Name: "kn", Namespace: "default"},
+		Status: v1alpha1.KnowledgeStatus{
+			RawLength: 1,
+			Conditions: []metav1.Condition{{
+				Type:   v1alpha1.KnowledgeConditionReady,
+				Status: metav1.ConditionTrue,
+				Reason: "Extracted",
+			}},
+		},
+	}
+	kpi := &v1alpha1.KPI{
+		ObjectMeta: metav1.ObjectMeta{Name: "cached-kpi"},
+		Spec: v1alpha1.KPISpec{
+			Impl:            "counting_kpi",
+			CollectInterval: &metav1.Duration{Duration: time.Hour},
+			Dependencies: v1alpha1.KPIDependenciesSpec{
+				Knowledges: []corev1.ObjectReference{{Name: "kn", Namespace: "default"}},
+			},
+		},
+	}
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(testScheme).
+		WithObjects(kpi, knowledge).
+		WithStatusSubresource(&v1alpha1.KPI{}, &v1alpha1.Knowledge{}).
+		Build()
+	inner := &countingKPI{}
+	controller := &Controller{
+		Client:                       fakeClient,
+		supportedKPIs:                map[string]plugins.KPI{"counting_kpi": inner},
+		registeredKPIsByResourceName: make(map[string]plugins.KPI),
+	}
+
+	if err := controller.handleKPIChange(context.Background(), kpi); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	registered, ok := controller.registeredKPIsByResourceName["cached-kpi"].(*cachedKPI)
+	if !ok {
+		t.Fatalf("expected kpi with collect interval to be cached, got %T",
+			controller.registeredKPIsByResourceName["cached-kpi"])
+	}
+	defer metrics.Registry.Unregister(registered)
+	collectCount(t, registered)
+	collectCount(t, registered)
+	if inner.collections != 1 {
+		t.Fatalf("expected 1 collection, got %d", inner.collections)
+	}
+
+	// New knowledge data invalidates the cached metrics.
+	knowledge.Status.RawLength = 2
+	if err := fakeClient.Status().Update(context.Background(), knowledge); err != nil {
+		t.Fatalf("failed to update knowledge: %v", err)
+	}
+	if err := controller.handleKPIChange(context.Background(), kpi); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	collectCount(t, registered)
+	if inner.collections != 2 {
+		t.Errorf("expected recomputation after knowledge change, got %d collections", inner.collections)
+	}
+}
@@ -5,8 +5,10 @@ package kpis
 
 import (
 	"context"
-	"errors"
 	"fmt"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/cobaltcore-dev/cortex/api/v1alpha1"
 	"github.com/cobaltcore-dev/cortex/internal/knowledge/db"
@@ -46,6 +48,8 @@ type Controller struct {
 	supportedKPIs map[string]plugins.KPI
 	// Registered kpis by name.
 	registeredKPIsByResourceName map[string]plugins.KPI
+	// Guards the registered kpis against concurrent reconciles and shutdown.
+	registeredKPIsMu sync.Mutex
 }
 
 // This loop will be called by the controller-runtime for each kpi
@@ -54,6 +58,9 @@ func (c *Controller) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	log := ctrl.LoggerFrom(ctx)
 	kpi := &v1alpha1.KPI{}
 
+	c.registeredKPIsMu.Lock()
+	defer c.registeredKPIsMu.Unlock()
+
 	if err := c.Get(ctx, req.NamespacedName, kpi); err != nil {
 		// Remove the kpi if it was deleted.
 		if client.IgnoreNotFound(err) != nil {
@@ -107,6 +114,8 @@ func (c *Controller) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 func (c *Controller) InitAllKPIs(ctx context.Context) error {
 	log := ctrl.LoggerFrom(ctx)
 	log.Info("initializing KPIs")
+	c.registeredKPIsMu.Lock()
+	defer c.registeredKPIsMu.Unlock()
 	c.registeredKPIsByResourceName = make(map[string]plugins.KPI)
 	// List all existing kpis and initialize them.
 	var kpis v1alpha1.KPIList
@@ -142,6 +151,22 @@ func (c *Controller) InitAllKPIs(ctx context.Context) error {
 	return nil
 }
 
+// Unregister all kpis once the given context is done, e.g. when the manager
+// shuts down, so that no kpi collector outlives the controller.
+// Database connections are shared across the process and not closed here.
+func (c *Controller) unregisterAllKPIsOnShutdown(ctx context.Context) error {
+	<-ctx.Done()
+	log := ctrl.LoggerFrom(ctx)
+	c.registeredKPIsMu.Lock()
+	defer c.registeredKPIsMu.Unlock()
+	for name, kpi := range c.registeredKPIsByResourceName {
+		metrics.Registry.Unregister(kpi)
+		log.Info("kpi: unregistered kpi on shutdown", "name", name)
+	}
+	clear(c.registeredKPIsByResourceName)
+	return nil
+}
+
 // Find a joint database connection for all given datasources.
 // The returned database can be nil if no database is needed.
 func (c *Controller) getJointDB(
@@ -150,6 +175,8 @@ func (c *Controller) getJointDB(
 ) (*db.DB, error) {
 	// Check if all datasources configured share the same database secret ref.
 	var databaseSecretRef *corev1.SecretReference
+	// The datasource the database secret ref was taken from.
+	var databaseSecretRefSource string
 	for _, dsRef := range datasources {
 		ds := &v1alpha1.Datasource{}
 		if err := c.Get(ctx, client.ObjectKey{
@@ -163,9 +190,15 @@ func (c *Controller) getJointDB(
 		}
 		if databaseSecretRef == nil {
 			databaseSecretRef = &ds.Spec.DatabaseSecretRef
+			databaseSecretRefSource = ds.Name
 		} else if databaseSecretRef.Name != ds.Spec.DatabaseSecretRef.Name ||
 			databaseSecretRef.Namespace != ds.Spec.DatabaseSecretRef.Namespace {
-			return nil, errors.New("datasources have different database secret refs")
+			return nil, fmt.Errorf(
+				"datasources have different database secret refs: "+
+					"datasource %s uses secret %s/%s but datasource %s uses secret %s/%s",
+				databaseSecretRefSource, databaseSecretRef.Namespace, databaseSecretRef.Name,
+				ds.Name, ds.Spec.DatabaseSecretRef.Namespace, ds.Spec.DatabaseSecretRef.Name,
+			)
 		}
 	}
 	// When we have datasources reading from a database, connect to it.
@@ -187,6 +220,8 @@ func (c *Controller) handleKPIChange(ctx context.Context, obj *v1alpha1.KPI) err
 
 	// Track if any datasource requires a database connection.
 	var datasourcesWithDB int
+	// Track the data versions of all dependencies, to invalidate cached metrics.
+	var dependencyVersions []string
 
 	// Get all the datasources this kpi depends on, if any.
 	var datasourcesReady int
@@ -211,6 +246,8 @@ func (c *Controller) handleKPIChange(ctx context.Context, obj *v1alpha1.KPI) err
 		if ds.Spec.DatabaseSecretRef.Name != "" {
 			datasourcesWithDB++
 		}
+		dependencyVersions = append(dependencyVersions, fmt.Sprintf("datasource/%s/%s@%s/%d",
+			ds.Namespace, ds.Name, ds.Status.LastSynced.UTC().Format(time.RFC3339Nano), ds.Status.NumberOfObjects))
 	}
 
 	// Get all knowledges this kpi depends on, if any.
@@ -231,6 +268,8 @@ func (c *Controller) handleKPIChange(ctx context.Context, obj *v1alpha1.KPI) err
 		if meta.IsStatusConditionTrue(kn.Status.Conditions, v1alpha1.KnowledgeConditionReady) {
 			knowledgesReady++
 		}
+		dependencyVersions = append(dependencyVersions, fmt.Sprintf("knowledge/%s/%s@%s/%d",
+			kn.Namespace, kn.Name, kn.Status.LastContentChange.UTC().Format(time.RFC3339Nano), kn.Status.RawLength))
 	}
 
 	dependenciesReadyTotal := datasourcesReady + knowledgesReady
@@ -248,6 +287,9 @@ func (c *Controller) handleKPIChange(ctx context.Context, obj *v1alpha1.KPI) err
 			return fmt.Errorf("kpi %s not supported", obj.Name)
 		}
 		registeredKPI = &kpilogger{kpi: registeredKPI}
+		if interval := obj.Spec.CollectInterval; interval != nil && interval.Duration > 0 {
+			registeredKPI = newCachedKPI(registeredKPI, interval.Duration)
+		}
 		// Get joint database connection for all dependencies.
 		jointDB, err := c.getJointDB(ctx, obj.Spec.Dependencies.Datasources)
 		if err != nil {
@@ -256,6 +298,10 @@ func (c *Controller) handleKPIChange(ctx context.Context, obj *v1alpha1.KPI) err
 		if jointDB == nil && datasourcesWithDB > 0 {
 			return fmt.Errorf("kpi %s has datasources requiring database but no connection available", obj.Name)
 		}
+		if jointDB != nil {
+			// KPIs only read from the database, so use the read replica if there is one.
+			jointDB = jointDB.Reader()
+		}
 		rawOpts := conf.NewRawOpts(`{}`)
 		if len(obj.Spec.Opts.Raw) > 0 {
 			rawOpts = conf.NewRawOptsBytes(obj.Spec.Opts.Raw)
@@ -267,6 +313,12 @@ func (c *Controller) handleKPIChange(ctx context.Context, obj *v1alpha1.KPI) err
 			return fmt.Errorf("failed to register kpi %s metrics: %w", obj.Name, err)
 		}
 		c.registeredKPIsByResourceName[obj.Name] = registeredKPI
+		registered = true
+	}
+
+	// Let kpis serving cached metrics know if the dependency data changed.
+	if cached, ok := registeredKPI.(*cachedKPI); ok && registered {
+		cached.setDependencyVersion(strings.Join(dependencyVersions, ","))
 	}
 
 	// If the dependencies are not all ready but the kpi is registered,
@@ -353,7 +405,10 @@ func (c *Controller) handleDatasourceUpdated(
 	// Only react to changes affecting the readiness.
 	dsBeforeReady := meta.IsStatusConditionTrue(dsBefore.Status.Conditions, v1alpha1.DatasourceConditionReady)
 	dsAfterReady := meta.IsStatusConditionTrue(dsAfter.Status.Conditions, v1alpha1.DatasourceConditionReady)
-	if dsBeforeReady == dsAfterReady {
+	// Also react to new data, which invalidates cached kpi metrics.
+	dataChanged := !dsBefore.Status.LastSynced.Equal(&dsAfter.Status.LastSynced) ||
+		dsBefore.Status.NumberOfObjects != dsAfter.Status.NumberOfObjects
+	if dsBeforeReady == dsAfterReady && !dataChanged {
 		return
 	}
 	// Handle the change.
@@ -418,7 +473,10 @@ func (c *Controller) handleKnowledgeUpdated(
 	// Only react to changes affecting the readiness.
 	knBeforeReady := meta.IsStatusConditionTrue(knBefore.Status.Conditions, v1alpha1.KnowledgeConditionReady)
 	knAfterReady := meta.IsStatusConditionTrue(knAfter.Status.Conditions, v1alpha1.KnowledgeConditionReady)
-	if knBeforeReady == knAfterReady {
+	// Also react to new data, which invalidates cached kpi metrics.
+	dataChanged := !knBefore.Status.LastContentChange.Equal(&knAfter.Status.LastContentChange) ||
+		knBefore.Status.RawLength != knAfter.Status.RawLength
+	if knBeforeReady == knAfterReady && !dataChanged {
 		return
 	}
 	// Handle the change.
@@ -442,6 +500,9 @@ func (c *Controller) SetupWithManager(mgr manager.Manager, mcl *multicluster.Cli
 	if err := mgr.Add(manager.RunnableFunc(c.InitAllKPIs)); err != nil {
 		return err
 	}
+	if err := mgr.Add(manager.RunnableFunc(c.unregisterAllKPIsOnShutdown)); err != nil {
+		return err
+	}
 	bldr := multicluster.BuildController(mcl, mgr)
 	// Watch datasource changes so that we can reconfigure kpis as needed.
 	bldr, err := bldr.WatchesMulticluster(
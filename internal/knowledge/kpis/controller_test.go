@@ -7,6 +7,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/cobaltcore-dev/cortex/api/v1alpha1"
@@ -462,19 +463,17 @@ func TestController_Reconcile(t *testing.T) {
 				Build()
 
 			mockKPIInstance := &mockKPI{name: "test_kpi"}
-			baseController := Controller{
-				Client: fakeClient,
-				Config: ControllerConfig{SchedulingDomain: "test-operator"},
-				supportedKPIs: map[string]plugins.KPI{
-					"test_kpi": mockKPIInstance,
-				},
-				registeredKPIsByResourceName: make(map[string]plugins.KPI),
-			}
-
 			// Use mock controller to avoid real database connections
 			controller := &mockController{
-				Controller: baseController,
-				mockDB:     &db.DB{}, // Mock database instance
+				Controller: Controller{
+					Client: fakeClient,
+					Config: ControllerConfig{SchedulingDomain: "test-operator"},
+					supportedKPIs: map[string]plugins.KPI{
+						"test_kpi": mockKPIInstance,
+					},
+					registeredKPIsByResourceName: make(map[string]plugins.KPI),
+				},
+				mockDB: &db.DB{}, // Mock database instance
 			}
 
 			req := ctrl.Request{
@@ -756,18 +755,16 @@ func TestController_handleKPIChange(t *testing.T) {
 				Build()
 
 			mockKPIInstance := &mockKPI{name: "test_kpi", initError: tt.mockInitError}
-			baseController := Controller{
-				Client: fakeClient,
-				supportedKPIs: map[string]plugins.KPI{
-					"test_kpi": mockKPIInstance,
-				},
-				registeredKPIsByResourceName: make(map[string]plugins.KPI),
-			}
-
 			// Use mock controller to avoid real database connections
 			controller := &mockController{
-				Controller: baseController,
-				mockDB:     &db.DB{}, // Mock database instance
+				Controller: Controller{
+					Client: fakeClient,
+					supportedKPIs: map[string]plugins.KPI{
+						"test_kpi": mockKPIInstance,
+					},
+					registeredKPIsByResourceName: make(map[string]plugins.KPI),
+				},
+				mockDB: &db.DB{}, // Mock database instance
 			}
 
 			err := controller.handleKPIChange(context.Background(), tt.kpi)
@@ -900,14 +897,12 @@ func TestController_getJointDB(t *testing.T) {
 				WithObjects(objects...).
 				Build()
 
-			baseController := Controller{
-				Client: fakeClient,
-			}
-
 			// Use mock controller to avoid real database connections
 			controller := &mockController{
-				Controller: baseController,
-				mockDB:     &db.DB{}, // Mock database instance
+				Controller: Controller{
+					Client: fakeClient,
+				},
+				mockDB: &db.DB{}, // Mock database instance
 			}
 
 			db, err := controller.getJointDB(context.Background(), tt.dsRefs, tt.knRefs)
@@ -929,6 +924,75 @@ func TestController_getJointDB(t *testing.T) {
 	}
 }
 
+func TestController_Reconcile_MismatchedDatabaseSecrets(t *testing.T) {
+	testScheme := runtime.NewScheme()
+	if err := scheme.AddToScheme(testScheme); err != nil {
+		t.Fatalf("Failed to add core scheme: %v", err)
+	}
+	if err := v1alpha1.AddToScheme(testScheme); err != nil {
+		t.Fatalf("Failed to add v1alpha1 scheme: %v", err)
+	}
+	datasource := func(name, secret string) *v1alpha1.Datasource {
+		return &v1alpha1.Datasource{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec: v1alpha1.DatasourceSpec{
+				DatabaseSecretRef: corev1.SecretReference{Name: secret, Namespace: "default"},
+			},
+			Status: v1alpha1.DatasourceStatus{
+				Conditions: []metav1.Condition{{
+					Type:   v1alpha1.DatasourceConditionReady,
+					Status: metav1.ConditionTrue,
+					Reason: "DatasourceSynced",
+				}},
+			},
+		}
+	}
+	kpi := &v1alpha1.KPI{
+		ObjectMeta: metav1.ObjectMeta{Name: "multi-ds-kpi"},
+		Spec: v1alpha1.KPISpec{
+			Impl: "test_kpi",
+			Dependencies: v1alpha1.KPIDependenciesSpec{
+				Datasources: []corev1.ObjectReference{
+					{Name: "ds1", Namespace: "default"},
+					{Name: "ds2", Namespace: "default"},
+				},
+			},
+		},
+	}
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(testScheme).
+		WithObjects(kpi, datasource("ds1", "db-secret1"), datasource("ds2", "db-secret2")).
+		WithStatusSubresource(&v1alpha1.KPI{}, &v1alpha1.Datasource{}).
+		Build()
+	controller := &Controller{
+		Client:                       fakeClient,
+		supportedKPIs:                map[string]plugins.KPI{"test_kpi": &mockKPI{name: "test_kpi"}},
+		registeredKPIsByResourceName: make(map[string]plugins.KPI),
+	}
+
+	_, err := controller.Reconcile(context.Background(), ctrl.Request{
+		NamespacedName: types.NamespacedName{Name: "multi-ds-kpi"},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	updated := &v1alpha1.KPI{}
+	if err := fakeClient.Get(context.Background(), types.NamespacedName{Name: "multi-ds-kpi"}, updated); err != nil {
+		t.Fatalf("failed to get kpi: %v", err)
+	}
+	condition := meta.FindStatusCondition(updated.Status.Conditions, v1alpha1.KPIConditionReady)
+	if condition == nil || condition.Status != metav1.ConditionFalse {
+		t.Fatalf("expected ready condition to be false, got %+v", condition)
+	}
+	expected := "datasource ds1 uses secret default/db-secret1 but datasource ds2 uses secret default/db-secret2"
+	if !strings.Contains(condition.Message, expected) {
+		t.Errorf("expected condition message to contain %q, got %q", expected, condition.Message)
+	}
+	if _, registered := controller.registeredKPIsByResourceName["multi-ds-kpi"]; registered {
+		t.Error("expected kpi not to be registered")
+	}
+}
+
 func TestController_InitAllKPIs(t *testing.T) {
 	testScheme := runtime.NewScheme()
 	if err := scheme.AddToScheme(testScheme); err != nil {
@@ -967,17 +1031,15 @@ func TestController_InitAllKPIs(t *testing.T) {
 		Build()
 
 	mockKPIInstance := &mockKPI{name: "test_kpi"}
-	baseController := Controller{
-		Client: fakeClient,
-		Config: ControllerConfig{SchedulingDomain: "test-operator"},
-		supportedKPIs: map[string]plugins.KPI{
-			"test_kpi": mockKPIInstance,
-		},
-	}
-
 	// Use mock controller to avoid real database connections
 	controller := &mockController{
-		Controller: baseController,
+		Controller: Controller{
+			Client: fakeClient,
+			Config: ControllerConfig{SchedulingDomain: "test-operator"},
+			supportedKPIs: map[string]plugins.KPI{
+				"test_kpi": mockKPIInstance,
+			},
+		},
 	}
 
 	err := controller.InitAllKPIs(context.Background())
@@ -999,3 +1061,33 @@ func TestController_InitAllKPIs(t *testing.T) {
 		t.Error("Expected kpi1 to be registered")
 	}
 }
+
+func TestController_unregisterAllKPIsOnShutdown(t *testing.T) {
+	kpi := &countingKPI{}
+	if err := kpi.Init(nil, nil, conf.NewRawOpts("{}")); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := metrics.Registry.Register(kpi); err != nil {
+		t.Fatalf("failed to register kpi: %v", err)
+	}
+	controller := &Controller{
+		registeredKPIsByResourceName: map[string]plugins.KPI{"counting-kpi": kpi},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- controller.unregisterAllKPIsOnShutdown(ctx) }()
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(controller.registeredKPIsByResourceName) != 0 {
+		t.Errorf("expected no registered kpis, got %d", len(controller.registeredKPIsByResourceName))
+	}
+	// The kpi can only be registered again if it was unregistered.
+	if err := metrics.Registry.Register(kpi); err != nil {
+		t.Errorf("expected kpi to be unregistered, got %v", err)
+	}
+	metrics.Registry.Unregister(kpi)
+}
@@ -0,0 +1,165 @@
+// Copyright SAP SE
+// SPDX-License-Identifier: Apache-2.0
+
+package deployment
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"math"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/cobaltcore-dev/cortex/api/v1alpha1"
+	"github.com/cobaltcore-dev/cortex/internal/knowledge/datasources/plugins/prometheus"
+	"github.com/cobaltcore-dev/cortex/internal/knowledge/db"
+	"github.com/cobaltcore-dev/cortex/internal/knowledge/kpis/plugins"
+	"github.com/cobaltcore-dev/cortex/pkg/conf"
+	prometheusclient "github.com/prometheus/client_golang/prometheus"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var decisionLatencyKPILogger = ctrl.Log.WithName("decision-latency-kpi")
+
+// Quantiles of the decision latency exposed by the kpi.
+var decisionLatencyQuantiles = []float64{0.5, 0.9, 0.99}
+
+// Time window of synced pipeline runs the quantiles are computed over.
+const decisionLatencyWindow = time.Hour
+
+type SchedulerDecisionLatencyKPIOpts struct {
+	// The scheduling domain to filter pipelines by.
+	DecisionSchedulingDomain v1alpha1.SchedulingDomain `json:"decisionSchedulingDomain"`
+}
+
+// KPI observing how long it took cortex to make its scheduling decisions.
+//
+// The latency is derived from the pipeline run duration histogram exported by
+// the scheduler, which is synced into the database by a prometheus datasource
+// of type pipeline_run_duration_metric. The buckets of all pipelines in the
+// scheduling domain are merged over the last hour, and the quantiles are
+// interpolated linearly within the buckets, like prometheus does it.
+type SchedulerDecisionLatencyKPI struct {
+	// Common base for all KPIs that provides standard functionality.
+	plugins.BaseKPI[SchedulerDecisionLatencyKPIOpts]
+
+	// Prometheus descriptor for the decision latency metric.
+	latency *prometheusclient.Desc
+}
+
+func (SchedulerDecisionLatencyKPI) GetName() string { return "scheduler_decision_latency_kpi" }
+
+// Initialize the KPI.
+func (k *SchedulerDecisionLatencyKPI) Init(db *db.DB, client client.Client, opts conf.RawOpts) error {
+	if err := k.BaseKPI.Init(db, client, opts); err != nil {
+		return err
+	}
+	k.latency = prometheusclient.NewDesc(
+		"cortex_decision_latency_seconds",
+		"Quantiles of the time cortex took to make its scheduling decisions",
+		[]string{"domain", "quantile"},
+		nil,
+	)
+	return nil
+}
+
+// Conform to the prometheus collector interface by providing the descriptor.
+func (k *SchedulerDecisionLatencyKPI) Describe(ch chan<- *prometheusclient.Desc) { ch <- k.latency }
+
+// Collect the decision latency quantiles.
+func (k *SchedulerDecisionLatencyKPI) Collect(ch chan<- prometheusclient.Metric) {
+	// This can happen when no datasource is provided that connects to a database.
+	if k.DB == nil {
+		decisionLatencyKPILogger.Error(errors.New("no database connection"), "cannot collect metric")
+		return
+	}
+	// The histogram is labeled by pipeline, so find the pipelines of the domain.
+	pipelineList := &v1alpha1.PipelineList{}
+	if err := k.Client.List(context.Background(), pipelineList); err != nil {
+		decisionLatencyKPILogger.Error(err, "failed to list pipelines")
+		return
+	}
+	pipelines := make(map[string]struct{})
+	for _, p := range pipelineList.Items {
+		if p.Spec.SchedulingDomain == k.Options.DecisionSchedulingDomain {
+			pipelines[p.Name] = struct{}{}
+		}
+	}
+	var metrics []prometheus.PipelineRunDurationMetric
+	query := "SELECT * FROM " + prometheus.PipelineRunDurationMetric{}.TableName() +
+		" WHERE timestamp >= :since"
+	since := time.Now().Add(-decisionLatencyWindow)
+	if _, err := k.DB.Select(&metrics, query, map[string]any{"since": since}); err != nil {
+		decisionLatencyKPILogger.Error(err, "failed to query pipeline run durations")
+		return
+	}
+	// Merge the cumulative bucket counts of all pipelines of the domain.
+	counts := make(map[float64]float64)
+	for _, m := range metrics {
+		if _, ok := pipelines[m.Pipeline]; !ok {
+			continue
+		}
+		upperBound, err := strconv.ParseFloat(m.Le, 64)
+		if err != nil {
+			continue
+		}
+		counts[upperBound] += m.Value
+	}
+	buckets := make([]latencyBucket, 0, len(counts))
+	for upperBound, count := range counts {
+		buckets = append(buckets, latencyBucket{upperBound: upperBound, count: count})
+	}
+	slices.SortFunc(buckets, func(a, b latencyBucket) int {
+		return cmp.Compare(a.upperBound, b.upperBound)
+	})
+	// Quantiles are undefined without any pipeline run.
+	if len(buckets) == 0 || buckets[len(buckets)-1].count <= 0 {
+		return
+	}
+	for _, q := range decisionLatencyQuantiles {
+		ch <- prometheusclient.MustNewConstMetric(
+			k.latency, prometheusclient.GaugeValue, bucketQuantile(q, buckets),
+			string(k.Options.DecisionSchedulingDomain),
+			strconv.FormatFloat(q, 'f', -1, 64),
+		)
+	}
+}
+
+// Cumulative histogram bucket, i.e. the number of observations <= upperBound.
+type latencyBucket struct {
+	upperBound float64
+	count      float64
+}
+
+// Estimate the quantile from buckets sorted by their upper bound, the last of
+// which is the +Inf bucket. This follows prometheus' histogram_quantile: the
+// value is interpolated linearly within the bucket containing the quantile,
+// and the highest finite upper bound is returned for the +Inf bucket.
+func bucketQuantile(q float64, buckets []latencyBucket) float64 {
+	total := buckets[len(buckets)-1].count
+	rank := q * total
+	i, _ := slices.BinarySearchFunc(buckets, rank, func(b latencyBucket, rank float64) int {
+		return cmp.Compare(b.count, rank)
+	})
+	if i >= len(buckets) {
+		i = len(buckets) - 1
+	}
+	if math.IsInf(buckets[i].upperBound, 1) {
+		if i == 0 {
+			return 0
+		}
+		return buckets[i-1].upperBound
+	}
+	lowerBound, lowerCount := 0.0, 0.0
+	if i > 0 {
+		lowerBound, lowerCount = buckets[i-1].upperBound, buckets[i-1].count
+	}
+	inBucket := buckets[i].count - lowerCount
+	if inBucket <= 0 {
+		return buckets[i].upperBound
+	}
+	return lowerBound + (buckets[i].upperBound-lowerBound)*(rank-lowerCount)/inBucket
+}
@@ -0,0 +1,168 @@
+// Copyright SAP SE
+// SPDX-License-Identifier: Apache-2.0
+
+package deployment
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/cobaltcore-dev/cortex/api/v1alpha1"
+	"github.com/cobaltcore-dev/cortex/internal/knowledge/datasources/plugins/prometheus"
+	"github.com/cobaltcore-dev/cortex/internal/knowledge/db"
+	testlibDB "github.com/cobaltcore-dev/cortex/internal/knowledge/db/testing"
+	"github.com/cobaltcore-dev/cortex/pkg/conf"
+	prometheusclient "github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestSchedulerDecisionLatencyKPI_Init(t *testing.T) {
+	kpi := &SchedulerDecisionLatencyKPI{}
+	if err := kpi.Init(nil, nil, conf.NewRawOpts(`{"decisionSchedulingDomain": "test-operator"}`)); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
+
+func TestSchedulerDecisionLatencyKPI_Collect(t *testing.T) {
+	scheme, err := v1alpha1.SchemeBuilder.Build()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	pipelines := []*v1alpha1.Pipeline{
+		{
+			ObjectMeta: v1.ObjectMeta{Name: "pipeline-a"},
+			Spec:       v1alpha1.PipelineSpec{SchedulingDomain: "test-operator"},
+		},
+		{
+			ObjectMeta: v1.ObjectMeta{Name: "pipeline-b"},
+			Spec:       v1alpha1.PipelineSpec{SchedulingDomain: "test-operator"},
+		},
+		{
+			ObjectMeta: v1.ObjectMeta{Name: "other-pipeline"},
+			Spec:       v1alpha1.PipelineSpec{SchedulingDomain: "other-operator"},
+		},
+	}
+
+	now := time.Now()
+	bucket := func(pipeline, le string, age time.Duration, value float64) any {
+		return &prometheus.PipelineRunDurationMetric{
+			Name:      "cortex_pipeline_run_duration",
+			Pipeline:  pipeline,
+			Le:        le,
+			Timestamp: now.Add(-age),
+			Value:     value,
+		}
+	}
+
+	tests := []struct {
+		name     string
+		buckets  []any
+		expected map[string]float64
+	}{
+		{
+			name:     "no pipeline runs",
+			expected: map[string]float64{},
+		},
+		{
+			name: "outdated and foreign pipeline runs are ignored",
+			buckets: []any{
+				bucket("pipeline-a", "1", 2*time.Hour, 10),
+				bucket("pipeline-a", "+Inf", 2*time.Hour, 10),
+				bucket("other-pipeline", "1", time.Minute, 10),
+				bucket("other-pipeline", "+Inf", time.Minute, 10),
+			},
+			expected: map[string]float64{},
+		},
+		{
+			name: "quantiles over the merged buckets of the domain",
+			buckets: []any{
+				// 20 runs in total, 5 <= 0.1s, 13 in (0.1s, 1s], 2 above 1s.
+				bucket("pipeline-a", "0.1", time.Minute, 3),
+				bucket("pipeline-a", "1", time.Minute, 10),
+				bucket("pipeline-a", "+Inf", time.Minute, 11),
+				bucket("pipeline-b", "0.1", 10*time.Minute, 2),
+				bucket("pipeline-b", "1", 10*time.Minute, 8),
+				bucket("pipeline-b", "+Inf", 10*time.Minute, 9),
+			},
+			// p50 at rank 10: 0.1 + 0.9 * (10-5)/13
+			expected: map[string]float64{"0.5": 0.1 + 0.9*5/13, "0.9": 1, "0.99": 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dbEnv := testlibDB.SetupDBEnv(t)
+			testDB := db.DB{DbMap: dbEnv.DbMap}
+			defer dbEnv.Close()
+			if err := testDB.CreateTable(testDB.AddTable(prometheus.PipelineRunDurationMetric{})); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if len(tt.buckets) > 0 {
+				if err := testDB.Insert(tt.buckets...); err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+			}
+			clientBuilder := fake.NewClientBuilder().WithScheme(scheme)
+			for _, obj := range pipelines {
+				clientBuilder = clientBuilder.WithObjects(obj)
+			}
+			client := clientBuilder.Build()
+
+			kpi := &SchedulerDecisionLatencyKPI{}
+			if err := kpi.Init(&testDB, client, conf.NewRawOpts(`{"decisionSchedulingDomain": "test-operator"}`)); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			ch := make(chan prometheusclient.Metric, 10)
+			kpi.Collect(ch)
+			close(ch)
+
+			got := map[string]float64{}
+			for metric := range ch {
+				var m dto.Metric
+				if err := metric.Write(&m); err != nil {
+					t.Fatalf("failed to write metric: %v", err)
+				}
+				var quantile string
+				for _, label := range m.GetLabel() {
+					if label.GetName() == "quantile" {
+						quantile = label.GetValue()
+					}
+				}
+				got[quantile] = m.GetGauge().GetValue()
+			}
+			if len(got) != len(tt.expected) {
+				t.Fatalf("expected %d metrics, got %v", len(tt.expected), got)
+			}
+			for quantile, value := range tt.expected {
+				if math.Abs(got[quantile]-value) > 1e-9 {
+					t.Errorf("quantile %s: expected %f, got %f", quantile, value, got[quantile])
+				}
+			}
+		})
+	}
+}
+
+func TestSchedulerDecisionLatencyKPI_Collect_NoDB(t *testing.T) {
+	kpi := &SchedulerDecisionLatencyKPI{}
+	if err := kpi.Init(nil, nil, conf.NewRawOpts(`{"decisionSchedulingDomain": "test-operator"}`)); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	ch := make(chan prometheusclient.Metric, 10)
+	kpi.Collect(ch)
+	close(ch)
+	if len(ch) != 0 {
+		t.Errorf("expected no metrics without a database, got %d", len(ch))
+	}
+}
+
+func TestSchedulerDecisionLatencyKPI_GetName(t *testing.T) {
+	kpi := &SchedulerDecisionLatencyKPI{}
+	expectedName := "scheduler_decision_latency_kpi"
+	if name := kpi.GetName(); name != expectedName {
+		t.Errorf("expected name %q, got %q", expectedName, name)
+	}
+}
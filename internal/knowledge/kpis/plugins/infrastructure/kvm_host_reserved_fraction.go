@@ -0,0 +1,91 @@
+// Copyright SAP SE
+// SPDX-License-Identifier: Apache-2.0
+
+package infrastructure
+
+import (
+	"context"
+	"log/slog"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/cobaltcore-dev/cortex/api/v1alpha1"
+	"github.com/cobaltcore-dev/cortex/internal/knowledge/db"
+	"github.com/cobaltcore-dev/cortex/internal/knowledge/kpis/plugins"
+	"github.com/cobaltcore-dev/cortex/pkg/conf"
+	"github.com/prometheus/client_golang/prometheus"
+
+	hv1 "github.com/cobaltcore-dev/openstack-hypervisor-operator/api/v1"
+)
+
+// KPI tracking which fraction of the capacity of each KVM host is locked by
+// ready reservations, i.e. committed resource reservations not yet in use
+// and failover reservations.
+type KVMHostReservedFractionKPI struct {
+	// Common base for all KPIs that provides standard functionality.
+	plugins.BaseKPI[struct{}] // No options passed through yaml config
+	reservedFractionPerHost   *prometheus.Desc
+}
+
+func (KVMHostReservedFractionKPI) GetName() string {
+	return "kvm_host_reserved_fraction_kpi"
+}
+
+func (k *KVMHostReservedFractionKPI) Init(db *db.DB, client client.Client, opts conf.RawOpts) error {
+	if err := k.BaseKPI.Init(db, client, opts); err != nil {
+		return err
+	}
+	k.reservedFractionPerHost = prometheus.NewDesc(
+		"cortex_kvm_host_capacity_reserved_fraction",
+		"Fraction of the resource capacity on the KVM hosts locked by reservations (individually by host). 0 for hosts without capacity.",
+		append(kvmHostLabels, "resource"),
+		nil,
+	)
+	return nil
+}
+
+func (k *KVMHostReservedFractionKPI) Describe(ch chan<- *prometheus.Desc) {
+	ch <- k.reservedFractionPerHost
+}
+
+func (k *KVMHostReservedFractionKPI) Collect(ch chan<- prometheus.Metric) {
+	hvs := &hv1.HypervisorList{}
+	if err := k.Client.List(context.Background(), hvs); err != nil {
+		slog.Error("failed to list hypervisors", "error", err)
+		return
+	}
+	reservations := &v1alpha1.ReservationList{}
+	if err := k.Client.List(context.Background(), reservations); err != nil {
+		slog.Error("failed to list reservations", "error", err)
+		return
+	}
+	failoverByHost, committedNotInUseByHost := aggregateReservationsByHost(reservations.Items)
+
+	for _, hv := range hvs.Items {
+		host := kvmHost{Hypervisor: hv}
+		failoverRes := failoverByHost[hv.Name]
+		committedRes := committedNotInUseByHost[hv.Name]
+
+		cpuReserved := committedRes.cpu.DeepCopy()
+		cpuReserved.Add(failoverRes.cpu)
+		ramReserved := committedRes.memory.DeepCopy()
+		ramReserved.Add(failoverRes.memory)
+
+		labels := host.getHostLabels()
+		cpuFraction := reservedFraction(host, hv1.ResourceCPU, cpuReserved)
+		ramFraction := reservedFraction(host, hv1.ResourceMemory, ramReserved)
+		ch <- prometheus.MustNewConstMetric(k.reservedFractionPerHost, prometheus.GaugeValue, cpuFraction, append(labels, "cpu")...)
+		ch <- prometheus.MustNewConstMetric(k.reservedFractionPerHost, prometheus.GaugeValue, ramFraction, append(labels, "ram")...)
+	}
+}
+
+// Get the fraction of the host's capacity of the given resource that is
+// reserved. Hosts without (or with zero) capacity report 0.
+func reservedFraction(host kvmHost, resourceName hv1.ResourceName, reserved resource.Quantity) float64 {
+	capacity, ok := host.getResourceCapacity(resourceName)
+	if !ok {
+		return 0
+	}
+	return reserved.AsApproximateFloat64() / capacity.AsApproximateFloat64()
+}
@@ -0,0 +1,139 @@
+// Copyright SAP SE
+// SPDX-License-Identifier: Apache-2.0
+
+package infrastructure
+
+import (
+	"math"
+	"testing"
+
+	"github.com/cobaltcore-dev/cortex/api/v1alpha1"
+	"github.com/cobaltcore-dev/cortex/pkg/conf"
+	hv1 "github.com/cobaltcore-dev/openstack-hypervisor-operator/api/v1"
+	"github.com/prometheus/client_golang/prometheus"
+	prometheusgo "github.com/prometheus/client_model/go"
+	"k8s.io/apimachinery/pkg/api/resource"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestKVMHostReservedFractionKPI_Init(t *testing.T) {
+	kpi := &KVMHostReservedFractionKPI{}
+	if err := kpi.Init(nil, nil, conf.NewRawOpts("{}")); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
+
+func TestKVMHostReservedFractionKPI_Collect(t *testing.T) {
+	hypervisor := func(name string, cpu, memory string) hv1.Hypervisor {
+		return hv1.Hypervisor{
+			ObjectMeta: v1.ObjectMeta{
+				Name:   name,
+				Labels: map[string]string{"topology.kubernetes.io/zone": "qa-1a"},
+			},
+			Status: hv1.HypervisorStatus{
+				EffectiveCapacity: map[hv1.ResourceName]resource.Quantity{
+					hv1.ResourceCPU:    resource.MustParse(cpu),
+					hv1.ResourceMemory: resource.MustParse(memory),
+				},
+			},
+		}
+	}
+	reservation := func(name, host string, ready bool) v1alpha1.Reservation {
+		status := v1.ConditionTrue
+		if !ready {
+			status = v1.ConditionFalse
+		}
+		return v1alpha1.Reservation{
+			ObjectMeta: v1.ObjectMeta{Name: name},
+			Spec: v1alpha1.ReservationSpec{
+				Type:             v1alpha1.ReservationTypeFailover,
+				SchedulingDomain: v1alpha1.SchedulingDomainNova,
+				Resources: map[hv1.ResourceName]resource.Quantity{
+					hv1.ResourceCPU:    resource.MustParse("16"),
+					hv1.ResourceMemory: resource.MustParse("64Gi"),
+				},
+				FailoverReservation: &v1alpha1.FailoverReservationSpec{},
+			},
+			Status: v1alpha1.ReservationStatus{
+				Host: host,
+				Conditions: []v1.Condition{
+					{Type: v1alpha1.ReservationConditionReady, Status: status},
+				},
+			},
+		}
+	}
+
+	hypervisors := []hv1.Hypervisor{
+		hypervisor("node001-bb088", "128", "512Gi"),
+		// Host without allocatable capacity must not divide by zero.
+		hypervisor("node006-bb088", "0", "0"),
+	}
+	reservations := []v1alpha1.Reservation{
+		reservation("failover-1", "node001-bb088", true),
+		reservation("failover-2", "node001-bb088", true),
+		// Not ready reservations don't lock any capacity.
+		reservation("failover-not-ready", "node001-bb088", false),
+		reservation("failover-3", "node006-bb088", true),
+	}
+	expected := map[string]float64{
+		"node001-bb088|cpu": 0.25, // 32/128
+		"node001-bb088|ram": 0.25, // 128Gi/512Gi
+		"node006-bb088|cpu": 0,
+		"node006-bb088|ram": 0,
+	}
+
+	scheme := runtime.NewScheme()
+	if err := hv1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add hypervisor scheme: %v", err)
+	}
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add v1alpha1 scheme: %v", err)
+	}
+	objects := make([]runtime.Object, 0, len(hypervisors)+len(reservations))
+	for i := range hypervisors {
+		objects = append(objects, &hypervisors[i])
+	}
+	for i := range reservations {
+		objects = append(objects, &reservations[i])
+	}
+	client := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(objects...).
+		Build()
+
+	kpi := &KVMHostReservedFractionKPI{}
+	if err := kpi.Init(nil, client, conf.NewRawOpts("{}")); err != nil {
+		t.Fatalf("failed to init KPI: %v", err)
+	}
+	ch := make(chan prometheus.Metric, 100)
+	kpi.Collect(ch)
+	close(ch)
+
+	actual := make(map[string]float64)
+	for m := range ch {
+		var pm prometheusgo.Metric
+		if err := m.Write(&pm); err != nil {
+			t.Fatalf("failed to write metric: %v", err)
+		}
+		labels := make(map[string]string)
+		for _, lbl := range pm.Label {
+			labels[lbl.GetName()] = lbl.GetValue()
+		}
+		actual[labels["compute_host"]+"|"+labels["resource"]] = pm.GetGauge().GetValue()
+	}
+	if len(actual) != len(expected) {
+		t.Fatalf("expected %d metrics, got %v", len(expected), actual)
+	}
+	for key, value := range expected {
+		got, ok := actual[key]
+		if !ok {
+			t.Errorf("missing metric %q", key)
+			continue
+		}
+		if math.IsNaN(got) || math.IsInf(got, 0) || math.Abs(got-value) > 1e-9 {
+			t.Errorf("metric %q: expected %f, got %f", key, value, got)
+		}
+	}
+}
@@ -23,6 +23,7 @@ var supportedKPIs = map[string]plugins.KPI{
 	"kvm_host_capacity_kpi":          &infrastructure.KVMHostCapacityKPI{},
 	"kvm_project_utilization_kpi":    &infrastructure.KVMProjectUtilizationKPI{},
 	"kvm_hana_stacking_kpi":          &infrastructure.KVMHanaStackingKPI{},
+	"kvm_host_reserved_fraction_kpi": &infrastructure.KVMHostReservedFractionKPI{},
 	"vmware_project_utilization_kpi": &infrastructure.VMwareProjectUtilizationKPI{},
 	"vmware_project_commitments_kpi": &infrastructure.VMwareProjectCommitmentsKPI{},
 	"vmware_host_capacity_kpi":       &infrastructure.VMwareHostCapacityKPI{},
@@ -34,4 +35,6 @@ var supportedKPIs = map[string]plugins.KPI{
 	"decision_state_kpi":   &deployment.DecisionStateKPI{},
 	"kpi_state_kpi":        &deployment.KPIStateKPI{},
 	"pipeline_state_kpi":   &deployment.PipelineStateKPI{},
+
+	"scheduler_decision_latency_kpi": &deployment.SchedulerDecisionLatencyKPI{},
 }
@@ -10,17 +10,25 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"slices"
 	"strings"
 	"testing"
+	"time"
 
 	cinderapi "github.com/cobaltcore-dev/cortex/api/external/cinder"
 	"github.com/cobaltcore-dev/cortex/api/v1alpha1"
+	"github.com/cobaltcore-dev/cortex/internal/scheduling/lib"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 type mockHTTPAPIDelegate struct {
 	processDecisionFunc func(ctx context.Context, decision *v1alpha1.Decision) error
+	knownPipelines      []string
+}
+
+func (m *mockHTTPAPIDelegate) HasPipeline(name string) bool {
+	return m.knownPipelines == nil || slices.Contains(m.knownPipelines, name)
 }
 
 func (m *mockHTTPAPIDelegate) ProcessNewDecisionFromAPI(ctx context.Context, decision *v1alpha1.Decision) error {
@@ -33,7 +41,7 @@ func (m *mockHTTPAPIDelegate) ProcessNewDecisionFromAPI(ctx context.Context, dec
 func TestNewAPI(t *testing.T) {
 	delegate := &mockHTTPAPIDelegate{}
 
-	api := NewAPI(delegate)
+	api := NewAPI(HTTPAPIConfig{}, delegate)
 
 	if api == nil {
 		t.Fatal("NewAPI returned nil")
@@ -55,7 +63,7 @@ func TestNewAPI(t *testing.T) {
 
 func TestHTTPAPI_Init(t *testing.T) {
 	delegate := &mockHTTPAPIDelegate{}
-	api := NewAPI(delegate)
+	api := NewAPI(HTTPAPIConfig{}, delegate)
 
 	mux := http.NewServeMux()
 	api.Init(mux)
@@ -73,7 +81,7 @@ func TestHTTPAPI_Init(t *testing.T) {
 
 func TestHTTPAPI_canRunScheduler(t *testing.T) {
 	delegate := &mockHTTPAPIDelegate{}
-	api := NewAPI(delegate).(*httpAPI)
+	api := NewAPI(HTTPAPIConfig{}, delegate).(*httpAPI)
 
 	tests := []struct {
 		name        string
@@ -212,6 +220,28 @@ func TestHTTPAPI_CinderExternalScheduler(t *testing.T) {
 			processDecisionErr: errors.New("processing failed"),
 			expectedStatus:     http.StatusInternalServerError,
 		},
+		{
+			name:   "pipeline not ready",
+			method: http.MethodPost,
+			body: func() string {
+				req := cinderapi.ExternalSchedulerRequest{
+					Hosts: []cinderapi.ExternalSchedulerHost{
+						{VolumeHost: "host1"},
+					},
+					Weights: map[string]float64{
+						"host1": 1.0,
+					},
+					Pipeline: "test-pipeline",
+				}
+				data, err := json.Marshal(req)
+				if err != nil {
+					t.Fatalf("Failed to marshal request data: %v", err)
+				}
+				return string(data)
+			}(),
+			processDecisionErr: &lib.PipelineNotReadyError{Pipeline: "test-pipeline"},
+			expectedStatus:     http.StatusServiceUnavailable,
+		},
 		{
 			name:   "decision failed",
 			method: http.MethodPost,
@@ -261,7 +291,7 @@ func TestHTTPAPI_CinderExternalScheduler(t *testing.T) {
 				},
 			}
 
-			api := NewAPI(delegate).(*httpAPI)
+			api := NewAPI(HTTPAPIConfig{}, delegate).(*httpAPI)
 
 			var body *strings.Reader
 			if tt.body != "" {
@@ -301,7 +331,7 @@ func TestHTTPAPI_CinderExternalScheduler(t *testing.T) {
 
 func TestHTTPAPI_inferPipelineName(t *testing.T) {
 	delegate := &mockHTTPAPIDelegate{}
-	api := NewAPI(delegate).(*httpAPI)
+	api := NewAPI(HTTPAPIConfig{}, delegate).(*httpAPI)
 
 	tests := []struct {
 		name         string
@@ -347,6 +377,43 @@ func TestHTTPAPI_inferPipelineName(t *testing.T) {
 	}
 }
 
+func TestHTTPAPI_CinderExternalScheduler_RequestTimeout(t *testing.T) {
+	delegate := &mockHTTPAPIDelegate{
+		processDecisionFunc: func(ctx context.Context, decision *v1alpha1.Decision) error {
+			if _, ok := ctx.Deadline(); !ok {
+				t.Error("expected the request context to have a deadline")
+			}
+			// Simulate a pipeline that doesn't finish in time.
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	}
+	config := HTTPAPIConfig{RequestTimeout: metav1.Duration{Duration: 10 * time.Millisecond}}
+	api := NewAPI(config, delegate).(*httpAPI)
+
+	requestData := cinderapi.ExternalSchedulerRequest{
+		Hosts: []cinderapi.ExternalSchedulerHost{
+			{VolumeHost: "host1"},
+		},
+		Weights: map[string]float64{
+			"host1": 1.0,
+		},
+		Pipeline: "test-pipeline",
+	}
+	body, err := json.Marshal(requestData)
+	if err != nil {
+		t.Fatalf("Failed to marshal request data: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/scheduler/cinder/external", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	api.CinderExternalScheduler(w, req)
+
+	if w.Code != http.StatusGatewayTimeout {
+		t.Errorf("Expected status %d, got %d", http.StatusGatewayTimeout, w.Code)
+	}
+}
+
 func TestHTTPAPI_CinderExternalScheduler_DecisionCreation(t *testing.T) {
 	var capturedDecision *v1alpha1.Decision
 	delegate := &mockHTTPAPIDelegate{
@@ -360,7 +427,7 @@ func TestHTTPAPI_CinderExternalScheduler_DecisionCreation(t *testing.T) {
 		},
 	}
 
-	api := NewAPI(delegate).(*httpAPI)
+	api := NewAPI(HTTPAPIConfig{}, delegate).(*httpAPI)
 
 	requestData := cinderapi.ExternalSchedulerRequest{
 		Hosts: []cinderapi.ExternalSchedulerHost{
@@ -406,3 +473,69 @@ func TestHTTPAPI_CinderExternalScheduler_DecisionCreation(t *testing.T) {
 		t.Error("CinderRaw should not be nil")
 	}
 }
+
+func TestHTTPAPI_CinderExternalScheduler_DefaultPipeline(t *testing.T) {
+	tests := []struct {
+		name             string
+		config           HTTPAPIConfig
+		pipeline         string
+		expectedPipeline string
+	}{
+		{
+			name:             "known pipeline is kept",
+			config:           HTTPAPIConfig{CinderDefaultPipeline: "cinder-default"},
+			pipeline:         "cinder-external-scheduler",
+			expectedPipeline: "cinder-external-scheduler",
+		},
+		{
+			name:             "unknown pipeline falls back to default",
+			config:           HTTPAPIConfig{CinderDefaultPipeline: "cinder-default"},
+			pipeline:         "unknown-pipeline",
+			expectedPipeline: "cinder-default",
+		},
+		{
+			name:             "unknown pipeline without default",
+			config:           HTTPAPIConfig{},
+			pipeline:         "unknown-pipeline",
+			expectedPipeline: "unknown-pipeline",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var capturedDecision *v1alpha1.Decision
+			delegate := &mockHTTPAPIDelegate{
+				processDecisionFunc: func(ctx context.Context, decision *v1alpha1.Decision) error {
+					capturedDecision = decision
+					decision.Status.Result = &v1alpha1.DecisionResult{
+						OrderedHosts: []string{"host1"},
+					}
+					return nil
+				},
+				knownPipelines: []string{"cinder-external-scheduler", "cinder-default"},
+			}
+			api := NewAPI(tt.config, delegate).(*httpAPI)
+
+			requestData := cinderapi.ExternalSchedulerRequest{
+				Hosts:    []cinderapi.ExternalSchedulerHost{{VolumeHost: "host1"}},
+				Weights:  map[string]float64{"host1": 1.0},
+				Pipeline: tt.pipeline,
+			}
+			body, err := json.Marshal(requestData)
+			if err != nil {
+				t.Fatalf("Failed to marshal request data: %v", err)
+			}
+			req := httptest.NewRequest(http.MethodPost, "/scheduler/cinder/external", bytes.NewReader(body))
+			w := httptest.NewRecorder()
+
+			api.CinderExternalScheduler(w, req)
+
+			if capturedDecision == nil {
+				t.Fatal("Decision was not captured")
+			}
+			if capturedDecision.Spec.PipelineRef.Name != tt.expectedPipeline {
+				t.Errorf("expected pipeline %s, got %s", tt.expectedPipeline, capturedDecision.Spec.PipelineRef.Name)
+			}
+		})
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"log/slog"
 	"sync"
 	"time"
 
@@ -39,6 +40,8 @@ type FilterWeigherPipelineController struct {
 	// Mutex to only allow one process at a time
 	processMu sync.Mutex
 
+	// Configuration of the History CRDs written after each decision.
+	HistoryConfig lib.HistoryConfig
 	// Monitor to pass down to all pipelines.
 	Monitor lib.FilterWeigherPipelineMonitor
 }
@@ -70,6 +73,11 @@ func (c *FilterWeigherPipelineController) Reconcile(ctx context.Context, req ctr
 
 // Process the decision from the API. Should create and return the updated decision.
 func (c *FilterWeigherPipelineController) ProcessNewDecisionFromAPI(ctx context.Context, decision *v1alpha1.Decision) error {
+	ctx, span := lib.StartSpan(ctx, "scheduling decision",
+		slog.String("domain", string(decision.Spec.SchedulingDomain)),
+		slog.String("resourceID", decision.Spec.ResourceID),
+		slog.String("pipeline", decision.Spec.PipelineRef.Name),
+	)
 	c.processMu.Lock()
 	defer c.processMu.Unlock()
 
@@ -89,6 +97,8 @@ func (c *FilterWeigherPipelineController) ProcessNewDecisionFromAPI(ctx context.
 			Message: "pipeline run succeeded",
 		})
 	}
+	c.Monitor.ObserveDecision(c.PipelineLabel(decision.Spec.PipelineRef.Name), err)
+	span.End(err)
 	return err
 }
 
@@ -96,10 +106,10 @@ func (c *FilterWeigherPipelineController) process(ctx context.Context, decision
 	log := ctrl.LoggerFrom(ctx)
 	startedAt := time.Now() // So we can measure sync duration.
 
-	pipeline, ok := c.Pipelines[decision.Spec.PipelineRef.Name]
+	pipeline, ok := c.GetPipeline(decision.Spec.PipelineRef.Name)
 	if !ok {
 		log.Error(nil, "pipeline not found or not ready", "pipelineName", decision.Spec.PipelineRef.Name)
-		return errors.New("pipeline not found or not ready")
+		return &lib.PipelineNotReadyError{Pipeline: decision.Spec.PipelineRef.Name}
 	}
 	if decision.Spec.CinderRaw == nil {
 		log.Error(nil, "skipping decision, no cinderRaw spec defined")
@@ -111,12 +121,15 @@ func (c *FilterWeigherPipelineController) process(ctx context.Context, decision
 		return err
 	}
 
-	result, err := pipeline.Run(request)
+	result, err := pipeline.Run(ctx, request)
 	if !request.Options.SkipHistory {
 		if upsertErr := c.HistoryManager.CreateOrUpdateHistory(ctx, decision, nil, err); upsertErr != nil {
 			log.Error(upsertErr, "failed to create/update history")
 		}
 	}
+	if conf, _ := c.GetPipelineConfig(decision.Spec.PipelineRef.Name); conf.Spec.Audit {
+		lib.AuditDecision(decision, request, &result, err)
+	}
 	if err != nil {
 		log.Error(err, "failed to run pipeline")
 		return err
@@ -136,14 +149,25 @@ func (c *FilterWeigherPipelineController) InitPipeline(
 		ctx, c.Client, p.Name,
 		filters.Index, p.Spec.Filters,
 		weighers.Index, p.Spec.Weighers,
+		p.Spec.TieBreaker,
+		p.Spec.InputWeightNormalization,
+		p.Spec.ParallelFilters,
 		c.Monitor,
 	)
 }
 
 func (c *FilterWeigherPipelineController) SetupWithManager(mgr manager.Manager, mcl *multicluster.Client) error {
 	c.Initializer = c
+	c.ObserveReadyPipelines = c.Monitor.ObserveReadyPipelines
 	c.SchedulingDomain = v1alpha1.SchedulingDomainCinder
-	c.HistoryManager = lib.HistoryClient{Client: mcl, Recorder: mcl.GetEventRecorder("cortex-cinder-scheduler")}
+	certainty := c.HistoryConfig.CertaintyThresholdsFor(v1alpha1.SchedulingDomainCinder)
+	c.HistoryManager = lib.HistoryClient{
+		Client:           mcl,
+		Recorder:         mcl.GetEventRecorder("cortex-cinder-scheduler"),
+		Certainty:        &certainty,
+		Verbosity:        c.HistoryConfig.ExplanationVerbosity,
+		MaxHistoryLength: c.HistoryConfig.MaxHistoryLength,
+	}
 	if err := mgr.Add(manager.RunnableFunc(c.InitAllPipelines)); err != nil {
 		return err
 	}
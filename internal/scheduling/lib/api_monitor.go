@@ -4,6 +4,7 @@
 package lib
 
 import (
+	"encoding/json"
 	"log/slog"
 	"net/http"
 	"strconv"
@@ -16,6 +17,8 @@ import (
 type APIMonitor struct {
 	// A histogram to measure how long the API requests take to run.
 	ApiRequestsTimer *prometheus.HistogramVec
+	// A counter of requests that fell back to the default pipeline.
+	DefaultPipelineFallbacks *prometheus.CounterVec
 }
 
 // Create a new scheduler monitor and register the necessary Prometheus metrics.
@@ -26,15 +29,44 @@ func NewSchedulerMonitor() APIMonitor {
 			Help:    "Duration of API requests",
 			Buckets: prometheus.DefBuckets,
 		}, []string{"method", "path", "status", "error"}),
+		DefaultPipelineFallbacks: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "cortex_scheduler_api_default_pipeline_fallbacks_total",
+			Help: "Number of requests that used the default pipeline instead of the requested one",
+		}, []string{"path", "pipeline", "reason"}),
 	}
 }
 
 func (m *APIMonitor) Describe(ch chan<- *prometheus.Desc) {
 	m.ApiRequestsTimer.Describe(ch)
+	m.DefaultPipelineFallbacks.Describe(ch)
 }
 
 func (m *APIMonitor) Collect(ch chan<- prometheus.Metric) {
 	m.ApiRequestsTimer.Collect(ch)
+	m.DefaultPipelineFallbacks.Collect(ch)
+}
+
+// Select the pipeline to use for a request to the given path. If the requested
+// pipeline is empty or not known, the default pipeline is used instead and the
+// fallback is counted. Without a default pipeline, the requested pipeline is
+// returned as is, so the caller can report it as missing.
+func (m *APIMonitor) SelectPipeline(pattern, requested, defaultPipeline string, known func(string) bool) string {
+	if defaultPipeline == "" || requested == defaultPipeline {
+		return requested
+	}
+	reason := "empty"
+	if requested != "" {
+		if known(requested) {
+			return requested
+		}
+		reason = "unknown"
+	}
+	slog.Info("falling back to default pipeline",
+		"path", pattern, "requested", requested, "pipeline", defaultPipeline, "reason", reason)
+	if m.DefaultPipelineFallbacks != nil {
+		m.DefaultPipelineFallbacks.WithLabelValues(pattern, defaultPipeline, reason).Inc()
+	}
+	return defaultPipeline
 }
 
 // Helper to respond to the request with the given code and error.
@@ -51,9 +83,19 @@ func (m *APIMonitor) Callback(w http.ResponseWriter, r *http.Request, pattern st
 	return MonitoredCallback{apiMonitor: m, w: w, r: r, pattern: pattern, t: time.Now()}
 }
 
-// Respond to the request with the given code and error.
-// Also log the time it took to handle the request.
-func (c MonitoredCallback) Respond(logger *slog.Logger, code int, err error, text string) {
+// Structured error body returned by the scheduler APIs for errors that
+// callers are expected to handle, e.g. by retrying the request later.
+type APIError struct {
+	// Machine-readable error code, e.g. "PipelineNotReady".
+	Code string `json:"code"`
+	// The pipeline the error refers to, if any.
+	Pipeline string `json:"pipeline,omitempty"`
+	// Human-readable reason of the error.
+	Reason string `json:"reason"`
+}
+
+// Observe the time it took to handle the request.
+func (c MonitoredCallback) observe(code int, text string) {
 	if c.apiMonitor != nil && c.apiMonitor.ApiRequestsTimer != nil {
 		observer := c.apiMonitor.ApiRequestsTimer.WithLabelValues(
 			c.r.Method,
@@ -63,14 +105,37 @@ func (c MonitoredCallback) Respond(logger *slog.Logger, code int, err error, tex
 		)
 		observer.Observe(time.Since(c.t).Seconds())
 	}
+}
+
+// Log the error that caused the request to fail.
+func logRequestError(logger *slog.Logger, err error) {
+	if logger == nil {
+		slog.Error("failed to handle request", "error", err)
+	} else {
+		logger.Error("failed to handle request", "error", err)
+	}
+}
+
+// Respond to the request with the given code and error.
+// Also log the time it took to handle the request.
+func (c MonitoredCallback) Respond(logger *slog.Logger, code int, err error, text string) {
+	c.observe(code, text)
 	if err != nil {
-		if logger == nil {
-			slog.Error("failed to handle request", "error", err)
-		} else {
-			logger.Error("failed to handle request", "error", err)
-		}
+		logRequestError(logger, err)
 		http.Error(c.w, text, code)
 		return
 	}
 	// If there was no error, nothing else to do.
 }
+
+// Respond to the request with the given code and a structured json error body.
+// Also log the time it took to handle the request.
+func (c MonitoredCallback) RespondError(logger *slog.Logger, code int, err error, apiErr APIError) {
+	c.observe(code, apiErr.Code)
+	logRequestError(logger, err)
+	c.w.Header().Set("Content-Type", "application/json")
+	c.w.WriteHeader(code)
+	if err := json.NewEncoder(c.w).Encode(apiErr); err != nil {
+		logRequestError(logger, err)
+	}
+}
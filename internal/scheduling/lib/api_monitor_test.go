@@ -4,6 +4,7 @@
 package lib
 
 import (
+	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
@@ -11,6 +12,7 @@ import (
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 )
 
 func TestNewSchedulerMonitor(t *testing.T) {
@@ -19,6 +21,9 @@ func TestNewSchedulerMonitor(t *testing.T) {
 	if monitor.ApiRequestsTimer == nil {
 		t.Error("expected ApiRequestsTimer to be initialized")
 	}
+	if monitor.DefaultPipelineFallbacks == nil {
+		t.Error("expected DefaultPipelineFallbacks to be initialized")
+	}
 
 	// Verify the metric is a histogram by recording a value
 	observer := monitor.ApiRequestsTimer.WithLabelValues("GET", "/test", "200", "")
@@ -27,19 +32,19 @@ func TestNewSchedulerMonitor(t *testing.T) {
 
 func TestAPIMonitor_Describe(t *testing.T) {
 	monitor := NewSchedulerMonitor()
-	ch := make(chan *prometheus.Desc, 1)
+	ch := make(chan *prometheus.Desc, 2)
 
 	monitor.Describe(ch)
 	close(ch)
 
-	// Should have exactly one descriptor
+	// Should have one descriptor per metric
 	count := 0
 	for range ch {
 		count++
 	}
 
-	if count != 1 {
-		t.Errorf("expected 1 descriptor, got %d", count)
+	if count != 2 {
+		t.Errorf("expected 2 descriptors, got %d", count)
 	}
 }
 
@@ -460,3 +465,63 @@ func TestMonitoredCallback_HTTPMethods(t *testing.T) {
 		})
 	}
 }
+
+func TestMonitoredCallback_RespondError(t *testing.T) {
+	monitor := NewSchedulerMonitor()
+	req := httptest.NewRequest(http.MethodPost, "/test", http.NoBody)
+	w := httptest.NewRecorder()
+
+	notReady := &PipelineNotReadyError{Pipeline: "test-pipeline"}
+	callback := monitor.Callback(w, req, "/test")
+	callback.RespondError(nil, http.StatusServiceUnavailable, notReady, notReady.APIError())
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected status %d, got %d", http.StatusServiceUnavailable, w.Code)
+	}
+	if contentType := w.Header().Get("Content-Type"); contentType != "application/json" {
+		t.Errorf("expected json content type, got %q", contentType)
+	}
+	var body APIError
+	if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if body.Code != "PipelineNotReady" || body.Pipeline != "test-pipeline" || body.Reason == "" {
+		t.Errorf("unexpected error body: %+v", body)
+	}
+}
+
+func TestAPIMonitor_SelectPipeline(t *testing.T) {
+	known := func(name string) bool { return name == "known" }
+	tests := []struct {
+		name            string
+		requested       string
+		defaultPipeline string
+		expected        string
+		expectedReason  string
+	}{
+		{name: "known pipeline", requested: "known", defaultPipeline: "default", expected: "known"},
+		{name: "empty pipeline", requested: "", defaultPipeline: "default", expected: "default", expectedReason: "empty"},
+		{name: "unknown pipeline", requested: "other", defaultPipeline: "default", expected: "default", expectedReason: "unknown"},
+		{name: "default pipeline requested", requested: "default", defaultPipeline: "default", expected: "default"},
+		{name: "no default pipeline", requested: "other", defaultPipeline: "", expected: "other"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			monitor := NewSchedulerMonitor()
+			pipeline := monitor.SelectPipeline("/test", tt.requested, tt.defaultPipeline, known)
+			if pipeline != tt.expected {
+				t.Errorf("expected pipeline %q, got %q", tt.expected, pipeline)
+			}
+			for _, reason := range []string{"empty", "unknown"} {
+				expected := 0.0
+				if reason == tt.expectedReason {
+					expected = 1
+				}
+				counter := monitor.DefaultPipelineFallbacks.WithLabelValues("/test", tt.defaultPipeline, reason)
+				if got := testutil.ToFloat64(counter); got != expected {
+					t.Errorf("expected %v fallbacks with reason %s, got %v", expected, reason, got)
+				}
+			}
+		})
+	}
+}
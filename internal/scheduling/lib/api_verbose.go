@@ -0,0 +1,32 @@
+// Copyright SAP SE
+// SPDX-License-Identifier: Apache-2.0
+
+package lib
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/cobaltcore-dev/cortex/api/v1alpha1"
+)
+
+// Check if the caller asked for a verbose scheduler response through the
+// verbose query parameter, e.g. ?verbose=1.
+func IsVerboseRequest(r *http.Request) bool {
+	verbose, err := strconv.ParseBool(r.URL.Query().Get("verbose"))
+	return err == nil && verbose
+}
+
+// Get the aggregated weights of the given hosts from the decision result,
+// to be included in verbose scheduler responses. Returns nil if the request
+// isn't verbose, so that the weights are omitted from compact responses.
+func VerboseWeights(r *http.Request, hosts []string, result *v1alpha1.DecisionResult) map[string]float64 {
+	if !IsVerboseRequest(r) || result == nil {
+		return nil
+	}
+	weights := make(map[string]float64, len(hosts))
+	for _, host := range hosts {
+		weights[host] = result.AggregatedOutWeights[host]
+	}
+	return weights
+}
@@ -0,0 +1,63 @@
+// Copyright SAP SE
+// SPDX-License-Identifier: Apache-2.0
+
+package lib
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/cobaltcore-dev/cortex/api/v1alpha1"
+)
+
+func TestVerboseWeights(t *testing.T) {
+	result := &v1alpha1.DecisionResult{
+		OrderedHosts:         []string{"host1", "host2", "host3"},
+		AggregatedOutWeights: map[string]float64{"host1": 2, "host2": 1, "host3": 0.5},
+	}
+	tests := []struct {
+		name     string
+		url      string
+		hosts    []string
+		expected map[string]float64
+	}{
+		{
+			name:  "compact by default",
+			url:   "/scheduler/nova/external",
+			hosts: []string{"host1", "host2"},
+		},
+		{
+			name:  "verbose disabled",
+			url:   "/scheduler/nova/external?verbose=0",
+			hosts: []string{"host1", "host2"},
+		},
+		{
+			name:  "invalid verbose value",
+			url:   "/scheduler/nova/external?verbose=yes-please",
+			hosts: []string{"host1", "host2"},
+		},
+		{
+			name:     "verbose includes weights of returned hosts only",
+			url:      "/scheduler/nova/external?verbose=1",
+			hosts:    []string{"host2", "host1"},
+			expected: map[string]float64{"host1": 2, "host2": 1},
+		},
+		{
+			name:     "verbose with boolean value",
+			url:      "/scheduler/nova/external?verbose=true",
+			hosts:    []string{"host3"},
+			expected: map[string]float64{"host3": 0.5},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, tt.url, http.NoBody)
+			weights := VerboseWeights(r, tt.hosts, result)
+			if !reflect.DeepEqual(weights, tt.expected) {
+				t.Errorf("expected weights %v, got %v", tt.expected, weights)
+			}
+		})
+	}
+}
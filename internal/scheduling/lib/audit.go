@@ -0,0 +1,49 @@
+// Copyright SAP SE
+// SPDX-License-Identifier: Apache-2.0
+
+package lib
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/cobaltcore-dev/cortex/api/v1alpha1"
+	"github.com/cobaltcore-dev/cortex/pkg/monitoring"
+)
+
+// Message of all audit records, so that they can be selected from the logs.
+const auditMessage = "scheduling decision audit"
+
+// Log an append-only audit record of a scheduling decision with a stable
+// schema: the scheduling domain, resource, pipeline, input hosts, the
+// selected host, and the request context such as the global request id.
+// Unlike the decision resource, the record outlives garbage collection.
+// Audit records are exempt from log sampling, so none of them is dropped.
+func AuditDecision(
+	decision *v1alpha1.Decision,
+	request FilterWeigherPipelineRequest,
+	result *v1alpha1.DecisionResult,
+	pipelineErr error,
+) {
+	targetHost := ""
+	if result != nil && result.TargetHost != nil {
+		targetHost = *result.TargetHost
+	}
+	errMsg := ""
+	if pipelineErr != nil {
+		errMsg = pipelineErr.Error()
+	}
+	attrs := []any{
+		slog.String("domain", string(decision.Spec.SchedulingDomain)),
+		slog.String("resourceID", decision.Spec.ResourceID),
+		slog.String("intent", string(decision.Spec.Intent)),
+		slog.String("pipeline", decision.Spec.PipelineRef.Name),
+		slog.Any("inputHosts", request.GetHosts()),
+		slog.String("targetHost", targetHost),
+		slog.String("error", errMsg),
+	}
+	for _, attr := range request.GetTraceLogArgs() {
+		attrs = append(attrs, attr)
+	}
+	slog.InfoContext(monitoring.WithoutSampling(context.Background()), auditMessage, attrs...)
+}
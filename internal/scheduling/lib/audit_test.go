@@ -0,0 +1,109 @@
+// Copyright SAP SE
+// SPDX-License-Identifier: Apache-2.0
+
+package lib
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cobaltcore-dev/cortex/api/v1alpha1"
+	"github.com/cobaltcore-dev/cortex/pkg/monitoring"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestAuditDecision(t *testing.T) {
+	decision := &v1alpha1.Decision{
+		Spec: v1alpha1.DecisionSpec{
+			SchedulingDomain: v1alpha1.SchedulingDomainNova,
+			ResourceID:       "vm-1",
+			PipelineRef:      corev1.ObjectReference{Name: "nova-pipeline"},
+		},
+	}
+	request := mockFilterWeigherPipelineRequest{
+		Hosts:        []string{"host1", "host2"},
+		TraceLogArgs: []slog.Attr{slog.String("greq", "req-global")},
+	}
+	targetHost := "host2"
+
+	tests := []struct {
+		name     string
+		result   *v1alpha1.DecisionResult
+		err      error
+		expected map[string]any
+	}{
+		{
+			name:   "successful decision",
+			result: &v1alpha1.DecisionResult{TargetHost: &targetHost},
+			expected: map[string]any{
+				"domain":     "nova",
+				"resourceID": "vm-1",
+				"pipeline":   "nova-pipeline",
+				"targetHost": "host2",
+				"error":      "",
+				"greq":       "req-global",
+			},
+		},
+		{
+			name:   "failed decision",
+			result: &v1alpha1.DecisionResult{},
+			err:    errors.New("pipeline failed"),
+			expected: map[string]any{
+				"targetHost": "",
+				"error":      "pipeline failed",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			defaultLogger := slog.Default()
+			slog.SetDefault(slog.New(slog.NewJSONHandler(&buf, nil)))
+			defer slog.SetDefault(defaultLogger)
+
+			AuditDecision(decision, request, tt.result, tt.err)
+
+			var record map[string]any
+			if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+				t.Fatalf("failed to parse audit record %q: %v", buf.String(), err)
+			}
+			if record["msg"] != auditMessage {
+				t.Errorf("expected message %q, got %v", auditMessage, record["msg"])
+			}
+			for key, value := range tt.expected {
+				if record[key] != value {
+					t.Errorf("expected %s=%v, got %v", key, value, record[key])
+				}
+			}
+			hosts, ok := record["inputHosts"].([]any)
+			if !ok || len(hosts) != 2 {
+				t.Errorf("expected 2 input hosts, got %v", record["inputHosts"])
+			}
+		})
+	}
+}
+
+func TestAuditDecision_NotSampled(t *testing.T) {
+	var buf bytes.Buffer
+	defaultLogger := slog.Default()
+	slog.SetDefault(slog.New(monitoring.NewSamplingSlogHandler(
+		monitoring.LogSamplingConfig{First: 1, Interval: metav1.Duration{Duration: time.Hour}},
+		slog.NewJSONHandler(&buf, nil),
+	)))
+	defer slog.SetDefault(defaultLogger)
+
+	decision := &v1alpha1.Decision{}
+	request := mockFilterWeigherPipelineRequest{Hosts: []string{"host1"}}
+	for range 5 {
+		AuditDecision(decision, request, nil, nil)
+	}
+	if count := strings.Count(buf.String(), auditMessage); count != 5 {
+		t.Errorf("expected all 5 audit records, got %d", count)
+	}
+}
@@ -0,0 +1,137 @@
+// Copyright SAP SE
+// SPDX-License-Identifier: Apache-2.0
+
+package lib
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/cobaltcore-dev/cortex/api/v1alpha1"
+)
+
+// Weights that differ by less than this are considered equal when comparing
+// a stored decision result against its replay.
+const decisionReplayTolerance = 1e-9
+
+// Returned if a decision result doesn't contain enough information to replay it.
+var ErrDecisionNotReplayable = errors.New("decision result can't be replayed")
+
+// Recompute the output weights, host ordering and winner of a stored decision
+// result from its normalized input weights and step activations, using the
+// same math as the filter-weigher pipeline. Filter steps are recognized by
+// their missing multiplier and only narrow down the hosts, weigher steps are
+// applied with their multiplier in their stored order.
+//
+// If the stored ordering was decided by a tie breaker, it depends on state
+// outside of the decision, so the stored ordering is kept and only the
+// weights are recomputed. If the stored ordering is shorter than the
+// recomputed one, it is trimmed the same way the pipeline trims candidates.
+func ReplayDecisionResult(stored *v1alpha1.DecisionResult) (*v1alpha1.DecisionResult, error) {
+	if stored == nil {
+		return nil, fmt.Errorf("%w: no result", ErrDecisionNotReplayable)
+	}
+	outWeights := maps.Clone(stored.NormalizedInWeights)
+	if outWeights == nil {
+		outWeights = map[string]float64{}
+	}
+	activationFunction := ActivationFunction{}
+	for _, step := range stored.StepResults {
+		if step.Multiplier == nil {
+			// Filters remove all hosts they don't return.
+			maps.DeleteFunc(outWeights, func(host string, _ float64) bool {
+				_, ok := step.Activations[host]
+				return !ok
+			})
+			continue
+		}
+		// Weighers only weigh the hosts remaining after the filters.
+		outWeights = activationFunction.Apply(outWeights, step.Activations, *step.Multiplier)
+	}
+
+	hosts := sortHostsForReplay(outWeights, stored.OrderedHosts)
+	if stored.TieBreaker != "" {
+		hosts = slices.Clone(stored.OrderedHosts)
+	}
+	if len(hosts) > len(stored.OrderedHosts) {
+		for _, host := range hosts[len(stored.OrderedHosts):] {
+			delete(outWeights, host)
+		}
+		hosts = hosts[:len(stored.OrderedHosts)]
+	}
+
+	replayed := stored.DeepCopy()
+	replayed.AggregatedOutWeights = outWeights
+	replayed.OrderedHosts = hosts
+	replayed.TargetHost = nil
+	if len(hosts) > 0 {
+		replayed.TargetHost = &hosts[0]
+	}
+	return replayed, nil
+}
+
+// Describe how the replayed decision result differs from the stored one:
+// a changed winner, and hosts whose output weights changed, appeared or
+// disappeared. Returns nothing if both results agree.
+func DiffDecisionResults(stored, replayed *v1alpha1.DecisionResult) []string {
+	var diffs []string
+	storedWinner, replayedWinner := "<none>", "<none>"
+	if stored.TargetHost != nil {
+		storedWinner = *stored.TargetHost
+	}
+	if replayed.TargetHost != nil {
+		replayedWinner = *replayed.TargetHost
+	}
+	if storedWinner != replayedWinner {
+		diffs = append(diffs, fmt.Sprintf("winner changed from %s to %s", storedWinner, replayedWinner))
+	}
+	hosts := maps.Clone(stored.AggregatedOutWeights)
+	maps.Copy(hosts, replayed.AggregatedOutWeights)
+	for _, host := range slices.Sorted(maps.Keys(hosts)) {
+		before, inStored := stored.AggregatedOutWeights[host]
+		after, inReplayed := replayed.AggregatedOutWeights[host]
+		switch {
+		case !inReplayed:
+			diffs = append(diffs, fmt.Sprintf("host %s is no longer a candidate (weight was %.4f)", host, before))
+		case !inStored:
+			diffs = append(diffs, fmt.Sprintf("host %s is a new candidate (weight %.4f)", host, after))
+		case math.Abs(before-after) > decisionReplayTolerance:
+			diffs = append(diffs, fmt.Sprintf("weight of host %s changed from %.4f to %.4f", host, before, after))
+		}
+	}
+	return diffs
+}
+
+// Sort the hosts by their replayed weights. Decisions made before ties were
+// broken by host name ordered hosts with the same weight arbitrarily, so ties
+// keep their stored order instead of being reported as a changed winner.
+// Hosts that weren't stored follow the stored ones, ordered by name.
+func sortHostsForReplay(weights map[string]float64, storedOrder []string) []string {
+	position := make(map[string]int, len(storedOrder))
+	for i, host := range storedOrder {
+		position[host] = i
+	}
+	hosts := slices.Collect(maps.Keys(weights))
+	slices.SortFunc(hosts, func(a, b string) int {
+		if c := cmp.Compare(weights[b], weights[a]); c != 0 {
+			return c
+		}
+		posA, okA := position[a]
+		posB, okB := position[b]
+		switch {
+		case okA && okB:
+			return cmp.Compare(posA, posB)
+		case okA:
+			return -1
+		case okB:
+			return 1
+		}
+		return strings.Compare(a, b)
+	})
+	return hosts
+}
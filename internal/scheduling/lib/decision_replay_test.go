@@ -0,0 +1,147 @@
+// Copyright SAP SE
+// SPDX-License-Identifier: Apache-2.0
+
+package lib
+
+import (
+	"errors"
+	"log/slog"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/cobaltcore-dev/cortex/api/v1alpha1"
+)
+
+func TestReplayDecisionResult(t *testing.T) {
+	pipeline := &filterWeigherPipeline[mockFilterWeigherPipelineRequest]{
+		filters: map[string]Filter[mockFilterWeigherPipelineRequest]{
+			"mock_filter": &mockFilter[mockFilterWeigherPipelineRequest]{
+				RunFunc: func(traceLog *slog.Logger, request mockFilterWeigherPipelineRequest) (*FilterWeigherPipelineStepResult, error) {
+					// Filter out host3
+					return &FilterWeigherPipelineStepResult{
+						Activations: map[string]float64{"host1": 0.0, "host2": 0.0},
+					}, nil
+				},
+			},
+		},
+		filtersOrder: []string{"mock_filter"},
+		weighers: map[string]Weigher[mockFilterWeigherPipelineRequest]{
+			"mock_weigher": &mockWeigher[mockFilterWeigherPipelineRequest]{
+				RunFunc: func(traceLog *slog.Logger, request mockFilterWeigherPipelineRequest) (*FilterWeigherPipelineStepResult, error) {
+					return &FilterWeigherPipelineStepResult{
+						Activations: map[string]float64{"host1": 1.0, "host2": -0.5},
+					}, nil
+				},
+			},
+		},
+		weighersOrder:       []string{"mock_weigher"},
+		weighersMultipliers: map[string]float64{"mock_weigher": 2.0},
+	}
+	request := mockFilterWeigherPipelineRequest{
+		Hosts:   []string{"host1", "host2", "host3"},
+		Weights: map[string]float64{"host1": 0.1, "host2": 1.5, "host3": 3.0},
+	}
+	stored, err := pipeline.Run(t.Context(), request)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	t.Run("unchanged result", func(t *testing.T) {
+		replayed, err := ReplayDecisionResult(&stored)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if !slices.Equal(replayed.OrderedHosts, stored.OrderedHosts) {
+			t.Errorf("expected ordering %v, got %v", stored.OrderedHosts, replayed.OrderedHosts)
+		}
+		if diffs := DiffDecisionResults(&stored, replayed); len(diffs) > 0 {
+			t.Errorf("expected no diffs, got %v", diffs)
+		}
+	})
+
+	t.Run("changed multiplier", func(t *testing.T) {
+		changed := stored.DeepCopy()
+		multiplier := -2.0
+		changed.StepResults[1].Multiplier = &multiplier
+		replayed, err := ReplayDecisionResult(changed)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		diffs := DiffDecisionResults(&stored, replayed)
+		if len(diffs) != 3 {
+			t.Fatalf("expected 3 diffs, got %v", diffs)
+		}
+		if diffs[0] != "winner changed from host1 to host2" {
+			t.Errorf("expected winner diff, got %q", diffs[0])
+		}
+		for _, diff := range diffs[1:] {
+			if !strings.HasPrefix(diff, "weight of host") {
+				t.Errorf("expected weight diff, got %q", diff)
+			}
+		}
+	})
+
+	t.Run("trimmed candidates", func(t *testing.T) {
+		trimmed := stored.DeepCopy()
+		trimmed.OrderedHosts = trimmed.OrderedHosts[:1]
+		delete(trimmed.AggregatedOutWeights, "host2")
+		replayed, err := ReplayDecisionResult(trimmed)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if diffs := DiffDecisionResults(trimmed, replayed); len(diffs) > 0 {
+			t.Errorf("expected no diffs, got %v", diffs)
+		}
+	})
+
+	t.Run("tie breaker keeps stored ordering", func(t *testing.T) {
+		tied := stored.DeepCopy()
+		tied.TieBreaker = v1alpha1.TieBreakerLeastRecentlySelected
+		slices.Reverse(tied.OrderedHosts)
+		tied.TargetHost = &tied.OrderedHosts[0]
+		replayed, err := ReplayDecisionResult(tied)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if diffs := DiffDecisionResults(tied, replayed); len(diffs) > 0 {
+			t.Errorf("expected no diffs, got %v", diffs)
+		}
+	})
+
+	t.Run("no result", func(t *testing.T) {
+		if _, err := ReplayDecisionResult(nil); !errors.Is(err, ErrDecisionNotReplayable) {
+			t.Errorf("expected ErrDecisionNotReplayable, got %v", err)
+		}
+	})
+}
+
+func TestDiffDecisionResults_Candidates(t *testing.T) {
+	host1, host2 := "host1", "host2"
+	stored := &v1alpha1.DecisionResult{
+		AggregatedOutWeights: map[string]float64{"host1": 1, "host3": 0.5},
+		TargetHost:           &host1,
+	}
+	replayed := &v1alpha1.DecisionResult{
+		AggregatedOutWeights: map[string]float64{"host1": 1, "host2": 2},
+		TargetHost:           &host2,
+	}
+	expected := []string{
+		"winner changed from host1 to host2",
+		"host host2 is a new candidate (weight 2.0000)",
+		"host host3 is no longer a candidate (weight was 0.5000)",
+	}
+	if diffs := DiffDecisionResults(stored, replayed); !slices.Equal(diffs, expected) {
+		t.Errorf("expected diffs %v, got %v", expected, diffs)
+	}
+}
+
+func TestSortHostsForReplay(t *testing.T) {
+	weights := map[string]float64{"host-a": 1.0, "host-b": 2.0, "host-c": 1.0, "host-d": 1.0, "host-e": 1.0}
+	// The live pipeline ordered the tied hosts arbitrarily, host-e was not stored.
+	storedOrder := []string{"host-b", "host-d", "host-a", "host-c"}
+	expected := []string{"host-b", "host-d", "host-a", "host-c", "host-e"}
+	if hosts := sortHostsForReplay(weights, storedOrder); !slices.Equal(hosts, expected) {
+		t.Errorf("expected ordering %v, got %v", expected, hosts)
+	}
+}
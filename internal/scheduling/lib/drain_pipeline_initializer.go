@@ -0,0 +1,49 @@
+// Copyright SAP SE
+// SPDX-License-Identifier: Apache-2.0
+
+package lib
+
+import (
+	"context"
+
+	"github.com/cobaltcore-dev/cortex/api/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Initializer for drain pipelines, which can be set as the delegate of a
+// base pipeline controller.
+//
+// Drain pipelines only consist of weighers which score the given hosts by
+// how safe they are to evacuate. They reuse the filter-weigher pipeline
+// without filters, so the highest ranked host in the result is the one
+// that should be drained first.
+type DrainPipelineInitializer[RequestType FilterWeigherPipelineRequest] struct {
+	// Kubernetes client passed down to the weighers.
+	Client client.Client
+	// The weighers supported in drain pipelines by their name.
+	Weighers map[string]func() Weigher[RequestType]
+	// Monitor to pass down to all pipelines.
+	Monitor FilterWeigherPipelineMonitor
+}
+
+// Initialize a new drain pipeline with the weighers of the given pipeline.
+func (i DrainPipelineInitializer[RequestType]) InitPipeline(
+	ctx context.Context,
+	p v1alpha1.Pipeline,
+) PipelineInitResult[FilterWeigherPipeline[RequestType]] {
+
+	return InitNewFilterWeigherPipeline(
+		ctx, i.Client, p.Name,
+		map[string]func() Filter[RequestType]{}, nil,
+		i.Weighers, p.Spec.Weighers,
+		p.Spec.TieBreaker,
+		p.Spec.InputWeightNormalization,
+		false,
+		i.Monitor,
+	)
+}
+
+// The type of pipeline this initializer creates.
+func (i DrainPipelineInitializer[RequestType]) PipelineType() v1alpha1.PipelineType {
+	return v1alpha1.PipelineTypeDrain
+}
@@ -0,0 +1,84 @@
+// Copyright SAP SE
+// SPDX-License-Identifier: Apache-2.0
+
+package lib
+
+import (
+	"log/slog"
+	"slices"
+	"testing"
+
+	"github.com/cobaltcore-dev/cortex/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestDrainPipelineInitializer_InitAllPipelines(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("Failed to add v1alpha1 scheme: %v", err)
+	}
+	pipelines := []client.Object{
+		&v1alpha1.Pipeline{
+			ObjectMeta: metav1.ObjectMeta{Name: "drain-pipeline"},
+			Spec: v1alpha1.PipelineSpec{
+				SchedulingDomain: v1alpha1.SchedulingDomainNova,
+				Type:             v1alpha1.PipelineTypeDrain,
+				Weighers:         []v1alpha1.WeigherSpec{{Name: "prefer-empty"}},
+			},
+		},
+		&v1alpha1.Pipeline{
+			ObjectMeta: metav1.ObjectMeta{Name: "filter-weigher-pipeline"},
+			Spec: v1alpha1.PipelineSpec{
+				SchedulingDomain: v1alpha1.SchedulingDomainNova,
+				Type:             v1alpha1.PipelineTypeFilterWeigher,
+			},
+		},
+	}
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(pipelines...).
+		WithStatusSubresource(&v1alpha1.Pipeline{}).
+		Build()
+
+	controller := &BasePipelineController[FilterWeigherPipeline[mockFilterWeigherPipelineRequest]]{
+		Client:           fakeClient,
+		SchedulingDomain: v1alpha1.SchedulingDomainNova,
+		Initializer: DrainPipelineInitializer[mockFilterWeigherPipelineRequest]{
+			Client: fakeClient,
+			Weighers: map[string]func() Weigher[mockFilterWeigherPipelineRequest]{
+				"prefer-empty": func() Weigher[mockFilterWeigherPipelineRequest] {
+					return &mockWeigher[mockFilterWeigherPipelineRequest]{
+						RunFunc: func(_ *slog.Logger, _ mockFilterWeigherPipelineRequest) (*FilterWeigherPipelineStepResult, error) {
+							return &FilterWeigherPipelineStepResult{
+								Activations: map[string]float64{"host1": -1.0, "host2": 1.0},
+							}, nil
+						},
+					}
+				},
+			},
+		},
+	}
+	if err := controller.InitAllPipelines(t.Context()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(controller.Pipelines) != 1 {
+		t.Fatalf("expected only the drain pipeline, got %d pipelines", len(controller.Pipelines))
+	}
+	pipeline, ok := controller.Pipelines["drain-pipeline"]
+	if !ok {
+		t.Fatal("expected drain pipeline to be initialized")
+	}
+	result, err := pipeline.Run(t.Context(), mockFilterWeigherPipelineRequest{
+		Hosts:   []string{"host1", "host2"},
+		Weights: map[string]float64{"host1": 0, "host2": 0},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !slices.Equal(result.OrderedHosts, []string{"host2", "host1"}) {
+		t.Errorf("expected host2 to be drained first, got %v", result.OrderedHosts)
+	}
+}
@@ -10,4 +10,29 @@ import (
 var (
 	// This error is returned from the step at any time when the step should be skipped.
 	ErrStepSkipped = errors.New("step skipped")
+	// This error is returned from the pipeline when a score is NaN or infinite.
+	ErrNonFiniteScore = errors.New("non-finite score")
+	// This error is returned when a step returns a host that was not part of its input.
+	ErrUnexpectedHost = errors.New("unexpected host")
 )
+
+// Error returned when a decision references a pipeline that the controller
+// has not loaded, either because it doesn't exist (yet) or because it failed
+// to initialize. Callers may retry once the pipeline becomes ready.
+type PipelineNotReadyError struct {
+	// The name of the referenced pipeline.
+	Pipeline string
+}
+
+func (e *PipelineNotReadyError) Error() string {
+	return "pipeline not found or not ready: " + e.Pipeline
+}
+
+// Convert the error into the structured body returned by the scheduler APIs.
+func (e *PipelineNotReadyError) APIError() APIError {
+	return APIError{
+		Code:     "PipelineNotReady",
+		Pipeline: e.Pipeline,
+		Reason:   "pipeline not found or not ready, retry later",
+	}
+}
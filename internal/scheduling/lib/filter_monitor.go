@@ -26,9 +26,14 @@ func monitorFilter[RequestType FilterWeigherPipelineRequest](
 	m FilterWeigherPipelineMonitor,
 ) *FilterMonitor[RequestType] {
 
+	stepMonitor := monitorStep[RequestType](stepName, m)
+	if m.filterRemovedHostsCounter != nil {
+		stepMonitor.removedHostsCounter = m.filterRemovedHostsCounter.
+			WithLabelValues(string(m.SchedulingDomain), m.PipelineName, stepName)
+	}
 	return &FilterMonitor[RequestType]{
 		filter:  filter,
-		monitor: monitorStep[RequestType](stepName, m),
+		monitor: stepMonitor,
 	}
 }
 
@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/cobaltcore-dev/cortex/api/v1alpha1"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -114,3 +115,36 @@ func TestFilterMonitor_Run(t *testing.T) {
 		t.Errorf("expected 2 activations, got %d", len(result.Activations))
 	}
 }
+
+func TestFilterMonitor_Run_DomainMetrics(t *testing.T) {
+	mockFilter := &mockFilter[mockFilterWeigherPipelineRequest]{
+		RunFunc: func(traceLog *slog.Logger, request mockFilterWeigherPipelineRequest) (*FilterWeigherPipelineStepResult, error) {
+			return &FilterWeigherPipelineStepResult{
+				Activations: map[string]float64{"host1": 0.0},
+			}, nil
+		},
+	}
+	monitor := NewPipelineMonitor().
+		SubDomain(v1alpha1.SchedulingDomainNova).
+		SubPipeline("test-pipeline")
+	fm := monitorFilter(mockFilter, "test-filter", monitor)
+
+	request := mockFilterWeigherPipelineRequest{
+		Hosts:   []string{"host1", "host2", "host3"},
+		Weights: map[string]float64{"host1": 0.0, "host2": 0.0, "host3": 0.0},
+	}
+	for range 2 {
+		if _, err := fm.Run(slog.Default(), request); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+
+	removed := testutil.ToFloat64(monitor.filterRemovedHostsCounter.
+		WithLabelValues("nova", "test-pipeline", "test-filter"))
+	if removed != 4 {
+		t.Errorf("expected 4 removed hosts, got %v", removed)
+	}
+	if n := testutil.CollectAndCount(monitor.stepDomainRunTimer, "cortex_filter_weigher_pipeline_step_duration_seconds"); n != 1 {
+		t.Errorf("expected 1 step duration series, got %d", n)
+	}
+}
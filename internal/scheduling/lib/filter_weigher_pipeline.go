@@ -4,14 +4,17 @@
 package lib
 
 import (
+	"cmp"
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
 	"maps"
 	"math"
 	"slices"
-	"sort"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/cobaltcore-dev/cortex/api/v1alpha1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -20,7 +23,9 @@ import (
 type FilterWeigherPipeline[RequestType FilterWeigherPipelineRequest] interface {
 	// Run the scheduling pipeline with the given request.
 	// Call-time options are read from request.GetOptions().
-	Run(request RequestType) (v1alpha1.DecisionResult, error)
+	// If the context is done before all steps finished, the run is
+	// aborted and the context's error is returned.
+	Run(ctx context.Context, request RequestType) (v1alpha1.DecisionResult, error)
 }
 
 // Pipeline of scheduler steps.
@@ -32,12 +37,22 @@ type filterWeigherPipeline[RequestType FilterWeigherPipelineRequest] struct {
 	filtersOrder []string
 	// The filters by their name.
 	filters map[string]Filter[RequestType]
+	// Filters that don't depend on the filters before them, by their name.
+	independentFilters map[string]bool
+	// If adjacent independent filters are run concurrently.
+	parallelFilters bool
 	// The order in which weighers are applied, by their step name.
 	weighersOrder []string
 	// The weighers by their name.
 	weighers map[string]Weigher[RequestType]
 	// Multipliers to apply to weigher outputs.
 	weighersMultipliers map[string]float64
+	// Ordering to fall back to for hosts with identical weights.
+	tieBreaker v1alpha1.TieBreaker
+	// How the request's input weights are normalized before weighing.
+	inputWeightNormalization v1alpha1.InputWeightNormalization
+	// When hosts were last selected, for the least-recently-selected tie breaker.
+	selections *hostSelectionTracker
 	// Monitor to observe the pipeline.
 	monitor FilterWeigherPipelineMonitor
 }
@@ -51,14 +66,19 @@ func InitNewFilterWeigherPipeline[RequestType FilterWeigherPipelineRequest](
 	confedFilters []v1alpha1.FilterSpec,
 	supportedWeighers map[string]func() Weigher[RequestType],
 	confedWeighers []v1alpha1.WeigherSpec,
+	tieBreaker v1alpha1.TieBreaker,
+	inputWeightNormalization v1alpha1.InputWeightNormalization,
+	parallelFilters bool,
 	monitor FilterWeigherPipelineMonitor,
 ) PipelineInitResult[FilterWeigherPipeline[RequestType]] {
 
 	pipelineMonitor := monitor.SubPipeline(name)
+	stepKnowledges := make(map[string][]string)
 
 	// Load all filters from the configuration.
 	filtersByName := make(map[string]Filter[RequestType], len(confedFilters))
 	filtersOrder := []string{}
+	independentFilters := make(map[string]bool)
 	filterErrors := make(map[string]error)
 	unknownFilters := []string{}
 	for _, filterConfig := range confedFilters {
@@ -71,15 +91,23 @@ func InitNewFilterWeigherPipeline[RequestType FilterWeigherPipelineRequest](
 			continue
 		}
 		filter := makeFilter()
+		unwrapped := filter
 		filter = validateFilter(filter)
 		filter = monitorFilter(filter, filterConfig.Name, pipelineMonitor)
-		if err := filter.Init(ctx, client, filterConfig); err != nil {
+		err := filter.Init(ctx, client, filterConfig)
+		if dependent, ok := unwrapped.(KnowledgeDependent); ok && len(dependent.Knowledges()) > 0 {
+			stepKnowledges[filterConfig.Name] = dependent.Knowledges()
+		}
+		if err != nil {
 			slog.Warn("scheduler: failed to initialize filter", "name", filterConfig.Name, "error", err)
 			filterErrors[filterConfig.Name] = errors.New("failed to initialize filter: " + err.Error())
 			continue
 		}
 		filtersByName[filterConfig.Name] = filter
 		filtersOrder = append(filtersOrder, filterConfig.Name)
+		if filterConfig.Independent {
+			independentFilters[filterConfig.Name] = true
+		}
 		slog.Info("scheduler: added filter", "name", filterConfig.Name)
 	}
 
@@ -99,13 +127,25 @@ func InitNewFilterWeigherPipeline[RequestType FilterWeigherPipelineRequest](
 			continue
 		}
 		weigher := makeWeigher()
+		unwrapped := weigher
 		// Validate that the weigher doesn't unexpectedly filter out hosts.
 		weigher = validateWeigher(weigher)
 		weigher = monitorWeigher(weigher, weigherConfig.Name, pipelineMonitor)
-		if err := weigher.Init(ctx, client, weigherConfig); err != nil {
+		err := weigher.Init(ctx, client, weigherConfig)
+		if dependent, ok := unwrapped.(KnowledgeDependent); ok && len(dependent.Knowledges()) > 0 {
+			stepKnowledges[weigherConfig.Name] = dependent.Knowledges()
+		}
+		if err != nil {
 			slog.Warn("scheduler: failed to initialize weigher", "name", weigherConfig.Name, "error", err)
 			weigherErrors[weigherConfig.Name] = errors.New("failed to initialize weigher: " + err.Error())
-			continue
+			if weigherConfig.FallbackActivation == nil {
+				continue
+			}
+			// Keep the weigher in the pipeline with its fallback activation.
+			slog.Info("scheduler: using fallback activation for weigher",
+				"name", weigherConfig.Name, "activation", *weigherConfig.FallbackActivation)
+			fallback := &fallbackWeigher[RequestType]{activation: *weigherConfig.FallbackActivation}
+			weigher = monitorWeigher(fallback, weigherConfig.Name, pipelineMonitor)
 		}
 		weighersByName[weigherConfig.Name] = weigher
 		weighersOrder = append(weighersOrder, weigherConfig.Name)
@@ -122,56 +162,193 @@ func InitNewFilterWeigherPipeline[RequestType FilterWeigherPipelineRequest](
 		UnknownFilters:  unknownFilters,
 		WeigherErrors:   weigherErrors,
 		UnknownWeighers: unknownWeighers,
+		StepKnowledges:  stepKnowledges,
 		Pipeline: &filterWeigherPipeline[RequestType]{
-			filtersOrder:        filtersOrder,
-			filters:             filtersByName,
-			weighersOrder:       weighersOrder,
-			weighers:            weighersByName,
-			weighersMultipliers: weighersMultipliers,
-			monitor:             pipelineMonitor,
+			filtersOrder:             filtersOrder,
+			filters:                  filtersByName,
+			independentFilters:       independentFilters,
+			parallelFilters:          parallelFilters,
+			weighersOrder:            weighersOrder,
+			weighers:                 weighersByName,
+			weighersMultipliers:      weighersMultipliers,
+			tieBreaker:               tieBreaker,
+			inputWeightNormalization: inputWeightNormalization,
+			selections:               newHostSelectionTracker(client, name),
+			monitor:                  pipelineMonitor,
 		},
 	}
 }
 
+// Check that a step only returned hosts that were part of its input. For
+// hosts that were removed by a prior filter, the error names that filter.
+func checkStepHosts(
+	stepName string,
+	activations map[string]float64,
+	inputHosts []string,
+	removedBy map[string]string,
+) error {
+
+	input := make(map[string]struct{}, len(inputHosts))
+	for _, host := range inputHosts {
+		input[host] = struct{}{}
+	}
+	for _, host := range slices.Sorted(maps.Keys(activations)) {
+		if _, ok := input[host]; ok {
+			continue
+		}
+		if remover, ok := removedBy[host]; ok {
+			return fmt.Errorf("%w: step %s returned host %s which was removed by %s",
+				ErrUnexpectedHost, stepName, host, remover)
+		}
+		return fmt.Errorf("%w: step %s returned host %s which was never in the input",
+			ErrUnexpectedHost, stepName, host)
+	}
+	return nil
+}
+
+// Group the filters into batches that are run one after the other. If
+// parallel filters are enabled, adjacent independent filters share a batch,
+// otherwise each filter is run in its own batch.
+func (p *filterWeigherPipeline[RequestType]) filterBatches() [][]string {
+	batches := [][]string{}
+	for i, filterName := range p.filtersOrder {
+		if i > 0 && p.parallelFilters && p.independentFilters[filterName] {
+			last := batches[len(batches)-1]
+			if p.independentFilters[last[len(last)-1]] {
+				batches[len(batches)-1] = append(last, filterName)
+				continue
+			}
+		}
+		batches = append(batches, []string{filterName})
+	}
+	return batches
+}
+
+// Run a batch of filters over the same request, concurrently if the batch
+// holds more than one filter. Only results of filters that ran successfully
+// are returned, by the filter name.
+func (p *filterWeigherPipeline[RequestType]) runFilterBatch(
+	ctx context.Context,
+	log *slog.Logger,
+	batch []string,
+	request RequestType,
+) (map[string]*FilterWeigherPipelineStepResult, error) {
+
+	results := make(map[string]*FilterWeigherPipelineStepResult, len(batch))
+	var lock sync.Mutex
+	var wg sync.WaitGroup
+	for _, filterName := range batch {
+		run := func() {
+			filter := p.filters[filterName]
+			stepLog := log.With("filter", filterName)
+			stepLog.Info("scheduler: running filter")
+			result, err := runStep(ctx, filterName, filter, stepLog, request)
+			if ctx.Err() != nil {
+				stepLog.Error("scheduler: aborted while running filter", "error", ctx.Err())
+				return
+			}
+			if errors.Is(err, ErrStepSkipped) {
+				stepLog.Info("scheduler: filter skipped")
+				return
+			}
+			if err != nil {
+				stepLog.Error("scheduler: failed to run filter", "error", err)
+				return
+			}
+			lock.Lock()
+			defer lock.Unlock()
+			results[filterName] = result
+		}
+		if len(batch) == 1 {
+			run()
+			continue
+		}
+		wg.Go(run)
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("filter %s: %w", strings.Join(batch, ", "), err)
+	}
+	return results, nil
+}
+
 // Execute filters and collect their activations by step name.
 // During this process, the request is mutated to only include the
-// remaining hosts.
+// remaining hosts. The returned map tracks which filter removed each host.
+//
+// Filters that ran in the same batch all saw the same hosts. Their results
+// are merged in the configured order, as if they had run one after the
+// other: hosts already removed by a prior filter of the batch are dropped
+// from the results of the following filters.
 func (p *filterWeigherPipeline[RequestType]) runFilters(
+	ctx context.Context,
 	log *slog.Logger,
 	request RequestType,
-) (filteredRequest RequestType, stepResults []v1alpha1.StepResult) {
+) (filteredRequest RequestType, stepResults []v1alpha1.StepResult, removedBy map[string]string, err error) {
 
 	filteredRequest = request
-	for _, filterName := range p.filtersOrder {
-		filter := p.filters[filterName]
-		stepLog := log.With("filter", filterName)
-		stepLog.Info("scheduler: running filter")
-		result, err := filter.Run(stepLog, filteredRequest)
-		if errors.Is(err, ErrStepSkipped) {
-			stepLog.Info("scheduler: filter skipped")
-			continue
-		}
+	removedBy = map[string]string{}
+	for _, batch := range p.filterBatches() {
+		results, err := p.runFilterBatch(ctx, log, batch, filteredRequest)
 		if err != nil {
-			stepLog.Error("scheduler: failed to run filter", "error", err)
-			continue
+			return filteredRequest, stepResults, removedBy, err
+		}
+		for _, filterName := range batch {
+			result, ok := results[filterName]
+			if !ok {
+				continue
+			}
+			stepLog := log.With("filter", filterName)
+			for host := range result.Activations {
+				if remover, ok := removedBy[host]; ok && slices.Contains(batch, remover) {
+					delete(result.Activations, host)
+				}
+			}
+			inputHosts := filteredRequest.GetHosts()
+			if err := checkStepHosts(filterName, result.Activations, inputHosts, removedBy); err != nil {
+				stepLog.Error("scheduler: filter returned invalid hosts", "error", err)
+				continue
+			}
+			stepLog.Info("scheduler: finished filter")
+			stepResult := v1alpha1.StepResult{
+				StepName:    filterName,
+				Activations: result.Activations,
+			}
+			for _, host := range inputHosts {
+				if _, ok := result.Activations[host]; ok {
+					continue
+				}
+				removedBy[host] = filterName
+				// Only keep the reasons of hosts this filter actually removed.
+				if reason, ok := result.Reasons[host]; ok {
+					if stepResult.Reasons == nil {
+						stepResult.Reasons = make(map[string]string)
+					}
+					stepResult.Reasons[host] = reason
+				}
+			}
+			stepResults = append(stepResults, stepResult)
+			// No host can be placed on anymore, which fails the request. This
+			// usually points to a filter that is too aggressive.
+			if len(inputHosts) > 0 && len(result.Activations) == 0 {
+				stepLog.Warn("scheduler: filter removed all remaining hosts")
+				p.monitor.observeAllHostsFiltered(filterName)
+			}
+			// Mutate the request to only include the remaining hosts.
+			// Assume the resulting request type is the same as the input type.
+			filteredRequest = filteredRequest.Filter(result.Activations).(RequestType)
 		}
-		stepLog.Info("scheduler: finished filter")
-		stepResults = append(stepResults, v1alpha1.StepResult{
-			StepName:    filterName,
-			Activations: result.Activations,
-		})
-		// Mutate the request to only include the remaining hosts.
-		// Assume the resulting request type is the same as the input type.
-		filteredRequest = filteredRequest.Filter(result.Activations).(RequestType)
 	}
-	return filteredRequest, stepResults
+	return filteredRequest, stepResults, removedBy, nil
 }
 
 // Execute weighers and collect their activations by step name.
 func (p *filterWeigherPipeline[RequestType]) runWeighers(
+	ctx context.Context,
 	log *slog.Logger,
 	filteredRequest RequestType,
-) map[string]map[string]float64 {
+	removedBy map[string]string,
+) (map[string]map[string]float64, error) {
 
 	activationsByStep := map[string]map[string]float64{}
 	// Weighers can be run in parallel as they do not modify the request.
@@ -182,7 +359,11 @@ func (p *filterWeigherPipeline[RequestType]) runWeighers(
 		wg.Go(func() {
 			stepLog := log.With("weigher", weigherName)
 			stepLog.Info("scheduler: running weigher")
-			result, err := weigher.Run(stepLog, filteredRequest)
+			result, err := runStep(ctx, weigherName, weigher, stepLog, filteredRequest)
+			if ctx.Err() != nil {
+				stepLog.Error("scheduler: aborted while running weigher", "error", ctx.Err())
+				return
+			}
 			if errors.Is(err, ErrStepSkipped) {
 				stepLog.Info("scheduler: weigher skipped")
 				return
@@ -191,6 +372,11 @@ func (p *filterWeigherPipeline[RequestType]) runWeighers(
 				stepLog.Error("scheduler: failed to run weigher", "error", err)
 				return
 			}
+			// Weights for hosts outside of the input are ignored when applying
+			// the weights, so only report where these hosts went missing.
+			if err := checkStepHosts(weigherName, result.Activations, filteredRequest.GetHosts(), removedBy); err != nil {
+				stepLog.Warn("scheduler: weigher returned hosts outside of its input", "error", err)
+			}
 			stepLog.Info("scheduler: finished weigher")
 			lock.Lock()
 			defer lock.Unlock()
@@ -198,7 +384,82 @@ func (p *filterWeigherPipeline[RequestType]) runWeighers(
 		})
 	}
 	wg.Wait()
-	return activationsByStep
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("weighers: %w", err)
+	}
+	return activationsByStep, nil
+}
+
+// Run the step, but stop waiting for it once the context's deadline passes.
+// Steps don't take a context themselves, so a hung step can't be interrupted.
+// It is left running in the background and its result is discarded, so that
+// the scheduling request isn't blocked by it. Each such step keeps holding
+// its goroutine until it returns, which is why steps are only run in a
+// separate goroutine if a deadline is set, i.e. a request timeout is
+// configured.
+func runStep[RequestType FilterWeigherPipelineRequest](
+	ctx context.Context,
+	stepName string,
+	step FilterWeigherPipelineStep[RequestType],
+	traceLog *slog.Logger,
+	request RequestType,
+) (result *FilterWeigherPipelineStepResult, err error) {
+
+	_, span := StartSpan(ctx, "scheduling step", slog.String("step", stepName))
+	defer func() { span.End(err) }()
+	// Without deadline, run the step inline. Cancellation alone, such as
+	// a client closing the connection, is noticed once the step returns.
+	if _, ok := ctx.Deadline(); !ok {
+		return step.Run(traceLog, request)
+	}
+	type outcome struct {
+		result *FilterWeigherPipelineStepResult
+		err    error
+	}
+	// Buffered so the step can finish even if nobody waits for it anymore.
+	done := make(chan outcome, 1)
+	go func() {
+		result, err := step.Run(traceLog, request)
+		done <- outcome{result, err}
+	}()
+	select {
+	case o := <-done:
+		return o.result, o.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Find the first NaN or infinite score and the step that produced it.
+// Returns the step "unknown" if only the aggregated weights are affected.
+func (p *filterWeigherPipeline[RequestType]) findNonFiniteScore(
+	rawInWeights map[string]float64,
+	activationsByStep map[string]map[string]float64,
+	outWeights map[string]float64,
+) (step, host string, ok bool) {
+
+	if host, ok := firstNonFinite(rawInWeights); ok {
+		return "input weights", host, true
+	}
+	for _, weigherName := range p.weighersOrder {
+		if host, ok := firstNonFinite(activationsByStep[weigherName]); ok {
+			return weigherName, host, true
+		}
+	}
+	if host, ok := firstNonFinite(outWeights); ok {
+		return "unknown", host, true
+	}
+	return "", "", false
+}
+
+// Get the (alphabetically) first host with a NaN or infinite score.
+func firstNonFinite(weights map[string]float64) (string, bool) {
+	for _, host := range slices.Sorted(maps.Keys(weights)) {
+		if math.IsNaN(weights[host]) || math.IsInf(weights[host], 0) {
+			return host, true
+		}
+	}
+	return "", false
 }
 
 // Apply an initial weight to the hosts.
@@ -207,13 +468,10 @@ func (p *filterWeigherPipeline[RequestType]) runWeighers(
 // Openstack schedulers may give us very large (positive/negative) weights such as
 // -99,000 or 99,000 (Nova). We want to respect these values, but still adjust them
 // to a meaningful value. If the scheduler really doesn't want us to run on a host, it
-// should run a filter instead of setting a weight.
+// should run a filter instead of setting a weight. By default, the weights are
+// squashed using tanh, but the pipeline can configure another normalization.
 func (p *filterWeigherPipeline[RequestType]) normalizeInputWeights(weights map[string]float64) map[string]float64 {
-	normalizedWeights := make(map[string]float64, len(weights))
-	for hostname, weight := range weights {
-		normalizedWeights[hostname] = math.Tanh(weight)
-	}
-	return normalizedWeights
+	return normalizeWeights(p.inputWeightNormalization, weights)
 }
 
 // Apply the step weights to the input weights.
@@ -252,18 +510,29 @@ func (p *filterWeigherPipeline[RequestType]) applyWeights(
 	return outWeights
 }
 
-// Sort the hosts by their weights.
+// Sort the hosts by their weights. Hosts with the same weight are sorted by
+// name, so identical runs always produce the same ordering.
 func (s *filterWeigherPipeline[RequestType]) sortHostsByWeights(weights map[string]float64) []string {
 	// Sort the hosts (keys) by their weights.
 	hosts := slices.Collect(maps.Keys(weights))
-	sort.Slice(hosts, func(i, j int) bool {
-		return weights[hosts[i]] > weights[hosts[j]]
+	slices.SortFunc(hosts, func(a, b string) int {
+		if c := cmp.Compare(weights[b], weights[a]); c != 0 {
+			return c
+		}
+		return strings.Compare(a, b)
 	})
 	return hosts
 }
 
 // Evaluate the pipeline and return a list of hosts in order of preference.
-func (p *filterWeigherPipeline[RequestType]) Run(request RequestType) (v1alpha1.DecisionResult, error) {
+func (p *filterWeigherPipeline[RequestType]) Run(ctx context.Context, request RequestType) (v1alpha1.DecisionResult, error) {
+	ctx, span := StartSpan(ctx, "scheduling pipeline", request.GetTraceLogArgs()...)
+	result, err := p.run(ctx, request)
+	span.End(err)
+	return result, err
+}
+
+func (p *filterWeigherPipeline[RequestType]) run(ctx context.Context, request RequestType) (v1alpha1.DecisionResult, error) {
 	opts := request.GetOptions()
 	if err := opts.Validate(); err != nil {
 		return v1alpha1.DecisionResult{}, err
@@ -278,37 +547,72 @@ func (p *filterWeigherPipeline[RequestType]) Run(request RequestType) (v1alpha1.
 	hostsIn := request.GetHosts()
 	traceLog.Info("scheduler: starting pipeline", "hosts", hostsIn)
 
+	// Run filters first to reduce the number of hosts.
+	// Any weights assigned to filtered out hosts are ignored.
+	filteredRequest, filterStepResults, removedBy, err := p.runFilters(ctx, traceLog, request)
+	if err != nil {
+		return v1alpha1.DecisionResult{}, err
+	}
+	traceLog.Info(
+		"scheduler: finished filters",
+		"remainingHosts", filteredRequest.GetHosts(),
+	)
+
 	// Normalize the input weights so we can apply step weights meaningfully.
-	// Only do this if there are weighers to combine with: tanh saturates large
+	// Only the weights of the hosts remaining after the filters are normalized,
+	// so that filtered out outliers don't shift the range of the other hosts.
+	remainingRawWeights := make(map[string]float64, len(filteredRequest.GetHosts()))
+	for _, host := range filteredRequest.GetHosts() {
+		remainingRawWeights[host] = request.GetWeights()[host]
+	}
+	// Only do this if there are weighers to combine with: e.g. tanh saturates large
 	// inputs (e.g. Nova's 50/55/60) to ~1.0, which would destroy the original
 	// ordering. With no weighers configured, the normalized map flows straight
 	// to the sort, so we must keep the raw values to preserve that ordering.
 	var inWeights map[string]float64
 	if len(p.weighers) > 0 {
-		inWeights = p.normalizeInputWeights(request.GetWeights())
+		inWeights = p.normalizeInputWeights(remainingRawWeights)
 	} else {
-		inWeights = maps.Clone(request.GetWeights())
+		inWeights = maps.Clone(remainingRawWeights)
 	}
 	traceLog.Info("scheduler: input weights", "weights", inWeights)
 
-	// Run filters first to reduce the number of hosts.
-	// Any weights assigned to filtered out hosts are ignored.
-	filteredRequest, filterStepResults := p.runFilters(traceLog, request)
-	traceLog.Info(
-		"scheduler: finished filters",
-		"remainingHosts", filteredRequest.GetHosts(),
-	)
-
 	// Run weighers on the filtered hosts.
-	remainingWeights := make(map[string]float64, len(filteredRequest.GetHosts()))
-	for _, host := range filteredRequest.GetHosts() {
-		remainingWeights[host] = inWeights[host]
+	stepWeights, err := p.runWeighers(ctx, traceLog, filteredRequest, removedBy)
+	if err != nil {
+		return v1alpha1.DecisionResult{}, err
 	}
-	stepWeights := p.runWeighers(traceLog, filteredRequest)
-	outWeights := p.applyWeights(traceLog, stepWeights, remainingWeights)
+	outWeights := p.applyWeights(traceLog, stepWeights, inWeights)
 	traceLog.Info("scheduler: output weights", "weights", outWeights)
 
+	// NaN or infinite scores make the ordering of hosts meaningless, since
+	// such values can't be compared. Fail loudly instead of returning an
+	// arbitrary (or empty) ordering. Scores of hosts removed by the filters
+	// don't take part in the ordering, so they are not checked.
+	if step, host, ok := p.findNonFiniteScore(remainingRawWeights, stepWeights, outWeights); ok {
+		p.monitor.observeInvalidScores(step)
+		traceLog.Error("scheduler: non-finite score detected", "step", step, "host", host)
+		return v1alpha1.DecisionResult{}, fmt.Errorf(
+			"%w: step %s produced a non-finite score for host %s",
+			ErrNonFiniteScore, step, host,
+		)
+	}
+
 	hosts := p.sortHostsByWeights(outWeights)
+	var usedTieBreaker v1alpha1.TieBreaker
+	if p.tieBreaker != "" {
+		// Only look up the last selections if they are needed to break a tie.
+		var lastSelected map[string]time.Time
+		if p.tieBreaker == v1alpha1.TieBreakerLeastRecentlySelected && hasTiedHosts(hosts, outWeights) {
+			lastSelected = p.selections.lastSelections(ctx)
+		}
+		ordered, tied := orderTiedHosts(p.tieBreaker, hosts, outWeights, request.GetWeights(), lastSelected)
+		if tied {
+			hosts = ordered
+			usedTieBreaker = p.tieBreaker
+			traceLog.Info("scheduler: hosts tied on their weights, using fallback ordering", "tieBreaker", p.tieBreaker)
+		}
+	}
 	traceLog.Info("scheduler: sorted hosts", "hosts", hosts)
 
 	if opts.MaxCandidates > 0 && len(hosts) > opts.MaxCandidates {
@@ -330,6 +634,12 @@ func (p *filterWeigherPipeline[RequestType]) Run(request RequestType) (v1alpha1.
 		}
 	}
 
+	// Remember the selected host so that least recently selected
+	// hosts are preferred the next time hosts are tied.
+	if p.tieBreaker == v1alpha1.TieBreakerLeastRecentlySelected && len(hosts) > 0 && !opts.ReadOnly {
+		p.selections.recordSelection(hosts[0], time.Now())
+	}
+
 	// Collect some metrics about the pipeline execution.
 	go p.monitor.observePipelineResult(request, hosts)
 
@@ -340,9 +650,14 @@ func (p *filterWeigherPipeline[RequestType]) Run(request RequestType) (v1alpha1.
 		if !ok {
 			continue
 		}
+		multiplier, ok := p.weighersMultipliers[weigherName]
+		if !ok {
+			multiplier = 1.0
+		}
 		stepResults = append(stepResults, v1alpha1.StepResult{
 			StepName:    weigherName,
 			Activations: activations,
+			Multiplier:  &multiplier,
 		})
 	}
 
@@ -352,6 +667,7 @@ func (p *filterWeigherPipeline[RequestType]) Run(request RequestType) (v1alpha1.
 		StepResults:          stepResults,
 		AggregatedOutWeights: outWeights,
 		OrderedHosts:         hosts,
+		TieBreaker:           usedTieBreaker,
 	}
 	if len(hosts) > 0 {
 		result.TargetHost = &hosts[0]
@@ -4,6 +4,7 @@
 package lib
 
 import (
+	"github.com/cobaltcore-dev/cortex/api/v1alpha1"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
@@ -11,13 +12,19 @@ import (
 type FilterWeigherPipelineMonitor struct {
 	// The pipeline name is used to differentiate between different pipelines.
 	PipelineName string
+	// The scheduling domain of the pipelines monitored.
+	SchedulingDomain v1alpha1.SchedulingDomain
 
 	// A histogram to measure how long each step takes to run.
 	stepRunTimer *prometheus.HistogramVec
+	// A histogram to measure how long each step takes to run per domain.
+	stepDomainRunTimer *prometheus.HistogramVec
 	// A metric to monitor how much the step modifies the weights of the hosts.
 	stepHostWeight *prometheus.GaugeVec
 	// A histogram to observe how many hosts are removed from the state.
 	stepRemovedHostsObserver *prometheus.HistogramVec
+	// Counter for the number of hosts removed by each filter.
+	filterRemovedHostsCounter *prometheus.CounterVec
 	// Histogram measuring where the host at a given index came from originally.
 	stepReorderingsObserver *prometheus.HistogramVec
 	// A histogram to observe the impact of the step on the hosts.
@@ -30,6 +37,18 @@ type FilterWeigherPipelineMonitor struct {
 	hostNumberOutObserver *prometheus.HistogramVec
 	// Counter for the number of requests processed by the scheduler.
 	requestCounter *prometheus.CounterVec
+	// Counter for pipeline runs rejected due to NaN or infinite scores.
+	invalidScoresCounter *prometheus.CounterVec
+	// Counter for pipeline runs in which a filter removed all remaining hosts.
+	allHostsFilteredCounter *prometheus.CounterVec
+	// Counter for the scheduling decisions requested through the api.
+	decisionRequestsCounter *prometheus.CounterVec
+	// Counter for the scheduling decisions that were created successfully.
+	decisionsCreatedCounter *prometheus.CounterVec
+	// Counter for the scheduling decisions that failed.
+	decisionErrorsCounter *prometheus.CounterVec
+	// Gauge for the number of pipelines that are ready to serve decisions.
+	readyPipelinesGauge *prometheus.GaugeVec
 }
 
 // Create a new scheduler monitor and register the necessary Prometheus metrics.
@@ -49,6 +68,11 @@ func NewPipelineMonitor() FilterWeigherPipelineMonitor {
 			Help:    "Duration of scheduler pipeline step run",
 			Buckets: prometheus.DefBuckets,
 		}, []string{"pipeline", "step"}),
+		stepDomainRunTimer: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "cortex_filter_weigher_pipeline_step_duration_seconds",
+			Help:    "Duration of scheduler pipeline step run per scheduling domain",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"domain", "step"}),
 		stepHostWeight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
 			Name: "cortex_filter_weigher_pipeline_step_weight_modification",
 			Help: "Modification of host weight by scheduler pipeline step",
@@ -58,6 +82,10 @@ func NewPipelineMonitor() FilterWeigherPipelineMonitor {
 			Help:    "Number of hosts removed by scheduler pipeline step",
 			Buckets: prometheus.ExponentialBucketsRange(1, 1000, 10),
 		}, []string{"pipeline", "step"}),
+		filterRemovedHostsCounter: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "cortex_filter_weigher_pipeline_filter_removed_hosts_total",
+			Help: "Total number of hosts removed by scheduler pipeline filter",
+		}, []string{"domain", "pipeline", "step"}),
 		stepReorderingsObserver: stepReorderingsObserver,
 		stepImpactObserver: prometheus.NewHistogramVec(prometheus.HistogramOpts{
 			Name:    "cortex_filter_weigher_pipeline_step_impact",
@@ -83,6 +111,30 @@ func NewPipelineMonitor() FilterWeigherPipelineMonitor {
 			Name: "cortex_filter_weigher_pipeline_requests_total",
 			Help: "Total number of requests processed by the scheduler.",
 		}, []string{"pipeline"}),
+		invalidScoresCounter: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "cortex_filter_weigher_pipeline_invalid_scores_total",
+			Help: "Total number of pipeline runs rejected due to NaN or infinite scores.",
+		}, []string{"pipeline", "step"}),
+		allHostsFilteredCounter: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "cortex_filter_weigher_pipeline_all_hosts_filtered_total",
+			Help: "Total number of pipeline runs in which a filter removed all remaining hosts.",
+		}, []string{"domain", "pipeline", "step"}),
+		decisionRequestsCounter: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "cortex_filter_weigher_pipeline_decision_requests_total",
+			Help: "Total number of scheduling decisions requested through the api.",
+		}, []string{"domain", "pipeline"}),
+		decisionsCreatedCounter: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "cortex_filter_weigher_pipeline_decisions_created_total",
+			Help: "Total number of scheduling decisions created successfully.",
+		}, []string{"domain", "pipeline"}),
+		decisionErrorsCounter: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "cortex_filter_weigher_pipeline_decision_errors_total",
+			Help: "Total number of scheduling decisions that failed.",
+		}, []string{"domain", "pipeline"}),
+		readyPipelinesGauge: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "cortex_filter_weigher_pipeline_ready_pipelines",
+			Help: "Number of pipelines that are ready to serve scheduling decisions.",
+		}, []string{"domain"}),
 	}
 }
 
@@ -93,6 +145,13 @@ func (m FilterWeigherPipelineMonitor) SubPipeline(name string) FilterWeigherPipe
 	return cp
 }
 
+// Get a copied pipeline monitor with the scheduling domain set.
+func (m FilterWeigherPipelineMonitor) SubDomain(domain v1alpha1.SchedulingDomain) FilterWeigherPipelineMonitor {
+	cp := m
+	cp.SchedulingDomain = domain
+	return cp
+}
+
 // Observe a scheduler pipeline result: hosts going in, and hosts going out.
 func (m *FilterWeigherPipelineMonitor) observePipelineResult(request FilterWeigherPipelineRequest, result []string) {
 	// Observe the number of hosts going into the scheduler pipeline.
@@ -115,26 +174,92 @@ func (m *FilterWeigherPipelineMonitor) observePipelineResult(request FilterWeigh
 	}
 }
 
+// Observe a pipeline run that was rejected because of NaN or infinite scores.
+func (m *FilterWeigherPipelineMonitor) observeInvalidScores(step string) {
+	if m.invalidScoresCounter != nil {
+		m.invalidScoresCounter.
+			WithLabelValues(m.PipelineName, step).
+			Inc()
+	}
+}
+
+// Observe a pipeline run in which the given filter removed all remaining hosts.
+func (m *FilterWeigherPipelineMonitor) observeAllHostsFiltered(step string) {
+	if m.allHostsFilteredCounter != nil {
+		m.allHostsFilteredCounter.
+			WithLabelValues(string(m.SchedulingDomain), m.PipelineName, step).
+			Inc()
+	}
+}
+
+// Pipeline label of decisions requested for a pipeline that isn't
+// configured, so that arbitrary names from requests don't create new series.
+const UnknownPipelineLabel = "unknown"
+
+// Observe a scheduling decision requested through the api, or for a new
+// machine or pod, for the given pipeline, and whether it failed. Callers
+// should pass UnknownPipelineLabel for pipelines that aren't configured.
+func (m *FilterWeigherPipelineMonitor) ObserveDecision(pipeline string, err error) {
+	domain := string(m.SchedulingDomain)
+	if m.decisionRequestsCounter != nil {
+		m.decisionRequestsCounter.WithLabelValues(domain, pipeline).Inc()
+	}
+	if err != nil {
+		if m.decisionErrorsCounter != nil {
+			m.decisionErrorsCounter.WithLabelValues(domain, pipeline).Inc()
+		}
+		return
+	}
+	if m.decisionsCreatedCounter != nil {
+		m.decisionsCreatedCounter.WithLabelValues(domain, pipeline).Inc()
+	}
+}
+
+// Observe the number of pipelines that are currently ready.
+func (m *FilterWeigherPipelineMonitor) ObserveReadyPipelines(count int) {
+	if m.readyPipelinesGauge != nil {
+		m.readyPipelinesGauge.
+			WithLabelValues(string(m.SchedulingDomain)).
+			Set(float64(count))
+	}
+}
+
 func (m *FilterWeigherPipelineMonitor) Describe(ch chan<- *prometheus.Desc) {
 	m.stepRunTimer.Describe(ch)
+	m.stepDomainRunTimer.Describe(ch)
 	m.stepHostWeight.Describe(ch)
 	m.stepRemovedHostsObserver.Describe(ch)
+	m.filterRemovedHostsCounter.Describe(ch)
 	m.stepReorderingsObserver.Describe(ch)
 	m.stepImpactObserver.Describe(ch)
 	m.pipelineRunTimer.Describe(ch)
 	m.hostNumberInObserver.Describe(ch)
 	m.hostNumberOutObserver.Describe(ch)
 	m.requestCounter.Describe(ch)
+	m.invalidScoresCounter.Describe(ch)
+	m.allHostsFilteredCounter.Describe(ch)
+	m.decisionRequestsCounter.Describe(ch)
+	m.decisionsCreatedCounter.Describe(ch)
+	m.decisionErrorsCounter.Describe(ch)
+	m.readyPipelinesGauge.Describe(ch)
 }
 
 func (m *FilterWeigherPipelineMonitor) Collect(ch chan<- prometheus.Metric) {
 	m.stepRunTimer.Collect(ch)
+	m.stepDomainRunTimer.Collect(ch)
 	m.stepHostWeight.Collect(ch)
 	m.stepRemovedHostsObserver.Collect(ch)
+	m.filterRemovedHostsCounter.Collect(ch)
 	m.stepReorderingsObserver.Collect(ch)
 	m.stepImpactObserver.Collect(ch)
 	m.pipelineRunTimer.Collect(ch)
 	m.hostNumberInObserver.Collect(ch)
 	m.hostNumberOutObserver.Collect(ch)
 	m.requestCounter.Collect(ch)
+	m.invalidScoresCounter.Collect(ch)
+	m.allHostsFilteredCounter.Collect(ch)
+	m.decisionRequestsCounter.Collect(ch)
+	m.decisionsCreatedCounter.Collect(ch)
+	m.decisionErrorsCounter.Collect(ch)
+	m.readyPipelinesGauge.Collect(ch)
 }
@@ -4,9 +4,11 @@
 package lib
 
 import (
+	"errors"
 	"strings"
 	"testing"
 
+	"github.com/cobaltcore-dev/cortex/api/v1alpha1"
 	"github.com/cobaltcore-dev/cortex/pkg/monitoring"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/testutil"
@@ -115,3 +117,43 @@ func TestSchedulerMonitor(t *testing.T) {
 		t.Fatalf("requestCounter test failed: %v", err)
 	}
 }
+
+func TestSchedulerMonitor_ObserveDecision(t *testing.T) {
+	registry := &monitoring.Registry{RegistererGatherer: prometheus.NewRegistry()}
+	monitor := NewPipelineMonitor().SubDomain(v1alpha1.SchedulingDomainNova)
+	registry.MustRegister(&monitor)
+
+	monitor.ObserveDecision("test", nil)
+	monitor.ObserveDecision("test", nil)
+	monitor.ObserveDecision("test", errors.New("pipeline not ready"))
+	monitor.ObserveReadyPipelines(2)
+
+	expected := strings.NewReader(`
+        # HELP cortex_filter_weigher_pipeline_decision_errors_total Total number of scheduling decisions that failed.
+        # TYPE cortex_filter_weigher_pipeline_decision_errors_total counter
+        cortex_filter_weigher_pipeline_decision_errors_total{domain="nova",pipeline="test"} 1
+        # HELP cortex_filter_weigher_pipeline_decision_requests_total Total number of scheduling decisions requested through the api.
+        # TYPE cortex_filter_weigher_pipeline_decision_requests_total counter
+        cortex_filter_weigher_pipeline_decision_requests_total{domain="nova",pipeline="test"} 3
+        # HELP cortex_filter_weigher_pipeline_decisions_created_total Total number of scheduling decisions created successfully.
+        # TYPE cortex_filter_weigher_pipeline_decisions_created_total counter
+        cortex_filter_weigher_pipeline_decisions_created_total{domain="nova",pipeline="test"} 2
+        # HELP cortex_filter_weigher_pipeline_ready_pipelines Number of pipelines that are ready to serve scheduling decisions.
+        # TYPE cortex_filter_weigher_pipeline_ready_pipelines gauge
+        cortex_filter_weigher_pipeline_ready_pipelines{domain="nova"} 2
+    `)
+	err := testutil.GatherAndCompare(registry, expected,
+		"cortex_filter_weigher_pipeline_decision_errors_total",
+		"cortex_filter_weigher_pipeline_decision_requests_total",
+		"cortex_filter_weigher_pipeline_decisions_created_total",
+		"cortex_filter_weigher_pipeline_ready_pipelines",
+	)
+	if err != nil {
+		t.Fatalf("decision metrics test failed: %v", err)
+	}
+
+	// A monitor without metrics, e.g. in tests, must not panic.
+	empty := FilterWeigherPipelineMonitor{}
+	empty.ObserveDecision("test", nil)
+	empty.ObserveReadyPipelines(1)
+}
@@ -0,0 +1,60 @@
+// Copyright SAP SE
+// SPDX-License-Identifier: Apache-2.0
+
+package lib
+
+import (
+	"maps"
+	"math"
+
+	"github.com/cobaltcore-dev/cortex/api/v1alpha1"
+)
+
+// Normalize the input weights using the given strategy. Without a strategy,
+// tanh is used. If all weights are equal, min-max and z-score normalization
+// map them to zero, since the input doesn't prefer any host over another.
+func normalizeWeights(
+	strategy v1alpha1.InputWeightNormalization,
+	weights map[string]float64,
+) map[string]float64 {
+
+	normalized := make(map[string]float64, len(weights))
+	switch strategy {
+	case v1alpha1.InputWeightNormalizationNone:
+		maps.Copy(normalized, weights)
+	case v1alpha1.InputWeightNormalizationMinMax:
+		lowest, highest := math.Inf(1), math.Inf(-1)
+		for _, weight := range weights {
+			lowest, highest = math.Min(lowest, weight), math.Max(highest, weight)
+		}
+		for host, weight := range weights {
+			if highest == lowest {
+				normalized[host] = 0
+				continue
+			}
+			normalized[host] = (weight - lowest) / (highest - lowest)
+		}
+	case v1alpha1.InputWeightNormalizationZScore:
+		var mean float64
+		for _, weight := range weights {
+			mean += weight / float64(len(weights))
+		}
+		var variance float64
+		for _, weight := range weights {
+			variance += (weight - mean) * (weight - mean) / float64(len(weights))
+		}
+		stddev := math.Sqrt(variance)
+		for host, weight := range weights {
+			if stddev == 0 {
+				normalized[host] = 0
+				continue
+			}
+			normalized[host] = (weight - mean) / stddev
+		}
+	default:
+		for host, weight := range weights {
+			normalized[host] = math.Tanh(weight)
+		}
+	}
+	return normalized
+}
@@ -0,0 +1,120 @@
+// Copyright SAP SE
+// SPDX-License-Identifier: Apache-2.0
+
+package lib
+
+import (
+	"log/slog"
+	"maps"
+	"math"
+	"testing"
+
+	"github.com/cobaltcore-dev/cortex/api/v1alpha1"
+)
+
+func TestNormalizeWeights(t *testing.T) {
+	weights := map[string]float64{"host1": 50, "host2": 55, "host3": 60}
+	tests := []struct {
+		name     string
+		strategy v1alpha1.InputWeightNormalization
+		weights  map[string]float64
+		expected map[string]float64
+	}{
+		{
+			name:     "default is tanh",
+			strategy: "",
+			weights:  map[string]float64{"host1": 1000, "host2": -1000, "host3": 0},
+			expected: map[string]float64{"host1": 1, "host2": -1, "host3": 0},
+		},
+		{
+			name:     "tanh saturates large weights",
+			strategy: v1alpha1.InputWeightNormalizationTanh,
+			weights:  weights,
+			expected: map[string]float64{"host1": 1, "host2": 1, "host3": 1},
+		},
+		{
+			name:     "none keeps weights",
+			strategy: v1alpha1.InputWeightNormalizationNone,
+			weights:  weights,
+			expected: weights,
+		},
+		{
+			name:     "min-max scales to unit interval",
+			strategy: v1alpha1.InputWeightNormalizationMinMax,
+			weights:  weights,
+			expected: map[string]float64{"host1": 0, "host2": 0.5, "host3": 1},
+		},
+		{
+			name:     "min-max with equal weights",
+			strategy: v1alpha1.InputWeightNormalizationMinMax,
+			weights:  map[string]float64{"host1": 3, "host2": 3},
+			expected: map[string]float64{"host1": 0, "host2": 0},
+		},
+		{
+			name:     "z-score centers around the mean",
+			strategy: v1alpha1.InputWeightNormalizationZScore,
+			weights:  weights,
+			expected: map[string]float64{"host1": -math.Sqrt(1.5), "host2": 0, "host3": math.Sqrt(1.5)},
+		},
+		{
+			name:     "z-score with equal weights",
+			strategy: v1alpha1.InputWeightNormalizationZScore,
+			weights:  map[string]float64{"host1": 3, "host2": 3},
+			expected: map[string]float64{"host1": 0, "host2": 0},
+		},
+		{
+			name:     "no weights",
+			strategy: v1alpha1.InputWeightNormalizationZScore,
+			weights:  map[string]float64{},
+			expected: map[string]float64{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := normalizeWeights(tt.strategy, tt.weights)
+			if len(result) != len(tt.expected) {
+				t.Fatalf("expected %d weights, got %d", len(tt.expected), len(result))
+			}
+			for host, expected := range tt.expected {
+				if math.Abs(result[host]-expected) > 1e-9 {
+					t.Errorf("expected weight %f for host %s, got %f", expected, host, result[host])
+				}
+			}
+		})
+	}
+}
+
+func TestPipeline_NormalizesRemainingHosts(t *testing.T) {
+	pipeline := &filterWeigherPipeline[mockFilterWeigherPipelineRequest]{
+		filters: map[string]Filter[mockFilterWeigherPipelineRequest]{
+			"mock_filter": &mockFilter[mockFilterWeigherPipelineRequest]{
+				RunFunc: func(traceLog *slog.Logger, request mockFilterWeigherPipelineRequest) (*FilterWeigherPipelineStepResult, error) {
+					return &FilterWeigherPipelineStepResult{Activations: map[string]float64{"host1": 0, "host2": 0}}, nil
+				},
+			},
+		},
+		filtersOrder: []string{"mock_filter"},
+		weighers: map[string]Weigher[mockFilterWeigherPipelineRequest]{
+			"mock_weigher": &mockWeigher[mockFilterWeigherPipelineRequest]{
+				RunFunc: func(traceLog *slog.Logger, request mockFilterWeigherPipelineRequest) (*FilterWeigherPipelineStepResult, error) {
+					return &FilterWeigherPipelineStepResult{Activations: map[string]float64{"host1": 0, "host2": 0}}, nil
+				},
+			},
+		},
+		weighersOrder:            []string{"mock_weigher"},
+		inputWeightNormalization: v1alpha1.InputWeightNormalizationMinMax,
+		monitor:                  NewPipelineMonitor().SubPipeline("test"),
+	}
+	// The filtered out outlier doesn't compress the range of the other hosts.
+	result, err := pipeline.Run(t.Context(), mockFilterWeigherPipelineRequest{
+		Hosts:   []string{"host1", "host2", "host3"},
+		Weights: map[string]float64{"host1": 1, "host2": 2, "host3": 1000},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	expected := map[string]float64{"host1": 0, "host2": 1}
+	if !maps.Equal(result.NormalizedInWeights, expected) {
+		t.Errorf("expected normalized weights %v, got %v", expected, result.NormalizedInWeights)
+	}
+}
@@ -44,6 +44,14 @@ type BaseFilterWeigherPipelineStep[RequestType FilterWeigherPipelineRequest, Opt
 	ActivationFunction
 	// The kubernetes client to use.
 	Client client.Client
+	// Names of the knowledges checked by this step, in order of first check.
+	knowledges []string
+}
+
+// Get the names of the knowledges this step depends on, as declared through
+// CheckKnowledges during its initialization.
+func (s *BaseFilterWeigherPipelineStep[RequestType, Opts]) Knowledges() []string {
+	return s.knowledges
 }
 
 // Init the step with the database and options.
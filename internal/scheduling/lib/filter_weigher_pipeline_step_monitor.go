@@ -28,10 +28,14 @@ type FilterWeigherPipelineStepMonitor[RequestType FilterWeigherPipelineRequest]
 
 	// A timer to measure how long the step takes to run.
 	runTimer prometheus.Observer
+	// A timer to measure how long the step takes to run per domain.
+	domainRunTimer prometheus.Observer
 	// A metric to monitor how much the step modifies the weights of the hosts.
 	stepHostWeight *prometheus.GaugeVec
 	// A metric to observe how many hosts are removed from the state.
 	removedHostsObserver prometheus.Observer
+	// A counter for the hosts removed by the step, only set for filters.
+	removedHostsCounter prometheus.Counter
 	// A metric measuring where the host at a given index came from originally.
 	stepReorderingsObserver *prometheus.HistogramVec
 	// A metric measuring the impact of the step on the hosts.
@@ -45,6 +49,11 @@ func monitorStep[RequestType FilterWeigherPipelineRequest](stepName string, m Fi
 		runTimer = m.stepRunTimer.
 			WithLabelValues(m.PipelineName, stepName)
 	}
+	var domainRunTimer prometheus.Observer
+	if m.stepDomainRunTimer != nil {
+		domainRunTimer = m.stepDomainRunTimer.
+			WithLabelValues(string(m.SchedulingDomain), stepName)
+	}
 	var removedHostsObserver prometheus.Observer
 	if m.stepRemovedHostsObserver != nil {
 		removedHostsObserver = m.stepRemovedHostsObserver.
@@ -52,6 +61,7 @@ func monitorStep[RequestType FilterWeigherPipelineRequest](stepName string, m Fi
 	}
 	return &FilterWeigherPipelineStepMonitor[RequestType]{
 		runTimer:                runTimer,
+		domainRunTimer:          domainRunTimer,
 		stepName:                stepName,
 		pipelineName:            m.PipelineName,
 		stepHostWeight:          m.stepHostWeight,
@@ -72,6 +82,10 @@ func (s *FilterWeigherPipelineStepMonitor[RequestType]) RunWrapped(
 		timer := prometheus.NewTimer(s.runTimer)
 		defer timer.ObserveDuration()
 	}
+	if s.domainRunTimer != nil {
+		timer := prometheus.NewTimer(s.domainRunTimer)
+		defer timer.ObserveDuration()
+	}
 
 	inWeights := request.GetWeights()
 	stepResult, err := step.Run(traceLog, request)
@@ -111,6 +125,9 @@ func (s *FilterWeigherPipelineStepMonitor[RequestType]) RunWrapped(
 	if s.removedHostsObserver != nil {
 		s.removedHostsObserver.Observe(float64(nHostsRemoved))
 	}
+	if s.removedHostsCounter != nil && nHostsRemoved > 0 {
+		s.removedHostsCounter.Add(float64(nHostsRemoved))
+	}
 
 	// Calculate additional metrics to see which hosts were reordered and how far.
 	sort.Slice(hostsIn, func(i, j int) bool {
@@ -23,6 +23,21 @@ type FilterWeigherPipelineStepResult struct {
 	// These statistics are used to display the step's effect on the hosts.
 	// For example: max cpu contention: before [ 100%, 50%, 40% ], after [ 40%, 50%, 100% ]
 	Statistics map[string]FilterWeigherPipelineStepStatistics
+
+	// Why hosts were removed by this step, by host. Only set by filters, and
+	// only for the hosts they give a reason for. E.g.:
+	//
+	//	{ "host 1": "insufficient vCPUs: need 14 have 12" }
+	Reasons map[string]string
+}
+
+// Remove the host from the activations and record why it was removed.
+func (r *FilterWeigherPipelineStepResult) RemoveHost(host, reason string) {
+	delete(r.Activations, host)
+	if r.Reasons == nil {
+		r.Reasons = make(map[string]string)
+	}
+	r.Reasons[host] = reason
 }
 
 type FilterWeigherPipelineStepStatistics struct {
@@ -5,13 +5,21 @@ package lib
 
 import (
 	"context"
+	"errors"
 	"log/slog"
+	"maps"
 	"math"
 	"slices"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/cobaltcore-dev/cortex/api/scheduling"
 	"github.com/cobaltcore-dev/cortex/api/v1alpha1"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -74,7 +82,7 @@ func TestPipeline_Run(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result, err := pipeline.Run(tt.request)
+			result, err := pipeline.Run(t.Context(), tt.request)
 			if err != nil {
 				t.Fatalf("expected no error, got %v", err)
 			}
@@ -90,6 +98,77 @@ func TestPipeline_Run(t *testing.T) {
 	}
 }
 
+func TestPipeline_Run_ContextTimeout(t *testing.T) {
+	// Steps that never finish on their own, until the test is over.
+	release := make(chan struct{})
+	defer close(release)
+	hang := func(traceLog *slog.Logger, request mockFilterWeigherPipelineRequest) (*FilterWeigherPipelineStepResult, error) {
+		<-release
+		return &FilterWeigherPipelineStepResult{}, nil
+	}
+	tests := []struct {
+		name     string
+		pipeline *filterWeigherPipeline[mockFilterWeigherPipelineRequest]
+		wantStep string
+	}{
+		{
+			name: "hung filter",
+			pipeline: &filterWeigherPipeline[mockFilterWeigherPipelineRequest]{
+				filtersOrder: []string{"hung_filter"},
+				filters: map[string]Filter[mockFilterWeigherPipelineRequest]{
+					"hung_filter": &mockFilter[mockFilterWeigherPipelineRequest]{RunFunc: hang},
+				},
+			},
+			wantStep: "filter hung_filter",
+		},
+		{
+			name: "hung weigher",
+			pipeline: &filterWeigherPipeline[mockFilterWeigherPipelineRequest]{
+				weighersOrder: []string{"hung_weigher"},
+				weighers: map[string]Weigher[mockFilterWeigherPipelineRequest]{
+					"hung_weigher": &mockWeigher[mockFilterWeigherPipelineRequest]{RunFunc: hang},
+				},
+			},
+			wantStep: "weighers",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(t.Context(), 10*time.Millisecond)
+			defer cancel()
+			_, err := tt.pipeline.Run(ctx, mockFilterWeigherPipelineRequest{
+				Hosts:   []string{"host1"},
+				Weights: map[string]float64{"host1": 0.0},
+			})
+			if !errors.Is(err, context.DeadlineExceeded) {
+				t.Fatalf("expected deadline exceeded, got %v", err)
+			}
+			if !strings.Contains(err.Error(), tt.wantStep) {
+				t.Errorf("expected error to mention %q, got %v", tt.wantStep, err)
+			}
+		})
+	}
+}
+
+func TestRunStep_WithoutDeadline(t *testing.T) {
+	// Without a deadline the step runs inline, so it is waited for even if
+	// the context is canceled in the meantime.
+	ctx, cancel := context.WithCancel(t.Context())
+	step := &mockFilter[mockFilterWeigherPipelineRequest]{
+		RunFunc: func(traceLog *slog.Logger, request mockFilterWeigherPipelineRequest) (*FilterWeigherPipelineStepResult, error) {
+			cancel()
+			return &FilterWeigherPipelineStepResult{Activations: map[string]float64{"host1": 1.0}}, nil
+		},
+	}
+	result, err := runStep(ctx, "filter", step, slog.Default(), mockFilterWeigherPipelineRequest{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result == nil || result.Activations["host1"] != 1.0 {
+		t.Errorf("expected the step result, got %v", result)
+	}
+}
+
 func TestPipeline_Run_NoWeighers_PreservesInputOrdering(t *testing.T) {
 	// With no weighers configured, the tanh normalization would saturate
 	// large input weights to ~1.0 and destroy the requester's ordering. The
@@ -109,7 +188,7 @@ func TestPipeline_Run_NoWeighers_PreservesInputOrdering(t *testing.T) {
 	// Run many times to surface any non-determinism from map iteration order.
 	expected := []string{"host3", "host2", "host1"}
 	for i := range 50 {
-		result, err := pipeline.Run(request)
+		result, err := pipeline.Run(t.Context(), request)
 		if err != nil {
 			t.Fatalf("expected no error, got %v", err)
 		}
@@ -218,6 +297,16 @@ func TestPipeline_SortHostsByWeights(t *testing.T) {
 			},
 			expected: []string{"host2", "host1", "host3"},
 		},
+		{
+			name: "Ties are broken by host name",
+			weights: map[string]float64{
+				"host3": 1.0,
+				"host1": 1.0,
+				"host4": 0.2,
+				"host2": 1.0,
+			},
+			expected: []string{"host1", "host2", "host3", "host4"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -258,12 +347,100 @@ func TestPipeline_RunFilters(t *testing.T) {
 		Weights: map[string]float64{"host1": 0.0, "host2": 0.0, "host3": 0.0},
 	}
 
-	req, _ := p.runFilters(slog.Default(), request)
+	req, _, _, err := p.runFilters(t.Context(), slog.Default(), request)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
 	if len(req.Hosts) != 2 {
 		t.Fatalf("expected 2 step results, got %d", len(req.Hosts))
 	}
 }
 
+func TestPipeline_RunFilters_ReaddedHost(t *testing.T) {
+	filter := func(hosts ...string) Filter[mockFilterWeigherPipelineRequest] {
+		return &mockFilter[mockFilterWeigherPipelineRequest]{
+			RunFunc: func(traceLog *slog.Logger, request mockFilterWeigherPipelineRequest) (*FilterWeigherPipelineStepResult, error) {
+				activations := map[string]float64{}
+				for _, host := range hosts {
+					activations[host] = 0.0
+				}
+				return &FilterWeigherPipelineStepResult{Activations: activations}, nil
+			},
+		}
+	}
+	p := &filterWeigherPipeline[mockFilterWeigherPipelineRequest]{
+		filtersOrder: []string{"filter_a", "filter_b", "filter_c"},
+		filters: map[string]Filter[mockFilterWeigherPipelineRequest]{
+			"filter_a": filter("host1", "host2"),
+			// Re-adds host3, which was removed by filter_a.
+			"filter_b": filter("host1", "host3"),
+			"filter_c": filter("host1"),
+		},
+	}
+	request := mockFilterWeigherPipelineRequest{
+		Hosts:   []string{"host1", "host2", "host3"},
+		Weights: map[string]float64{"host1": 0.0, "host2": 0.0, "host3": 0.0},
+	}
+
+	req, stepResults, removedBy, err := p.runFilters(t.Context(), slog.Default(), request)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !slices.Equal(req.Hosts, []string{"host1"}) {
+		t.Errorf("expected only host1 to remain, got %v", req.Hosts)
+	}
+	if len(stepResults) != 2 || stepResults[0].StepName != "filter_a" || stepResults[1].StepName != "filter_c" {
+		t.Errorf("expected results of filter_a and filter_c, got %v", stepResults)
+	}
+	expected := map[string]string{"host3": "filter_a", "host2": "filter_c"}
+	if !maps.Equal(removedBy, expected) {
+		t.Errorf("expected removedBy %v, got %v", expected, removedBy)
+	}
+}
+
+func TestCheckStepHosts(t *testing.T) {
+	tests := []struct {
+		name        string
+		activations map[string]float64
+		removedBy   map[string]string
+		expectedErr string
+	}{
+		{
+			name:        "all hosts in input",
+			activations: map[string]float64{"host1": 0, "host2": 0},
+		},
+		{
+			name:        "host removed by prior step",
+			activations: map[string]float64{"host1": 0, "host3": 0},
+			removedBy:   map[string]string{"host3": "filter_a"},
+			expectedErr: "unexpected host: step step_x returned host host3 which was removed by filter_a",
+		},
+		{
+			name:        "host never in input",
+			activations: map[string]float64{"host4": 0},
+			removedBy:   map[string]string{"host3": "filter_a"},
+			expectedErr: "unexpected host: step step_x returned host host4 which was never in the input",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkStepHosts("step_x", tt.activations, []string{"host1", "host2"}, tt.removedBy)
+			if tt.expectedErr == "" {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrUnexpectedHost) {
+				t.Fatalf("expected ErrUnexpectedHost, got %v", err)
+			}
+			if err.Error() != tt.expectedErr {
+				t.Errorf("expected error %q, got %q", tt.expectedErr, err.Error())
+			}
+		})
+	}
+}
+
 func TestInitNewFilterWeigherPipeline_Success(t *testing.T) {
 	scheme := runtime.NewScheme()
 	cl := fake.NewClientBuilder().WithScheme(scheme).Build()
@@ -314,6 +491,9 @@ func TestInitNewFilterWeigherPipeline_Success(t *testing.T) {
 		confedFilters,
 		supportedWeighers,
 		confedWeighers,
+		"",
+		"",
+		false,
 		monitor,
 	)
 
@@ -354,6 +534,9 @@ func TestInitNewFilterWeigherPipeline_UnsupportedFilter(t *testing.T) {
 		confedFilters,
 		supportedWeighers,
 		nil,
+		"",
+		"",
+		false,
 		monitor,
 	)
 
@@ -388,6 +571,9 @@ func TestInitNewFilterWeigherPipeline_UnsupportedWeigher(t *testing.T) {
 		nil,
 		supportedWeighers,
 		confedWeighers,
+		"",
+		"",
+		false,
 		monitor,
 	)
 
@@ -396,6 +582,68 @@ func TestInitNewFilterWeigherPipeline_UnsupportedWeigher(t *testing.T) {
 	}
 }
 
+func TestInitNewFilterWeigherPipeline_FallbackActivation(t *testing.T) {
+	scheme := runtime.NewScheme()
+	cl := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	failingWeigher := func() Weigher[mockFilterWeigherPipelineRequest] {
+		return &mockWeigher[mockFilterWeigherPipelineRequest]{
+			InitFunc: func(ctx context.Context, c client.Client, step v1alpha1.WeigherSpec) error {
+				return errors.New("knowledge not ready")
+			},
+		}
+	}
+	supportedWeighers := map[string]func() Weigher[mockFilterWeigherPipelineRequest]{
+		"with-fallback":    failingWeigher,
+		"without-fallback": failingWeigher,
+	}
+	fallback := 0.5
+	confedWeighers := []v1alpha1.WeigherSpec{
+		{Name: "with-fallback", FallbackActivation: &fallback},
+		{Name: "without-fallback"},
+	}
+
+	result := InitNewFilterWeigherPipeline(
+		t.Context(),
+		cl,
+		"test-pipeline",
+		map[string]func() Filter[mockFilterWeigherPipelineRequest]{},
+		nil,
+		supportedWeighers,
+		confedWeighers,
+		"",
+		"",
+		false,
+		FilterWeigherPipelineMonitor{PipelineName: "test-pipeline"},
+	)
+
+	// Both errors are still reported, so the pipeline status shows them.
+	if len(result.WeigherErrors) != 2 {
+		t.Fatalf("expected 2 weigher errors, got %v", result.WeigherErrors)
+	}
+	pipeline, ok := result.Pipeline.(*filterWeigherPipeline[mockFilterWeigherPipelineRequest])
+	if !ok {
+		t.Fatalf("unexpected pipeline type %T", result.Pipeline)
+	}
+	if !slices.Equal(pipeline.weighersOrder, []string{"with-fallback"}) {
+		t.Fatalf("expected only the weigher with fallback, got %v", pipeline.weighersOrder)
+	}
+
+	request := mockFilterWeigherPipelineRequest{
+		Hosts:   []string{"host1", "host2"},
+		Weights: map[string]float64{"host1": 0, "host2": 0},
+	}
+	activations, err := pipeline.runWeighers(t.Context(), slog.Default(), request, nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	for _, host := range request.Hosts {
+		if got := activations["with-fallback"][host]; got != fallback {
+			t.Errorf("expected fallback activation %f for %s, got %f", fallback, host, got)
+		}
+	}
+}
+
 func TestFilterWeigherPipelineMonitor_SubPipeline(t *testing.T) {
 	monitor := NewPipelineMonitor()
 
@@ -440,7 +688,7 @@ func TestPipeline_MaxCandidates(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			req := request
 			req.Options = scheduling.Options{MaxCandidates: tt.maxCandidates}
-			result, err := pipeline.Run(req)
+			result, err := pipeline.Run(t.Context(), req)
 			if err != nil {
 				t.Fatalf("expected no error, got %v", err)
 			}
@@ -466,3 +714,555 @@ func TestPipeline_MaxCandidates(t *testing.T) {
 		})
 	}
 }
+
+func TestPipeline_TieBreaker(t *testing.T) {
+	// Weigher that scores all hosts identically, e.g. because its knowledge is stale.
+	newPipeline := func(tieBreaker v1alpha1.TieBreaker) *filterWeigherPipeline[mockFilterWeigherPipelineRequest] {
+		return &filterWeigherPipeline[mockFilterWeigherPipelineRequest]{
+			filters:      map[string]Filter[mockFilterWeigherPipelineRequest]{},
+			filtersOrder: []string{},
+			weighers: map[string]Weigher[mockFilterWeigherPipelineRequest]{
+				"neutral_weigher": &mockWeigher[mockFilterWeigherPipelineRequest]{
+					RunFunc: func(traceLog *slog.Logger, request mockFilterWeigherPipelineRequest) (*FilterWeigherPipelineStepResult, error) {
+						return &FilterWeigherPipelineStepResult{
+							Activations: map[string]float64{"host-a": 0, "host-b": 0, "host-c": 0},
+						}, nil
+					},
+				},
+			},
+			weighersOrder: []string{"neutral_weigher"},
+			tieBreaker:    tieBreaker,
+			selections:    newHostSelectionTracker(nil, "test"),
+		}
+	}
+	// The input weights differ, but are all squashed to 1 by tanh.
+	request := mockFilterWeigherPipelineRequest{
+		Hosts:   []string{"host-a", "host-b", "host-c"},
+		Weights: map[string]float64{"host-a": 50, "host-b": 70, "host-c": 60},
+	}
+
+	tests := []struct {
+		name       string
+		tieBreaker v1alpha1.TieBreaker
+		// Expected ordering for each consecutive run.
+		expected [][]string
+	}{
+		{
+			name:       "by host name",
+			tieBreaker: v1alpha1.TieBreakerHostName,
+			expected: [][]string{
+				{"host-a", "host-b", "host-c"},
+				{"host-a", "host-b", "host-c"},
+			},
+		},
+		{
+			name:       "by input weight",
+			tieBreaker: v1alpha1.TieBreakerInputWeight,
+			expected:   [][]string{{"host-b", "host-c", "host-a"}},
+		},
+		{
+			name:       "by least recently selected",
+			tieBreaker: v1alpha1.TieBreakerLeastRecentlySelected,
+			expected: [][]string{
+				{"host-a", "host-b", "host-c"},
+				{"host-b", "host-c", "host-a"},
+				{"host-c", "host-a", "host-b"},
+				{"host-a", "host-b", "host-c"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pipeline := newPipeline(tt.tieBreaker)
+			for i, expected := range tt.expected {
+				result, err := pipeline.Run(t.Context(), request)
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				if !slices.Equal(result.OrderedHosts, expected) {
+					t.Errorf("run %d: expected ordering %v, got %v", i, expected, result.OrderedHosts)
+				}
+				if result.TieBreaker != tt.tieBreaker {
+					t.Errorf("run %d: expected tie breaker %q, got %q", i, tt.tieBreaker, result.TieBreaker)
+				}
+			}
+		})
+	}
+}
+
+func TestHostSelectionTracker_LastSelections(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(h int) metav1.Time { return metav1.NewTime(base.Add(time.Duration(h) * time.Hour)) }
+	hostA, hostB := "host-a", "host-b"
+	cl := fake.NewClientBuilder().
+		WithScheme(newTestScheme(t)).
+		WithObjects(
+			&v1alpha1.History{
+				ObjectMeta: metav1.ObjectMeta{Name: "history-1"},
+				Status: v1alpha1.HistoryStatus{
+					Current: v1alpha1.CurrentDecision{
+						Timestamp: at(3), PipelineRef: corev1.ObjectReference{Name: "pipeline"}, TargetHost: &hostA,
+					},
+					History: []v1alpha1.SchedulingHistoryEntry{{
+						Timestamp: at(1), PipelineRef: corev1.ObjectReference{Name: "pipeline"},
+						OrderedHosts: []string{"host-b"}, Successful: true,
+					}},
+				},
+			},
+			&v1alpha1.History{
+				ObjectMeta: metav1.ObjectMeta{Name: "history-2"},
+				Status: v1alpha1.HistoryStatus{
+					// Selections of other pipelines are ignored.
+					Current: v1alpha1.CurrentDecision{
+						Timestamp: at(5), PipelineRef: corev1.ObjectReference{Name: "other"}, TargetHost: &hostB,
+					},
+				},
+			},
+		).
+		Build()
+	tracker := newHostSelectionTracker(cl, "pipeline")
+	// Selections of this replica are newer than their history.
+	tracker.recordSelection("host-c", at(4).Time)
+	tracker.recordSelection("host-a", at(2).Time)
+
+	expected := map[string]time.Time{"host-a": at(3).Time, "host-b": at(1).Time, "host-c": at(4).Time}
+	got := tracker.lastSelections(t.Context())
+	if !maps.EqualFunc(got, expected, time.Time.Equal) {
+		t.Errorf("expected last selections %v, got %v", expected, got)
+	}
+}
+
+func TestPipeline_TieBreaker_OnlyOrdersTiedHosts(t *testing.T) {
+	pipeline := &filterWeigherPipeline[mockFilterWeigherPipelineRequest]{
+		weighers: map[string]Weigher[mockFilterWeigherPipelineRequest]{
+			"neutral_weigher": &mockWeigher[mockFilterWeigherPipelineRequest]{
+				RunFunc: func(traceLog *slog.Logger, request mockFilterWeigherPipelineRequest) (*FilterWeigherPipelineStepResult, error) {
+					return &FilterWeigherPipelineStepResult{
+						Activations: map[string]float64{"host-a": 0, "host-b": 0, "host-c": 0, "host-d": 0},
+					}, nil
+				},
+			},
+		},
+		weighersOrder: []string{"neutral_weigher"},
+		tieBreaker:    v1alpha1.TieBreakerHostName,
+		selections:    newHostSelectionTracker(nil, "test"),
+	}
+
+	// Weighers tied, but the input weights still order the hosts.
+	result, err := pipeline.Run(t.Context(), mockFilterWeigherPipelineRequest{
+		Hosts:   []string{"host-a", "host-b", "host-c"},
+		Weights: map[string]float64{"host-a": 0.1, "host-b": 0.3, "host-c": 0.2},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !slices.Equal(result.OrderedHosts, []string{"host-b", "host-c", "host-a"}) {
+		t.Errorf("expected input weights to determine the ordering, got %v", result.OrderedHosts)
+	}
+	if result.TieBreaker != "" {
+		t.Errorf("expected no tie breaker to be used, got %q", result.TieBreaker)
+	}
+
+	// Only the tied hosts are reordered.
+	result, err = pipeline.Run(t.Context(), mockFilterWeigherPipelineRequest{
+		Hosts:   []string{"host-a", "host-b", "host-c", "host-d"},
+		Weights: map[string]float64{"host-a": 0.1, "host-b": 0.1, "host-c": 0.3, "host-d": 0.3},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !slices.Equal(result.OrderedHosts, []string{"host-c", "host-d", "host-a", "host-b"}) {
+		t.Errorf("expected tied hosts to be ordered by name within their weight, got %v", result.OrderedHosts)
+	}
+	if result.TieBreaker != v1alpha1.TieBreakerHostName {
+		t.Errorf("expected tie breaker %q, got %q", v1alpha1.TieBreakerHostName, result.TieBreaker)
+	}
+}
+
+func TestPipeline_TieBreaker_NotUsedWhenWeighersDiffer(t *testing.T) {
+	pipeline := &filterWeigherPipeline[mockFilterWeigherPipelineRequest]{
+		weighers: map[string]Weigher[mockFilterWeigherPipelineRequest]{
+			"mock_weigher": &mockWeigher[mockFilterWeigherPipelineRequest]{
+				RunFunc: func(traceLog *slog.Logger, request mockFilterWeigherPipelineRequest) (*FilterWeigherPipelineStepResult, error) {
+					return &FilterWeigherPipelineStepResult{
+						Activations: map[string]float64{"host-a": 0, "host-b": 1},
+					}, nil
+				},
+			},
+		},
+		weighersOrder: []string{"mock_weigher"},
+		tieBreaker:    v1alpha1.TieBreakerHostName,
+		selections:    newHostSelectionTracker(nil, "test"),
+	}
+	result, err := pipeline.Run(t.Context(), mockFilterWeigherPipelineRequest{
+		Hosts:   []string{"host-a", "host-b"},
+		Weights: map[string]float64{"host-a": 0, "host-b": 0},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !slices.Equal(result.OrderedHosts, []string{"host-b", "host-a"}) {
+		t.Errorf("expected weighers to determine the ordering, got %v", result.OrderedHosts)
+	}
+	if result.TieBreaker != "" {
+		t.Errorf("expected no tie breaker to be used, got %q", result.TieBreaker)
+	}
+}
+
+func TestPipeline_NonFiniteScores(t *testing.T) {
+	newWeigher := func(activations map[string]float64) Weigher[mockFilterWeigherPipelineRequest] {
+		return &mockWeigher[mockFilterWeigherPipelineRequest]{
+			RunFunc: func(traceLog *slog.Logger, request mockFilterWeigherPipelineRequest) (*FilterWeigherPipelineStepResult, error) {
+				return &FilterWeigherPipelineStepResult{Activations: activations}, nil
+			},
+		}
+	}
+	tests := []struct {
+		name         string
+		inWeights    map[string]float64
+		weighers     map[string]Weigher[mockFilterWeigherPipelineRequest]
+		expectedStep string
+		expectError  bool
+	}{
+		{
+			name:      "finite scores",
+			inWeights: map[string]float64{"host-a": 0, "host-b": 0},
+			weighers: map[string]Weigher[mockFilterWeigherPipelineRequest]{
+				"good_weigher": newWeigher(map[string]float64{"host-a": 0.5, "host-b": 1}),
+			},
+			expectError: false,
+		},
+		{
+			name:      "weigher returns NaN",
+			inWeights: map[string]float64{"host-a": 0, "host-b": 0},
+			weighers: map[string]Weigher[mockFilterWeigherPipelineRequest]{
+				"good_weigher": newWeigher(map[string]float64{"host-a": 0.5, "host-b": 1}),
+				"nan_weigher":  newWeigher(map[string]float64{"host-a": math.NaN(), "host-b": 1}),
+			},
+			expectedStep: "nan_weigher",
+			expectError:  true,
+		},
+		{
+			name:      "weigher returns infinity",
+			inWeights: map[string]float64{"host-a": 0, "host-b": 0},
+			weighers: map[string]Weigher[mockFilterWeigherPipelineRequest]{
+				"inf_weigher": newWeigher(map[string]float64{"host-a": 0, "host-b": math.Inf(-1)}),
+			},
+			expectedStep: "inf_weigher",
+			expectError:  true,
+		},
+		{
+			name:      "input weight is NaN",
+			inWeights: map[string]float64{"host-a": math.NaN(), "host-b": 0},
+			weighers: map[string]Weigher[mockFilterWeigherPipelineRequest]{
+				"good_weigher": newWeigher(map[string]float64{"host-a": 0.5, "host-b": 1}),
+			},
+			expectedStep: "input weights",
+			expectError:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			monitor := NewPipelineMonitor().SubPipeline("test")
+			weighersOrder := slices.Sorted(maps.Keys(tt.weighers))
+			pipeline := &filterWeigherPipeline[mockFilterWeigherPipelineRequest]{
+				weighers:      tt.weighers,
+				weighersOrder: weighersOrder,
+				monitor:       monitor,
+			}
+			result, err := pipeline.Run(t.Context(), mockFilterWeigherPipelineRequest{
+				Hosts:   []string{"host-a", "host-b"},
+				Weights: tt.inWeights,
+			})
+			if !tt.expectError {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				if result.TargetHost == nil {
+					t.Fatal("expected a target host")
+				}
+				return
+			}
+			if !errors.Is(err, ErrNonFiniteScore) {
+				t.Fatalf("expected non-finite score error, got %v", err)
+			}
+			if !strings.Contains(err.Error(), tt.expectedStep) {
+				t.Errorf("expected error to name step %q, got %v", tt.expectedStep, err)
+			}
+			if result.TargetHost != nil {
+				t.Errorf("expected no target host, got %s", *result.TargetHost)
+			}
+			counter := monitor.invalidScoresCounter.WithLabelValues("test", tt.expectedStep)
+			if got := testutil.ToFloat64(counter); got != 1 {
+				t.Errorf("expected invalid scores counter to be 1, got %f", got)
+			}
+		})
+	}
+}
+
+func TestPipeline_NonFiniteScores_FilteredHost(t *testing.T) {
+	pipeline := &filterWeigherPipeline[mockFilterWeigherPipelineRequest]{
+		filters: map[string]Filter[mockFilterWeigherPipelineRequest]{
+			"mock_filter": &mockFilter[mockFilterWeigherPipelineRequest]{
+				RunFunc: func(traceLog *slog.Logger, request mockFilterWeigherPipelineRequest) (*FilterWeigherPipelineStepResult, error) {
+					return &FilterWeigherPipelineStepResult{Activations: map[string]float64{"host-b": 0}}, nil
+				},
+			},
+		},
+		filtersOrder: []string{"mock_filter"},
+		weighers: map[string]Weigher[mockFilterWeigherPipelineRequest]{
+			"mock_weigher": &mockWeigher[mockFilterWeigherPipelineRequest]{
+				RunFunc: func(traceLog *slog.Logger, request mockFilterWeigherPipelineRequest) (*FilterWeigherPipelineStepResult, error) {
+					return &FilterWeigherPipelineStepResult{Activations: map[string]float64{"host-b": 1}}, nil
+				},
+			},
+		},
+		weighersOrder: []string{"mock_weigher"},
+		monitor:       NewPipelineMonitor().SubPipeline("test"),
+	}
+	// The NaN weight of the filtered out host doesn't affect the ordering.
+	result, err := pipeline.Run(t.Context(), mockFilterWeigherPipelineRequest{
+		Hosts:   []string{"host-a", "host-b"},
+		Weights: map[string]float64{"host-a": math.NaN(), "host-b": 0},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.TargetHost == nil || *result.TargetHost != "host-b" {
+		t.Errorf("expected host-b to be selected, got %v", result.TargetHost)
+	}
+}
+
+func TestPipeline_Run_FilterReasons(t *testing.T) {
+	pipeline := &filterWeigherPipeline[mockFilterWeigherPipelineRequest]{
+		filters: map[string]Filter[mockFilterWeigherPipelineRequest]{
+			"mock_filter": &mockFilter[mockFilterWeigherPipelineRequest]{
+				RunFunc: func(traceLog *slog.Logger, request mockFilterWeigherPipelineRequest) (*FilterWeigherPipelineStepResult, error) {
+					result := &FilterWeigherPipelineStepResult{
+						Activations: map[string]float64{"host1": 0.0, "host2": 0.0, "host3": 0.0},
+					}
+					result.RemoveHost("host3", "not enough capacity")
+					// Reasons for hosts that are kept are dropped.
+					result.Reasons["host1"] = "stale reason"
+					return result, nil
+				},
+			},
+		},
+		filtersOrder: []string{"mock_filter"},
+	}
+	request := mockFilterWeigherPipelineRequest{
+		Hosts:   []string{"host1", "host2", "host3"},
+		Weights: map[string]float64{"host1": 0.0, "host2": 0.0, "host3": 0.0},
+	}
+	result, err := pipeline.Run(t.Context(), request)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(result.StepResults) != 1 {
+		t.Fatalf("expected 1 step result, got %d", len(result.StepResults))
+	}
+	expected := map[string]string{"host3": "not enough capacity"}
+	if !maps.Equal(result.StepResults[0].Reasons, expected) {
+		t.Errorf("expected reasons %v, got %v", expected, result.StepResults[0].Reasons)
+	}
+}
+
+func TestPipeline_Run_AllHostsFiltered(t *testing.T) {
+	monitor := NewPipelineMonitor().SubPipeline("test").SubDomain(v1alpha1.SchedulingDomainNova)
+	pipeline := &filterWeigherPipeline[mockFilterWeigherPipelineRequest]{
+		filters: map[string]Filter[mockFilterWeigherPipelineRequest]{
+			"mock_filter": &mockFilter[mockFilterWeigherPipelineRequest]{
+				RunFunc: func(traceLog *slog.Logger, request mockFilterWeigherPipelineRequest) (*FilterWeigherPipelineStepResult, error) {
+					return &FilterWeigherPipelineStepResult{Activations: map[string]float64{}}, nil
+				},
+			},
+		},
+		filtersOrder: []string{"mock_filter"},
+		monitor:      monitor,
+	}
+	result, err := pipeline.Run(t.Context(), mockFilterWeigherPipelineRequest{
+		Hosts:   []string{"host1", "host2"},
+		Weights: map[string]float64{"host1": 0.0, "host2": 0.0},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.TargetHost != nil {
+		t.Errorf("expected no target host, got %s", *result.TargetHost)
+	}
+	counter := monitor.allHostsFilteredCounter.WithLabelValues("nova", "test", "mock_filter")
+	if got := testutil.ToFloat64(counter); got != 1 {
+		t.Errorf("expected all hosts filtered counter to be 1, got %f", got)
+	}
+}
+
+func TestPipeline_FilterBatches(t *testing.T) {
+	tests := []struct {
+		name            string
+		parallelFilters bool
+		independent     map[string]bool
+		expected        [][]string
+	}{
+		{
+			name:            "parallel filters disabled",
+			parallelFilters: false,
+			independent:     map[string]bool{"a": true, "b": true, "c": true, "d": true},
+			expected:        [][]string{{"a"}, {"b"}, {"c"}, {"d"}},
+		},
+		{
+			name:            "all filters independent",
+			parallelFilters: true,
+			independent:     map[string]bool{"a": true, "b": true, "c": true, "d": true},
+			expected:        [][]string{{"a", "b", "c", "d"}},
+		},
+		{
+			name:            "dependent filter splits batches",
+			parallelFilters: true,
+			independent:     map[string]bool{"a": true, "b": true, "d": true},
+			expected:        [][]string{{"a", "b"}, {"c"}, {"d"}},
+		},
+		{
+			name:            "no filter independent",
+			parallelFilters: true,
+			independent:     map[string]bool{},
+			expected:        [][]string{{"a"}, {"b"}, {"c"}, {"d"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &filterWeigherPipeline[mockFilterWeigherPipelineRequest]{
+				filtersOrder:       []string{"a", "b", "c", "d"},
+				independentFilters: tt.independent,
+				parallelFilters:    tt.parallelFilters,
+			}
+			batches := p.filterBatches()
+			if !slices.EqualFunc(batches, tt.expected, slices.Equal[[]string]) {
+				t.Errorf("expected batches %v, got %v", tt.expected, batches)
+			}
+		})
+	}
+}
+
+func TestPipeline_RunFilters_Parallel(t *testing.T) {
+	filter := func(removed ...string) Filter[mockFilterWeigherPipelineRequest] {
+		return &mockFilter[mockFilterWeigherPipelineRequest]{
+			RunFunc: func(traceLog *slog.Logger, request mockFilterWeigherPipelineRequest) (*FilterWeigherPipelineStepResult, error) {
+				result := &FilterWeigherPipelineStepResult{Activations: map[string]float64{}}
+				for _, host := range request.Hosts {
+					result.Activations[host] = 0.0
+				}
+				for _, host := range removed {
+					result.RemoveHost(host, "removed")
+				}
+				return result, nil
+			},
+		}
+	}
+	newPipeline := func(parallelFilters bool) *filterWeigherPipeline[mockFilterWeigherPipelineRequest] {
+		return &filterWeigherPipeline[mockFilterWeigherPipelineRequest]{
+			filtersOrder: []string{"filter_a", "filter_b", "filter_c"},
+			filters: map[string]Filter[mockFilterWeigherPipelineRequest]{
+				"filter_a": filter("host1"),
+				// Also removes host1, which is attributed to filter_a.
+				"filter_b": filter("host1", "host2"),
+				"filter_c": filter("host3"),
+			},
+			independentFilters: map[string]bool{"filter_a": true, "filter_b": true, "filter_c": true},
+			parallelFilters:    parallelFilters,
+		}
+	}
+	request := mockFilterWeigherPipelineRequest{
+		Hosts:   []string{"host1", "host2", "host3", "host4"},
+		Weights: map[string]float64{"host1": 0.0, "host2": 0.0, "host3": 0.0, "host4": 0.0},
+	}
+
+	seqReq, seqResults, seqRemovedBy, err := newPipeline(false).runFilters(t.Context(), slog.Default(), request)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	parReq, parResults, parRemovedBy, err := newPipeline(true).runFilters(t.Context(), slog.Default(), request)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if !slices.Equal(parReq.Hosts, []string{"host4"}) {
+		t.Errorf("expected only host4 to remain, got %v", parReq.Hosts)
+	}
+	if !slices.Equal(slices.Sorted(slices.Values(seqReq.Hosts)), parReq.Hosts) {
+		t.Errorf("expected remaining hosts %v, got %v", seqReq.Hosts, parReq.Hosts)
+	}
+	expectedRemovedBy := map[string]string{"host1": "filter_a", "host2": "filter_b", "host3": "filter_c"}
+	if !maps.Equal(parRemovedBy, expectedRemovedBy) {
+		t.Errorf("expected removedBy %v, got %v", expectedRemovedBy, parRemovedBy)
+	}
+	if !maps.Equal(seqRemovedBy, parRemovedBy) {
+		t.Errorf("expected removedBy %v, got %v", seqRemovedBy, parRemovedBy)
+	}
+	if len(seqResults) != len(parResults) {
+		t.Fatalf("expected %d step results, got %d", len(seqResults), len(parResults))
+	}
+	for i := range seqResults {
+		if seqResults[i].StepName != parResults[i].StepName {
+			t.Errorf("expected step %s at %d, got %s", seqResults[i].StepName, i, parResults[i].StepName)
+		}
+		if !maps.Equal(seqResults[i].Activations, parResults[i].Activations) {
+			t.Errorf("expected activations %v for %s, got %v",
+				seqResults[i].Activations, seqResults[i].StepName, parResults[i].Activations)
+		}
+		if !maps.Equal(seqResults[i].Reasons, parResults[i].Reasons) {
+			t.Errorf("expected reasons %v for %s, got %v",
+				seqResults[i].Reasons, seqResults[i].StepName, parResults[i].Reasons)
+		}
+	}
+}
+
+// Filters usually wait on the kubernetes api or a database, which is
+// simulated by a fixed delay per filter run.
+func BenchmarkPipeline_RunFilters(b *testing.B) {
+	const nHosts = 200
+	hosts := make([]string, nHosts)
+	weights := make(map[string]float64, nHosts)
+	for i := range hosts {
+		hosts[i] = "host" + strconv.Itoa(i)
+		weights[hosts[i]] = 0.0
+	}
+	filter := &mockFilter[mockFilterWeigherPipelineRequest]{
+		RunFunc: func(traceLog *slog.Logger, request mockFilterWeigherPipelineRequest) (*FilterWeigherPipelineStepResult, error) {
+			time.Sleep(time.Millisecond)
+			activations := make(map[string]float64, len(request.Hosts))
+			for _, host := range request.Hosts {
+				activations[host] = 0.0
+			}
+			return &FilterWeigherPipelineStepResult{Activations: activations}, nil
+		},
+	}
+	filtersOrder := []string{"filter_a", "filter_b", "filter_c", "filter_d", "filter_e"}
+	filters := make(map[string]Filter[mockFilterWeigherPipelineRequest], len(filtersOrder))
+	independentFilters := make(map[string]bool, len(filtersOrder))
+	for _, name := range filtersOrder {
+		filters[name] = filter
+		independentFilters[name] = true
+	}
+	request := mockFilterWeigherPipelineRequest{Hosts: hosts, Weights: weights}
+	log := slog.New(slog.DiscardHandler)
+
+	for _, parallelFilters := range []bool{false, true} {
+		name := "sequential"
+		if parallelFilters {
+			name = "parallel"
+		}
+		b.Run(name, func(b *testing.B) {
+			p := &filterWeigherPipeline[mockFilterWeigherPipelineRequest]{
+				filtersOrder:       filtersOrder,
+				filters:            filters,
+				independentFilters: independentFilters,
+				parallelFilters:    parallelFilters,
+			}
+			for b.Loop() {
+				if _, _, _, err := p.runFilters(b.Context(), log, request); err != nil {
+					b.Fatalf("expected no error, got %v", err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright SAP SE
+// SPDX-License-Identifier: Apache-2.0
+
+package lib
+
+import (
+	"cmp"
+	"context"
+	"log/slog"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/cobaltcore-dev/cortex/api/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Tracks when hosts were last selected by a pipeline, to support the
+// least-recently-selected tie breaker.
+//
+// Selections are persisted through the histories of the pipeline's
+// decisions, so they survive restarts and are shared between replicas.
+// Selections made by this replica are also kept in memory, to cover the
+// time until their history is written and visible to the client.
+type hostSelectionTracker struct {
+	// Client to read the histories with, none to only track in memory.
+	client client.Client
+	// Name of the pipeline whose selections are tracked.
+	pipelineName string
+
+	mu sync.Mutex
+	// When each host was last selected by this replica.
+	lastSelected map[string]time.Time
+}
+
+func newHostSelectionTracker(cl client.Client, pipelineName string) *hostSelectionTracker {
+	return &hostSelectionTracker{
+		client:       cl,
+		pipelineName: pipelineName,
+		lastSelected: make(map[string]time.Time),
+	}
+}
+
+// Record that the given host was selected at the given time.
+func (t *hostSelectionTracker) recordSelection(host string, at time.Time) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.lastSelected[host] = at
+}
+
+// Get when each host was last selected, from the histories of the pipeline's
+// decisions merged with the selections made by this replica.
+func (t *hostSelectionTracker) lastSelections(ctx context.Context) map[string]time.Time {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	selected := maps.Clone(t.lastSelected)
+	t.mu.Unlock()
+	if t.client == nil {
+		return selected
+	}
+	histories := &v1alpha1.HistoryList{}
+	if err := t.client.List(ctx, histories); err != nil {
+		slog.Warn("scheduler: failed to list histories, only using selections of this replica",
+			"pipeline", t.pipelineName, "error", err)
+		return selected
+	}
+	observe := func(host string, at time.Time) {
+		if at.After(selected[host]) {
+			selected[host] = at
+		}
+	}
+	for _, history := range histories.Items {
+		current := history.Status.Current
+		if current.PipelineRef.Name == t.pipelineName && current.TargetHost != nil {
+			observe(*current.TargetHost, current.Timestamp.Time)
+		}
+		for _, entry := range history.Status.History {
+			if entry.PipelineRef.Name == t.pipelineName && entry.Successful && len(entry.OrderedHosts) > 0 {
+				observe(entry.OrderedHosts[0], entry.Timestamp.Time)
+			}
+		}
+	}
+	return selected
+}
+
+// Check if any hosts, given in order of their output weights, are tied.
+func hasTiedHosts(hosts []string, outWeights map[string]float64) bool {
+	for i := 1; i < len(hosts); i++ {
+		if outWeights[hosts[i]] == outWeights[hosts[i-1]] {
+			return true
+		}
+	}
+	return false
+}
+
+// Order hosts with equal output weights using the given tie breaker, while
+// hosts with different weights keep their ordering by weight. Hosts are
+// ordered by name as a last resort, so the resulting ordering is always
+// deterministic. Returns whether any hosts were tied.
+func orderTiedHosts(
+	tieBreaker v1alpha1.TieBreaker,
+	hosts []string,
+	outWeights map[string]float64,
+	inWeights map[string]float64,
+	lastSelected map[string]time.Time,
+) ([]string, bool) {
+
+	ordered := slices.Clone(hosts)
+	slices.SortFunc(ordered, func(a, b string) int {
+		if c := cmp.Compare(outWeights[b], outWeights[a]); c != 0 {
+			return c
+		}
+		switch tieBreaker {
+		case v1alpha1.TieBreakerLeastRecentlySelected:
+			// Hosts never selected (zero) come first.
+			if c := lastSelected[a].Compare(lastSelected[b]); c != 0 {
+				return c
+			}
+		case v1alpha1.TieBreakerInputWeight:
+			if c := cmp.Compare(inWeights[b], inWeights[a]); c != 0 {
+				return c
+			}
+		}
+		return cmp.Compare(a, b)
+	})
+	if !hasTiedHosts(ordered, outWeights) {
+		return hosts, false
+	}
+	return ordered, true
+}
@@ -0,0 +1,100 @@
+// Copyright SAP SE
+// SPDX-License-Identifier: Apache-2.0
+
+package lib
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/cobaltcore-dev/cortex/api/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Explanation of the latest scheduling decision for a resource, as returned
+// by the history API.
+type HistoryExplanation struct {
+	// The scheduling domain of the resource (e.g., nova, cinder, manila).
+	SchedulingDomain v1alpha1.SchedulingDomain `json:"schedulingDomain"`
+	// The resource ID the decision was made for.
+	ResourceID string `json:"resourceID"`
+	// The timestamp of when the decision was made.
+	Timestamp metav1.Time `json:"timestamp"`
+	// The name of the pipeline that was used for the decision.
+	Pipeline string `json:"pipeline"`
+	// The intent of the decision.
+	Intent v1alpha1.SchedulingIntent `json:"intent"`
+	// Whether the scheduling decision was successful.
+	Successful bool `json:"successful"`
+	// The host that won the decision, if any.
+	TargetHost *string `json:"targetHost,omitempty"`
+	// The top hosts ordered by score.
+	OrderedHosts []string `json:"orderedHosts,omitempty"`
+	// The human-readable explanation of the decision.
+	Explanation string `json:"explanation"`
+	// The structured pieces the explanation is derived from, if recorded.
+	Summary *v1alpha1.DecisionSummary `json:"summary,omitempty"`
+}
+
+// HistoryAPI exposes the explanations stored in the History CRDs over http,
+// so operators can debug placements without access to the cluster.
+type HistoryAPI struct {
+	Client client.Client
+}
+
+// Init the API mux and bind the handlers.
+func (api *HistoryAPI) Init(mux *http.ServeMux) {
+	mux.HandleFunc("GET /histories/{domain}/{resourceID}/explain", api.HandleExplain)
+}
+
+// Return the explanation of the latest decision for the requested resource.
+// By default the response is json, use ?format=text to get the plain text
+// explanation only.
+func (api *HistoryAPI) HandleExplain(w http.ResponseWriter, r *http.Request) {
+	domain := v1alpha1.SchedulingDomain(r.PathValue("domain"))
+	resourceID := r.PathValue("resourceID")
+	if domain == "" || resourceID == "" {
+		http.Error(w, "missing scheduling domain or resource id", http.StatusBadRequest)
+		return
+	}
+
+	history := &v1alpha1.History{}
+	name := getName(domain, resourceID)
+	if err := api.Client.Get(r.Context(), client.ObjectKey{Name: name}, history); err != nil {
+		if apierrors.IsNotFound(err) {
+			http.Error(w, "no history found for "+name, http.StatusNotFound)
+			return
+		}
+		slog.Error("failed to get history", "name", name, "error", err)
+		http.Error(w, "failed to get history", http.StatusInternalServerError)
+		return
+	}
+
+	current := history.Status.Current
+	if r.URL.Query().Get("format") == "text" {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		if _, err := w.Write([]byte(current.Explanation)); err != nil {
+			slog.Error("failed to write explanation", "name", name, "error", err)
+		}
+		return
+	}
+	explanation := HistoryExplanation{
+		SchedulingDomain: history.Spec.SchedulingDomain,
+		ResourceID:       history.Spec.ResourceID,
+		Timestamp:        current.Timestamp,
+		Pipeline:         current.PipelineRef.Name,
+		Intent:           current.Intent,
+		Successful:       current.Successful,
+		TargetHost:       current.TargetHost,
+		OrderedHosts:     current.OrderedHosts,
+		Explanation:      current.Explanation,
+		Summary:          current.Summary,
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(explanation); err != nil {
+		slog.Error("failed to encode explanation", "name", name, "error", err)
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright SAP SE
+// SPDX-License-Identifier: Apache-2.0
+
+package lib
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cobaltcore-dev/cortex/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestHistoryAPI_HandleExplain(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add scheme: %v", err)
+	}
+	targetHost := "host-1"
+	history := &v1alpha1.History{
+		ObjectMeta: metav1.ObjectMeta{Name: "nova-uuid-1"},
+		Spec: v1alpha1.HistorySpec{
+			SchedulingDomain: v1alpha1.SchedulingDomainNova,
+			ResourceID:       "uuid-1",
+		},
+		Status: v1alpha1.HistoryStatus{
+			Current: v1alpha1.CurrentDecision{
+				PipelineRef:  corev1.ObjectReference{Name: "kvm-general-purpose-load-balancing"},
+				Intent:       v1alpha1.SchedulingIntentUnknown,
+				Successful:   true,
+				TargetHost:   &targetHost,
+				OrderedHosts: []string{"host-1", "host-2"},
+				Explanation:  "Selected host: host-1.",
+				Summary: &v1alpha1.DecisionSummary{
+					TargetHostScore: new(1.5),
+					HostsEvaluated:  3,
+					HostsRemaining:  2,
+					CriticalSteps:   []string{"kvm_binpack"},
+				},
+			},
+		},
+	}
+	api := &HistoryAPI{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(history).Build()}
+	mux := http.NewServeMux()
+	api.Init(mux)
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{"json explanation", http.MethodGet, "/histories/nova/uuid-1/explain", http.StatusOK, ""},
+		{"text explanation", http.MethodGet, "/histories/nova/uuid-1/explain?format=text", http.StatusOK, "Selected host: host-1."},
+		{"unknown resource", http.MethodGet, "/histories/nova/uuid-2/explain", http.StatusNotFound, ""},
+		{"wrong domain", http.MethodGet, "/histories/cinder/uuid-1/explain", http.StatusNotFound, ""},
+		{"wrong method", http.MethodPost, "/histories/nova/uuid-1/explain", http.StatusMethodNotAllowed, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, http.NoBody)
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+			if tt.expectedBody != "" && w.Body.String() != tt.expectedBody {
+				t.Errorf("expected body %q, got %q", tt.expectedBody, w.Body.String())
+			}
+		})
+	}
+
+	t.Run("json fields", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/histories/nova/uuid-1/explain", http.NoBody)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		var explanation HistoryExplanation
+		if err := json.NewDecoder(w.Body).Decode(&explanation); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if explanation.TargetHost == nil || *explanation.TargetHost != "host-1" {
+			t.Errorf("expected target host host-1, got %v", explanation.TargetHost)
+		}
+		if explanation.Pipeline != "kvm-general-purpose-load-balancing" {
+			t.Errorf("unexpected pipeline %q", explanation.Pipeline)
+		}
+		if len(explanation.OrderedHosts) != 2 || explanation.Explanation != "Selected host: host-1." {
+			t.Errorf("unexpected explanation %+v", explanation)
+		}
+		summary := explanation.Summary
+		if summary == nil || summary.TargetHostScore == nil || *summary.TargetHostScore != 1.5 {
+			t.Fatalf("expected summary with target host score 1.5, got %+v", summary)
+		}
+		if summary.HostsEvaluated != 3 || summary.HostsRemaining != 2 ||
+			len(summary.CriticalSteps) != 1 || summary.CriticalSteps[0] != "kvm_binpack" {
+
+			t.Errorf("unexpected summary %+v", summary)
+		}
+	})
+}
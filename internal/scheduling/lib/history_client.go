@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/cobaltcore-dev/cortex/api/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
@@ -22,9 +23,12 @@ import (
 )
 
 const (
-	maxHostsInExplanation = 10
-	maxHistoryEntries     = 10
-	maxHostsInOrderedList = 3
+	maxHostsInExplanation    = 10
+	defaultMaxHistoryEntries = 10
+	maxHostsInOrderedList    = 3
+	// Minimum number of moves back and forth between the same two hosts
+	// before it is reported as ping-pong, e.g. A->B->A->B.
+	minPingPongMoves = 3
 )
 
 // joinHostsCapped joins up to max host names. If hosts exceeds max, it appends
@@ -36,48 +40,153 @@ func joinHostsCapped(hosts []string, maxHosts int) string {
 	return fmt.Sprintf("%s (and %d more)", strings.Join(hosts[:maxHosts], ", "), len(hosts)-maxHosts)
 }
 
-func getName(schedulingDomain v1alpha1.SchedulingDomain, resourceID string) string {
-	return fmt.Sprintf("%s-%s", schedulingDomain, resourceID)
+// ExplanationVerbosity controls which analyses are computed and rendered into
+// the explanation of a scheduling decision.
+type ExplanationVerbosity string
+
+const (
+	// Only the selected host (or the pipeline error) is reported, without
+	// the analyses of earlier decisions for the same resource.
+	ExplanationVerbosityMinimal ExplanationVerbosity = "minimal"
+	// Filtered hosts, remaining hosts, selected host, and weighing impact.
+	// This is the default when no verbosity is configured.
+	ExplanationVerbosityStandard ExplanationVerbosity = "standard"
+	// Everything from standard, plus the final scores of the ordered hosts.
+	ExplanationVerbosityVerbose ExplanationVerbosity = "verbose"
+)
+
+// Validate that the verbosity is one of the supported levels.
+func (v ExplanationVerbosity) Validate() error {
+	switch v {
+	case "", ExplanationVerbosityMinimal, ExplanationVerbosityStandard, ExplanationVerbosityVerbose:
+		return nil
+	default:
+		return fmt.Errorf("unsupported explanation verbosity %q", v)
+	}
 }
 
-// generateSimplifiedExplanation produces a human-readable explanation from a
-// decision result. On failure it includes the error. On success it describes
-// which pipeline steps filtered out which hosts.
-func generateExplanation(result *v1alpha1.DecisionResult, pipelineErr error) string {
-	if pipelineErr != nil {
-		return fmt.Sprintf("Pipeline run failed: %s.", pipelineErr.Error())
+// Minimum score gaps between the selected host and the runner-up for the
+// selection to be considered of high or medium certainty.
+type CertaintyThresholds struct {
+	High   float64 `json:"high"`
+	Medium float64 `json:"medium"`
+}
+
+// Thresholds used when none are configured for a scheduling domain.
+var DefaultCertaintyThresholds = CertaintyThresholds{High: 0.5, Medium: 0.2}
+
+// Validate that the medium threshold is not negative and below the high one.
+func (t CertaintyThresholds) Validate() error {
+	if t.Medium < 0 || t.Medium >= t.High {
+		return fmt.Errorf("certainty thresholds must satisfy 0 <= medium < high, got medium=%v high=%v", t.Medium, t.High)
 	}
+	return nil
+}
 
-	if result == nil || len(result.StepResults) == 0 {
-		if result != nil && result.TargetHost != nil {
-			return fmt.Sprintf("Selected host: %s.", *result.TargetHost)
+// Configuration of the History CRDs written after each pipeline run.
+type HistoryConfig struct {
+	// Verbosity of the explanation stored in the History CRD and emitted
+	// as event. Defaults to standard.
+	ExplanationVerbosity ExplanationVerbosity `json:"explanationVerbosity,omitempty"`
+	// Certainty thresholds per scheduling domain, since the score scales
+	// differ between domains. Defaults to DefaultCertaintyThresholds.
+	CertaintyThresholds map[v1alpha1.SchedulingDomain]CertaintyThresholds `json:"certaintyThresholds,omitempty"`
+	// Maximum number of past decisions kept individually in the History CRD.
+	// Older decisions are only kept as a summary. Defaults to 10.
+	MaxHistoryLength int `json:"maxHistoryLength,omitempty"`
+}
+
+// Validate the verbosity, the history length, and all configured certainty
+// thresholds.
+func (c HistoryConfig) Validate() error {
+	if err := c.ExplanationVerbosity.Validate(); err != nil {
+		return err
+	}
+	if c.MaxHistoryLength < 0 {
+		return fmt.Errorf("max history length must not be negative, got %d", c.MaxHistoryLength)
+	}
+	for domain, thresholds := range c.CertaintyThresholds {
+		if err := thresholds.Validate(); err != nil {
+			return fmt.Errorf("invalid certainty thresholds for %s: %w", domain, err)
 		}
-		return ""
 	}
+	return nil
+}
 
-	// Get all initial hosts from input weights.
-	var allHosts map[string]float64
-	if len(result.RawInWeights) > 0 {
-		allHosts = result.RawInWeights
-	} else if len(result.NormalizedInWeights) > 0 {
-		allHosts = result.NormalizedInWeights
+// Get the certainty thresholds for the given scheduling domain.
+func (c HistoryConfig) CertaintyThresholdsFor(domain v1alpha1.SchedulingDomain) CertaintyThresholds {
+	if thresholds, ok := c.CertaintyThresholds[domain]; ok {
+		return thresholds
 	}
-	if allHosts == nil {
-		if result.TargetHost != nil {
-			return fmt.Sprintf("Selected host: %s.", *result.TargetHost)
+	return DefaultCertaintyThresholds
+}
+
+func getName(schedulingDomain v1alpha1.SchedulingDomain, resourceID string) string {
+	return fmt.Sprintf("%s-%s", schedulingDomain, resourceID)
+}
+
+// Hosts removed by a single pipeline step.
+type stepFilteredHosts struct {
+	stepName string
+	hosts    []string
+	// Why the step removed the hosts, for the hosts it gave a reason for.
+	reasons map[string]string
+}
+
+// Hosts with the reason they were removed, if the step gave one,
+// e.g. "host-a (insufficient vCPUs: need 14 have 12)".
+func (f stepFilteredHosts) hostsWithReasons() []string {
+	out := make([]string, 0, len(f.hosts))
+	for _, h := range f.hosts {
+		if reason, ok := f.reasons[h]; ok && reason != "" {
+			out = append(out, fmt.Sprintf("%s (%s)", h, reason))
+			continue
 		}
-		return ""
+		out = append(out, h)
 	}
+	return out
+}
 
-	var sb strings.Builder
-	fmt.Fprintf(&sb, "Started with %d host(s).\n\n", len(allHosts))
+// decisionSummary holds the structured pieces of a decision that the
+// explanation is rendered from, so the text and the structured summary
+// stored in the History CRD can't drift apart.
+type decisionSummary struct {
+	// Number of hosts the pipeline started with, if known.
+	hostsEvaluated int
+	// Hosts removed by each step, in pipeline order.
+	filtered []stepFilteredHosts
+	// Hosts remaining after all steps, sorted by name.
+	remaining []string
+	// Score of the highest-ranked host and gap to the runner-up, if known.
+	targetHostScore *float64
+	gapToRunnerUp   *float64
+	// Number of hosts sharing the top score whose ordering was decided by
+	// host name, zero if there was no such tie.
+	tiedHosts int
+	// Weighers without which a different host would have been selected.
+	criticalSteps []string
+}
+
+// summarizeDecision extracts the structured pieces of the given decision
+// result. Returns nil if there is no result.
+func summarizeDecision(result *v1alpha1.DecisionResult) *decisionSummary {
+	if result == nil {
+		return nil
+	}
+	summary := &decisionSummary{}
+
+	// Get all initial hosts from input weights.
+	allHosts := result.RawInWeights
+	if len(allHosts) == 0 {
+		allHosts = result.NormalizedInWeights
+	}
+	summary.hostsEvaluated = len(allHosts)
 
 	// Track current set of surviving hosts.
 	currentHosts := make(map[string]bool, len(allHosts))
 	for h := range allHosts {
 		currentHosts[h] = true
 	}
-
 	for _, step := range result.StepResults {
 		// Determine which hosts were removed by this step.
 		var removed []string
@@ -91,41 +200,301 @@ func generateExplanation(result *v1alpha1.DecisionResult, pipelineErr error) str
 			for _, h := range removed {
 				delete(currentHosts, h)
 			}
-			fmt.Fprintf(&sb, "%s filtered out %s\n",
-				step.StepName,
-				joinHostsCapped(removed, maxHostsInExplanation),
-			)
+			summary.filtered = append(summary.filtered, stepFilteredHosts{
+				stepName: step.StepName,
+				hosts:    removed,
+				reasons:  step.Reasons,
+			})
 		}
 	}
-
-	// Summary of remaining hosts.
-	remaining := make([]string, 0, len(currentHosts))
+	summary.remaining = make([]string, 0, len(currentHosts))
 	for h := range currentHosts {
-		remaining = append(remaining, h)
+		summary.remaining = append(summary.remaining, h)
+	}
+	sort.Strings(summary.remaining)
+
+	if len(result.OrderedHosts) > 0 {
+		if score, ok := result.AggregatedOutWeights[result.OrderedHosts[0]]; ok {
+			summary.targetHostScore = &score
+			if len(result.OrderedHosts) > 1 {
+				if runnerUp, ok := result.AggregatedOutWeights[result.OrderedHosts[1]]; ok {
+					gap := score - runnerUp
+					summary.gapToRunnerUp = &gap
+				}
+			}
+			// Ties are ordered by host name, unless a tie breaker was used.
+			if result.TieBreaker == "" {
+				tied := 0
+				for _, h := range result.OrderedHosts {
+					if result.AggregatedOutWeights[h] == score {
+						tied++
+					}
+				}
+				if tied > 1 {
+					summary.tiedHosts = tied
+				}
+			}
+		}
+	}
+	summary.criticalSteps = findCriticalWeighers(result)
+	return summary
+}
+
+// findEmptyingStep returns the filter step that removed all remaining hosts,
+// or an empty string if hosts remained after every filter. Weighers don't
+// remove hosts, so their activations are not considered.
+func findEmptyingStep(result *v1alpha1.DecisionResult) string {
+	if result == nil {
+		return ""
+	}
+	remaining := len(result.RawInWeights)
+	if remaining == 0 {
+		remaining = len(result.NormalizedInWeights)
+	}
+	for _, step := range result.StepResults {
+		if step.Multiplier != nil {
+			continue
+		}
+		if remaining > 0 && len(step.Activations) == 0 {
+			return step.StepName
+		}
+		remaining = len(step.Activations)
+	}
+	return ""
+}
+
+// toAPI converts the summary into its representation in the History CRD.
+// Filtered hosts are capped per step to keep the CRD compact.
+func (s *decisionSummary) toAPI() *v1alpha1.DecisionSummary {
+	if s == nil {
+		return nil
+	}
+	out := &v1alpha1.DecisionSummary{
+		TargetHostScore: s.targetHostScore,
+		GapToRunnerUp:   s.gapToRunnerUp,
+		HostsEvaluated:  s.hostsEvaluated,
+		HostsRemaining:  len(s.remaining),
+		TiedHosts:       s.tiedHosts,
+		CriticalSteps:   s.criticalSteps,
+	}
+	if len(s.filtered) > 0 {
+		out.FilteredHosts = make(map[string][]string, len(s.filtered))
+		for _, f := range s.filtered {
+			hosts := f.hosts
+			if len(hosts) > maxHostsInExplanation {
+				hosts = hosts[:maxHostsInExplanation]
+			}
+			out.FilteredHosts[f.stepName] = append(out.FilteredHosts[f.stepName], hosts...)
+		}
+	}
+	return out
+}
+
+// generateExplanation produces a human-readable explanation from a decision
+// result. On failure it includes the error. On success it describes which
+// pipeline steps filtered out which hosts, based on the summary of the
+// result. The verbosity determines which of these analyses are run, see
+// ExplanationVerbosity. The certainty thresholds classify how clear the
+// selection of the target host was.
+func generateExplanation(
+	result *v1alpha1.DecisionResult,
+	summary *decisionSummary,
+	pipelineErr error,
+	verbosity ExplanationVerbosity,
+	certainty CertaintyThresholds,
+) string {
+
+	if pipelineErr != nil {
+		return fmt.Sprintf("Pipeline run failed: %s.", pipelineErr.Error())
+	}
+
+	if verbosity == ExplanationVerbosityMinimal {
+		if result != nil && result.TargetHost != nil {
+			return fmt.Sprintf("Selected host: %s.", *result.TargetHost)
+		}
+		return ""
+	}
+
+	if result == nil || len(result.StepResults) == 0 {
+		if result != nil && result.TargetHost != nil {
+			return fmt.Sprintf("Selected host: %s.", *result.TargetHost)
+		}
+		return ""
+	}
+
+	if summary == nil || summary.hostsEvaluated == 0 {
+		if result.TargetHost != nil {
+			return fmt.Sprintf("Selected host: %s.", *result.TargetHost)
+		}
+		return ""
 	}
-	sort.Strings(remaining)
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Started with %d host(s).\n\n", summary.hostsEvaluated)
+	for _, f := range summary.filtered {
+		fmt.Fprintf(&sb, "%s filtered out %s\n",
+			f.stepName,
+			joinHostsCapped(f.hostsWithReasons(), maxHostsInExplanation),
+		)
+	}
+
+	// Summary of remaining hosts.
 	fmt.Fprintf(&sb, "\n%d hosts remaining (%s)\n",
-		len(remaining),
-		joinHostsCapped(remaining, maxHostsInExplanation),
+		len(summary.remaining),
+		joinHostsCapped(summary.remaining, maxHostsInExplanation),
 	)
 
 	if result.TargetHost != nil {
-		fmt.Fprintf(&sb, "\nSelected host: %s.", *result.TargetHost)
+		fmt.Fprintf(&sb, "\nSelected host: %s%s.", *result.TargetHost, explainCertainty(summary, certainty))
+	}
+	if result.TieBreaker != "" {
+		fmt.Fprintf(&sb, "\nSome hosts had identical scores, used fallback ordering %s for them.", result.TieBreaker)
 	}
 
 	if weighingExpl := ExplainWeighing(result); weighingExpl != "" {
 		fmt.Fprintf(&sb, "\n\n%s", weighingExpl)
 	}
 
+	if verbosity == ExplanationVerbosityVerbose && len(result.OrderedHosts) > 0 {
+		scores := make([]string, 0, len(result.OrderedHosts))
+		for _, h := range result.OrderedHosts {
+			scores = append(scores, fmt.Sprintf("%s (%.4f)", h, result.AggregatedOutWeights[h]))
+		}
+		fmt.Fprintf(&sb, "\n\nFinal scores: %s",
+			joinHostsCapped(scores, maxHostsInExplanation),
+		)
+	}
+
 	return strings.TrimSpace(sb.String())
 }
 
+// getCertaintyLevel classifies how clear the selection of a host was, based
+// on the score gap between the selected host and the runner-up.
+func getCertaintyLevel(gap float64, thresholds CertaintyThresholds) string {
+	switch {
+	case gap >= thresholds.High:
+		return "high"
+	case gap >= thresholds.Medium:
+		return "medium"
+	default:
+		return "low"
+	}
+}
+
+// explainCertainty returns the score of the selected host and the certainty
+// of the selection, e.g. " (score: 1.20, certainty: high)". If several hosts
+// shared the top score, a note on the tie is added. Returns an empty string
+// if there is no runner-up to compare against.
+func explainCertainty(summary *decisionSummary, thresholds CertaintyThresholds) string {
+	if summary == nil || summary.targetHostScore == nil || summary.gapToRunnerUp == nil {
+		return ""
+	}
+	tie := ""
+	if summary.tiedHosts > 1 {
+		tie = fmt.Sprintf(", tie broken by name, %d hosts tied at score", summary.tiedHosts)
+	}
+	return fmt.Sprintf(" (score: %.2f, certainty: %s%s)",
+		*summary.targetHostScore, getCertaintyLevel(*summary.gapToRunnerUp, thresholds), tie)
+}
+
+// explainPingPong reports if the resource has repeatedly bounced between the
+// same two hosts, e.g. "Ping-pong between host-1 and host-2 (4 times in 3h0m0s)."
+// Only the placements at the end of the history are considered, starting
+// with the most recent one. Returns an empty string if there is no ping-pong.
+func explainPingPong(entries []v1alpha1.SchedulingHistoryEntry, current v1alpha1.CurrentDecision) string {
+	type placement struct {
+		host      string
+		timestamp time.Time
+	}
+	// Collect the successful placements, skipping decisions that kept the host.
+	var placements []placement
+	add := func(host string, timestamp time.Time) {
+		if len(placements) > 0 && placements[len(placements)-1].host == host {
+			return
+		}
+		placements = append(placements, placement{host: host, timestamp: timestamp})
+	}
+	for _, entry := range entries {
+		if entry.Successful && len(entry.OrderedHosts) > 0 {
+			add(entry.OrderedHosts[0], entry.Timestamp.Time)
+		}
+	}
+	if current.Successful && current.TargetHost != nil {
+		add(*current.TargetHost, current.Timestamp.Time)
+	}
+	if len(placements) < minPingPongMoves+1 {
+		return ""
+	}
+
+	// Walk back as long as the placements alternate between the last two hosts.
+	last := len(placements) - 1
+	start := last - 1
+	for start > 0 && placements[start-1].host == placements[start+1].host {
+		start--
+	}
+	moves := last - start
+	if moves < minPingPongMoves {
+		return ""
+	}
+	span := placements[last].timestamp.Sub(placements[start].timestamp).Round(time.Minute)
+	return fmt.Sprintf("Ping-pong between %s and %s (%d times in %s).",
+		placements[last-1].host, placements[last].host, moves, span)
+}
+
+// compactHistory drops the oldest history entries exceeding maxEntries and
+// folds them into the summary of older decisions, so the total number of
+// decisions and the time span they cover are retained.
+func compactHistory(status *v1alpha1.HistoryStatus, maxEntries int) {
+	if len(status.History) <= maxEntries {
+		return
+	}
+	dropped := status.History[:len(status.History)-maxEntries]
+	if status.Summarized == nil {
+		status.Summarized = &v1alpha1.SummarizedHistory{EarliestTimestamp: dropped[0].Timestamp}
+	}
+	for _, entry := range dropped {
+		if entry.Timestamp.Before(&status.Summarized.EarliestTimestamp) {
+			status.Summarized.EarliestTimestamp = entry.Timestamp
+		}
+	}
+	status.Summarized.Count += len(dropped)
+	status.History = status.History[len(status.History)-maxEntries:]
+}
+
+// explainDecisionChain reports how many decisions were made for the resource
+// and the time span they cover, including decisions that are only kept as a
+// summary, e.g. "14 decisions in 5h30m0s." Returns an empty string if the
+// current decision is the first one.
+func explainDecisionChain(status v1alpha1.HistoryStatus, current v1alpha1.CurrentDecision) string {
+	total := len(status.History) + 1
+	var earliest time.Time
+	if len(status.History) > 0 {
+		earliest = status.History[0].Timestamp.Time
+	}
+	if status.Summarized != nil {
+		total += status.Summarized.Count
+		earliest = status.Summarized.EarliestTimestamp.Time
+	}
+	if total < 2 {
+		return ""
+	}
+	span := current.Timestamp.Sub(earliest).Round(time.Minute)
+	return fmt.Sprintf("%d decisions in %s.", total, span)
+}
+
 // HistoryClient manages History CRDs for scheduling decisions. It holds the
 // Kubernetes client and event recorder so callers don't have to pass them on
 // every invocation.
 type HistoryClient struct {
 	Client   client.Client
 	Recorder events.EventRecorder
+	// Verbosity of the generated explanations, defaults to standard.
+	Verbosity ExplanationVerbosity
+	// Certainty thresholds of the scheduling domain, defaults to
+	// DefaultCertaintyThresholds if unset.
+	Certainty *CertaintyThresholds
+	// Maximum number of past decisions kept individually, defaults to 10.
+	MaxHistoryLength int
 }
 
 // CreateOrUpdateHistory creates or updates a History CRD for the given decision.
@@ -147,6 +516,14 @@ func (h *HistoryClient) CreateOrUpdateHistory(
 	log := ctrl.LoggerFrom(ctx)
 
 	name := getName(decision.Spec.SchedulingDomain, decision.Spec.ResourceID)
+	certainty := DefaultCertaintyThresholds
+	if h.Certainty != nil {
+		certainty = *h.Certainty
+	}
+	maxHistoryEntries := defaultMaxHistoryEntries
+	if h.MaxHistoryLength > 0 {
+		maxHistoryEntries = h.MaxHistoryLength
+	}
 
 	history := &v1alpha1.History{}
 	err := h.Client.Get(ctx, client.ObjectKey{Name: name}, history)
@@ -210,18 +587,21 @@ func (h *HistoryClient) CreateOrUpdateHistory(
 				Successful:   history.Status.Current.Successful,
 			}
 			history.Status.History = append(history.Status.History, entry)
-			if len(history.Status.History) > maxHistoryEntries {
-				history.Status.History = history.Status.History[len(history.Status.History)-maxHistoryEntries:]
-			}
+			compactHistory(&history.Status, maxHistoryEntries)
 		}
 
 		// Build the new current decision.
+		var summary *decisionSummary
+		if pipelineErr == nil {
+			summary = summarizeDecision(decision.Status.Result)
+		}
 		current := v1alpha1.CurrentDecision{
 			Timestamp:   metav1.Now(),
 			PipelineRef: decision.Spec.PipelineRef,
 			Intent:      decision.Spec.Intent,
 			Successful:  successful,
-			Explanation: generateExplanation(decision.Status.Result, pipelineErr),
+			Explanation: generateExplanation(decision.Status.Result, summary, pipelineErr, h.Verbosity, certainty),
+			Summary:     summary.toAPI(),
 		}
 
 		current.OrderedHosts = []string{}
@@ -233,6 +613,15 @@ func (h *HistoryClient) CreateOrUpdateHistory(
 			}
 			current.OrderedHosts = hosts
 		}
+		// The analyses of the history are omitted from minimal explanations.
+		if h.Verbosity != ExplanationVerbosityMinimal {
+			if pingPong := explainPingPong(history.Status.History, current); pingPong != "" {
+				current.Explanation = strings.TrimSpace(current.Explanation + "\n\n" + pingPong)
+			}
+			if chain := explainDecisionChain(history.Status, current); chain != "" {
+				current.Explanation = strings.TrimSpace(current.Explanation + "\n\n" + chain)
+			}
+		}
 		history.Status.Current = current
 
 		// Set Ready condition — True only when a host was successfully selected.
@@ -274,6 +663,12 @@ func (h *HistoryClient) CreateOrUpdateHistory(
 			action = "FailedScheduling"
 		}
 		h.Recorder.Eventf(history, nil, eventType, eventReason, action, "%s", history.Status.Current.Explanation)
+		// A filter that removes all hosts is a signal on its own, e.g. for
+		// alerting on filters that are too aggressive.
+		if step := findEmptyingStep(decision.Status.Result); pipelineErr == nil && step != "" {
+			h.Recorder.Eventf(history, nil, corev1.EventTypeWarning, "AllHostsFiltered", "FilteredAllHosts",
+				"step %s removed all remaining hosts", step)
+		}
 	}
 
 	log.Info("history CRD updated", "name", name, "entries", len(history.Status.History))
@@ -7,13 +7,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/cobaltcore-dev/cortex/api/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/events"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
@@ -97,6 +100,30 @@ func TestGenerateExplanation(t *testing.T) {
 				"1 hosts remaining (host-a)\n\n" +
 				"Selected host: host-a.",
 		},
+		{
+			name: "filtering steps with reasons",
+			result: &v1alpha1.DecisionResult{
+				RawInWeights: map[string]float64{
+					"host-a": 1.0,
+					"host-b": 0.5,
+					"host-c": 0.3,
+				},
+				StepResults: []v1alpha1.StepResult{
+					{
+						StepName:    "filter_capacity",
+						Activations: map[string]float64{"host-a": 1.0},
+						Reasons: map[string]string{
+							"host-b": "insufficient vCPUs: need 14 have 12",
+						},
+					},
+				},
+				TargetHost: new("host-a"),
+			},
+			expected: "Started with 3 host(s).\n\n" +
+				"filter_capacity filtered out host-b (insufficient vCPUs: need 14 have 12), host-c\n\n" +
+				"1 hosts remaining (host-a)\n\n" +
+				"Selected host: host-a.",
+		},
 		{
 			name: "uses NormalizedInWeights when RawInWeights empty",
 			result: &v1alpha1.DecisionResult{
@@ -187,7 +214,7 @@ func TestGenerateExplanation(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := generateExplanation(tt.result, tt.err)
+			got := generateExplanation(tt.result, summarizeDecision(tt.result), tt.err, ExplanationVerbosityStandard, DefaultCertaintyThresholds)
 			if got != tt.expected {
 				t.Errorf("generateExplanation() =\n%q\nwant:\n%q", got, tt.expected)
 			}
@@ -619,3 +646,573 @@ func TestHistoryClient_Delete(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerateExplanation_Verbosity(t *testing.T) {
+	target := "host-a"
+	result := &v1alpha1.DecisionResult{
+		TargetHost:           &target,
+		RawInWeights:         map[string]float64{"host-a": 0, "host-b": 0, "host-c": 0},
+		NormalizedInWeights:  map[string]float64{"host-a": 0, "host-b": 0, "host-c": 0},
+		AggregatedOutWeights: map[string]float64{"host-a": 1, "host-b": 0.5},
+		OrderedHosts:         []string{"host-a", "host-b"},
+		StepResults: []v1alpha1.StepResult{
+			{StepName: "filter_x", Activations: map[string]float64{"host-a": 0, "host-b": 0}},
+		},
+	}
+	tests := []struct {
+		verbosity ExplanationVerbosity
+		contains  []string
+		excludes  []string
+	}{
+		{
+			verbosity: ExplanationVerbosityMinimal,
+			contains:  []string{"Selected host: host-a."},
+			excludes:  []string{"Started with", "filtered out", "hosts remaining", "Final scores"},
+		},
+		{
+			verbosity: ExplanationVerbosityStandard,
+			contains:  []string{"Started with 3 host(s).", "filter_x filtered out host-c", "2 hosts remaining", "Selected host: host-a (score: 1.00, certainty: high)."},
+			excludes:  []string{"Final scores"},
+		},
+		{
+			verbosity: ExplanationVerbosityVerbose,
+			contains:  []string{"Started with 3 host(s).", "filter_x filtered out host-c", "Selected host: host-a (score: 1.00, certainty: high).", "Final scores: host-a (1.0000), host-b (0.5000)"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.verbosity), func(t *testing.T) {
+			got := generateExplanation(result, summarizeDecision(result), nil, tt.verbosity, DefaultCertaintyThresholds)
+			for _, s := range tt.contains {
+				if !strings.Contains(got, s) {
+					t.Errorf("expected explanation to contain %q, got:\n%s", s, got)
+				}
+			}
+			for _, s := range tt.excludes {
+				if strings.Contains(got, s) {
+					t.Errorf("expected explanation not to contain %q, got:\n%s", s, got)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateExplanation_TieBreaker(t *testing.T) {
+	target := "host-a"
+	result := &v1alpha1.DecisionResult{
+		TargetHost:           &target,
+		RawInWeights:         map[string]float64{"host-a": 0, "host-b": 0},
+		AggregatedOutWeights: map[string]float64{"host-a": 0, "host-b": 0},
+		OrderedHosts:         []string{"host-a", "host-b"},
+		StepResults: []v1alpha1.StepResult{
+			{StepName: "weigher_x", Activations: map[string]float64{"host-a": 0, "host-b": 0}},
+		},
+	}
+	if got := generateExplanation(result, summarizeDecision(result), nil, ExplanationVerbosityStandard, DefaultCertaintyThresholds); strings.Contains(got, "fallback ordering") {
+		t.Errorf("expected no fallback note without tie breaker, got:\n%s", got)
+	}
+	result.TieBreaker = v1alpha1.TieBreakerHostName
+	got := generateExplanation(result, summarizeDecision(result), nil, ExplanationVerbosityStandard, DefaultCertaintyThresholds)
+	if !strings.Contains(got, "used fallback ordering hostName") {
+		t.Errorf("expected fallback note in explanation, got:\n%s", got)
+	}
+}
+
+func TestExplainPingPong(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	entry := func(minutes int, host string, successful bool) v1alpha1.SchedulingHistoryEntry {
+		return v1alpha1.SchedulingHistoryEntry{
+			Timestamp:    metav1.NewTime(base.Add(time.Duration(minutes) * time.Minute)),
+			OrderedHosts: []string{host},
+			Successful:   successful,
+		}
+	}
+	current := func(minutes int, host string) v1alpha1.CurrentDecision {
+		return v1alpha1.CurrentDecision{
+			Timestamp:  metav1.NewTime(base.Add(time.Duration(minutes) * time.Minute)),
+			TargetHost: &host,
+			Successful: true,
+		}
+	}
+
+	tests := []struct {
+		name     string
+		entries  []v1alpha1.SchedulingHistoryEntry
+		current  v1alpha1.CurrentDecision
+		expected string
+	}{
+		{
+			name:     "no history",
+			current:  current(0, "host-1"),
+			expected: "",
+		},
+		{
+			name: "single loop is not reported",
+			entries: []v1alpha1.SchedulingHistoryEntry{
+				entry(0, "host-1", true),
+				entry(60, "host-2", true),
+			},
+			current:  current(120, "host-1"),
+			expected: "",
+		},
+		{
+			name: "ping-pong between two hosts",
+			entries: []v1alpha1.SchedulingHistoryEntry{
+				entry(0, "host-1", true),
+				entry(60, "host-2", true),
+				entry(120, "host-1", true),
+			},
+			current:  current(180, "host-2"),
+			expected: "Ping-pong between host-1 and host-2 (3 times in 3h0m0s).",
+		},
+		{
+			name: "only the alternating tail is counted",
+			entries: []v1alpha1.SchedulingHistoryEntry{
+				entry(0, "host-3", true),
+				entry(30, "host-1", true),
+				entry(60, "host-2", true),
+				entry(90, "host-2", true), // Kept the host, not a move.
+				entry(120, "host-1", true),
+				entry(150, "host-9", false), // Failed decisions are ignored.
+				entry(180, "host-2", true),
+			},
+			current:  current(240, "host-1"),
+			expected: "Ping-pong between host-2 and host-1 (4 times in 3h30m0s).",
+		},
+		{
+			name: "moves between three hosts are not ping-pong",
+			entries: []v1alpha1.SchedulingHistoryEntry{
+				entry(0, "host-1", true),
+				entry(60, "host-2", true),
+				entry(120, "host-3", true),
+			},
+			current:  current(180, "host-1"),
+			expected: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := explainPingPong(tt.entries, tt.current)
+			if got != tt.expected {
+				t.Errorf("got %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGetCertaintyLevel(t *testing.T) {
+	custom := CertaintyThresholds{High: 2.0, Medium: 1.0}
+	tests := []struct {
+		gap        float64
+		thresholds CertaintyThresholds
+		expected   string
+	}{
+		{1.0, DefaultCertaintyThresholds, "high"},
+		{0.5, DefaultCertaintyThresholds, "high"},
+		{0.49, DefaultCertaintyThresholds, "medium"},
+		{0.2, DefaultCertaintyThresholds, "medium"},
+		{0.19, DefaultCertaintyThresholds, "low"},
+		{0, DefaultCertaintyThresholds, "low"},
+		{2.0, custom, "high"},
+		{1.5, custom, "medium"},
+		{0.5, custom, "low"},
+	}
+	for _, tt := range tests {
+		if got := getCertaintyLevel(tt.gap, tt.thresholds); got != tt.expected {
+			t.Errorf("getCertaintyLevel(%v, %+v) = %q, want %q", tt.gap, tt.thresholds, got, tt.expected)
+		}
+	}
+}
+
+func TestHistoryConfig_CertaintyThresholds(t *testing.T) {
+	custom := CertaintyThresholds{High: 2.0, Medium: 1.0}
+	config := HistoryConfig{CertaintyThresholds: map[v1alpha1.SchedulingDomain]CertaintyThresholds{
+		v1alpha1.SchedulingDomainCinder: custom,
+	}}
+	if err := config.Validate(); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if got := config.CertaintyThresholdsFor(v1alpha1.SchedulingDomainCinder); got != custom {
+		t.Errorf("expected custom thresholds for cinder, got %+v", got)
+	}
+	if got := config.CertaintyThresholdsFor(v1alpha1.SchedulingDomainNova); got != DefaultCertaintyThresholds {
+		t.Errorf("expected default thresholds for nova, got %+v", got)
+	}
+	config.CertaintyThresholds[v1alpha1.SchedulingDomainManila] = CertaintyThresholds{High: 0.2, Medium: 0.5}
+	if err := config.Validate(); err == nil {
+		t.Error("expected error for medium threshold above high threshold")
+	}
+}
+
+func TestHistoryConfig_MaxHistoryLength(t *testing.T) {
+	if err := (HistoryConfig{MaxHistoryLength: 20}).Validate(); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if err := (HistoryConfig{MaxHistoryLength: -1}).Validate(); err == nil {
+		t.Error("expected error for negative max history length")
+	}
+}
+
+func TestCompactHistory(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	entries := func(from, to int) []v1alpha1.SchedulingHistoryEntry {
+		var out []v1alpha1.SchedulingHistoryEntry
+		for i := from; i < to; i++ {
+			out = append(out, v1alpha1.SchedulingHistoryEntry{
+				Timestamp:   metav1.NewTime(base.Add(time.Duration(i) * time.Hour)),
+				PipelineRef: corev1.ObjectReference{Name: fmt.Sprintf("pipeline-%d", i)},
+			})
+		}
+		return out
+	}
+
+	// Nothing is summarized while the history fits.
+	status := v1alpha1.HistoryStatus{History: entries(0, 3)}
+	compactHistory(&status, 3)
+	if len(status.History) != 3 || status.Summarized != nil {
+		t.Fatalf("expected history to be untouched, got %d entries and summary %+v", len(status.History), status.Summarized)
+	}
+
+	// The oldest entries are dropped and counted.
+	status.History = entries(0, 5)
+	compactHistory(&status, 3)
+	if len(status.History) != 3 || status.History[0].PipelineRef.Name != "pipeline-2" {
+		t.Fatalf("expected the last 3 entries to be kept, got %+v", status.History)
+	}
+	if status.Summarized == nil || status.Summarized.Count != 2 || !status.Summarized.EarliestTimestamp.Time.Equal(base) {
+		t.Fatalf("expected 2 summarized entries starting at %v, got %+v", base, status.Summarized)
+	}
+
+	// Further compaction adds to the count but keeps the earliest timestamp.
+	status.History = append(status.History, entries(5, 7)...)
+	compactHistory(&status, 3)
+	if len(status.History) != 3 || status.History[0].PipelineRef.Name != "pipeline-4" {
+		t.Fatalf("expected the last 3 entries to be kept, got %+v", status.History)
+	}
+	if status.Summarized.Count != 4 || !status.Summarized.EarliestTimestamp.Time.Equal(base) {
+		t.Errorf("expected 4 summarized entries starting at %v, got %+v", base, status.Summarized)
+	}
+}
+
+func TestExplainDecisionChain(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(h int) metav1.Time { return metav1.NewTime(base.Add(time.Duration(h) * time.Hour)) }
+	tests := []struct {
+		name     string
+		status   v1alpha1.HistoryStatus
+		expected string
+	}{
+		{
+			name:     "first decision",
+			expected: "",
+		},
+		{
+			name: "history only",
+			status: v1alpha1.HistoryStatus{History: []v1alpha1.SchedulingHistoryEntry{
+				{Timestamp: at(2)}, {Timestamp: at(3)},
+			}},
+			expected: "3 decisions in 3h0m0s.",
+		},
+		{
+			name: "summarized entries are included",
+			status: v1alpha1.HistoryStatus{
+				History:    []v1alpha1.SchedulingHistoryEntry{{Timestamp: at(2)}, {Timestamp: at(3)}},
+				Summarized: &v1alpha1.SummarizedHistory{Count: 8, EarliestTimestamp: at(-10)},
+			},
+			expected: "11 decisions in 15h0m0s.",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := explainDecisionChain(tt.status, v1alpha1.CurrentDecision{Timestamp: at(5)})
+			if got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestExplainCertainty(t *testing.T) {
+	tests := []struct {
+		name     string
+		result   *v1alpha1.DecisionResult
+		expected string
+	}{
+		{
+			name:     "no ordered hosts",
+			result:   &v1alpha1.DecisionResult{},
+			expected: "",
+		},
+		{
+			name: "single host has no runner-up",
+			result: &v1alpha1.DecisionResult{
+				OrderedHosts:         []string{"host-a"},
+				AggregatedOutWeights: map[string]float64{"host-a": 1},
+			},
+			expected: "",
+		},
+		{
+			name: "medium certainty",
+			result: &v1alpha1.DecisionResult{
+				OrderedHosts:         []string{"host-a", "host-b"},
+				AggregatedOutWeights: map[string]float64{"host-a": 0.8, "host-b": 0.5},
+			},
+			expected: " (score: 0.80, certainty: medium)",
+		},
+		{
+			name: "low certainty",
+			result: &v1alpha1.DecisionResult{
+				OrderedHosts:         []string{"host-a", "host-b", "host-c"},
+				AggregatedOutWeights: map[string]float64{"host-a": 0.55, "host-b": 0.5, "host-c": -1},
+			},
+			expected: " (score: 0.55, certainty: low)",
+		},
+		{
+			name: "missing weights",
+			result: &v1alpha1.DecisionResult{
+				OrderedHosts: []string{"host-a", "host-b"},
+			},
+			expected: "",
+		},
+		{
+			name: "tie broken by name",
+			result: &v1alpha1.DecisionResult{
+				OrderedHosts:         []string{"host-a", "host-b", "host-c"},
+				AggregatedOutWeights: map[string]float64{"host-a": 0.5, "host-b": 0.5, "host-c": 0.1},
+			},
+			expected: " (score: 0.50, certainty: low, tie broken by name, 2 hosts tied at score)",
+		},
+		{
+			name: "tie resolved by the tie breaker",
+			result: &v1alpha1.DecisionResult{
+				OrderedHosts:         []string{"host-b", "host-a"},
+				AggregatedOutWeights: map[string]float64{"host-a": 0.5, "host-b": 0.5},
+				TieBreaker:           v1alpha1.TieBreakerLeastRecentlySelected,
+			},
+			expected: " (score: 0.50, certainty: low)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := explainCertainty(summarizeDecision(tt.result), DefaultCertaintyThresholds); got != tt.expected {
+				t.Errorf("explainCertainty() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSummarizeDecision(t *testing.T) {
+	if summarizeDecision(nil) != nil {
+		t.Fatal("expected nil summary for nil result")
+	}
+
+	rawIn := map[string]float64{}
+	for i := range 15 {
+		rawIn[fmt.Sprintf("host-%02d", i)] = 0
+	}
+	rawIn["host-a"], rawIn["host-b"], rawIn["host-c"] = 0, 0, 0
+	in := map[string]float64{"host-a": 0, "host-b": 0, "host-c": 0}
+	actX := map[string]float64{"host-a": 1, "host-b": 0, "host-c": 0}
+	actY := map[string]float64{"host-a": 0, "host-b": 0.1, "host-c": 0}
+	out := map[string]float64{
+		"host-a": math.Tanh(actX["host-a"]) + math.Tanh(actY["host-a"]),
+		"host-b": math.Tanh(actX["host-b"]) + math.Tanh(actY["host-b"]),
+		"host-c": math.Tanh(actX["host-c"]) + math.Tanh(actY["host-c"]),
+	}
+	result := &v1alpha1.DecisionResult{
+		RawInWeights:        rawIn,
+		NormalizedInWeights: in,
+		StepResults: []v1alpha1.StepResult{
+			// Filters out the 15 numbered hosts.
+			{StepName: "filter_x", Activations: map[string]float64{"host-a": 1, "host-b": 1, "host-c": 1}},
+			{StepName: "weigher_x", Activations: actX},
+			{StepName: "weigher_y", Activations: actY},
+		},
+		AggregatedOutWeights: out,
+		OrderedHosts:         []string{"host-a", "host-b", "host-c"},
+		TargetHost:           new("host-a"),
+	}
+
+	summary := summarizeDecision(result).toAPI()
+	if summary.HostsEvaluated != 18 {
+		t.Errorf("expected 18 hosts evaluated, got %d", summary.HostsEvaluated)
+	}
+	if summary.HostsRemaining != 3 {
+		t.Errorf("expected 3 hosts remaining, got %d", summary.HostsRemaining)
+	}
+	if got := len(summary.FilteredHosts["filter_x"]); got != maxHostsInExplanation {
+		t.Errorf("expected %d filtered hosts for filter_x, got %d", maxHostsInExplanation, got)
+	}
+	if summary.TargetHostScore == nil || math.Abs(*summary.TargetHostScore-out["host-a"]) > 1e-9 {
+		t.Errorf("unexpected target host score %v", summary.TargetHostScore)
+	}
+	if summary.GapToRunnerUp == nil || math.Abs(*summary.GapToRunnerUp-(out["host-a"]-out["host-b"])) > 1e-9 {
+		t.Errorf("unexpected gap to runner-up %v", summary.GapToRunnerUp)
+	}
+	if len(summary.CriticalSteps) != 1 || summary.CriticalSteps[0] != "weigher_x" {
+		t.Errorf("expected weigher_x to be the only critical step, got %v", summary.CriticalSteps)
+	}
+
+	// The explanation is rendered from the same summary.
+	explanation := generateExplanation(result, summarizeDecision(result), nil, ExplanationVerbosityStandard, DefaultCertaintyThresholds)
+	for _, want := range []string{
+		"Started with 18 host(s).",
+		"filter_x filtered out host-00",
+		"(and 5 more)",
+		"3 hosts remaining (host-a, host-b, host-c)",
+		fmt.Sprintf("Selected host: host-a (score: %.2f, certainty: high).", out["host-a"]),
+		"Without weigher_x",
+	} {
+		if !strings.Contains(explanation, want) {
+			t.Errorf("expected explanation to contain %q, got:\n%s", want, explanation)
+		}
+	}
+}
+
+func TestFindEmptyingStep(t *testing.T) {
+	tests := []struct {
+		name     string
+		result   *v1alpha1.DecisionResult
+		expected string
+	}{
+		{name: "nil result", result: nil, expected: ""},
+		{
+			name: "hosts remaining",
+			result: &v1alpha1.DecisionResult{
+				RawInWeights: map[string]float64{"host-a": 1, "host-b": 1},
+				StepResults: []v1alpha1.StepResult{
+					{StepName: "filter_a", Activations: map[string]float64{"host-a": 1}},
+				},
+			},
+			expected: "",
+		},
+		{
+			name: "second filter removes all hosts",
+			result: &v1alpha1.DecisionResult{
+				RawInWeights: map[string]float64{"host-a": 1, "host-b": 1},
+				StepResults: []v1alpha1.StepResult{
+					{StepName: "filter_a", Activations: map[string]float64{"host-a": 1}},
+					{StepName: "filter_b", Activations: map[string]float64{}},
+					{StepName: "filter_c", Activations: map[string]float64{}},
+				},
+			},
+			expected: "filter_b",
+		},
+		{
+			name: "weigher without activations",
+			result: &v1alpha1.DecisionResult{
+				RawInWeights: map[string]float64{"host-a": 1, "host-b": 1},
+				StepResults: []v1alpha1.StepResult{
+					{StepName: "filter_a", Activations: map[string]float64{"host-a": 1}},
+					{StepName: "weigher_a", Activations: map[string]float64{}, Multiplier: new(1.0)},
+					{StepName: "filter_b", Activations: map[string]float64{"host-a": 1}},
+				},
+			},
+			expected: "",
+		},
+		{
+			name: "no hosts in the request",
+			result: &v1alpha1.DecisionResult{
+				StepResults: []v1alpha1.StepResult{
+					{StepName: "filter_a", Activations: map[string]float64{}},
+				},
+			},
+			expected: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := findEmptyingStep(tt.result); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestHistoryClient_CreateOrUpdateHistory_AllHostsFilteredEvent(t *testing.T) {
+	cl := fake.NewClientBuilder().
+		WithScheme(newTestScheme(t)).
+		WithStatusSubresource(&v1alpha1.History{}).
+		Build()
+	recorder := events.NewFakeRecorder(10)
+	hc := HistoryClient{Client: cl, Recorder: recorder}
+	decision := &v1alpha1.Decision{
+		Spec: v1alpha1.DecisionSpec{
+			SchedulingDomain: v1alpha1.SchedulingDomainNova,
+			ResourceID:       "uuid-1",
+			PipelineRef:      corev1.ObjectReference{Name: "nova-pipeline"},
+		},
+		Status: v1alpha1.DecisionStatus{
+			Result: &v1alpha1.DecisionResult{
+				RawInWeights: map[string]float64{"host-a": 1},
+				StepResults: []v1alpha1.StepResult{
+					{StepName: "filter_capacity", Activations: map[string]float64{}},
+				},
+			},
+		},
+	}
+	if err := hc.CreateOrUpdateHistory(context.Background(), decision, nil, nil); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	close(recorder.Events)
+	var found bool
+	for event := range recorder.Events {
+		if strings.HasPrefix(event, "Warning AllHostsFiltered step filter_capacity removed all remaining hosts") {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("expected an AllHostsFiltered event")
+	}
+}
+
+func TestHistoryClient_CreateOrUpdateHistory_MinimalVerbosity(t *testing.T) {
+	target := "host-a"
+	newDecision := func() *v1alpha1.Decision {
+		return &v1alpha1.Decision{
+			Spec: v1alpha1.DecisionSpec{
+				SchedulingDomain: v1alpha1.SchedulingDomainNova,
+				ResourceID:       "uuid-1",
+				PipelineRef:      corev1.ObjectReference{Name: "nova-pipeline"},
+			},
+			Status: v1alpha1.DecisionStatus{
+				Result: &v1alpha1.DecisionResult{
+					TargetHost:   &target,
+					RawInWeights: map[string]float64{"host-a": 1},
+					OrderedHosts: []string{"host-a"},
+					StepResults: []v1alpha1.StepResult{
+						{StepName: "filter_capacity", Activations: map[string]float64{"host-a": 0}},
+					},
+				},
+			},
+		}
+	}
+	for _, verbosity := range []ExplanationVerbosity{ExplanationVerbosityMinimal, ExplanationVerbosityStandard} {
+		t.Run(string(verbosity), func(t *testing.T) {
+			cl := fake.NewClientBuilder().
+				WithScheme(newTestScheme(t)).
+				WithStatusSubresource(&v1alpha1.History{}).
+				Build()
+			hc := HistoryClient{Client: cl, Verbosity: verbosity}
+			for range 2 {
+				if err := hc.CreateOrUpdateHistory(context.Background(), newDecision(), nil, nil); err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+			}
+			var histories v1alpha1.HistoryList
+			if err := cl.List(context.Background(), &histories); err != nil {
+				t.Fatalf("failed to list histories: %v", err)
+			}
+			if len(histories.Items) != 1 {
+				t.Fatalf("expected 1 history, got %d", len(histories.Items))
+			}
+			current := histories.Items[0].Status.Current
+			hasChain := strings.Contains(current.Explanation, "decisions in")
+			if hasChain == (verbosity == ExplanationVerbosityMinimal) {
+				t.Errorf("unexpected decision chain in %s explanation:\n%s", verbosity, current.Explanation)
+			}
+			if current.Summary == nil || current.Summary.HostsEvaluated != 1 {
+				t.Errorf("expected structured summary regardless of verbosity, got %+v", current.Summary)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright SAP SE
+// SPDX-License-Identifier: Apache-2.0
+
+package lib
+
+import (
+	"cmp"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"slices"
+
+	"github.com/cobaltcore-dev/cortex/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Description of a pipeline step, as returned by the pipeline API.
+type PipelineStepDescription struct {
+	// The name of the step.
+	Name string `json:"name"`
+	// The kind of the step (filter, weigher, or detector).
+	Kind string `json:"kind"`
+	// The knowledges the step depends on, if known.
+	Knowledges []string `json:"knowledges,omitempty"`
+}
+
+// Description of a pipeline held in memory by a pipeline controller, as
+// returned by the pipeline API.
+type PipelineDescription struct {
+	// The name of the pipeline.
+	Name string `json:"name"`
+	// The scheduling domain of the pipeline.
+	SchedulingDomain v1alpha1.SchedulingDomain `json:"schedulingDomain"`
+	// The type of the pipeline.
+	Type v1alpha1.PipelineType `json:"type"`
+	// Whether the pipeline is initialized and used to serve requests.
+	Ready bool `json:"ready"`
+	// The status conditions of the pipeline from its last initialization.
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// The configured steps of the pipeline, in order.
+	Steps []PipelineStepDescription `json:"steps,omitempty"`
+}
+
+// Implemented by pipeline controllers that can describe their pipelines.
+type PipelineDescriber interface {
+	// Describe the pipelines currently held in memory.
+	DescribePipelines() []PipelineDescription
+}
+
+// PipelineAPI exposes the pipelines currently loaded by the pipeline
+// controllers over http, so operators can check what the scheduler uses.
+type PipelineAPI struct {
+	// The controllers whose pipelines should be exposed.
+	Describers []PipelineDescriber
+}
+
+// Init the API mux and bind the handlers.
+func (api *PipelineAPI) Init(mux *http.ServeMux) {
+	mux.HandleFunc("GET /pipelines", api.HandlePipelines)
+}
+
+// Return the pipelines of all registered controllers. Use ?domain=<domain>
+// to only return the pipelines of one scheduling domain.
+func (api *PipelineAPI) HandlePipelines(w http.ResponseWriter, r *http.Request) {
+	domain := v1alpha1.SchedulingDomain(r.URL.Query().Get("domain"))
+	pipelines := []PipelineDescription{}
+	for _, describer := range api.Describers {
+		for _, pipeline := range describer.DescribePipelines() {
+			if domain != "" && pipeline.SchedulingDomain != domain {
+				continue
+			}
+			pipelines = append(pipelines, pipeline)
+		}
+	}
+	slices.SortFunc(pipelines, func(a, b PipelineDescription) int {
+		return cmp.Or(
+			cmp.Compare(a.SchedulingDomain, b.SchedulingDomain),
+			cmp.Compare(a.Name, b.Name),
+		)
+	})
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(pipelines); err != nil {
+		slog.Error("failed to encode pipelines", "error", err)
+	}
+}
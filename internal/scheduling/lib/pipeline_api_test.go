@@ -0,0 +1,122 @@
+// Copyright SAP SE
+// SPDX-License-Identifier: Apache-2.0
+
+package lib
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/cobaltcore-dev/cortex/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestPipelineAPI_HandlePipelines(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add scheme: %v", err)
+	}
+	ready := &v1alpha1.Pipeline{
+		ObjectMeta: metav1.ObjectMeta{Name: "ready-pipeline"},
+		Spec: v1alpha1.PipelineSpec{
+			SchedulingDomain: v1alpha1.SchedulingDomainNova,
+			Type:             v1alpha1.PipelineTypeFilterWeigher,
+			Filters:          []v1alpha1.FilterSpec{{Name: "filter1"}},
+			Weighers:         []v1alpha1.WeigherSpec{{Name: "weigher1"}},
+		},
+	}
+	broken := &v1alpha1.Pipeline{
+		ObjectMeta: metav1.ObjectMeta{Name: "broken-pipeline"},
+		Spec: v1alpha1.PipelineSpec{
+			SchedulingDomain: v1alpha1.SchedulingDomainNova,
+			Type:             v1alpha1.PipelineTypeFilterWeigher,
+			Filters:          []v1alpha1.FilterSpec{{Name: "filter2"}},
+		},
+	}
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(ready, broken).
+		WithStatusSubresource(&v1alpha1.Pipeline{}).
+		Build()
+
+	controller := &BasePipelineController[mockPipeline]{
+		Client:           fakeClient,
+		SchedulingDomain: v1alpha1.SchedulingDomainNova,
+		Initializer: &mockPipelineInitializer{
+			pipelineType: v1alpha1.PipelineTypeFilterWeigher,
+			initPipelineFunc: func(ctx context.Context, p v1alpha1.Pipeline) PipelineInitResult[mockPipeline] {
+				if p.Name == "broken-pipeline" {
+					return PipelineInitResult[mockPipeline]{
+						FilterErrors:   map[string]error{"filter2": context.Canceled},
+						StepKnowledges: map[string][]string{"filter2": {"knowledge2"}},
+					}
+				}
+				return PipelineInitResult[mockPipeline]{
+					Pipeline:       mockPipeline{name: p.Name},
+					StepKnowledges: map[string][]string{"weigher1": {"knowledge1"}},
+				}
+			},
+		},
+	}
+	if err := controller.InitAllPipelines(t.Context()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	api := &PipelineAPI{Describers: []PipelineDescriber{controller}}
+	mux := http.NewServeMux()
+	api.Init(mux)
+
+	req := httptest.NewRequest(http.MethodGet, "/pipelines", http.NoBody)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	var pipelines []PipelineDescription
+	if err := json.NewDecoder(w.Body).Decode(&pipelines); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(pipelines) != 2 {
+		t.Fatalf("expected 2 pipelines, got %d", len(pipelines))
+	}
+	if pipelines[0].Name != "broken-pipeline" || pipelines[0].Ready {
+		t.Errorf("expected broken-pipeline not to be ready, got %+v", pipelines[0])
+	}
+	if !slices.Equal(pipelines[0].Steps[0].Knowledges, []string{"knowledge2"}) {
+		t.Errorf("expected knowledges of the failed filter, got %+v", pipelines[0].Steps)
+	}
+	if pipelines[1].Name != "ready-pipeline" || !pipelines[1].Ready {
+		t.Errorf("expected ready-pipeline to be ready, got %+v", pipelines[1])
+	}
+	expectedSteps := []PipelineStepDescription{
+		{Name: "filter1", Kind: "filter"},
+		{Name: "weigher1", Kind: "weigher", Knowledges: []string{"knowledge1"}},
+	}
+	if len(pipelines[1].Steps) != len(expectedSteps) {
+		t.Fatalf("expected %d steps, got %+v", len(expectedSteps), pipelines[1].Steps)
+	}
+	for i, expected := range expectedSteps {
+		got := pipelines[1].Steps[i]
+		if got.Name != expected.Name || got.Kind != expected.Kind || !slices.Equal(got.Knowledges, expected.Knowledges) {
+			t.Errorf("step %d: expected %+v, got %+v", i, expected, got)
+		}
+	}
+
+	// Filtering by another domain returns no pipelines.
+	req = httptest.NewRequest(http.MethodGet, "/pipelines?domain=cinder", http.NoBody)
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	pipelines = nil
+	if err := json.NewDecoder(w.Body).Decode(&pipelines); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(pipelines) != 0 {
+		t.Errorf("expected no cinder pipelines, got %+v", pipelines)
+	}
+}
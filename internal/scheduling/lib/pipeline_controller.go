@@ -7,6 +7,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
+	"sync"
 
 	"github.com/cobaltcore-dev/cortex/api/v1alpha1"
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -20,10 +23,16 @@ import (
 
 // Base controller for decision pipelines.
 type BasePipelineController[PipelineType any] struct {
+	// Guards the pipeline maps below, which are written by the watch handlers
+	// while requests are being served concurrently.
+	mu sync.RWMutex
 	// Initialized pipelines by their name.
 	Pipelines map[string]PipelineType
 	// The configured pipelines by their name.
 	PipelineConfigs map[string]v1alpha1.Pipeline
+	// Knowledges the steps of each pipeline depend on, by pipeline and step
+	// name. Kept for pipelines that failed to initialize, to help debugging.
+	StepKnowledges map[string]map[string][]string
 	// Delegate to create pipelines.
 	Initializer PipelineInitializer[PipelineType]
 	// Kubernetes client to manage/fetch resources.
@@ -32,14 +41,20 @@ type BasePipelineController[PipelineType any] struct {
 	SchedulingDomain v1alpha1.SchedulingDomain
 	// Manager for creating, updating, and deleting History CRDs.
 	HistoryManager HistoryClient
+	// Optional callback notified with the number of ready pipelines whenever
+	// pipelines are (re-)initialized or removed, e.g. to update a gauge.
+	ObserveReadyPipelines func(count int)
 }
 
 // Handle the startup of the manager by initializing the pipeline map.
 func (c *BasePipelineController[PipelineType]) InitAllPipelines(ctx context.Context) error {
 	log := ctrl.LoggerFrom(ctx)
 	log.Info("initializing pipeline map")
+	c.mu.Lock()
 	c.Pipelines = make(map[string]PipelineType)
 	c.PipelineConfigs = make(map[string]v1alpha1.Pipeline)
+	c.StepKnowledges = make(map[string]map[string][]string)
+	c.mu.Unlock()
 	// List all existing pipelines and initialize them.
 	var pipelines v1alpha1.PipelineList
 	if err := c.List(ctx, &pipelines); err != nil {
@@ -54,7 +69,9 @@ func (c *BasePipelineController[PipelineType]) InitAllPipelines(ctx context.Cont
 		}
 		log.Info("initializing existing pipeline", "pipelineName", pipelineConf.Name)
 		c.handlePipelineChange(ctx, &pipelineConf, nil)
+		c.mu.Lock()
 		c.PipelineConfigs[pipelineConf.Name] = pipelineConf
+		c.mu.Unlock()
 	}
 	return nil
 }
@@ -66,15 +83,21 @@ func (c *BasePipelineController[PipelineType]) handlePipelineChange(
 	_ workqueue.TypedRateLimitingInterface[reconcile.Request],
 ) {
 
+	defer c.observeReadyPipelines()
 	if obj.Spec.SchedulingDomain != c.SchedulingDomain {
-		delete(c.Pipelines, obj.Name) // Just to be sure.
-		delete(c.PipelineConfigs, obj.Name)
+		c.removePipeline(obj.Name) // Just to be sure.
 		return
 	}
 	log := ctrl.LoggerFrom(ctx)
 	old := obj.DeepCopy()
 
 	initResult := c.Initializer.InitPipeline(ctx, *obj)
+	c.mu.Lock()
+	if c.StepKnowledges == nil {
+		c.StepKnowledges = make(map[string]map[string][]string)
+	}
+	c.StepKnowledges[obj.Name] = initResult.StepKnowledges
+	c.mu.Unlock()
 
 	// If there was a critical error, the pipeline cannot be used.
 	if len(initResult.FilterErrors) > 0 {
@@ -96,8 +119,10 @@ func (c *BasePipelineController[PipelineType]) handlePipelineChange(
 		if err := c.Status().Patch(ctx, obj, patch); err != nil {
 			log.Error(err, "failed to patch pipeline status", "pipelineName", obj.Name)
 		}
+		c.mu.Lock()
 		delete(c.Pipelines, obj.Name)
 		delete(c.PipelineConfigs, obj.Name)
+		c.mu.Unlock()
 		return
 	}
 
@@ -153,8 +178,10 @@ func (c *BasePipelineController[PipelineType]) handlePipelineChange(
 		})
 	}
 
+	c.mu.Lock()
 	c.Pipelines[obj.Name] = initResult.Pipeline
 	c.PipelineConfigs[obj.Name] = *obj
+	c.mu.Unlock()
 	log.Info("pipeline created and ready", "pipelineName", obj.Name)
 	meta.SetStatusCondition(&obj.Status.Conditions, metav1.Condition{
 		Type:    v1alpha1.PipelineConditionReady,
@@ -207,8 +234,17 @@ func (c *BasePipelineController[PipelineType]) HandlePipelineDeleted(
 ) {
 
 	pipelineConf := evt.Object.(*v1alpha1.Pipeline)
-	delete(c.Pipelines, pipelineConf.Name)
-	delete(c.PipelineConfigs, pipelineConf.Name)
+	c.removePipeline(pipelineConf.Name)
+	c.observeReadyPipelines()
+}
+
+// Remove the pipeline with the given name from all pipeline maps.
+func (c *BasePipelineController[PipelineType]) removePipeline(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.Pipelines, name)
+	delete(c.PipelineConfigs, name)
+	delete(c.StepKnowledges, name)
 }
 
 // Handle a knowledge creation, readiness update, or delete event from watching knowledge resources.
@@ -270,7 +306,9 @@ func (c *BasePipelineController[PipelineType]) HandleKnowledgeUpdated(
 	errorAfter := meta.IsStatusConditionFalse(after.Status.Conditions, v1alpha1.KnowledgeConditionReady)
 	errorChanged := errorBefore != errorAfter
 	dataBecameAvailable := before.Status.RawLength == 0 && after.Status.RawLength > 0
-	if !errorChanged && !dataBecameAvailable {
+	// Pipelines depending on emptied knowledge can no longer be considered ready.
+	dataDisappeared := before.Status.RawLength > 0 && after.Status.RawLength == 0
+	if !errorChanged && !dataBecameAvailable && !dataDisappeared {
 		// No relevant change, skip re-evaluation.
 		return
 	}
@@ -289,3 +327,93 @@ func (c *BasePipelineController[PipelineType]) HandleKnowledgeDeleted(
 	knowledgeConf := evt.Object.(*v1alpha1.Knowledge)
 	c.handleKnowledgeChange(ctx, knowledgeConf, queue)
 }
+
+// Check if a pipeline with the given name is configured for this controller.
+// The pipeline may still fail to initialize, so it isn't necessarily ready.
+func (c *BasePipelineController[PipelineType]) HasPipeline(name string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	_, ok := c.PipelineConfigs[name]
+	return ok
+}
+
+// Get the label to report decisions for the pipeline with the given name in
+// metrics. The name comes from the request, so pipelines that aren't
+// configured are reported as UnknownPipelineLabel.
+func (c *BasePipelineController[PipelineType]) PipelineLabel(name string) string {
+	if !c.HasPipeline(name) {
+		return UnknownPipelineLabel
+	}
+	return name
+}
+
+// Get the initialized pipeline with the given name, if it is ready.
+func (c *BasePipelineController[PipelineType]) GetPipeline(name string) (PipelineType, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	pipeline, ok := c.Pipelines[name]
+	return pipeline, ok
+}
+
+// Get the configuration of the pipeline with the given name.
+func (c *BasePipelineController[PipelineType]) GetPipelineConfig(name string) (v1alpha1.Pipeline, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	conf, ok := c.PipelineConfigs[name]
+	return conf, ok
+}
+
+// Count the pipelines that are initialized and ready to serve requests.
+func (c *BasePipelineController[PipelineType]) ReadyPipelineCount() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.Pipelines)
+}
+
+// Notify the ready pipelines observer, if any, of the current count.
+func (c *BasePipelineController[PipelineType]) observeReadyPipelines() {
+	if c.ObserveReadyPipelines != nil {
+		c.ObserveReadyPipelines(c.ReadyPipelineCount())
+	}
+}
+
+// Describe the pipelines currently held in memory by this controller.
+//
+// This only reads the live pipeline maps and doesn't query the cluster, so it
+// reflects exactly what the controller uses to serve requests.
+func (c *BasePipelineController[PipelineType]) DescribePipelines() []PipelineDescription {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	descriptions := make([]PipelineDescription, 0, len(c.PipelineConfigs))
+	for name, conf := range c.PipelineConfigs {
+		_, ready := c.Pipelines[name]
+		description := PipelineDescription{
+			Name:             name,
+			SchedulingDomain: conf.Spec.SchedulingDomain,
+			Type:             conf.Spec.Type,
+			Ready:            ready,
+			Conditions:       conf.Status.Conditions,
+		}
+		knowledges := c.StepKnowledges[name]
+		for _, filter := range conf.Spec.Filters {
+			description.Steps = append(description.Steps, PipelineStepDescription{
+				Name: filter.Name, Kind: "filter", Knowledges: knowledges[filter.Name],
+			})
+		}
+		for _, weigher := range conf.Spec.Weighers {
+			description.Steps = append(description.Steps, PipelineStepDescription{
+				Name: weigher.Name, Kind: "weigher", Knowledges: knowledges[weigher.Name],
+			})
+		}
+		for _, detector := range conf.Spec.Detectors {
+			description.Steps = append(description.Steps, PipelineStepDescription{
+				Name: detector.Name, Kind: "detector", Knowledges: knowledges[detector.Name],
+			})
+		}
+		descriptions = append(descriptions, description)
+	}
+	slices.SortFunc(descriptions, func(a, b PipelineDescription) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return descriptions
+}
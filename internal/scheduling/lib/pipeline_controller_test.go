@@ -8,6 +8,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"sync"
 	"testing"
 
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -493,6 +494,8 @@ func TestBasePipelineController_HandlePipelineCreated(t *testing.T) {
 		Pipelines:       make(map[string]mockPipeline),
 		PipelineConfigs: make(map[string]v1alpha1.Pipeline),
 	}
+	readyPipelines := -1
+	controller.ObserveReadyPipelines = func(count int) { readyPipelines = count }
 
 	evt := event.CreateEvent{
 		Object: pipeline,
@@ -503,6 +506,9 @@ func TestBasePipelineController_HandlePipelineCreated(t *testing.T) {
 	if _, exists := controller.Pipelines[pipeline.Name]; !exists {
 		t.Error("Expected pipeline to be in map after creation")
 	}
+	if readyPipelines != 1 {
+		t.Errorf("Expected 1 ready pipeline to be observed, got %d", readyPipelines)
+	}
 }
 
 func TestBasePipelineController_HandlePipelineUpdated(t *testing.T) {
@@ -578,6 +584,8 @@ func TestBasePipelineController_HandlePipelineDeleted(t *testing.T) {
 			"test-pipeline": *pipeline,
 		},
 	}
+	readyPipelines := -1
+	controller.ObserveReadyPipelines = func(count int) { readyPipelines = count }
 
 	evt := event.DeleteEvent{
 		Object: pipeline,
@@ -585,6 +593,10 @@ func TestBasePipelineController_HandlePipelineDeleted(t *testing.T) {
 
 	controller.HandlePipelineDeleted(context.Background(), evt, nil)
 
+	if readyPipelines != 0 {
+		t.Errorf("Expected 0 ready pipelines to be observed, got %d", readyPipelines)
+	}
+
 	if _, exists := controller.Pipelines[pipeline.Name]; exists {
 		t.Error("Expected pipeline to be removed from map after deletion")
 	}
@@ -593,6 +605,74 @@ func TestBasePipelineController_HandlePipelineDeleted(t *testing.T) {
 	}
 }
 
+func TestBasePipelineController_ConcurrentAccess(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("Failed to add v1alpha1 scheme: %v", err)
+	}
+
+	pipeline := &v1alpha1.Pipeline{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-pipeline",
+		},
+		Spec: v1alpha1.PipelineSpec{
+			SchedulingDomain: v1alpha1.SchedulingDomainNova,
+			Type:             v1alpha1.PipelineTypeFilterWeigher,
+		},
+	}
+	controller := &BasePipelineController[mockPipeline]{
+		Initializer: &mockPipelineInitializer{
+			pipelineType: v1alpha1.PipelineTypeFilterWeigher,
+		},
+		Client:           fake.NewClientBuilder().WithScheme(scheme).WithObjects(pipeline).WithStatusSubresource(pipeline).Build(),
+		SchedulingDomain: v1alpha1.SchedulingDomainNova,
+	}
+	if err := controller.InitAllPipelines(context.Background()); err != nil {
+		t.Fatalf("Failed to init pipelines: %v", err)
+	}
+
+	// Writers and readers run concurrently, run with -race to detect issues.
+	var wg sync.WaitGroup
+	for i := range 10 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if i%2 == 0 {
+				controller.HandlePipelineCreated(context.Background(), event.CreateEvent{Object: pipeline.DeepCopy()}, nil)
+			} else {
+				controller.HandlePipelineDeleted(context.Background(), event.DeleteEvent{Object: pipeline.DeepCopy()}, nil)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			controller.DescribePipelines()
+			controller.HasPipeline(pipeline.Name)
+			controller.GetPipeline(pipeline.Name)
+			controller.GetPipelineConfig(pipeline.Name)
+			controller.ReadyPipelineCount()
+		}()
+	}
+	wg.Wait()
+
+	// Settle on a known state.
+	controller.HandlePipelineCreated(context.Background(), event.CreateEvent{Object: pipeline.DeepCopy()}, nil)
+	if _, ok := controller.GetPipeline(pipeline.Name); !ok {
+		t.Error("Expected pipeline to be ready")
+	}
+	if !controller.HasPipeline(pipeline.Name) {
+		t.Error("Expected pipeline to be configured")
+	}
+	if count := controller.ReadyPipelineCount(); count != 1 {
+		t.Errorf("Expected 1 ready pipeline, got %d", count)
+	}
+	if label := controller.PipelineLabel(pipeline.Name); label != pipeline.Name {
+		t.Errorf("Expected label %q for a configured pipeline, got %q", pipeline.Name, label)
+	}
+	if label := controller.PipelineLabel("not-configured"); label != UnknownPipelineLabel {
+		t.Errorf("Expected label %q for an unknown pipeline, got %q", UnknownPipelineLabel, label)
+	}
+}
+
 func TestBasePipelineController_handleKnowledgeChange(t *testing.T) {
 	scheme := runtime.NewScheme()
 	if err := v1alpha1.AddToScheme(scheme); err != nil {
@@ -855,6 +935,34 @@ func TestBasePipelineController_HandleKnowledgeUpdated(t *testing.T) {
 			},
 			expectReEvaluate: true,
 		},
+		{
+			name: "data disappeared",
+			oldKnowledge: &v1alpha1.Knowledge{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-knowledge",
+					Namespace: "default",
+				},
+				Spec: v1alpha1.KnowledgeSpec{
+					SchedulingDomain: v1alpha1.SchedulingDomainNova,
+				},
+				Status: v1alpha1.KnowledgeStatus{
+					RawLength: 10,
+				},
+			},
+			newKnowledge: &v1alpha1.Knowledge{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-knowledge",
+					Namespace: "default",
+				},
+				Spec: v1alpha1.KnowledgeSpec{
+					SchedulingDomain: v1alpha1.SchedulingDomainNova,
+				},
+				Status: v1alpha1.KnowledgeStatus{
+					RawLength: 0,
+				},
+			},
+			expectReEvaluate: true,
+		},
 		{
 			name: "no relevant change",
 			oldKnowledge: &v1alpha1.Knowledge{
@@ -26,6 +26,14 @@ type PipelineInitResult[PipelineType any] struct {
 	DetectorErrors map[string]error
 	// Unknown detectors that were referenced but not found in the index, by their name.
 	UnknownDetectors []string
+	// Names of the knowledges the steps depend on, by step name.
+	StepKnowledges map[string][]string
+}
+
+// Implemented by steps that can tell which knowledges they depend on.
+type KnowledgeDependent interface {
+	// Get the names of the knowledges this step depends on.
+	Knowledges() []string
 }
 
 // The base pipeline controller will delegate some methods to the parent
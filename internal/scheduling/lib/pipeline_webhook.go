@@ -32,6 +32,20 @@ type PipelineAdmissionWebhook struct {
 	ValidatableWeighers map[string]Validatable
 	// ValidatableDetectors maps detector names to validatable detector instances.
 	ValidatableDetectors map[string]Validatable
+	// ValidatableDrainWeighers maps the names of weighers supported in drain
+	// pipelines to validatable weigher instances.
+	ValidatableDrainWeighers map[string]Validatable
+	// If set, pipelines referencing steps that are not in the indexes are
+	// rejected instead of only being admitted with a warning.
+	RejectUnknownSteps bool
+}
+
+// Configuration of the pipeline admission webhooks.
+type PipelineWebhookConfig struct {
+	// If set, pipelines referencing unsupported steps are rejected. By default
+	// they are admitted with a warning, so that pipelines can be rolled out
+	// before the cortex version supporting their steps.
+	RejectUnknownSteps bool `json:"rejectUnknownSteps,omitempty"`
 }
 
 // ValidateCreate implements admission.Validator.
@@ -87,6 +101,8 @@ func (w *PipelineAdmissionWebhook) validatePipeline(
 	// doesn't exist in our indexes, in case cortex is updated with new
 	// filters/weighers/detectors, so we don't break our rollout.
 	var warnings []string
+	// Names of the referenced steps that don't exist in our indexes.
+	var unknownSteps []string
 
 	// Validate based on pipeline type
 	switch pipeline.Spec.Type {
@@ -100,6 +116,7 @@ func (w *PipelineAdmissionWebhook) validatePipeline(
 			filter, ok := w.ValidatableFilters[filterSpec.Name]
 			if !ok {
 				warnings = append(warnings, fmt.Sprintf("unknown filter %q: this filter will be ignored", filterSpec.Name))
+				unknownSteps = append(unknownSteps, filterSpec.Name)
 				continue
 			}
 			if err := filter.Validate(ctx, filterSpec.Params); err != nil {
@@ -110,6 +127,7 @@ func (w *PipelineAdmissionWebhook) validatePipeline(
 			weigher, ok := w.ValidatableWeighers[weigherSpec.Name]
 			if !ok {
 				warnings = append(warnings, fmt.Sprintf("unknown weigher %q: this weigher will be ignored", weigherSpec.Name))
+				unknownSteps = append(unknownSteps, weigherSpec.Name)
 				continue
 			}
 			if err := weigher.Validate(ctx, weigherSpec.Params); err != nil {
@@ -129,16 +147,42 @@ func (w *PipelineAdmissionWebhook) validatePipeline(
 			detector, ok := w.ValidatableDetectors[detectorSpec.Name]
 			if !ok {
 				warnings = append(warnings, fmt.Sprintf("unknown detector %q: this detector will be ignored", detectorSpec.Name))
+				unknownSteps = append(unknownSteps, detectorSpec.Name)
 				continue
 			}
 			if err := detector.Validate(ctx, detectorSpec.Params); err != nil {
 				errMsgs = append(errMsgs, fmt.Sprintf("detector %q: %v", detectorSpec.Name, err))
 			}
 		}
+	case v1alpha1.PipelineTypeDrain:
+		// Drain pipelines only score hosts, so filters and detectors
+		// are not allowed in them.
+		if len(pipeline.Spec.Filters) > 0 {
+			errMsgs = append(errMsgs, "filters are not allowed in a drain pipeline")
+		}
+		if len(pipeline.Spec.Detectors) > 0 {
+			errMsgs = append(errMsgs, "detectors are not allowed in a drain pipeline")
+		}
+		for _, weigherSpec := range pipeline.Spec.Weighers {
+			weigher, ok := w.ValidatableDrainWeighers[weigherSpec.Name]
+			if !ok {
+				warnings = append(warnings, fmt.Sprintf("unknown drain weigher %q: this weigher will be ignored", weigherSpec.Name))
+				unknownSteps = append(unknownSteps, weigherSpec.Name)
+				continue
+			}
+			if err := weigher.Validate(ctx, weigherSpec.Params); err != nil {
+				errMsgs = append(errMsgs, fmt.Sprintf("weigher %q: %v", weigherSpec.Name, err))
+			}
+		}
 	default:
 		errMsgs = append(errMsgs, fmt.Sprintf("unknown pipeline type: %s", pipeline.Spec.Type))
 	}
 
+	if w.RejectUnknownSteps && len(unknownSteps) > 0 {
+		errMsgs = append(errMsgs, fmt.Sprintf("unsupported steps for scheduling domain %s: %s",
+			w.SchedulingDomain, strings.Join(unknownSteps, ", ")))
+	}
+
 	if len(errMsgs) > 0 {
 		return warnings, fmt.Errorf("pipeline is invalid: %s", strings.Join(errMsgs, "; "))
 	}
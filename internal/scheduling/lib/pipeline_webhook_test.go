@@ -328,6 +328,111 @@ func TestPipelineAdmissionWebhook_ValidateCreate_DetectorPipeline(t *testing.T)
 	}
 }
 
+func TestPipelineAdmissionWebhook_ValidateCreate_DrainPipeline(t *testing.T) {
+	tests := []struct {
+		name           string
+		pipeline       *v1alpha1.Pipeline
+		expectError    bool
+		expectWarnings bool
+	}{
+		{
+			name: "valid drain pipeline with known weigher",
+			pipeline: &v1alpha1.Pipeline{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-pipeline"},
+				Spec: v1alpha1.PipelineSpec{
+					SchedulingDomain: v1alpha1.SchedulingDomainNova,
+					Type:             v1alpha1.PipelineTypeDrain,
+					Weighers:         []v1alpha1.WeigherSpec{{Name: "drain-weigher1"}},
+				},
+			},
+			expectError:    false,
+			expectWarnings: false,
+		},
+		{
+			name: "drain pipeline with placement weigher",
+			pipeline: &v1alpha1.Pipeline{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-pipeline"},
+				Spec: v1alpha1.PipelineSpec{
+					SchedulingDomain: v1alpha1.SchedulingDomainNova,
+					Type:             v1alpha1.PipelineTypeDrain,
+					Weighers:         []v1alpha1.WeigherSpec{{Name: "weigher1"}},
+				},
+			},
+			expectError:    false,
+			expectWarnings: true,
+		},
+		{
+			name: "drain pipeline with unknown weigher",
+			pipeline: &v1alpha1.Pipeline{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-pipeline"},
+				Spec: v1alpha1.PipelineSpec{
+					SchedulingDomain: v1alpha1.SchedulingDomainNova,
+					Type:             v1alpha1.PipelineTypeDrain,
+					Weighers:         []v1alpha1.WeigherSpec{{Name: "unknown-weigher"}},
+				},
+			},
+			expectError:    false,
+			expectWarnings: true,
+		},
+		{
+			name: "invalid drain pipeline with filters",
+			pipeline: &v1alpha1.Pipeline{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-pipeline"},
+				Spec: v1alpha1.PipelineSpec{
+					SchedulingDomain: v1alpha1.SchedulingDomainNova,
+					Type:             v1alpha1.PipelineTypeDrain,
+					Filters:          []v1alpha1.FilterSpec{{Name: "filter1"}},
+					Weighers:         []v1alpha1.WeigherSpec{{Name: "drain-weigher1"}},
+				},
+			},
+			expectError:    true,
+			expectWarnings: false,
+		},
+		{
+			name: "invalid drain pipeline with detectors",
+			pipeline: &v1alpha1.Pipeline{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-pipeline"},
+				Spec: v1alpha1.PipelineSpec{
+					SchedulingDomain: v1alpha1.SchedulingDomainNova,
+					Type:             v1alpha1.PipelineTypeDrain,
+					Detectors:        []v1alpha1.DetectorSpec{{Name: "detector1"}},
+				},
+			},
+			expectError:    true,
+			expectWarnings: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			webhook := &PipelineAdmissionWebhook{
+				SchedulingDomain:     v1alpha1.SchedulingDomainNova,
+				ValidatableFilters:   map[string]Validatable{"filter1": &mockValidatable{}},
+				ValidatableWeighers:  map[string]Validatable{"weigher1": &mockValidatable{}},
+				ValidatableDetectors: map[string]Validatable{"detector1": &mockValidatable{}},
+				ValidatableDrainWeighers: map[string]Validatable{
+					"drain-weigher1": &mockValidatable{},
+				},
+			}
+
+			warnings, err := webhook.ValidateCreate(t.Context(), tt.pipeline)
+
+			if tt.expectError && err == nil {
+				t.Error("expected error but got nil")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("expected no error but got: %v", err)
+			}
+			if tt.expectWarnings && len(warnings) == 0 {
+				t.Error("expected warnings but got none")
+			}
+			if !tt.expectWarnings && len(warnings) > 0 {
+				t.Errorf("expected no warnings but got: %v", warnings)
+			}
+		})
+	}
+}
+
 func TestPipelineAdmissionWebhook_ValidateCreate_DifferentSchedulingDomain(t *testing.T) {
 	webhook := &PipelineAdmissionWebhook{
 		SchedulingDomain:     v1alpha1.SchedulingDomainNova,
@@ -473,6 +578,42 @@ func TestPipelineAdmissionWebhook_MultipleValidationWarnings(t *testing.T) {
 	}
 }
 
+func TestPipelineAdmissionWebhook_RejectUnknownSteps(t *testing.T) {
+	webhook := &PipelineAdmissionWebhook{
+		SchedulingDomain:     v1alpha1.SchedulingDomainNova,
+		ValidatableFilters:   map[string]Validatable{"known-filter": &mockValidatable{}},
+		ValidatableWeighers:  map[string]Validatable{},
+		ValidatableDetectors: map[string]Validatable{},
+		RejectUnknownSteps:   true,
+	}
+
+	pipeline := &v1alpha1.Pipeline{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-pipeline"},
+		Spec: v1alpha1.PipelineSpec{
+			SchedulingDomain: v1alpha1.SchedulingDomainNova,
+			Type:             v1alpha1.PipelineTypeFilterWeigher,
+			Filters: []v1alpha1.FilterSpec{
+				{Name: "known-filter", Params: nil},
+				{Name: "unknown-filter", Params: nil},
+			},
+			Weighers: []v1alpha1.WeigherSpec{
+				{Name: "unknown-weigher", Params: nil},
+			},
+		},
+	}
+
+	_, err := webhook.ValidateCreate(t.Context(), pipeline)
+	if err == nil {
+		t.Fatal("expected error for unknown steps, got nil")
+	}
+	if !strings.Contains(err.Error(), "unknown-filter, unknown-weigher") {
+		t.Errorf("expected error to list the unknown steps, got %q", err.Error())
+	}
+	if !strings.Contains(err.Error(), "steps for scheduling domain nova: unknown-filter,") {
+		t.Errorf("expected error not to list known steps, got %q", err.Error())
+	}
+}
+
 func TestPipelineAdmissionWebhook_EmptyPipeline(t *testing.T) {
 	webhook := &PipelineAdmissionWebhook{
 		SchedulingDomain:     v1alpha1.SchedulingDomainNova,
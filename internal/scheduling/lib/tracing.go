@@ -0,0 +1,122 @@
+// Copyright SAP SE
+// SPDX-License-Identifier: Apache-2.0
+
+package lib
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Configuration for exporting traces of scheduling requests.
+type TracingConfig struct {
+	// Whether to create spans for scheduling requests. Defaults to false.
+	TracingEnabled bool `json:"tracingEnabled,omitempty"`
+	// OTLP gRPC endpoint url to export the spans to, e.g.
+	// "http://otel-collector:4317". If unset, the standard
+	// OTEL_EXPORTER_OTLP_* environment variables are used.
+	TracingEndpoint string `json:"tracingEndpoint,omitempty"`
+}
+
+var (
+	// Tracer for scheduling spans, nil if tracing is disabled.
+	tracer trace.Tracer
+	// Propagator to extract the trace context from incoming requests.
+	tracePropagator propagation.TextMapPropagator = propagation.TraceContext{}
+)
+
+// Set up exporting spans of scheduling requests if enabled in the config.
+// The returned function flushes and stops the exporter.
+func InitTracing(ctx context.Context, conf TracingConfig) (shutdown func(context.Context) error, err error) {
+	if !conf.TracingEnabled {
+		return func(context.Context) error { return nil }, nil
+	}
+	var opts []otlptracegrpc.Option
+	if conf.TracingEndpoint != "" {
+		opts = append(opts, otlptracegrpc.WithEndpointURL(conf.TracingEndpoint))
+	}
+	exporter, err := otlptracegrpc.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create trace exporter: %w", err)
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", "cortex"))),
+	)
+	tracer = provider.Tracer("github.com/cobaltcore-dev/cortex/internal/scheduling")
+	return provider.Shutdown, nil
+}
+
+// Extract the trace context propagated in the headers of an incoming
+// request, so that scheduling spans are children of the caller's span.
+func ExtractTraceContext(ctx context.Context, header http.Header) context.Context {
+	if tracer == nil {
+		return ctx
+	}
+	return tracePropagator.Extract(ctx, propagation.HeaderCarrier(header))
+}
+
+// Span of a scheduling request. The zero value is a no-op span,
+// which is used when tracing is disabled.
+type Span struct {
+	span trace.Span
+}
+
+// Start a span with the given attributes, e.g. the trace log args of a
+// scheduling request. Does nothing if tracing is disabled.
+func StartSpan(ctx context.Context, name string, attrs ...slog.Attr) (context.Context, Span) {
+	if tracer == nil {
+		return ctx, Span{}
+	}
+	ctx, span := tracer.Start(ctx, name, trace.WithAttributes(spanAttributes(attrs)...))
+	return ctx, Span{span: span}
+}
+
+// Add attributes to the span.
+func (s Span) SetAttributes(attrs ...slog.Attr) {
+	if s.span == nil {
+		return
+	}
+	s.span.SetAttributes(spanAttributes(attrs)...)
+}
+
+// End the span, marking it as failed if an error is given.
+func (s Span) End(err error) {
+	if s.span == nil {
+		return
+	}
+	if err != nil {
+		s.span.RecordError(err)
+		s.span.SetStatus(codes.Error, err.Error())
+	}
+	s.span.End()
+}
+
+// Convert slog attributes into span attributes.
+func spanAttributes(attrs []slog.Attr) []attribute.KeyValue {
+	kvs := make([]attribute.KeyValue, 0, len(attrs))
+	for _, attr := range attrs {
+		value := attr.Value.Resolve()
+		switch value.Kind() {
+		case slog.KindBool:
+			kvs = append(kvs, attribute.Bool(attr.Key, value.Bool()))
+		case slog.KindInt64:
+			kvs = append(kvs, attribute.Int64(attr.Key, value.Int64()))
+		case slog.KindFloat64:
+			kvs = append(kvs, attribute.Float64(attr.Key, value.Float64()))
+		default:
+			kvs = append(kvs, attribute.String(attr.Key, value.String()))
+		}
+	}
+	return kvs
+}
@@ -0,0 +1,113 @@
+// Copyright SAP SE
+// SPDX-License-Identifier: Apache-2.0
+
+package lib
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"testing"
+
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Enable tracing into an in-memory recorder for the duration of the test.
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	tracer = provider.Tracer("test")
+	t.Cleanup(func() { tracer = nil })
+	return recorder
+}
+
+func TestStartSpan_Disabled(t *testing.T) {
+	ctx := context.Background()
+	spanCtx, span := StartSpan(ctx, "test", slog.String("key", "value"))
+	if spanCtx != ctx {
+		t.Error("expected unchanged context when tracing is disabled")
+	}
+	// The no-op span must be safe to use.
+	span.SetAttributes(slog.String("key", "value"))
+	span.End(errors.New("failed"))
+}
+
+func TestStartSpan(t *testing.T) {
+	recorder := recordSpans(t)
+
+	_, span := StartSpan(context.Background(), "test", slog.String("greq", "req-1"), slog.Int("hosts", 2))
+	span.End(errors.New("failed"))
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	if spans[0].Name() != "test" {
+		t.Errorf("expected span name test, got %s", spans[0].Name())
+	}
+	if spans[0].Status().Code != codes.Error {
+		t.Errorf("expected error status, got %v", spans[0].Status())
+	}
+	attrs := map[string]string{}
+	for _, attr := range spans[0].Attributes() {
+		attrs[string(attr.Key)] = attr.Value.Emit()
+	}
+	if attrs["greq"] != "req-1" || attrs["hosts"] != "2" {
+		t.Errorf("unexpected span attributes: %v", attrs)
+	}
+}
+
+func TestExtractTraceContext(t *testing.T) {
+	recordSpans(t)
+	header := http.Header{}
+	header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	ctx := ExtractTraceContext(context.Background(), header)
+	spanContext := trace.SpanContextFromContext(ctx)
+	if spanContext.TraceID().String() != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("expected trace id from header, got %s", spanContext.TraceID())
+	}
+}
+
+func TestPipeline_Run_Spans(t *testing.T) {
+	recorder := recordSpans(t)
+	pipeline := &filterWeigherPipeline[mockFilterWeigherPipelineRequest]{
+		filtersOrder: []string{"mock_filter"},
+		filters: map[string]Filter[mockFilterWeigherPipelineRequest]{
+			"mock_filter": &mockFilter[mockFilterWeigherPipelineRequest]{
+				RunFunc: func(traceLog *slog.Logger, request mockFilterWeigherPipelineRequest) (*FilterWeigherPipelineStepResult, error) {
+					return &FilterWeigherPipelineStepResult{Activations: map[string]float64{"host1": 0.0}}, nil
+				},
+			},
+		},
+	}
+	_, err := pipeline.Run(context.Background(), mockFilterWeigherPipelineRequest{
+		Hosts:        []string{"host1"},
+		Weights:      map[string]float64{"host1": 0.0},
+		TraceLogArgs: []slog.Attr{slog.String("greq", "req-1")},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	spans := map[string]sdktrace.ReadOnlySpan{}
+	for _, span := range recorder.Ended() {
+		spans[span.Name()] = span
+	}
+	pipelineSpan, ok := spans["scheduling pipeline"]
+	if !ok {
+		t.Fatalf("expected pipeline span, got %v", spans)
+	}
+	stepSpan, ok := spans["scheduling step"]
+	if !ok {
+		t.Fatalf("expected step span, got %v", spans)
+	}
+	if stepSpan.Parent().SpanID() != pipelineSpan.SpanContext().SpanID() {
+		t.Error("expected step span to be a child of the pipeline span")
+	}
+}
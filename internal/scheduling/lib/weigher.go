@@ -7,6 +7,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 
 	"github.com/cobaltcore-dev/cortex/api/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
@@ -43,6 +44,11 @@ func (s *BaseWeigher[RequestType, Opts]) Validate(ctx context.Context, params v1
 
 // Check if all knowledges are ready, and if not, return an error indicating why not.
 func (d *BaseFilterWeigherPipelineStep[RequestType, Opts]) CheckKnowledges(ctx context.Context, kns ...corev1.ObjectReference) error {
+	for _, objRef := range kns {
+		if !slices.Contains(d.knowledges, objRef.Name) {
+			d.knowledges = append(d.knowledges, objRef.Name)
+		}
+	}
 	if d.Client == nil {
 		return errors.New("kubernetes client not initialized")
 	}
@@ -0,0 +1,44 @@
+// Copyright SAP SE
+// SPDX-License-Identifier: Apache-2.0
+
+package lib
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/cobaltcore-dev/cortex/api/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Weigher that takes the place of a weigher which could not be initialized.
+// It assigns the configured fallback activation to all hosts, so that the
+// number of weighers in the pipeline and thus the scale of the combined
+// weights stays stable while the original weigher is unavailable.
+type fallbackWeigher[RequestType FilterWeigherPipelineRequest] struct {
+	// The activation assigned to all hosts.
+	activation float64
+}
+
+// Nothing to initialize for the fallback weigher.
+func (w *fallbackWeigher[RequestType]) Init(ctx context.Context, client client.Client, step v1alpha1.WeigherSpec) error {
+	return nil
+}
+
+// The fallback weigher has no parameters to validate.
+func (w *fallbackWeigher[RequestType]) Validate(ctx context.Context, params v1alpha1.Parameters) error {
+	return nil
+}
+
+// Assign the fallback activation to all hosts in the request.
+func (w *fallbackWeigher[RequestType]) Run(traceLog *slog.Logger, request RequestType) (*FilterWeigherPipelineStepResult, error) {
+	activations := make(map[string]float64, len(request.GetHosts()))
+	for _, host := range request.GetHosts() {
+		activations[host] = w.activation
+	}
+	traceLog.Info("scheduler: using fallback activation", "activation", w.activation)
+	return &FilterWeigherPipelineStepResult{
+		Activations: activations,
+		Statistics:  map[string]FilterWeigherPipelineStepStatistics{},
+	}, nil
+}
@@ -246,6 +246,19 @@ func TestBaseFilterWeigherPipelineStep_CheckKnowledges_NilClient(t *testing.T) {
 	}
 }
 
+func TestBaseFilterWeigherPipelineStep_Knowledges(t *testing.T) {
+	step := &BaseFilterWeigherPipelineStep[mockFilterWeigherPipelineRequest, weigherTestOptions]{}
+
+	// Dependencies are recorded even if the knowledges can't be checked.
+	_ = step.CheckKnowledges(t.Context(), corev1.ObjectReference{Name: "kn1"}, corev1.ObjectReference{Name: "kn2"})
+	_ = step.CheckKnowledges(t.Context(), corev1.ObjectReference{Name: "kn1"})
+
+	got := step.Knowledges()
+	if len(got) != 2 || got[0] != "kn1" || got[1] != "kn2" {
+		t.Errorf("expected knowledges [kn1 kn2], got %v", got)
+	}
+}
+
 func containsString(s, substr string) bool {
 	return len(s) >= len(substr) && (s == substr || s != "" && containsSubstring(s, substr))
 }
@@ -6,6 +6,7 @@ package lib
 import (
 	"fmt"
 	"math"
+	"slices"
 	"sort"
 	"strings"
 
@@ -40,10 +41,11 @@ type weigherContribution struct {
 //
 //  1. Multiplier recovery: The pipeline applies weights via an additive formula:
 //     AggregatedOut[h] = NormalizedIn[h] + sum_i(mult_i * tanh(act_i[h])).
-//     Since DecisionResult stores raw activations but not multipliers, we recover
-//     them by solving the over-determined linear system (M hosts, N weighers)
-//     via least-squares (normal equations). This handles negative multipliers
-//     correctly and produces exact results when M >= N.
+//     If the step results carry the multipliers applied by the pipeline, they
+//     are used as-is. Older decisions only store raw activations, so for them
+//     we recover the multipliers by solving the over-determined linear system
+//     (M hosts, N weighers) via least-squares (normal equations). This handles
+//     negative multipliers correctly and produces exact results when M >= N.
 //
 //  2. Counterfactual analysis: For the #1 host, we ask "if weigher X were
 //     removed, would a different host be selected?" This identifies decisive
@@ -69,12 +71,13 @@ func ExplainWeighing(result *v1alpha1.DecisionResult) string {
 	topN := min(maxExplainedHosts, len(result.OrderedHosts))
 	topHosts := result.OrderedHosts[:topN]
 
-	// Recover the multipliers from the linear system:
+	// Use the multipliers stored with the step results, or recover them from
+	// the linear system:
 	//   sum_i(mult_i * tanh(act_i[h])) = AggregatedOut[h] - NormalizedIn[h]
 	// using all ordered hosts as data points for a least-squares fit.
 	// Falls back to initial-bias-only explanation if recovery fails (e.g., all
 	// activations are zero making the matrix singular, or under-determined).
-	multipliers, ok := recoverMultipliers(weigherSteps, result.OrderedHosts, result.NormalizedInWeights, result.AggregatedOutWeights)
+	multipliers, ok := resolveMultipliers(result, weigherSteps)
 	if !ok {
 		return explainWithoutMultipliers(result, topHosts, weigherSteps)
 	}
@@ -192,9 +195,84 @@ func ExplainWeighing(result *v1alpha1.DecisionResult) string {
 			strings.Join(names, ", "), topN)
 	}
 
+	// --- Share of each weigher in the final score of the #1 host ---
+	initialWeight := 0.0
+	if result.NormalizedInWeights != nil {
+		initialWeight = result.NormalizedInWeights[topHosts[0]]
+	}
+	if breakdown := explainScoreBreakdown(topHosts[0], initialWeight, weigherSteps, contributions); breakdown != "" {
+		sb.WriteString(breakdown)
+	}
+
 	return strings.TrimSpace(sb.String())
 }
 
+// findCriticalWeighers returns the weighers without which a different host
+// would have been ranked #1, in pipeline order. Returns nil if the weigher
+// multipliers can't be recovered from the result.
+func findCriticalWeighers(result *v1alpha1.DecisionResult) []string {
+	if result == nil || len(result.OrderedHosts) < 2 {
+		return nil
+	}
+	weigherSteps := identifyWeigherSteps(result)
+	if len(weigherSteps) == 0 {
+		return nil
+	}
+	multipliers, ok := resolveMultipliers(result, weigherSteps)
+	if !ok {
+		return nil
+	}
+	var critical []string
+	for i, step := range weigherSteps {
+		contributions := make(map[string]float64, len(result.OrderedHosts))
+		for _, h := range result.OrderedHosts {
+			contributions[h] = multipliers[i] * math.Tanh(step.Activations[h])
+		}
+		newRanking := computeCounterfactualRanking(result.OrderedHosts, result.AggregatedOutWeights, contributions)
+		if newRanking[0] != result.OrderedHosts[0] {
+			critical = append(critical, step.StepName)
+		}
+	}
+	return critical
+}
+
+// explainScoreBreakdown reports which share of the final score of the given
+// host came from each weigher and from the initial weight. Shares are taken
+// relative to the sum of the absolute contributions, so opposing weighers show
+// up with a negative percentage and the absolute shares add up to 100%.
+// Contributions below the negligible threshold are left out. Returns an empty
+// string if there is nothing to report.
+func explainScoreBreakdown(
+	host string,
+	initialWeight float64,
+	weigherSteps []v1alpha1.StepResult,
+	contributions []map[string]float64,
+) string {
+
+	total := math.Abs(initialWeight)
+	for i := range weigherSteps {
+		total += math.Abs(contributions[i][host])
+	}
+	if total < negligibleContributionThreshold {
+		return ""
+	}
+	var parts []string
+	for i, step := range weigherSteps {
+		c := contributions[i][host]
+		if math.Abs(c) < negligibleContributionThreshold {
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%s %+.0f%%", step.StepName, 100*c/total))
+	}
+	if math.Abs(initialWeight) >= negligibleContributionThreshold {
+		parts = append(parts, fmt.Sprintf("initial weight %+.0f%%", 100*initialWeight/total))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("  Score of %s came from: %s.\n", host, strings.Join(parts, ", "))
+}
+
 // explainWithoutMultipliers provides a simpler explanation when multiplier
 // recovery is not possible (e.g., all activations are zero, or the system is
 // under-determined). It reports only the initial weight bias and raw activation
@@ -236,10 +314,23 @@ func explainWithoutMultipliers(result *v1alpha1.DecisionResult, topHosts []strin
 }
 
 // identifyWeigherSteps returns the subset of step results that represent
-// weigher (scoring) steps rather than filter steps. A weigher step is one
-// whose activation map contains entries for ALL hosts in OrderedHosts —
+// weigher (scoring) steps rather than filter steps. If any step result carries
+// a multiplier, exactly those steps are weighers. Otherwise, a weigher step is
+// one whose activation map contains entries for ALL hosts in OrderedHosts —
 // filters reduce the host set while weighers score all remaining hosts.
 func identifyWeigherSteps(result *v1alpha1.DecisionResult) []v1alpha1.StepResult {
+	if slices.ContainsFunc(result.StepResults, func(step v1alpha1.StepResult) bool {
+		return step.Multiplier != nil
+	}) {
+		var weigherSteps []v1alpha1.StepResult
+		for _, step := range result.StepResults {
+			if step.Multiplier != nil && len(step.Activations) > 0 {
+				weigherSteps = append(weigherSteps, step)
+			}
+		}
+		return weigherSteps
+	}
+
 	orderedHostSet := make(map[string]struct{}, len(result.OrderedHosts))
 	for _, h := range result.OrderedHosts {
 		orderedHostSet[h] = struct{}{}
@@ -264,6 +355,20 @@ func identifyWeigherSteps(result *v1alpha1.DecisionResult) []v1alpha1.StepResult
 	return weigherSteps
 }
 
+// resolveMultipliers returns the multipliers of the given weigher steps. When
+// all steps carry the multiplier applied by the pipeline, these are returned
+// directly. Otherwise, the multipliers are recovered from the decision result.
+func resolveMultipliers(result *v1alpha1.DecisionResult, weigherSteps []v1alpha1.StepResult) ([]float64, bool) {
+	multipliers := make([]float64, 0, len(weigherSteps))
+	for _, step := range weigherSteps {
+		if step.Multiplier == nil {
+			return recoverMultipliers(weigherSteps, result.OrderedHosts, result.NormalizedInWeights, result.AggregatedOutWeights)
+		}
+		multipliers = append(multipliers, *step.Multiplier)
+	}
+	return multipliers, len(multipliers) > 0
+}
+
 // recoverMultipliers solves for the weigher multipliers using least-squares.
 //
 // The additive pipeline formula guarantees:
@@ -410,10 +515,12 @@ func computeCounterfactualRanking(
 		hypothetical[h] = aggregatedOut[h] - weigherContribs[h]
 	}
 
-	// Sort by hypothetical score descending.
+	// Sort by hypothetical score descending. Ties keep the given order, so
+	// that the explanation is the same for identical results and a tied
+	// selected host isn't mistaken for a changed winner.
 	ranking := make([]string, len(topHosts))
 	copy(ranking, topHosts)
-	sort.Slice(ranking, func(i, j int) bool {
+	sort.SliceStable(ranking, func(i, j int) bool {
 		return hypothetical[ranking[i]] > hypothetical[ranking[j]]
 	})
 	return ranking
@@ -67,7 +67,7 @@ func TestExplainWeighing(t *testing.T) {
 					},
 				}
 			}(),
-			contains: []string{"host-a", "host-b", "weigher_cpu", "is #1 because of"},
+			contains: []string{"host-a", "host-b", "weigher_cpu", "is #1 because of", "Score of host-a came from: weigher_cpu +84%, initial weight +16%."},
 		},
 		{
 			name: "two hosts single weigher negative multiplier",
@@ -360,6 +360,47 @@ func TestRecoverMultipliers(t *testing.T) {
 	}
 }
 
+func TestResolveMultipliers(t *testing.T) {
+	hosts := []string{"h1", "h2"}
+	acts := map[string]float64{"h1": 0.8, "h2": -0.3}
+	normalizedIn := map[string]float64{"h1": 0.1, "h2": 0.2}
+	aggregatedOut := map[string]float64{
+		"h1": normalizedIn["h1"] + 2.5*math.Tanh(acts["h1"]),
+		"h2": normalizedIn["h2"] + 2.5*math.Tanh(acts["h2"]),
+	}
+	result := &v1alpha1.DecisionResult{
+		OrderedHosts:         hosts,
+		NormalizedInWeights:  normalizedIn,
+		AggregatedOutWeights: aggregatedOut,
+	}
+
+	// Without stored multipliers, they are recovered from the result.
+	steps := []v1alpha1.StepResult{{StepName: "w1", Activations: acts}}
+	multipliers, ok := resolveMultipliers(result, steps)
+	if !ok || len(multipliers) != 1 || math.Abs(multipliers[0]-2.5) > 1e-6 {
+		t.Errorf("got %v (ok=%v), want recovered [2.5]", multipliers, ok)
+	}
+
+	// Stored multipliers are used as-is, even where recovery would fail
+	// because there are more weighers than hosts.
+	m1, m2, m3 := 2.5, 0.0, -1.0
+	steps = []v1alpha1.StepResult{
+		{StepName: "w1", Activations: acts, Multiplier: &m1},
+		{StepName: "w2", Activations: acts, Multiplier: &m2},
+		{StepName: "w3", Activations: acts, Multiplier: &m3},
+	}
+	multipliers, ok = resolveMultipliers(result, steps)
+	if !ok {
+		t.Fatal("resolveMultipliers failed unexpectedly")
+	}
+	want := []float64{2.5, 0.0, -1.0}
+	for i := range want {
+		if multipliers[i] != want[i] {
+			t.Errorf("multiplier[%d] = %v, want %v", i, multipliers[i], want[i])
+		}
+	}
+}
+
 func TestSolveLinearSystem(t *testing.T) {
 	// Simple 2x2 system: 2x + y = 5, x + 3y = 10 => x=1, y=3
 	a := [][]float64{{2, 1}, {1, 3}}
@@ -408,6 +449,66 @@ func TestIdentifyWeigherSteps(t *testing.T) {
 	}
 }
 
+func TestIdentifyWeigherSteps_WithMultipliers(t *testing.T) {
+	mult := 1.0
+	result := &v1alpha1.DecisionResult{
+		OrderedHosts: []string{"a", "b"},
+		StepResults: []v1alpha1.StepResult{
+			// Filter that kept all hosts: only recognizable by the missing multiplier.
+			{StepName: "filter_x", Activations: map[string]float64{"a": 0.0, "b": 0.0}},
+			{StepName: "weigher_y", Activations: map[string]float64{"a": 0.5, "b": 0.3}, Multiplier: &mult},
+		},
+	}
+
+	steps := identifyWeigherSteps(result)
+	if len(steps) != 1 || steps[0].StepName != "weigher_y" {
+		t.Errorf("got %v, want only weigher_y", steps)
+	}
+}
+
+func TestExplainScoreBreakdown(t *testing.T) {
+	steps := []v1alpha1.StepResult{{StepName: "weigher_a"}, {StepName: "weigher_b"}}
+	tests := []struct {
+		name          string
+		initialWeight float64
+		contributions []map[string]float64
+		expected      string
+	}{
+		{
+			name:          "positive contributions",
+			initialWeight: 0,
+			contributions: []map[string]float64{{"h": 0.75}, {"h": 0.25}},
+			expected:      "  Score of h came from: weigher_a +75%, weigher_b +25%.\n",
+		},
+		{
+			name:          "negative contribution and initial weight",
+			initialWeight: 0.2,
+			contributions: []map[string]float64{{"h": 0.6}, {"h": -0.2}},
+			expected:      "  Score of h came from: weigher_a +60%, weigher_b -20%, initial weight +20%.\n",
+		},
+		{
+			name:          "negligible contributions are left out",
+			initialWeight: 0,
+			contributions: []map[string]float64{{"h": 1.0}, {"h": 0.001}},
+			expected:      "  Score of h came from: weigher_a +100%.\n",
+		},
+		{
+			name:          "all contributions zero",
+			initialWeight: 0,
+			contributions: []map[string]float64{{"h": 0}, {"h": 0}},
+			expected:      "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := explainScoreBreakdown("h", tt.initialWeight, steps, tt.contributions)
+			if got != tt.expected {
+				t.Errorf("got %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
 // sortByWeight sorts hosts in descending order of their weight (for test setup).
 func sortByWeight(hosts []string, weights map[string]float64) {
 	sort.Slice(hosts, func(i, j int) bool {
@@ -24,9 +24,20 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/metrics"
 )
 
+// Custom configuration for the Manila external scheduler api.
+type HTTPAPIConfig struct {
+	// Pipeline to use if the request's pipeline is not configured.
+	ManilaDefaultPipeline string `json:"manilaDefaultPipeline,omitempty"`
+	// Maximum time to spend on a scheduling request. If exceeded, the pipeline
+	// run is aborted and the request fails with a timeout. Zero disables it.
+	RequestTimeout metav1.Duration `json:"requestTimeout,omitempty"`
+}
+
 type HTTPAPIDelegate interface {
 	// Process the decision from the API. Should create and return the updated decision.
 	ProcessNewDecisionFromAPI(ctx context.Context, decision *v1alpha1.Decision) error
+	// Check if a pipeline with the given name is configured.
+	HasPipeline(name string) bool
 }
 
 type HTTPAPI interface {
@@ -37,12 +48,14 @@ type HTTPAPI interface {
 type httpAPI struct {
 	monitor  scheduling.APIMonitor
 	delegate HTTPAPIDelegate
+	config   HTTPAPIConfig
 }
 
-func NewAPI(delegate HTTPAPIDelegate) HTTPAPI {
+func NewAPI(config HTTPAPIConfig, delegate HTTPAPIDelegate) HTTPAPI {
 	return &httpAPI{
 		monitor:  scheduling.NewSchedulerMonitor(),
 		delegate: delegate,
+		config:   config,
 	}
 }
 
@@ -139,6 +152,10 @@ func (httpAPI *httpAPI) ManilaExternalScheduler(w http.ResponseWriter, r *http.R
 		}
 		logger.Info("inferred pipeline name", "pipeline", requestData.Pipeline)
 	}
+	requestData.Pipeline = httpAPI.monitor.SelectPipeline(
+		"/scheduler/manila/external", requestData.Pipeline,
+		httpAPI.config.ManilaDefaultPipeline, httpAPI.delegate.HasPipeline,
+	)
 
 	// Create the decision object in kubernetes.
 	decision := &v1alpha1.Decision{
@@ -155,8 +172,24 @@ func (httpAPI *httpAPI) ManilaExternalScheduler(w http.ResponseWriter, r *http.R
 			Intent:     v1alpha1.SchedulingIntentUnknown,
 		},
 	}
-	ctx := r.Context()
+	// Continue the trace of the caller, if any.
+	ctx := scheduling.ExtractTraceContext(r.Context(), r.Header)
+	if timeout := httpAPI.config.RequestTimeout.Duration; timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
 	if err := httpAPI.delegate.ProcessNewDecisionFromAPI(ctx, decision); err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			c.Respond(logger, http.StatusGatewayTimeout, err, "scheduling request timed out")
+			return
+		}
+		// Let the caller know it can retry once the pipeline is ready.
+		var notReady *scheduling.PipelineNotReadyError
+		if errors.As(err, &notReady) {
+			c.RespondError(logger, http.StatusServiceUnavailable, err, notReady.APIError())
+			return
+		}
 		c.Respond(logger, http.StatusInternalServerError, err, "failed to process scheduling decision")
 		return
 	}
@@ -170,7 +203,10 @@ func (httpAPI *httpAPI) ManilaExternalScheduler(w http.ResponseWriter, r *http.R
 		return
 	}
 	hosts := decision.Status.Result.OrderedHosts
-	response := api.ExternalSchedulerResponse{Hosts: hosts}
+	response := api.ExternalSchedulerResponse{
+		Hosts:   hosts,
+		Weights: scheduling.VerboseWeights(r, hosts, decision.Status.Result),
+	}
 	w.Header().Set("Content-Type", "application/json")
 	if err = json.NewEncoder(w).Encode(response); err != nil {
 		c.Respond(logger, http.StatusInternalServerError, err, "failed to encode response")
@@ -0,0 +1,136 @@
+// Copyright SAP SE
+// SPDX-License-Identifier: Apache-2.0
+
+package filters
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	api "github.com/cobaltcore-dev/cortex/api/external/manila"
+	"github.com/cobaltcore-dev/cortex/api/v1alpha1"
+	"github.com/cobaltcore-dev/cortex/internal/knowledge/extractor/plugins/storage"
+	"github.com/cobaltcore-dev/cortex/internal/scheduling/lib"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Filter out storage pools that don't have enough free capacity for the share.
+type FilterHasEnoughCapacity struct {
+	lib.BaseFilter[api.ExternalSchedulerRequest, lib.EmptyFilterWeigherPipelineStepOpts]
+}
+
+// Initialize the step and validate that all required knowledges are ready.
+func (s *FilterHasEnoughCapacity) Init(ctx context.Context, client client.Client, filter v1alpha1.FilterSpec) error {
+	if err := s.BaseFilter.Init(ctx, client, filter); err != nil {
+		return err
+	}
+	if err := s.CheckKnowledges(ctx, corev1.ObjectReference{Name: "storage-pool-utilization-manila"}); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Get the requested share size in GB from the request spec. The size is
+// either given at the top level of the spec or in its share properties.
+func requestedShareSizeGB(spec any) (float64, bool) {
+	raw, err := json.Marshal(spec)
+	if err != nil {
+		return 0, false
+	}
+	var parsed struct {
+		Size            *float64 `json:"size"`
+		ShareProperties struct {
+			Size *float64 `json:"size"`
+		} `json:"share_properties"`
+	}
+	if err := json.Unmarshal(raw, &parsed); err != nil {
+		return 0, false
+	}
+	if parsed.Size != nil {
+		return *parsed.Size, true
+	}
+	if parsed.ShareProperties.Size != nil {
+		return *parsed.ShareProperties.Size, true
+	}
+	return 0, false
+}
+
+// Check if the share fits into the storage pool, following the capacity
+// filter of manila. Thin provisioned pools may be oversubscribed up to their
+// max oversubscription ratio. Returns the capacity available to the share.
+func fitsStoragePool(u storage.StoragePoolUtilization, sizeGB float64) (float64, bool) {
+	reservedGB := u.TotalCapacityGB * float64(u.ReservedPercentage) / 100
+	freeGB := u.FreeCapacityGB - reservedGB
+	if !u.ThinProvisioning || u.MaxOverSubscriptionRatio < 1 {
+		return freeGB, freeGB >= sizeGB
+	}
+	if u.TotalCapacityGB <= 0 {
+		return 0, false
+	}
+	// The provisioned capacity may not exceed the oversubscribed capacity.
+	provisionedRatio := (u.ProvisionedCapacityGB + sizeGB) / u.TotalCapacityGB
+	if provisionedRatio > u.MaxOverSubscriptionRatio {
+		availableGB := u.TotalCapacityGB*u.MaxOverSubscriptionRatio - u.ProvisionedCapacityGB
+		return availableGB, false
+	}
+	virtualFreeGB := freeGB * u.MaxOverSubscriptionRatio
+	return virtualFreeGB, virtualFreeGB >= sizeGB
+}
+
+// Only keep storage pools whose free capacity, minus the capacity reserved
+// by the backend, fits the requested share size. Thin provisioned storage
+// pools are oversubscribed up to their max oversubscription ratio. Storage
+// pools without known capacity are kept and logged.
+func (s *FilterHasEnoughCapacity) Run(traceLog *slog.Logger, request api.ExternalSchedulerRequest) (*lib.FilterWeigherPipelineStepResult, error) {
+	result := s.IncludeAllHostsFromRequest(request)
+	sizeGB, ok := requestedShareSizeGB(request.Spec)
+	if !ok {
+		traceLog.Warn("no share size in request spec, skipping capacity check")
+		return result, nil
+	}
+
+	knowledge := &v1alpha1.Knowledge{}
+	if err := s.Client.Get(
+		context.Background(),
+		client.ObjectKey{Name: "storage-pool-utilization-manila"},
+		knowledge,
+	); err != nil {
+		return nil, err
+	}
+	utilizations, err := v1alpha1.
+		UnboxFeatureList[storage.StoragePoolUtilization](knowledge.Status.Raw)
+	if err != nil {
+		return nil, err
+	}
+
+	hostsEncountered := make(map[string]struct{}, len(utilizations))
+	for _, u := range utilizations {
+		if _, ok := result.Activations[u.StoragePoolName]; !ok {
+			continue
+		}
+		hostsEncountered[u.StoragePoolName] = struct{}{}
+		if availableGB, ok := fitsStoragePool(u, sizeGB); !ok {
+			traceLog.Info(
+				"filtering storage pool due to insufficient capacity",
+				"host", u.StoragePoolName, "requestedGB", sizeGB, "availableGB", availableGB,
+				"thinProvisioning", u.ThinProvisioning,
+			)
+			result.RemoveHost(u.StoragePoolName, fmt.Sprintf(
+				"insufficient capacity: need %.1f GB have %.1f GB", sizeGB, availableGB,
+			))
+		}
+	}
+	for host := range result.Activations {
+		if _, ok := hostsEncountered[host]; !ok {
+			traceLog.Info("keeping storage pool with unknown capacity", "host", host)
+		}
+	}
+	return result, nil
+}
+
+func init() {
+	Index["filter_has_enough_capacity"] = func() ManilaFilter { return &FilterHasEnoughCapacity{} }
+}
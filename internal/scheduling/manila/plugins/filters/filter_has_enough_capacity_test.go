@@ -0,0 +1,139 @@
+// Copyright SAP SE
+// SPDX-License-Identifier: Apache-2.0
+
+package filters
+
+import (
+	"log/slog"
+	"testing"
+
+	api "github.com/cobaltcore-dev/cortex/api/external/manila"
+	"github.com/cobaltcore-dev/cortex/api/v1alpha1"
+	"github.com/cobaltcore-dev/cortex/internal/knowledge/extractor/plugins/storage"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestFilterHasEnoughCapacity_Run(t *testing.T) {
+	scheme, err := v1alpha1.SchemeBuilder.Build()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	utilizations, err := v1alpha1.BoxFeatureList([]any{
+		&storage.StoragePoolUtilization{StoragePoolName: "pool1", TotalCapacityGB: 1000, FreeCapacityGB: 500},
+		&storage.StoragePoolUtilization{StoragePoolName: "pool2", TotalCapacityGB: 1000, FreeCapacityGB: 100},
+		&storage.StoragePoolUtilization{StoragePoolName: "pool3", TotalCapacityGB: 1000, FreeCapacityGB: 150, ReservedPercentage: 10},
+		&storage.StoragePoolUtilization{
+			StoragePoolName: "thin1", TotalCapacityGB: 100, FreeCapacityGB: 10,
+			ThinProvisioning: true, MaxOverSubscriptionRatio: 20, ProvisionedCapacityGB: 1500,
+		},
+		&storage.StoragePoolUtilization{
+			StoragePoolName: "thin2", TotalCapacityGB: 100, FreeCapacityGB: 90,
+			ThinProvisioning: true, MaxOverSubscriptionRatio: 2, ProvisionedCapacityGB: 150,
+		},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	step := &FilterHasEnoughCapacity{}
+	step.Client = fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(&v1alpha1.Knowledge{
+			ObjectMeta: v1.ObjectMeta{Name: "storage-pool-utilization-manila"},
+			Status:     v1alpha1.KnowledgeStatus{Raw: utilizations},
+		}).
+		Build()
+
+	hosts := []api.ExternalSchedulerHost{
+		{ShareHost: "pool1"},
+		{ShareHost: "pool2"},
+		{ShareHost: "pool3"},
+	}
+	tests := []struct {
+		name     string
+		spec     any
+		hosts    []api.ExternalSchedulerHost
+		expected []string
+	}{
+		{
+			name:     "exact match fits",
+			spec:     map[string]any{"size": 100},
+			hosts:    hosts,
+			expected: []string{"pool1", "pool2"},
+		},
+		{
+			name:     "over capacity is filtered",
+			spec:     map[string]any{"size": 101},
+			hosts:    hosts,
+			expected: []string{"pool1"},
+		},
+		{
+			name:     "reserved capacity is not available",
+			spec:     map[string]any{"size": 60},
+			hosts:    hosts,
+			expected: []string{"pool1", "pool2"},
+		},
+		{
+			name:     "size from share properties",
+			spec:     map[string]any{"share_properties": map[string]any{"size": 200}},
+			hosts:    hosts,
+			expected: []string{"pool1"},
+		},
+		{
+			name:     "missing pool is kept",
+			spec:     map[string]any{"size": 1},
+			hosts:    append(hosts, api.ExternalSchedulerHost{ShareHost: "pool4"}),
+			expected: []string{"pool1", "pool2", "pool3", "pool4"},
+		},
+		{
+			name:     "thin pool is oversubscribed",
+			spec:     map[string]any{"size": 150},
+			hosts:    []api.ExternalSchedulerHost{{ShareHost: "pool2"}, {ShareHost: "thin1"}},
+			expected: []string{"thin1"},
+		},
+		{
+			name:     "thin pool over virtual free capacity is filtered",
+			spec:     map[string]any{"size": 250},
+			hosts:    []api.ExternalSchedulerHost{{ShareHost: "thin1"}},
+			expected: []string{},
+		},
+		{
+			name:     "thin pool within oversubscription ratio fits",
+			spec:     map[string]any{"size": 40},
+			hosts:    []api.ExternalSchedulerHost{{ShareHost: "thin2"}},
+			expected: []string{"thin2"},
+		},
+		{
+			name:     "thin pool over oversubscription ratio is filtered",
+			spec:     map[string]any{"size": 60},
+			hosts:    []api.ExternalSchedulerHost{{ShareHost: "thin2"}},
+			expected: []string{},
+		},
+		{
+			name:     "no size keeps all hosts",
+			spec:     map[string]any{"share_id": "share-1"},
+			hosts:    append(hosts, api.ExternalSchedulerHost{ShareHost: "pool4"}),
+			expected: []string{"pool1", "pool2", "pool3", "pool4"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := api.ExternalSchedulerRequest{Spec: tt.spec, Hosts: tt.hosts}
+			result, err := step.Run(slog.Default(), request)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if len(result.Activations) != len(tt.expected) {
+				t.Errorf("expected %d hosts, got %d: %v", len(tt.expected), len(result.Activations), result.Activations)
+			}
+			for _, host := range tt.expected {
+				if _, ok := result.Activations[host]; !ok {
+					t.Errorf("expected host %s to remain", host)
+				}
+			}
+		})
+	}
+}
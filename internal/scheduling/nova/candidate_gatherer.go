@@ -6,10 +6,15 @@ package nova
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
+	"sync"
+	"time"
 
 	api "github.com/cobaltcore-dev/cortex/api/external/nova"
 	hv1 "github.com/cobaltcore-dev/openstack-hypervisor-operator/api/v1"
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -20,9 +25,100 @@ type CandidateGatherer interface {
 	MutateWithAllCandidates(ctx context.Context, request *api.ExternalSchedulerRequest) error
 }
 
+// Configuration of the cache for gathered placement candidates.
+type CandidateCacheConfig struct {
+	// How long gathered placement candidates are reused for subsequent
+	// requests, so that bursts of requests ignoring preselection don't each
+	// gather all candidates again. The cache is also invalidated whenever
+	// a hypervisor is created, deleted, or marked for deletion. If unset,
+	// candidates are gathered every time.
+	CandidateCacheTTL metav1.Duration `json:"candidateCacheTTL,omitempty"`
+}
+
+// Create the prometheus counter for candidate cache lookups, by result
+// (hit/miss). Register it with the metrics registry before passing it on.
+func NewCandidateCacheCounter() *prometheus.CounterVec {
+	return prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "cortex_nova_candidate_cache_lookups_total",
+		Help: "Lookups of the cached nova placement candidates, by result (hit/miss).",
+	}, []string{"result"})
+}
+
 // candidateGatherer is the default implementation of CandidateGatherer
 // for Nova scheduling requests.
-type candidateGatherer struct{ client.Client }
+type candidateGatherer struct {
+	client.Client
+	// How long gathered candidates are reused, zero disables the cache.
+	ttl time.Duration
+	// Optional counter of cache lookups, by result.
+	lookups *prometheus.CounterVec
+
+	mu sync.Mutex
+	// Candidates of the last gathering, and when they were gathered.
+	cached     []api.ExternalSchedulerHost
+	gatheredAt time.Time
+}
+
+// Drop the cached candidates, e.g. because the hypervisors changed.
+func (g *candidateGatherer) invalidate() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.cached = nil
+	g.gatheredAt = time.Time{}
+}
+
+// Check if a hypervisor update affects the gathered candidates. Only the
+// hypervisor names are gathered, so frequent status updates are ignored.
+func hypervisorCandidateChanged(oldObj, newObj client.Object) bool {
+	if oldObj.GetName() != newObj.GetName() {
+		return true
+	}
+	return oldObj.GetDeletionTimestamp().IsZero() != newObj.GetDeletionTimestamp().IsZero()
+}
+
+// Get all kvm hosts, from the cache if it is still fresh.
+func (g *candidateGatherer) gatherHosts(ctx context.Context) ([]api.ExternalSchedulerHost, error) {
+	if g.ttl <= 0 {
+		return g.listHosts(ctx)
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.cached != nil && time.Since(g.gatheredAt) < g.ttl {
+		g.countLookup("hit")
+		return slices.Clone(g.cached), nil
+	}
+	g.countLookup("miss")
+	hosts, err := g.listHosts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	g.cached = hosts
+	g.gatheredAt = time.Now()
+	return slices.Clone(hosts), nil
+}
+
+func (g *candidateGatherer) countLookup(result string) {
+	if g.lookups != nil {
+		g.lookups.WithLabelValues(result).Inc()
+	}
+}
+
+// List all kvm hypervisors as placement candidates.
+func (g *candidateGatherer) listHosts(ctx context.Context) ([]api.ExternalSchedulerHost, error) {
+	hypervisorList := &hv1.HypervisorList{}
+	if err := g.List(ctx, hypervisorList); err != nil {
+		return nil, err
+	}
+	hosts := make([]api.ExternalSchedulerHost, 0, len(hypervisorList.Items))
+	for _, hv := range hypervisorList.Items {
+		hosts = append(hosts, api.ExternalSchedulerHost{
+			// For KVM hosts, compute host name and hypervisor hostname is identical.
+			ComputeHost:        hv.Name,
+			HypervisorHostname: hv.Name,
+		})
+	}
+	return hosts, nil
+}
 
 // MutateWithAllCandidates gathers all placement candidates and mutates
 // the request accordingly.
@@ -46,20 +142,12 @@ func (g *candidateGatherer) MutateWithAllCandidates(ctx context.Context, request
 		)
 	}
 
-	// List all kvm hypervisors.
-	hypervisorList := &hv1.HypervisorList{}
-	if err := g.List(ctx, hypervisorList); err != nil {
+	hosts, err := g.gatherHosts(ctx)
+	if err != nil {
 		return err
 	}
-	hosts := make([]api.ExternalSchedulerHost, 0, len(hypervisorList.Items))
-	weights := make(map[string]float64, len(hypervisorList.Items))
-	for _, hv := range hypervisorList.Items {
-		host := api.ExternalSchedulerHost{
-			// For KVM hosts, compute host name and hypervisor hostname is identical.
-			ComputeHost:        hv.Name,
-			HypervisorHostname: hv.Name,
-		}
-		hosts = append(hosts, host)
+	weights := make(map[string]float64, len(hosts))
+	for _, host := range hosts {
 		weights[host.ComputeHost] = 0.0 // Default weight.
 	}
 
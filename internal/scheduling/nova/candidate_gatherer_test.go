@@ -7,9 +7,11 @@ import (
 	"context"
 	"strings"
 	"testing"
+	"time"
 
 	api "github.com/cobaltcore-dev/cortex/api/external/nova"
 	hv1 "github.com/cobaltcore-dev/openstack-hypervisor-operator/api/v1"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -358,3 +360,86 @@ func TestCandidateGatherer_Interface(t *testing.T) {
 	// Verify that candidateGatherer implements CandidateGatherer interface
 	var _ CandidateGatherer = (*candidateGatherer)(nil)
 }
+
+func TestCandidateGatherer_Cache(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := hv1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to build scheme: %v", err)
+	}
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(&hv1.Hypervisor{ObjectMeta: metav1.ObjectMeta{Name: "host1"}}).
+		Build()
+	lookups := NewCandidateCacheCounter()
+	gatherer := &candidateGatherer{Client: fakeClient, ttl: time.Hour, lookups: lookups}
+	newRequest := func() *api.ExternalSchedulerRequest {
+		return &api.ExternalSchedulerRequest{
+			Spec: api.NovaObject[api.NovaSpec]{Data: api.NovaSpec{
+				Flavor: api.NovaObject[api.NovaFlavor]{Data: api.NovaFlavor{
+					ExtraSpecs: map[string]string{"capabilities:hypervisor_type": "qemu"},
+				}},
+			}},
+		}
+	}
+	gather := func() []api.ExternalSchedulerHost {
+		t.Helper()
+		request := newRequest()
+		if err := gatherer.MutateWithAllCandidates(context.Background(), request); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return request.Hosts
+	}
+
+	if hosts := gather(); len(hosts) != 1 {
+		t.Fatalf("expected 1 host, got %d", len(hosts))
+	}
+	// New hypervisors aren't seen while the cache is fresh.
+	if err := fakeClient.Create(context.Background(), &hv1.Hypervisor{ObjectMeta: metav1.ObjectMeta{Name: "host2"}}); err != nil {
+		t.Fatalf("failed to create hypervisor: %v", err)
+	}
+	if hosts := gather(); len(hosts) != 1 {
+		t.Errorf("expected cached host, got %d hosts", len(hosts))
+	}
+	// Invalidation makes the next request gather again.
+	gatherer.invalidate()
+	if hosts := gather(); len(hosts) != 2 {
+		t.Errorf("expected 2 hosts after invalidation, got %d", len(hosts))
+	}
+
+	if hits := testutil.ToFloat64(lookups.WithLabelValues("hit")); hits != 1 {
+		t.Errorf("expected 1 cache hit, got %v", hits)
+	}
+	if misses := testutil.ToFloat64(lookups.WithLabelValues("miss")); misses != 2 {
+		t.Errorf("expected 2 cache misses, got %v", misses)
+	}
+}
+
+func TestHypervisorCandidateChanged(t *testing.T) {
+	now := metav1.Now()
+	hypervisor := func(name string, deletionTimestamp *metav1.Time) *hv1.Hypervisor {
+		return &hv1.Hypervisor{ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			DeletionTimestamp: deletionTimestamp,
+		}}
+	}
+	withStatus := hypervisor("host1", nil)
+	withStatus.Status.NumInstances = 5
+
+	tests := []struct {
+		name     string
+		old, new *hv1.Hypervisor
+		expected bool
+	}{
+		{"status update", hypervisor("host1", nil), withStatus, false},
+		{"marked for deletion", hypervisor("host1", nil), hypervisor("host1", &now), true},
+		{"deletion mark removed", hypervisor("host1", &now), hypervisor("host1", nil), true},
+		{"name changed", hypervisor("host1", nil), hypervisor("host2", nil), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hypervisorCandidateChanged(tt.old, tt.new); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
@@ -70,7 +70,7 @@ func (c *DetectorPipelineController) CreateDeschedulingsPeriodically(ctx context
 			return
 		default:
 			// Get the pipeline for the current configuration.
-			p, ok := c.Pipelines["kvm-descheduler"]
+			p, ok := c.GetPipeline("kvm-descheduler")
 			if !ok {
 				slog.Error("descheduler: pipeline not found or not ready yet")
 				time.Sleep(jobloop.DefaultJitter(time.Minute))
@@ -0,0 +1,110 @@
+// Copyright SAP SE
+// SPDX-License-Identifier: Apache-2.0
+
+package nova
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	api "github.com/cobaltcore-dev/cortex/api/external/nova"
+	scheduling "github.com/cobaltcore-dev/cortex/internal/scheduling/lib"
+)
+
+type DrainAPIDelegate interface {
+	// Rank the hosts in the request with the given drain pipeline, the
+	// host that is safest to evacuate first.
+	RankHostsForDrain(ctx context.Context, pipelineName string, request api.ExternalSchedulerRequest) ([]string, error)
+}
+
+// Request to rank compute hosts by how safe they are to drain.
+type DrainRequest struct {
+	// The drain pipeline to rank the hosts with.
+	Pipeline string `json:"pipeline"`
+	// The compute hosts to rank.
+	Hosts []string `json:"hosts"`
+}
+
+// Response with the compute hosts ranked by how safe they are to drain.
+type DrainResponse struct {
+	// The ranked compute hosts, the host to drain first comes first.
+	Hosts []string `json:"hosts"`
+}
+
+// Handle the POST request to rank compute hosts for draining.
+// The request contains the drain pipeline and the hosts to rank. The
+// response contains the hosts ordered by how safe they are to evacuate,
+// so that operators know which host to drain first.
+func (httpAPI *httpAPI) NovaDrain(w http.ResponseWriter, r *http.Request) {
+	c := httpAPI.monitor.Callback(w, r, "/scheduler/nova/drain")
+
+	// Exit early if the request method is not POST.
+	if r.Method != http.MethodPost {
+		internalErr := fmt.Errorf("invalid request method: %s", r.Method)
+		c.Respond(nil, http.StatusMethodNotAllowed, internalErr, "invalid request method")
+		return
+	}
+
+	// Ensure body is closed after reading.
+	defer r.Body.Close()
+
+	var requestData DrainRequest
+	if err := json.NewDecoder(r.Body).Decode(&requestData); err != nil {
+		c.Respond(nil, http.StatusBadRequest, err, "failed to decode request body")
+		return
+	}
+	logger := slog.With("pipeline", requestData.Pipeline)
+	logger.Info("handling POST request", "url", "/scheduler/nova/drain", "hosts", requestData.Hosts)
+
+	if requestData.Pipeline == "" {
+		c.Respond(logger, http.StatusBadRequest, errors.New("missing pipeline"), "missing pipeline")
+		return
+	}
+	if len(requestData.Hosts) == 0 {
+		c.Respond(logger, http.StatusBadRequest, errors.New("missing hosts"), "missing hosts")
+		return
+	}
+
+	// Drain weighers only score the hosts, so all input weights are equal.
+	request := api.ExternalSchedulerRequest{
+		Pipeline: requestData.Pipeline,
+		Weights:  make(map[string]float64, len(requestData.Hosts)),
+	}
+	for _, host := range requestData.Hosts {
+		request.Hosts = append(request.Hosts, api.ExternalSchedulerHost{ComputeHost: host})
+		request.Weights[host] = 0
+	}
+
+	// Continue the trace of the caller, if any.
+	ctx := scheduling.ExtractTraceContext(r.Context(), r.Header)
+	if timeout := httpAPI.config.RequestTimeout.Duration; timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	hosts, err := httpAPI.drain.RankHostsForDrain(ctx, requestData.Pipeline, request)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			c.Respond(logger, http.StatusGatewayTimeout, err, "drain request timed out")
+			return
+		}
+		// Let the caller know it can retry once the pipeline is ready.
+		var notReady *scheduling.PipelineNotReadyError
+		if errors.As(err, &notReady) {
+			c.RespondError(logger, http.StatusServiceUnavailable, err, notReady.APIError())
+			return
+		}
+		c.Respond(logger, http.StatusInternalServerError, err, "failed to rank hosts for drain")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(DrainResponse{Hosts: hosts}); err != nil {
+		c.Respond(logger, http.StatusInternalServerError, err, "failed to encode response")
+		return
+	}
+	c.Respond(logger, http.StatusOK, nil, "Success")
+}
@@ -0,0 +1,121 @@
+// Copyright SAP SE
+// SPDX-License-Identifier: Apache-2.0
+
+package nova
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	api "github.com/cobaltcore-dev/cortex/api/external/nova"
+	scheduling "github.com/cobaltcore-dev/cortex/internal/scheduling/lib"
+)
+
+type mockDrainAPIDelegate struct {
+	rankFunc func(ctx context.Context, pipelineName string, request api.ExternalSchedulerRequest) ([]string, error)
+}
+
+func (m *mockDrainAPIDelegate) RankHostsForDrain(ctx context.Context, pipelineName string, request api.ExternalSchedulerRequest) ([]string, error) {
+	return m.rankFunc(ctx, pipelineName, request)
+}
+
+func TestHTTPAPI_NovaDrain(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		body           string
+		rankFunc       func(ctx context.Context, pipelineName string, request api.ExternalSchedulerRequest) ([]string, error)
+		expectedStatus int
+		expectedHosts  []string
+	}{
+		{
+			name:   "hosts ranked by the drain pipeline",
+			method: http.MethodPost,
+			body:   `{"pipeline": "kvm-drain", "hosts": ["host1", "host2"]}`,
+			rankFunc: func(_ context.Context, pipelineName string, request api.ExternalSchedulerRequest) ([]string, error) {
+				if pipelineName != "kvm-drain" {
+					return nil, errors.New("unexpected pipeline")
+				}
+				if len(request.Hosts) != 2 || len(request.Weights) != 2 {
+					return nil, errors.New("unexpected request")
+				}
+				return []string{"host2", "host1"}, nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedHosts:  []string{"host2", "host1"},
+		},
+		{
+			name:           "invalid method",
+			method:         http.MethodGet,
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			name:           "invalid body",
+			method:         http.MethodPost,
+			body:           `{`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "missing pipeline",
+			method:         http.MethodPost,
+			body:           `{"hosts": ["host1"]}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "missing hosts",
+			method:         http.MethodPost,
+			body:           `{"pipeline": "kvm-drain"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "pipeline not ready",
+			method: http.MethodPost,
+			body:   `{"pipeline": "unknown", "hosts": ["host1"]}`,
+			rankFunc: func(_ context.Context, pipelineName string, _ api.ExternalSchedulerRequest) ([]string, error) {
+				return nil, &scheduling.PipelineNotReadyError{Pipeline: pipelineName}
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+		{
+			name:   "pipeline run fails",
+			method: http.MethodPost,
+			body:   `{"pipeline": "kvm-drain", "hosts": ["host1"]}`,
+			rankFunc: func(_ context.Context, _ string, _ api.ExternalSchedulerRequest) ([]string, error) {
+				return nil, errors.New("weigher failed")
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			delegate := &mockHTTPAPIDelegate{}
+			drain := &mockDrainAPIDelegate{rankFunc: tt.rankFunc}
+			api := NewAPI(HTTPAPIConfig{}, delegate, drain).(*httpAPI)
+
+			req := httptest.NewRequest(tt.method, "/scheduler/nova/drain", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			api.NovaDrain(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+			var response DrainResponse
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if !slices.Equal(response.Hosts, tt.expectedHosts) {
+				t.Errorf("expected hosts %v, got %v", tt.expectedHosts, response.Hosts)
+			}
+		})
+	}
+}
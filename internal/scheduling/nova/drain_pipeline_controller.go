@@ -0,0 +1,110 @@
+// Copyright SAP SE
+// SPDX-License-Identifier: Apache-2.0
+
+package nova
+
+import (
+	"context"
+
+	api "github.com/cobaltcore-dev/cortex/api/external/nova"
+	"github.com/cobaltcore-dev/cortex/api/v1alpha1"
+	"github.com/cobaltcore-dev/cortex/internal/scheduling/lib"
+	"github.com/cobaltcore-dev/cortex/internal/scheduling/nova/plugins/drainweighers"
+	"github.com/cobaltcore-dev/cortex/pkg/multicluster"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// The drain pipeline controller keeps the nova drain pipelines initialized.
+// Drain pipelines score hosts by how safe they are to evacuate, so that
+// callers can find out which host should be drained first.
+//
+// Additionally, the controller watches for pipeline and knowledge changes to
+// reconfigure the pipelines as needed.
+type DrainPipelineController struct {
+	// Toolbox shared between all pipeline controllers.
+	lib.BasePipelineController[lib.FilterWeigherPipeline[api.ExternalSchedulerRequest]]
+
+	// Monitor to pass down to all pipelines.
+	Monitor lib.FilterWeigherPipelineMonitor
+}
+
+// Rank the hosts in the request with the given drain pipeline. The first
+// host in the returned list is the one that is safest to evacuate. If the
+// pipeline is not known or not ready, a PipelineNotReadyError is returned.
+func (c *DrainPipelineController) RankHostsForDrain(
+	ctx context.Context,
+	pipelineName string,
+	request api.ExternalSchedulerRequest,
+) ([]string, error) {
+
+	pipeline, ok := c.GetPipeline(pipelineName)
+	if !ok {
+		return nil, &lib.PipelineNotReadyError{Pipeline: pipelineName}
+	}
+	result, err := pipeline.Run(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	return result.OrderedHosts, nil
+}
+
+func (c *DrainPipelineController) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	// This controller does not reconcile any resources directly.
+	return ctrl.Result{}, nil
+}
+
+func (c *DrainPipelineController) SetupWithManager(mgr ctrl.Manager, mcl *multicluster.Client) error {
+	c.Initializer = lib.DrainPipelineInitializer[api.ExternalSchedulerRequest]{
+		Client:   mcl,
+		Weighers: drainweighers.Index,
+		Monitor:  c.Monitor,
+	}
+	c.SchedulingDomain = v1alpha1.SchedulingDomainNova
+	if err := mgr.Add(manager.RunnableFunc(c.InitAllPipelines)); err != nil {
+		return err
+	}
+	bldr := multicluster.BuildController(mcl, mgr)
+	// Watch pipeline changes so that we can reconfigure pipelines as needed.
+	bldr, err := bldr.WatchesMulticluster(
+		&v1alpha1.Pipeline{},
+		handler.Funcs{
+			CreateFunc: c.HandlePipelineCreated,
+			UpdateFunc: c.HandlePipelineUpdated,
+			DeleteFunc: c.HandlePipelineDeleted,
+		},
+		predicate.NewPredicateFuncs(func(obj client.Object) bool {
+			pipeline := obj.(*v1alpha1.Pipeline)
+			// Only react to pipelines matching the scheduling domain.
+			if pipeline.Spec.SchedulingDomain != v1alpha1.SchedulingDomainNova {
+				return false
+			}
+			return pipeline.Spec.Type == c.Initializer.PipelineType()
+		}),
+	)
+	if err != nil {
+		return err
+	}
+	// Watch knowledge changes so that we can reconfigure pipelines as needed.
+	bldr, err = bldr.WatchesMulticluster(
+		&v1alpha1.Knowledge{},
+		handler.Funcs{
+			CreateFunc: c.HandleKnowledgeCreated,
+			UpdateFunc: c.HandleKnowledgeUpdated,
+			DeleteFunc: c.HandleKnowledgeDeleted,
+		},
+		predicate.NewPredicateFuncs(func(obj client.Object) bool {
+			knowledge := obj.(*v1alpha1.Knowledge)
+			// Only react to knowledge matching the scheduling domain.
+			return knowledge.Spec.SchedulingDomain == v1alpha1.SchedulingDomainNova
+		}),
+	)
+	if err != nil {
+		return err
+	}
+	return bldr.Named("cortex-nova-drain").
+		Complete(c)
+}
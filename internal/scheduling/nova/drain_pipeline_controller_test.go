@@ -0,0 +1,88 @@
+// Copyright SAP SE
+// SPDX-License-Identifier: Apache-2.0
+
+package nova
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+	"testing"
+
+	api "github.com/cobaltcore-dev/cortex/api/external/nova"
+	"github.com/cobaltcore-dev/cortex/api/v1alpha1"
+	"github.com/cobaltcore-dev/cortex/internal/scheduling/lib"
+	"github.com/cobaltcore-dev/cortex/internal/scheduling/nova/plugins/drainweighers"
+	hv1 "github.com/cobaltcore-dev/openstack-hypervisor-operator/api/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestDrainPipelineController_RankHostsForDrain(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("Failed to add v1alpha1 scheme: %v", err)
+	}
+	if err := hv1.SchemeBuilder.AddToScheme(scheme); err != nil {
+		t.Fatalf("Failed to add hv1 scheme: %v", err)
+	}
+	hypervisor := func(name string, count int) *hv1.Hypervisor {
+		instances := make([]hv1.Instance, count)
+		for i := range instances {
+			instances[i] = hv1.Instance{ID: name + "-" + strconv.Itoa(i), Active: true}
+		}
+		return &hv1.Hypervisor{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status:     hv1.HypervisorStatus{Instances: instances},
+		}
+	}
+	pipeline := &v1alpha1.Pipeline{
+		ObjectMeta: metav1.ObjectMeta{Name: "kvm-drain"},
+		Spec: v1alpha1.PipelineSpec{
+			SchedulingDomain: v1alpha1.SchedulingDomainNova,
+			Type:             v1alpha1.PipelineTypeDrain,
+			Weighers: []v1alpha1.WeigherSpec{{
+				Name:   "kvm_prefer_few_instances",
+				Params: v1alpha1.Parameters{{Key: "strength", FloatValue: new(1.0)}},
+			}},
+		},
+	}
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(pipeline, hypervisor("host1", 5), hypervisor("host2", 0), hypervisor("host3", 2)).
+		WithStatusSubresource(&v1alpha1.Pipeline{}).
+		Build()
+
+	controller := &DrainPipelineController{Monitor: lib.NewPipelineMonitor()}
+	controller.Client = fakeClient
+	controller.SchedulingDomain = v1alpha1.SchedulingDomainNova
+	controller.Initializer = lib.DrainPipelineInitializer[api.ExternalSchedulerRequest]{
+		Client:   fakeClient,
+		Weighers: drainweighers.Index,
+		Monitor:  controller.Monitor,
+	}
+	if err := controller.InitAllPipelines(t.Context()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	request := api.ExternalSchedulerRequest{
+		Hosts: []api.ExternalSchedulerHost{
+			{ComputeHost: "host1"}, {ComputeHost: "host2"}, {ComputeHost: "host3"},
+		},
+		Weights: map[string]float64{"host1": 0, "host2": 0, "host3": 0},
+	}
+	hosts, err := controller.RankHostsForDrain(t.Context(), "kvm-drain", request)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if expected := []string{"host2", "host3", "host1"}; !slices.Equal(hosts, expected) {
+		t.Errorf("expected hosts %v, got %v", expected, hosts)
+	}
+
+	_, err = controller.RankHostsForDrain(t.Context(), "unknown", request)
+	var notReady *lib.PipelineNotReadyError
+	if !errors.As(err, &notReady) {
+		t.Fatalf("expected pipeline not ready error, got %v", err)
+	}
+}
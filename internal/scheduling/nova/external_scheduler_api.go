@@ -33,11 +33,19 @@ type HTTPAPIConfig struct {
 	// NovaLimitHostsToRequest, if true, will filter the Nova scheduler response
 	// to only include hosts that were in the original request.
 	NovaLimitHostsToRequest bool `json:"novaLimitHostsToRequest,omitempty"`
+	// Maximum time to spend on a scheduling request. If exceeded, the pipeline
+	// run is aborted and the request fails with a timeout. Zero disables it.
+	RequestTimeout metav1.Duration `json:"requestTimeout,omitempty"`
+	// Pipeline to use if the request's pipeline is not configured, or if no
+	// pipeline was given and none can be inferred from the request data.
+	NovaDefaultPipeline string `json:"novaDefaultPipeline,omitempty"`
 }
 
 type HTTPAPIDelegate interface {
 	// Process the decision from the API. Should create and return the updated decision.
 	ProcessNewDecisionFromAPI(ctx context.Context, decision *v1alpha1.Decision) error
+	// Check if a pipeline with the given name is configured.
+	HasPipeline(name string) bool
 }
 
 type HTTPAPI interface {
@@ -48,13 +56,17 @@ type HTTPAPI interface {
 type httpAPI struct {
 	monitor  scheduling.APIMonitor
 	delegate HTTPAPIDelegate
+	drain    DrainAPIDelegate
 	config   HTTPAPIConfig
 }
 
-func NewAPI(config HTTPAPIConfig, delegate HTTPAPIDelegate) HTTPAPI {
+// Create a new nova scheduler api. If the drain delegate is nil, the drain
+// endpoint is not served.
+func NewAPI(config HTTPAPIConfig, delegate HTTPAPIDelegate, drain DrainAPIDelegate) HTTPAPI {
 	return &httpAPI{
 		monitor:  scheduling.NewSchedulerMonitor(),
 		delegate: delegate,
+		drain:    drain,
 		config:   config,
 	}
 }
@@ -63,6 +75,9 @@ func NewAPI(config HTTPAPIConfig, delegate HTTPAPIDelegate) HTTPAPI {
 func (httpAPI *httpAPI) Init(mux *http.ServeMux) {
 	metrics.Registry.MustRegister(&httpAPI.monitor)
 	mux.HandleFunc("/scheduler/nova/external", httpAPI.NovaExternalScheduler)
+	if httpAPI.drain != nil {
+		mux.HandleFunc("/scheduler/nova/drain", httpAPI.NovaDrain)
+	}
 }
 
 // Check if the scheduler can run based on the request data.
@@ -218,12 +233,16 @@ func (httpAPI *httpAPI) NovaExternalScheduler(w http.ResponseWriter, r *http.Req
 	if requestData.Pipeline == "" {
 		var err error
 		requestData.Pipeline, err = httpAPI.inferPipelineName(requestData)
-		if err != nil {
+		if err != nil && httpAPI.config.NovaDefaultPipeline == "" {
 			c.Respond(logger, http.StatusBadRequest, err, err.Error())
 			return
 		}
 		logger.Info("inferred pipeline name", "pipeline", requestData.Pipeline)
 	}
+	requestData.Pipeline = httpAPI.monitor.SelectPipeline(
+		"/scheduler/nova/external", requestData.Pipeline,
+		httpAPI.config.NovaDefaultPipeline, httpAPI.delegate.HasPipeline,
+	)
 
 	decision := &v1alpha1.Decision{
 		TypeMeta: metav1.TypeMeta{
@@ -243,8 +262,24 @@ func (httpAPI *httpAPI) NovaExternalScheduler(w http.ResponseWriter, r *http.Req
 			Intent:     v1alpha1.SchedulingIntentUnknown,
 		},
 	}
-	ctx := r.Context()
+	// Continue the trace of the caller, if any.
+	ctx := scheduling.ExtractTraceContext(r.Context(), r.Header)
+	if timeout := httpAPI.config.RequestTimeout.Duration; timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
 	if err := httpAPI.delegate.ProcessNewDecisionFromAPI(ctx, decision); err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			c.Respond(logger, http.StatusGatewayTimeout, err, "scheduling request timed out")
+			return
+		}
+		// Let the caller know it can retry once the pipeline is ready.
+		var notReady *scheduling.PipelineNotReadyError
+		if errors.As(err, &notReady) {
+			c.RespondError(logger, http.StatusServiceUnavailable, err, notReady.APIError())
+			return
+		}
 		c.Respond(logger, http.StatusInternalServerError, err, "failed to process scheduling decision")
 		return
 	}
@@ -269,7 +304,10 @@ func (httpAPI *httpAPI) NovaExternalScheduler(w http.ResponseWriter, r *http.Req
 	if err == nil && intent == api.EvacuateIntent {
 		hosts = shuffleTopHosts(hosts, httpAPI.config.EvacuationShuffleK)
 	}
-	response := api.ExternalSchedulerResponse{Hosts: hosts}
+	response := api.ExternalSchedulerResponse{
+		Hosts:   hosts,
+		Weights: scheduling.VerboseWeights(r, hosts, decision.Status.Result),
+	}
 	w.Header().Set("Content-Type", "application/json")
 	if err = json.NewEncoder(w).Encode(response); err != nil {
 		c.Respond(logger, http.StatusInternalServerError, err, "failed to encode response")
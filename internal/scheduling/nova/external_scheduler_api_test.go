@@ -10,17 +10,25 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"slices"
 	"strings"
 	"testing"
+	"time"
 
 	novaapi "github.com/cobaltcore-dev/cortex/api/external/nova"
 	"github.com/cobaltcore-dev/cortex/api/v1alpha1"
+	"github.com/cobaltcore-dev/cortex/internal/scheduling/lib"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 type mockHTTPAPIDelegate struct {
 	processDecisionFunc func(ctx context.Context, decision *v1alpha1.Decision) error
+	knownPipelines      []string
+}
+
+func (m *mockHTTPAPIDelegate) HasPipeline(name string) bool {
+	return m.knownPipelines == nil || slices.Contains(m.knownPipelines, name)
 }
 
 func (m *mockHTTPAPIDelegate) ProcessNewDecisionFromAPI(ctx context.Context, decision *v1alpha1.Decision) error {
@@ -33,7 +41,7 @@ func (m *mockHTTPAPIDelegate) ProcessNewDecisionFromAPI(ctx context.Context, dec
 func TestNewAPI(t *testing.T) {
 	delegate := &mockHTTPAPIDelegate{}
 
-	api := NewAPI(HTTPAPIConfig{}, delegate)
+	api := NewAPI(HTTPAPIConfig{}, delegate, nil)
 
 	if api == nil {
 		t.Fatal("NewAPI returned nil")
@@ -55,25 +63,27 @@ func TestNewAPI(t *testing.T) {
 
 func TestHTTPAPI_Init(t *testing.T) {
 	delegate := &mockHTTPAPIDelegate{}
-	api := NewAPI(HTTPAPIConfig{}, delegate)
+	api := NewAPI(HTTPAPIConfig{}, delegate, &mockDrainAPIDelegate{})
 
 	mux := http.NewServeMux()
 	api.Init(mux)
 
-	// Test that the handler is registered by making a request
-	req := httptest.NewRequest(http.MethodGet, "/scheduler/nova/external", http.NoBody)
-	w := httptest.NewRecorder()
-	mux.ServeHTTP(w, req)
+	// Test that the handlers are registered by making requests
+	for _, path := range []string{"/scheduler/nova/external", "/scheduler/nova/drain"} {
+		req := httptest.NewRequest(http.MethodGet, path, http.NoBody)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
 
-	// Should get method not allowed since we're using GET instead of POST
-	if w.Code != http.StatusMethodNotAllowed {
-		t.Errorf("Expected status %d, got %d", http.StatusMethodNotAllowed, w.Code)
+		// Should get method not allowed since we're using GET instead of POST
+		if w.Code != http.StatusMethodNotAllowed {
+			t.Errorf("Expected status %d for %s, got %d", http.StatusMethodNotAllowed, path, w.Code)
+		}
 	}
 }
 
 func TestHTTPAPI_canRunScheduler(t *testing.T) {
 	delegate := &mockHTTPAPIDelegate{}
-	api := NewAPI(HTTPAPIConfig{}, delegate).(*httpAPI)
+	api := NewAPI(HTTPAPIConfig{}, delegate, nil).(*httpAPI)
 
 	tests := []struct {
 		name        string
@@ -222,6 +232,33 @@ func TestHTTPAPI_NovaExternalScheduler(t *testing.T) {
 			processDecisionErr: errors.New("processing failed"),
 			expectedStatus:     http.StatusInternalServerError,
 		},
+		{
+			name:   "pipeline not ready",
+			method: http.MethodPost,
+			body: func() string {
+				req := novaapi.ExternalSchedulerRequest{
+					Spec: novaapi.NovaObject[novaapi.NovaSpec]{
+						Data: novaapi.NovaSpec{
+							InstanceUUID: "test-uuid",
+						},
+					},
+					Hosts: []novaapi.ExternalSchedulerHost{
+						{ComputeHost: "host1"},
+					},
+					Weights: map[string]float64{
+						"host1": 1.0,
+					},
+					Pipeline: "test-pipeline",
+				}
+				data, err := json.Marshal(req)
+				if err != nil {
+					t.Fatalf("Failed to marshal request data: %v", err)
+				}
+				return string(data)
+			}(),
+			processDecisionErr: &lib.PipelineNotReadyError{Pipeline: "test-pipeline"},
+			expectedStatus:     http.StatusServiceUnavailable,
+		},
 		{
 			name:   "decision failed",
 			method: http.MethodPost,
@@ -276,7 +313,7 @@ func TestHTTPAPI_NovaExternalScheduler(t *testing.T) {
 				},
 			}
 
-			api := NewAPI(HTTPAPIConfig{}, delegate).(*httpAPI)
+			api := NewAPI(HTTPAPIConfig{}, delegate, nil).(*httpAPI)
 
 			var body *strings.Reader
 			if tt.body != "" {
@@ -327,7 +364,7 @@ func TestHTTPAPI_NovaExternalScheduler_DecisionCreation(t *testing.T) {
 		},
 	}
 
-	api := NewAPI(HTTPAPIConfig{}, delegate).(*httpAPI)
+	api := NewAPI(HTTPAPIConfig{}, delegate, nil).(*httpAPI)
 
 	requestData := novaapi.ExternalSchedulerRequest{
 		Spec: novaapi.NovaObject[novaapi.NovaSpec]{
@@ -383,6 +420,97 @@ func TestHTTPAPI_NovaExternalScheduler_DecisionCreation(t *testing.T) {
 	}
 }
 
+func TestHTTPAPI_NovaExternalScheduler_RequestTimeout(t *testing.T) {
+	delegate := &mockHTTPAPIDelegate{
+		processDecisionFunc: func(ctx context.Context, decision *v1alpha1.Decision) error {
+			if _, ok := ctx.Deadline(); !ok {
+				t.Error("expected the request context to have a deadline")
+			}
+			// Simulate a pipeline that doesn't finish in time.
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	}
+	config := HTTPAPIConfig{RequestTimeout: metav1.Duration{Duration: 10 * time.Millisecond}}
+	api := NewAPI(config, delegate, nil).(*httpAPI)
+
+	requestData := novaapi.ExternalSchedulerRequest{
+		Spec: novaapi.NovaObject[novaapi.NovaSpec]{
+			Data: novaapi.NovaSpec{
+				InstanceUUID: "test-uuid-123",
+			},
+		},
+		Hosts: []novaapi.ExternalSchedulerHost{
+			{ComputeHost: "host1"},
+		},
+		Weights: map[string]float64{
+			"host1": 1.0,
+		},
+		Pipeline: "test-pipeline",
+	}
+	body, err := json.Marshal(requestData)
+	if err != nil {
+		t.Fatalf("Failed to marshal request data: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/scheduler/nova/external", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	api.NovaExternalScheduler(w, req)
+
+	if w.Code != http.StatusGatewayTimeout {
+		t.Errorf("Expected status %d, got %d", http.StatusGatewayTimeout, w.Code)
+	}
+}
+
+func TestHTTPAPI_NovaExternalScheduler_DefaultPipeline(t *testing.T) {
+	var capturedDecision *v1alpha1.Decision
+	delegate := &mockHTTPAPIDelegate{
+		processDecisionFunc: func(ctx context.Context, decision *v1alpha1.Decision) error {
+			capturedDecision = decision
+			decision.Status.Result = &v1alpha1.DecisionResult{
+				OrderedHosts: []string{"host1"},
+			}
+			return nil
+		},
+		knownPipelines: []string{"nova-default"},
+	}
+	config := HTTPAPIConfig{NovaDefaultPipeline: "nova-default"}
+	api := NewAPI(config, delegate, nil).(*httpAPI)
+
+	// Without hypervisor type, no pipeline can be inferred from the request.
+	requestData := novaapi.ExternalSchedulerRequest{
+		Spec: novaapi.NovaObject[novaapi.NovaSpec]{
+			Data: novaapi.NovaSpec{
+				InstanceUUID: "test-uuid-123",
+			},
+		},
+		Hosts: []novaapi.ExternalSchedulerHost{
+			{ComputeHost: "host1"},
+		},
+		Weights: map[string]float64{
+			"host1": 1.0,
+		},
+	}
+	body, err := json.Marshal(requestData)
+	if err != nil {
+		t.Fatalf("Failed to marshal request data: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/scheduler/nova/external", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	api.NovaExternalScheduler(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if capturedDecision == nil {
+		t.Fatal("Decision was not captured")
+	}
+	if capturedDecision.Spec.PipelineRef.Name != "nova-default" {
+		t.Errorf("Expected pipeline 'nova-default', got %s", capturedDecision.Spec.PipelineRef.Name)
+	}
+}
+
 func TestLimitHostsToRequest(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -616,7 +744,7 @@ func TestShuffleTopHosts(t *testing.T) {
 
 func TestHTTPAPI_inferPipelineName(t *testing.T) {
 	delegate := &mockHTTPAPIDelegate{}
-	api := NewAPI(HTTPAPIConfig{}, delegate).(*httpAPI)
+	api := NewAPI(HTTPAPIConfig{}, delegate, nil).(*httpAPI)
 
 	tests := []struct {
 		name           string
@@ -7,6 +7,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"log/slog"
 	"sync"
 	"time"
 
@@ -21,11 +22,15 @@ import (
 	"github.com/cobaltcore-dev/cortex/internal/scheduling/nova/plugins/weighers"
 	"github.com/cobaltcore-dev/cortex/pkg/multicluster"
 	hv1 "github.com/cobaltcore-dev/openstack-hypervisor-operator/api/v1"
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/client-go/util/workqueue"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
 // The decision pipeline controller takes decision resources containing a
@@ -44,8 +49,14 @@ type FilterWeigherPipelineController struct {
 
 	// FeatureGates holds feature flags for this controller.
 	FeatureGates FeatureGates
+	// Configuration of the History CRDs written after each decision.
+	HistoryConfig lib.HistoryConfig
 	// Monitor to pass down to all pipelines.
 	Monitor lib.FilterWeigherPipelineMonitor
+	// Configuration of the cache for gathered placement candidates.
+	CandidateCache CandidateCacheConfig
+	// Optional counter of candidate cache lookups, see NewCandidateCacheCounter.
+	CandidateCacheCounter *prometheus.CounterVec
 	// Candidate gatherer to get all placement candidates if needed.
 	gatherer CandidateGatherer
 
@@ -90,6 +101,11 @@ func (c *FilterWeigherPipelineController) Reconcile(ctx context.Context, req ctr
 
 // Process the decision from the API. Should create and return the updated decision.
 func (c *FilterWeigherPipelineController) ProcessNewDecisionFromAPI(ctx context.Context, decision *v1alpha1.Decision) error {
+	ctx, span := lib.StartSpan(ctx, "scheduling decision",
+		slog.String("domain", string(decision.Spec.SchedulingDomain)),
+		slog.String("resourceID", decision.Spec.ResourceID),
+		slog.String("pipeline", decision.Spec.PipelineRef.Name),
+	)
 	// Read-only runs share the cached decision state; no re-fetch needed because they
 	// don't observe writes from concurrent exclusive-lock runs.
 	if c.peekReadOnly(decision) {
@@ -132,6 +148,12 @@ func (c *FilterWeigherPipelineController) ProcessNewDecisionFromAPI(ctx context.
 			c.CRRecorder.RecordNoHostFound(ctx, decision, *request)
 		}
 	}
+	c.Monitor.ObserveDecision(c.PipelineLabel(decision.Spec.PipelineRef.Name), err)
+	if request != nil {
+		// Attach the request context, e.g. the global request id.
+		span.SetAttributes(request.GetTraceLogArgs()...)
+	}
+	span.End(err)
 	return err
 }
 
@@ -160,10 +182,10 @@ func (c *FilterWeigherPipelineController) process(ctx context.Context, decision
 	log := ctrl.LoggerFrom(ctx)
 	startedAt := time.Now() // So we can measure sync duration.
 
-	pipeline, ok := c.Pipelines[decision.Spec.PipelineRef.Name]
+	pipeline, ok := c.GetPipeline(decision.Spec.PipelineRef.Name)
 	if !ok {
 		log.Error(nil, "pipeline not found or not ready", "pipelineName", decision.Spec.PipelineRef.Name)
-		return nil, errors.New("pipeline not found or not ready")
+		return nil, &lib.PipelineNotReadyError{Pipeline: decision.Spec.PipelineRef.Name}
 	}
 	if decision.Spec.NovaRaw == nil {
 		log.Error(nil, "skipping decision, no novaRaw spec defined")
@@ -184,7 +206,7 @@ func (c *FilterWeigherPipelineController) process(ctx context.Context, decision
 
 	// If necessary gather all placement candidates before filtering.
 	// This will override the hosts and weights in the nova request.
-	pipelineConf, ok := c.PipelineConfigs[decision.Spec.PipelineRef.Name]
+	pipelineConf, ok := c.GetPipelineConfig(decision.Spec.PipelineRef.Name)
 	if !ok {
 		log.Error(nil, "pipeline config not found", "pipelineName", decision.Spec.PipelineRef.Name)
 		return &request, errors.New("pipeline config not found")
@@ -198,9 +220,13 @@ func (c *FilterWeigherPipelineController) process(ctx context.Context, decision
 		log.Info("gathered all placement candidates", "numHosts", len(request.Hosts))
 	}
 
-	result, err := pipeline.Run(request)
+	result, err := pipeline.Run(ctx, request)
 	if !request.Options.SkipHistory {
-		c.upsertHistory(ctx, decision, err)
+		// Also record runs that were aborted because the request timed out.
+		c.upsertHistory(context.WithoutCancel(ctx), decision, err)
+	}
+	if pipelineConf.Spec.Audit {
+		lib.AuditDecision(decision, request, &result, err)
 	}
 	if err != nil {
 		log.Error(err, "failed to run pipeline")
@@ -239,15 +265,31 @@ func (c *FilterWeigherPipelineController) InitPipeline(
 		ctx, c.Client, p.Name,
 		filters.Index, p.Spec.Filters,
 		weighers.Index, p.Spec.Weighers,
+		p.Spec.TieBreaker,
+		p.Spec.InputWeightNormalization,
+		p.Spec.ParallelFilters,
 		c.Monitor,
 	)
 }
 
 func (c *FilterWeigherPipelineController) SetupWithManager(mgr manager.Manager, mcl *multicluster.Client) error {
 	c.Initializer = c
+	c.ObserveReadyPipelines = c.Monitor.ObserveReadyPipelines
 	c.SchedulingDomain = v1alpha1.SchedulingDomainNova
-	c.HistoryManager = lib.HistoryClient{Client: mcl, Recorder: mcl.GetEventRecorder("cortex-nova-scheduler")}
-	c.gatherer = &candidateGatherer{Client: mcl}
+	certainty := c.HistoryConfig.CertaintyThresholdsFor(v1alpha1.SchedulingDomainNova)
+	c.HistoryManager = lib.HistoryClient{
+		Client:           mcl,
+		Recorder:         mcl.GetEventRecorder("cortex-nova-scheduler"),
+		Verbosity:        c.HistoryConfig.ExplanationVerbosity,
+		Certainty:        &certainty,
+		MaxHistoryLength: c.HistoryConfig.MaxHistoryLength,
+	}
+	gatherer := &candidateGatherer{
+		Client:  mcl,
+		ttl:     c.CandidateCache.CandidateCacheTTL.Duration,
+		lookups: c.CandidateCacheCounter,
+	}
+	c.gatherer = gatherer
 	if err := mgr.Add(manager.RunnableFunc(c.InitAllPipelines)); err != nil {
 		return err
 	}
@@ -289,8 +331,22 @@ func (c *FilterWeigherPipelineController) SetupWithManager(mgr manager.Manager,
 	if err != nil {
 		return err
 	}
-	// Watch hypervisor changes so the cache gets updated.
-	bldr, err = bldr.WatchesMulticluster(&hv1.Hypervisor{}, handler.Funcs{})
+	// Watch hypervisor changes so the cache gets updated, and the gathered
+	// placement candidates are gathered again on the next request if the
+	// set of hypervisors changed.
+	bldr, err = bldr.WatchesMulticluster(&hv1.Hypervisor{}, handler.Funcs{
+		CreateFunc: func(context.Context, event.CreateEvent, workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			gatherer.invalidate()
+		},
+		UpdateFunc: func(_ context.Context, evt event.UpdateEvent, _ workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			if hypervisorCandidateChanged(evt.ObjectOld, evt.ObjectNew) {
+				gatherer.invalidate()
+			}
+		},
+		DeleteFunc: func(context.Context, event.DeleteEvent, workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			gatherer.invalidate()
+		},
+	})
 	if err != nil {
 		return err
 	}
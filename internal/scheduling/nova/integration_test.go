@@ -275,6 +275,9 @@ func NewIntegrationTestServer(t *testing.T, pipelineConfig PipelineConfig, objec
 		ctx, k8sClient, testPipeline.Name,
 		filters.Index, testPipeline.Spec.Filters,
 		weighers.Index, testPipeline.Spec.Weighers,
+		testPipeline.Spec.TieBreaker,
+		testPipeline.Spec.InputWeightNormalization,
+		testPipeline.Spec.ParallelFilters,
 		controller.Monitor,
 	)
 	if len(result.FilterErrors) > 0 || len(result.WeigherErrors) > 0 {
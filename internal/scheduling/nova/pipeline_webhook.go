@@ -7,12 +7,13 @@ import (
 	"github.com/cobaltcore-dev/cortex/api/v1alpha1"
 	"github.com/cobaltcore-dev/cortex/internal/scheduling/lib"
 	"github.com/cobaltcore-dev/cortex/internal/scheduling/nova/plugins/detectors"
+	"github.com/cobaltcore-dev/cortex/internal/scheduling/nova/plugins/drainweighers"
 	"github.com/cobaltcore-dev/cortex/internal/scheduling/nova/plugins/filters"
 	"github.com/cobaltcore-dev/cortex/internal/scheduling/nova/plugins/weighers"
 )
 
 // Create a new pipeline admission webhook for the nova scheduling domain,
-// using the known filters, weighers and detectors for validation.
+// using the known filters, weighers, detectors and drain weighers for validation.
 func NewPipelineWebhook() lib.PipelineAdmissionWebhook {
 	validatableFilters := map[string]lib.Validatable{}
 	for name, constructor := range filters.Index {
@@ -26,10 +27,15 @@ func NewPipelineWebhook() lib.PipelineAdmissionWebhook {
 	for name, detector := range detectors.Index {
 		validatableDetectors[name] = detector
 	}
+	validatableDrainWeighers := map[string]lib.Validatable{}
+	for name, constructor := range drainweighers.Index {
+		validatableDrainWeighers[name] = constructor()
+	}
 	return lib.PipelineAdmissionWebhook{
-		SchedulingDomain:     v1alpha1.SchedulingDomainNova,
-		ValidatableFilters:   validatableFilters,
-		ValidatableWeighers:  validatableWeighers,
-		ValidatableDetectors: validatableDetectors,
+		SchedulingDomain:         v1alpha1.SchedulingDomainNova,
+		ValidatableFilters:       validatableFilters,
+		ValidatableWeighers:      validatableWeighers,
+		ValidatableDetectors:     validatableDetectors,
+		ValidatableDrainWeighers: validatableDrainWeighers,
 	}
 }
@@ -0,0 +1,87 @@
+// Copyright SAP SE
+// SPDX-License-Identifier: Apache-2.0
+
+package drainweighers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	api "github.com/cobaltcore-dev/cortex/api/external/nova"
+	"github.com/cobaltcore-dev/cortex/internal/scheduling/lib"
+	hv1 "github.com/cobaltcore-dev/openstack-hypervisor-operator/api/v1"
+)
+
+type KVMPreferFewInstancesStepOpts struct {
+	// Activation given to the hosts with the fewest instances. Hosts with
+	// more instances get less, see the step.
+	Strength float64 `json:"strength"`
+}
+
+// Validate the options to ensure they are correct before running the weigher.
+func (o KVMPreferFewInstancesStepOpts) Validate() error {
+	if o.Strength <= 0 {
+		return errors.New("strength must be greater than zero")
+	}
+	return nil
+}
+
+// This step prefers draining hosts that run few instances, since fewer
+// instances have to be live-migrated to evacuate them.
+//
+// Hosts are boosted inversely proportional to their instance count, scaled
+// so that the hosts with the fewest instances get the configured strength.
+// Hosts for which no hypervisor is known are not weighed.
+type KVMPreferFewInstancesStep struct {
+	// Base weigher providing common functionality.
+	lib.BaseWeigher[api.ExternalSchedulerRequest, KVMPreferFewInstancesStepOpts]
+}
+
+// Run this weigher in the drain pipeline.
+func (s *KVMPreferFewInstancesStep) Run(traceLog *slog.Logger, request api.ExternalSchedulerRequest) (*lib.FilterWeigherPipelineStepResult, error) {
+	result := s.IncludeAllHostsFromRequest(request)
+	result.Statistics["instance count"] = s.PrepareStats(request, "instances")
+
+	hvs := &hv1.HypervisorList{}
+	if err := s.Client.List(context.Background(), hvs); err != nil {
+		traceLog.Error("failed to list hypervisors", "error", err)
+		return nil, err
+	}
+	countsByHost := make(map[string]int, len(hvs.Items))
+	for _, hv := range hvs.Items {
+		if _, ok := result.Activations[hv.Name]; !ok {
+			continue
+		}
+		countsByHost[hv.Name] = len(hv.Status.Instances)
+	}
+	if len(countsByHost) == 0 {
+		traceLog.Info("no hypervisors found for hosts, skipping weigher")
+		return result, nil
+	}
+	minCount := -1
+	for _, count := range countsByHost {
+		if minCount < 0 || count < minCount {
+			minCount = count
+		}
+	}
+
+	for host := range result.Activations {
+		count, ok := countsByHost[host]
+		if !ok {
+			traceLog.Info("hypervisor of host unknown, skipping", "host", host)
+			continue
+		}
+		// Smoothed by one, so that empty hosts don't divide by zero.
+		weight := s.Options.Strength * float64(minCount+1) / float64(count+1)
+		result.Activations[host] = weight
+		result.Statistics["instance count"].Hosts[host] = float64(count)
+		traceLog.Info("calculated drain preference for host",
+			"host", host, "count", count, "minCount", minCount, "weight", weight)
+	}
+	return result, nil
+}
+
+func init() {
+	Index["kvm_prefer_few_instances"] = func() NovaDrainWeigher { return &KVMPreferFewInstancesStep{} }
+}
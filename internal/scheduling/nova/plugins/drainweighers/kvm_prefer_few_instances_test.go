@@ -0,0 +1,163 @@
+// Copyright SAP SE
+// SPDX-License-Identifier: Apache-2.0
+
+package drainweighers
+
+import (
+	"log/slog"
+	"math"
+	"strconv"
+	"testing"
+
+	api "github.com/cobaltcore-dev/cortex/api/external/nova"
+	hv1 "github.com/cobaltcore-dev/openstack-hypervisor-operator/api/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newHypervisorWithInstanceCount(name string, count int) *hv1.Hypervisor {
+	instances := make([]hv1.Instance, count)
+	for i := range instances {
+		id := name + "-instance-" + strconv.Itoa(i)
+		instances[i] = hv1.Instance{ID: id, Name: "instance-" + id, Active: true}
+	}
+	return &hv1.Hypervisor{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status:     hv1.HypervisorStatus{Instances: instances},
+	}
+}
+
+func TestKVMPreferFewInstancesStepOpts_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    KVMPreferFewInstancesStepOpts
+		wantErr bool
+	}{
+		{
+			name:    "valid opts",
+			opts:    KVMPreferFewInstancesStepOpts{Strength: 1},
+			wantErr: false,
+		},
+		{
+			name:    "zero strength",
+			opts:    KVMPreferFewInstancesStepOpts{},
+			wantErr: true,
+		},
+		{
+			name:    "negative strength",
+			opts:    KVMPreferFewInstancesStepOpts{Strength: -1},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.opts.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("expected error: %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestKVMPreferFewInstancesStep_Run(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := hv1.SchemeBuilder.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add hv1 scheme: %v", err)
+	}
+
+	tests := []struct {
+		name            string
+		hypervisors     []*hv1.Hypervisor
+		hosts           []string
+		expectedWeights map[string]float64
+	}{
+		{
+			name: "boost inversely proportional to instance count",
+			hypervisors: []*hv1.Hypervisor{
+				newHypervisorWithInstanceCount("host1", 1),
+				newHypervisorWithInstanceCount("host2", 3),
+				newHypervisorWithInstanceCount("host3", 7),
+			},
+			hosts: []string{"host1", "host2", "host3"},
+			expectedWeights: map[string]float64{
+				"host1": 2,   // host with the fewest instances
+				"host2": 1,   // 2 * (1+1)/(3+1)
+				"host3": 0.5, // 2 * (1+1)/(7+1)
+			},
+		},
+		{
+			name: "hosts outside the request don't affect the scale",
+			hypervisors: []*hv1.Hypervisor{
+				newHypervisorWithInstanceCount("host1", 3),
+				newHypervisorWithInstanceCount("host2", 0),
+			},
+			hosts: []string{"host1"},
+			expectedWeights: map[string]float64{
+				"host1": 2,
+			},
+		},
+		{
+			name: "unknown hypervisor - no activation",
+			hypervisors: []*hv1.Hypervisor{
+				newHypervisorWithInstanceCount("host1", 0),
+			},
+			hosts: []string{"host1", "host2"},
+			expectedWeights: map[string]float64{
+				"host1": 2,
+				"host2": 0,
+			},
+		},
+		{
+			name:        "no hypervisors - weigher skips",
+			hypervisors: nil,
+			hosts:       []string{"host1"},
+			expectedWeights: map[string]float64{
+				"host1": 0,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			objects := make([]client.Object, 0, len(tt.hypervisors))
+			for _, hv := range tt.hypervisors {
+				objects = append(objects, hv)
+			}
+			request := api.ExternalSchedulerRequest{}
+			for _, host := range tt.hosts {
+				request.Hosts = append(request.Hosts, api.ExternalSchedulerHost{ComputeHost: host})
+			}
+
+			step := &KVMPreferFewInstancesStep{}
+			step.Options = KVMPreferFewInstancesStepOpts{Strength: 2}
+			step.Client = fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+
+			result, err := step.Run(slog.Default(), request)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			for host, expectedWeight := range tt.expectedWeights {
+				actualWeight, ok := result.Activations[host]
+				if !ok {
+					t.Errorf("expected host %s to be in activations", host)
+					continue
+				}
+				if math.Abs(actualWeight-expectedWeight) > 1e-9 {
+					t.Errorf("for host %s, expected weight %.2f, got %.2f", host, expectedWeight, actualWeight)
+				}
+			}
+		})
+	}
+}
+
+func TestKVMPreferFewInstancesStep_IndexRegistration(t *testing.T) {
+	factory, ok := Index["kvm_prefer_few_instances"]
+	if !ok {
+		t.Fatal("kvm_prefer_few_instances not found in Index")
+	}
+	if _, ok := factory().(*KVMPreferFewInstancesStep); !ok {
+		t.Fatalf("expected *KVMPreferFewInstancesStep, got %T", factory())
+	}
+}
@@ -0,0 +1,18 @@
+// Copyright SAP SE
+// SPDX-License-Identifier: Apache-2.0
+
+package drainweighers
+
+import (
+	api "github.com/cobaltcore-dev/cortex/api/external/nova"
+	"github.com/cobaltcore-dev/cortex/internal/scheduling/lib"
+)
+
+type NovaDrainWeigher = lib.Weigher[api.ExternalSchedulerRequest]
+
+// Configuration of weighers supported by the nova drain pipelines.
+//
+// Drain weighers score the hosts by how safe they are to evacuate, so
+// they are kept apart from the placement weighers which score the hosts
+// by how well they fit a new instance.
+var Index = map[string]func() NovaDrainWeigher{}
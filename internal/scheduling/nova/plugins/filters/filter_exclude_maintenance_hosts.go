@@ -0,0 +1,89 @@
+// Copyright SAP SE
+// SPDX-License-Identifier: Apache-2.0
+
+package filters
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	api "github.com/cobaltcore-dev/cortex/api/external/nova"
+	"github.com/cobaltcore-dev/cortex/api/v1alpha1"
+	"github.com/cobaltcore-dev/cortex/internal/scheduling/lib"
+	hv1 "github.com/cobaltcore-dev/openstack-hypervisor-operator/api/v1"
+)
+
+type FilterExcludeMaintenanceHostsStepOpts struct {
+	// How many minutes before the start of a maintenance window the host
+	// should already be excluded from scheduling.
+	LeadTimeMinutes int `json:"leadTimeMinutes"`
+}
+
+func (opts FilterExcludeMaintenanceHostsStepOpts) Validate() error {
+	if opts.LeadTimeMinutes < 0 {
+		return errors.New("leadTimeMinutes must not be negative")
+	}
+	return nil
+}
+
+// Step that filters out hosts whose scheduled maintenance window overlaps
+// with the time of the request, extended by the configured lead time.
+// Hosts without an announced (or with an invalid) window are kept.
+type FilterExcludeMaintenanceHostsStep struct {
+	lib.BaseFilter[api.ExternalSchedulerRequest, FilterExcludeMaintenanceHostsStepOpts]
+}
+
+// Remove hosts that are in, or about to enter, a maintenance window.
+func (s *FilterExcludeMaintenanceHostsStep) Run(traceLog *slog.Logger, request api.ExternalSchedulerRequest) (*lib.FilterWeigherPipelineStepResult, error) {
+	result := s.IncludeAllHostsFromRequest(request)
+
+	hvs := &hv1.HypervisorList{}
+	if err := s.Client.List(context.Background(), hvs); err != nil {
+		traceLog.Error("failed to list hypervisors", "error", err)
+		return nil, err
+	}
+
+	now := time.Now()
+	leadTime := time.Duration(s.Options.LeadTimeMinutes) * time.Minute
+	for _, hv := range hvs.Items {
+		if _, ok := result.Activations[hv.Name]; !ok {
+			continue
+		}
+		value, ok := hv.Annotations[v1alpha1.AnnotationMaintenanceWindowStart]
+		if !ok {
+			continue
+		}
+		windowStart, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			traceLog.Warn("invalid maintenance window start, keeping host",
+				"host", hv.Name, "value", value, "error", err)
+			continue
+		}
+		// Without an announced end, the window is only known to begin at the
+		// given start. Windows that started in the past are then considered
+		// stale, as the weigher does.
+		windowEnd := windowStart
+		if value, ok := hv.Annotations[v1alpha1.AnnotationMaintenanceWindowEnd]; ok {
+			parsed, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				traceLog.Warn("invalid maintenance window end, ignoring it",
+					"host", hv.Name, "value", value, "error", err)
+			} else {
+				windowEnd = parsed
+			}
+		}
+		if windowStart.After(now.Add(leadTime)) || windowEnd.Before(now) {
+			continue
+		}
+		delete(result.Activations, hv.Name)
+		traceLog.Info("filtering host in or close to a maintenance window",
+			"host", hv.Name, "windowStart", windowStart, "windowEnd", windowEnd)
+	}
+	return result, nil
+}
+
+func init() {
+	Index["filter_exclude_maintenance_hosts"] = func() NovaFilter { return &FilterExcludeMaintenanceHostsStep{} }
+}
@@ -0,0 +1,142 @@
+// Copyright SAP SE
+// SPDX-License-Identifier: Apache-2.0
+
+package filters
+
+import (
+	"log/slog"
+	"testing"
+	"time"
+
+	api "github.com/cobaltcore-dev/cortex/api/external/nova"
+	"github.com/cobaltcore-dev/cortex/api/v1alpha1"
+	hv1 "github.com/cobaltcore-dev/openstack-hypervisor-operator/api/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestFilterExcludeMaintenanceHostsStepOpts_Validate(t *testing.T) {
+	opts := FilterExcludeMaintenanceHostsStepOpts{LeadTimeMinutes: -1}
+	if err := opts.Validate(); err == nil {
+		t.Error("expected error for negative lead time")
+	}
+	opts.LeadTimeMinutes = 60
+	if err := opts.Validate(); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
+
+func TestFilterExcludeMaintenanceHostsStep_Run(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := hv1.AddToScheme(scheme); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	now := time.Now()
+	window := func(name string, start, end *time.Duration) client.Object {
+		annotations := map[string]string{}
+		if start != nil {
+			annotations[v1alpha1.AnnotationMaintenanceWindowStart] = now.Add(*start).Format(time.RFC3339)
+		}
+		if end != nil {
+			annotations[v1alpha1.AnnotationMaintenanceWindowEnd] = now.Add(*end).Format(time.RFC3339)
+		}
+		return &hv1.Hypervisor{ObjectMeta: v1.ObjectMeta{Name: name, Annotations: annotations}}
+	}
+	d := func(d time.Duration) *time.Duration { return &d }
+
+	hvs := []client.Object{
+		// Window far in the future, outside of the lead time.
+		window("host1", d(48*time.Hour), d(50*time.Hour)),
+		// Window in the future, but within the lead time.
+		window("host2", d(30*time.Minute), d(2*time.Hour)),
+		// Window that is already active.
+		window("host3", d(-time.Hour), d(time.Hour)),
+		// Window that is already over.
+		window("host4", d(-3*time.Hour), d(-time.Hour)),
+		// No maintenance window announced.
+		window("host5", nil, nil),
+		// Window without an end, starting within the lead time.
+		window("host6", d(10*time.Minute), nil),
+		// Window without an end that started in the past is stale.
+		window("host7", d(-time.Hour), nil),
+		&hv1.Hypervisor{ObjectMeta: v1.ObjectMeta{
+			Name:        "host8",
+			Annotations: map[string]string{v1alpha1.AnnotationMaintenanceWindowStart: "not-a-time"},
+		}},
+	}
+
+	tests := []struct {
+		name          string
+		leadTime      int
+		request       api.ExternalSchedulerRequest
+		expectedHosts []string
+	}{
+		{
+			name:     "Exclude active and upcoming windows within lead time",
+			leadTime: 60,
+			request: api.ExternalSchedulerRequest{
+				Hosts: []api.ExternalSchedulerHost{
+					{ComputeHost: "host1"},
+					{ComputeHost: "host2"},
+					{ComputeHost: "host3"},
+					{ComputeHost: "host4"},
+					{ComputeHost: "host5"},
+					{ComputeHost: "host6"},
+					{ComputeHost: "host7"},
+					{ComputeHost: "host8"},
+				},
+			},
+			expectedHosts: []string{"host1", "host4", "host5", "host7", "host8"},
+		},
+		{
+			name:     "Without lead time only active windows are excluded",
+			leadTime: 0,
+			request: api.ExternalSchedulerRequest{
+				Hosts: []api.ExternalSchedulerHost{
+					{ComputeHost: "host1"},
+					{ComputeHost: "host2"},
+					{ComputeHost: "host3"},
+					{ComputeHost: "host6"},
+				},
+			},
+			expectedHosts: []string{"host1", "host2", "host6"},
+		},
+		{
+			name:     "Host without hypervisor resource is kept",
+			leadTime: 60,
+			request: api.ExternalSchedulerRequest{
+				Hosts: []api.ExternalSchedulerHost{
+					{ComputeHost: "host3"},
+					{ComputeHost: "host9"},
+				},
+			},
+			expectedHosts: []string{"host9"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step := &FilterExcludeMaintenanceHostsStep{}
+			step.Options.LeadTimeMinutes = tt.leadTime
+			step.Client = fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(hvs...).
+				Build()
+			result, err := step.Run(slog.Default(), tt.request)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if len(result.Activations) != len(tt.expectedHosts) {
+				t.Errorf("expected %d hosts, got %d: %v", len(tt.expectedHosts), len(result.Activations), result.Activations)
+			}
+			for _, host := range tt.expectedHosts {
+				if _, ok := result.Activations[host]; !ok {
+					t.Errorf("expected host %s to be present", host)
+				}
+			}
+		})
+	}
+}
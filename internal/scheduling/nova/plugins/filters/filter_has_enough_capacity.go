@@ -6,8 +6,12 @@ package filters
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
+	"math"
+	"regexp"
 	"slices"
+	"sort"
 
 	api "github.com/cobaltcore-dev/cortex/api/external/nova"
 	"github.com/cobaltcore-dev/cortex/api/v1alpha1"
@@ -15,6 +19,7 @@ import (
 	resv "github.com/cobaltcore-dev/cortex/internal/scheduling/reservations"
 	hv1 "github.com/cobaltcore-dev/openstack-hypervisor-operator/api/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 type FilterHasEnoughCapacityOpts struct {
@@ -30,12 +35,133 @@ type FilterHasEnoughCapacityOpts struct {
 	// IgnoreAllocations skips subtracting current VM allocations from host capacity.
 	// When true, only raw hardware capacity is considered (empty datacenter scenario).
 	IgnoreAllocations bool `json:"ignoreAllocations,omitempty"`
+
+	// OvercommitOverrideAllowedProjects lists the IDs of trusted projects
+	// whose instances may override the overcommit ratios of the hypervisors
+	// through the OvercommitOverrides request option. The project is the one
+	// nova authenticated the request for, not a value chosen by the caller.
+	// Overrides requested for instances of any other project are ignored.
+	OvercommitOverrideAllowedProjects []string `json:"overcommitOverrideAllowedProjects,omitempty"`
+
+	// The flavor headroom options require extra free capacity on the host for
+	// specific flavors, e.g. to keep some room next to GPU or big VMs. The keys
+	// are regular expressions that must match the full flavor name. If a flavor
+	// name is a key itself, that entry is used, otherwise the first matching
+	// expression in lexical order. Each option is resolved on its own, and
+	// flavors without a match need no headroom.
+
+	// Additional vCPUs as a percentage of the flavor's vCPUs (rounded up).
+	FlavorHeadroomVCPUsPercent map[string]float64 `json:"flavorHeadroomVCPUsPercent,omitempty"`
+	// Additional absolute number of vCPUs (rounded up).
+	FlavorHeadroomVCPUs map[string]float64 `json:"flavorHeadroomVCPUs,omitempty"`
+	// Additional memory as a percentage of the flavor's memory (rounded up).
+	FlavorHeadroomMemoryPercent map[string]float64 `json:"flavorHeadroomMemoryPercent,omitempty"`
+	// Additional absolute memory in MB (rounded up).
+	FlavorHeadroomMemoryMB map[string]float64 `json:"flavorHeadroomMemoryMB,omitempty"`
+}
+
+func (opts FilterHasEnoughCapacityOpts) Validate() error {
+	_, err := opts.compileFlavorHeadroom()
+	return err
+}
+
+// Flavor headroom options with their patterns compiled, see
+// FilterHasEnoughCapacityOpts.
+type flavorHeadroom struct {
+	vcpusPercent  flavorHeadroomMatcher
+	vcpus         flavorHeadroomMatcher
+	memoryPercent flavorHeadroomMatcher
+	memoryMB      flavorHeadroomMatcher
+}
+
+// Compile the patterns of all flavor headroom options.
+func (opts FilterHasEnoughCapacityOpts) compileFlavorHeadroom() (flavorHeadroom, error) {
+	var headroom flavorHeadroom
+	var err error
+	if headroom.vcpusPercent, err = newFlavorHeadroomMatcher(opts.FlavorHeadroomVCPUsPercent); err != nil {
+		return headroom, fmt.Errorf("invalid flavorHeadroomVCPUsPercent: %w", err)
+	}
+	if headroom.vcpus, err = newFlavorHeadroomMatcher(opts.FlavorHeadroomVCPUs); err != nil {
+		return headroom, fmt.Errorf("invalid flavorHeadroomVCPUs: %w", err)
+	}
+	if headroom.memoryPercent, err = newFlavorHeadroomMatcher(opts.FlavorHeadroomMemoryPercent); err != nil {
+		return headroom, fmt.Errorf("invalid flavorHeadroomMemoryPercent: %w", err)
+	}
+	if headroom.memoryMB, err = newFlavorHeadroomMatcher(opts.FlavorHeadroomMemoryMB); err != nil {
+		return headroom, fmt.Errorf("invalid flavorHeadroomMemoryMB: %w", err)
+	}
+	return headroom, nil
+}
+
+// Headroom values by flavor name, with the keys compiled as patterns in
+// lexical order.
+type flavorHeadroomMatcher struct {
+	values   map[string]float64
+	patterns []flavorHeadroomPattern
+}
+
+type flavorHeadroomPattern struct {
+	key string
+	re  *regexp.Regexp
 }
 
-func (FilterHasEnoughCapacityOpts) Validate() error { return nil }
+func newFlavorHeadroomMatcher(values map[string]float64) (flavorHeadroomMatcher, error) {
+	keys := make([]string, 0, len(values))
+	for key, value := range values {
+		if value < 0 {
+			return flavorHeadroomMatcher{}, fmt.Errorf("headroom for %q must not be negative", key)
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	m := flavorHeadroomMatcher{values: values}
+	for _, key := range keys {
+		re, err := regexp.Compile("^(?:" + key + ")$")
+		if err != nil {
+			return flavorHeadroomMatcher{}, fmt.Errorf("invalid flavor pattern %q: %w", key, err)
+		}
+		m.patterns = append(m.patterns, flavorHeadroomPattern{key: key, re: re})
+	}
+	return m, nil
+}
+
+// Get the headroom configured for the given flavor, or 0 if there is none.
+func (m flavorHeadroomMatcher) lookup(flavorName string) float64 {
+	if value, ok := m.values[flavorName]; ok {
+		return value
+	}
+	for _, pattern := range m.patterns {
+		if pattern.re.MatchString(flavorName) {
+			return m.values[pattern.key]
+		}
+	}
+	return 0
+}
+
+// Add the percentage and absolute headroom to the requested amount.
+func withHeadroom(requested uint64, percent, absolute float64) uint64 {
+	extra := uint64(math.Ceil(float64(requested)*percent/100) + math.Ceil(absolute))
+	return requested + extra
+}
 
 type FilterHasEnoughCapacity struct {
 	lib.BaseFilter[api.ExternalSchedulerRequest, FilterHasEnoughCapacityOpts]
+
+	// Flavor headroom compiled once from the options during Init.
+	headroom flavorHeadroom
+}
+
+// Init the filter and compile the flavor headroom patterns.
+func (s *FilterHasEnoughCapacity) Init(ctx context.Context, client client.Client, step v1alpha1.FilterSpec) error {
+	if err := s.BaseFilter.Init(ctx, client, step); err != nil {
+		return err
+	}
+	headroom, err := s.Options.compileFlavorHeadroom()
+	if err != nil {
+		return err
+	}
+	s.headroom = headroom
+	return nil
 }
 
 // Filter hosts that don't have enough capacity to run the requested flavor.
@@ -66,6 +192,30 @@ func (s *FilterHasEnoughCapacity) Run(traceLog *slog.Logger, request api.Externa
 	opts := request.GetOptions()
 	result := s.IncludeAllHostsFromRequest(request)
 
+	var overcommitOverrides map[string]float64
+	if len(opts.OvercommitOverrides) > 0 {
+		if slices.Contains(s.Options.OvercommitOverrideAllowedProjects, request.Spec.Data.ProjectID) {
+			traceLog.Info("applying requested overcommit overrides", "overrides", opts.OvercommitOverrides)
+			overcommitOverrides = opts.OvercommitOverrides
+		} else {
+			traceLog.Warn("ignoring overcommit overrides from unauthorized project",
+				"project", request.Spec.Data.ProjectID, "overrides", opts.OvercommitOverrides)
+		}
+	}
+
+	// Resources needed per instance, including the configured headroom.
+	requiredVCPUs := request.Spec.Data.Flavor.Data.VCPUs
+	requiredMemoryMB := request.Spec.Data.Flavor.Data.MemoryMB
+	flavorName := request.Spec.Data.Flavor.Data.Name
+	requiredVCPUs = withHeadroom(requiredVCPUs,
+		s.headroom.vcpusPercent.lookup(flavorName), s.headroom.vcpus.lookup(flavorName))
+	requiredMemoryMB = withHeadroom(requiredMemoryMB,
+		s.headroom.memoryPercent.lookup(flavorName), s.headroom.memoryMB.lookup(flavorName))
+	if requiredVCPUs != request.Spec.Data.Flavor.Data.VCPUs || requiredMemoryMB != request.Spec.Data.Flavor.Data.MemoryMB {
+		traceLog.Info("applying flavor headroom", "flavor", flavorName,
+			"requiredVCPUs", requiredVCPUs, "requiredMemoryMB", requiredMemoryMB)
+	}
+
 	// This map holds the free resources per host.
 	freeResourcesByHost := make(map[string]map[hv1.ResourceName]resource.Quantity)
 
@@ -85,6 +235,16 @@ func (s *FilterHasEnoughCapacity) Run(traceLog *slog.Logger, request api.Externa
 			// Start with the total effective capacity which is capacity * overcommit ratio.
 			freeResourcesByHost[hv.Name] = hv.Status.EffectiveCapacity
 		}
+		// Replace the effective capacity with the overridden one if requested.
+		for resourceName, ratio := range overcommitOverrides {
+			capacity, ok := hv.Status.Capacity[hv1.ResourceName(resourceName)]
+			if !ok {
+				continue
+			}
+			//nolint:gosec // Overcommit ratios are validated to be >= 1.0.
+			overridden := resource.NewQuantity(int64(capacity.AsApproximateFloat64()*ratio), capacity.Format)
+			freeResourcesByHost[hv.Name][hv1.ResourceName(resourceName)] = *overridden
+		}
 
 		// Subtract allocated resources (skip when ignoring allocations for empty-datacenter capacity queries).
 		if !s.Options.IgnoreAllocations {
@@ -152,11 +312,14 @@ func (s *FilterHasEnoughCapacity) Run(traceLog *slog.Logger, request api.Externa
 					// For committed resource reservations: unlock resources only if:
 					// 1. Project ID matches
 					// 2. ResourceGroup matches the flavor's hw_version
-					reservation.Spec.CommittedResourceReservation.MatchesGroup(request.Spec.Data.ProjectID, request.Spec.Data.Flavor.Data.ExtraSpecs["hw_version"]):
+					// 3. Domain ID matches, if the reservation specifies one
+					reservation.Spec.CommittedResourceReservation.MatchesGroup(request.Spec.Data.ProjectID, request.Spec.Data.Flavor.Data.ExtraSpecs["hw_version"]) &&
+					reservation.Spec.CommittedResourceReservation.MatchesDomain(request.Context.ProjectDomainID):
 					traceLog.Info("unlocking resources reserved by matching committed resource reservation with allocation",
 						"reservation", reservation.Name,
 						"instanceUUID", request.Spec.Data.InstanceUUID,
 						"projectID", request.Spec.Data.ProjectID,
+						"domainID", request.Context.ProjectDomainID,
 						"resourceGroup", reservation.Spec.CommittedResourceReservation.ResourceGroup)
 					continue
 				}
@@ -271,15 +434,17 @@ func (s *FilterHasEnoughCapacity) Run(traceLog *slog.Logger, request api.Externa
 		}
 		// Calculate how many instances can fit on this host, based on cpu.
 		//nolint:gosec // We're checking for underflows above (< 0).
-		vcpuSlots := uint64(freeCPU.Value()) /
-			request.Spec.Data.Flavor.Data.VCPUs
+		vcpuSlots := uint64(freeCPU.Value()) / requiredVCPUs
 		if vcpuSlots < request.Spec.Data.NumInstances {
 			traceLog.Info(
 				"filtering host due to insufficient CPU capacity",
-				"host", host, "requested", request.Spec.Data.Flavor.Data.VCPUs,
+				"host", host, "requested", requiredVCPUs,
 				"available", freeCPU.String(),
 			)
-			delete(result.Activations, host)
+			result.RemoveHost(host, fmt.Sprintf(
+				"insufficient vCPUs: need %d have %d",
+				requiredVCPUs*request.Spec.Data.NumInstances, freeCPU.Value(),
+			))
 			continue
 		}
 
@@ -299,22 +464,24 @@ func (s *FilterHasEnoughCapacity) Run(traceLog *slog.Logger, request api.Externa
 		// Note: according to the OpenStack docs, the memory is in MB, not MiB.
 		// See: https://docs.openstack.org/nova/latest/user/flavors.html
 		//nolint:gosec // We're checking for underflows above (< 0).
-		memorySlots := uint64(freeMemory.Value()/1_000_000 /* MB */) /
-			request.Spec.Data.Flavor.Data.MemoryMB
+		memorySlots := uint64(freeMemory.Value()/1_000_000 /* MB */) / requiredMemoryMB
 		if memorySlots < request.Spec.Data.NumInstances {
 			traceLog.Info(
 				"filtering host due to insufficient RAM capacity",
-				"host", host, "requested_mb", request.Spec.Data.Flavor.Data.MemoryMB,
+				"host", host, "requested_mb", requiredMemoryMB,
 				"available_mb", freeMemory.String(),
 			)
-			delete(result.Activations, host)
+			result.RemoveHost(host, fmt.Sprintf(
+				"insufficient memory: need %d MB have %d MB",
+				requiredMemoryMB*request.Spec.Data.NumInstances, freeMemory.Value()/1_000_000,
+			))
 			continue
 		}
 		traceLog.Info(
 			"host has enough capacity", "host", host,
-			"requested_cpus", request.Spec.Data.Flavor.Data.VCPUs,
+			"requested_cpus", requiredVCPUs,
 			"available_cpus", freeCPU.String(),
-			"requested_memory_mb", request.Spec.Data.Flavor.Data.MemoryMB,
+			"requested_memory_mb", requiredMemoryMB,
 			"available_memory", freeMemory.String(),
 		)
 	}
@@ -322,7 +489,7 @@ func (s *FilterHasEnoughCapacity) Run(traceLog *slog.Logger, request api.Externa
 	// Remove all hosts that weren't encountered.
 	for host := range result.Activations {
 		if _, ok := hostsEncountered[host]; !ok {
-			delete(result.Activations, host)
+			result.RemoveHost(host, "unknown capacity: no hypervisor found")
 			traceLog.Info(
 				"removing host with unknown capacity",
 				"host", host,
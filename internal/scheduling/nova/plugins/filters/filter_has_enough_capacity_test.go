@@ -4,7 +4,9 @@
 package filters
 
 import (
+	"context"
 	"log/slog"
+	"strings"
 	"testing"
 
 	api "github.com/cobaltcore-dev/cortex/api/external/nova"
@@ -332,6 +334,19 @@ func TestFilterHasEnoughCapacityOpts_Validate(t *testing.T) {
 		{"valid options with lock reserved true", FilterHasEnoughCapacityOpts{LockReserved: true}, false},
 		{"valid options with lock reserved false", FilterHasEnoughCapacityOpts{LockReserved: false}, false},
 		{"valid options with default values", FilterHasEnoughCapacityOpts{}, false},
+		{"valid flavor headroom", FilterHasEnoughCapacityOpts{
+			FlavorHeadroomVCPUsPercent: map[string]float64{"g_.*": 10},
+			FlavorHeadroomMemoryMB:     map[string]float64{"g_.*": 1024},
+		}, false},
+		{"invalid flavor headroom pattern", FilterHasEnoughCapacityOpts{
+			FlavorHeadroomVCPUs: map[string]float64{"g_[": 1},
+		}, true},
+		{"negative flavor headroom percentage", FilterHasEnoughCapacityOpts{
+			FlavorHeadroomMemoryPercent: map[string]float64{"g_.*": -5},
+		}, true},
+		{"negative absolute flavor headroom", FilterHasEnoughCapacityOpts{
+			FlavorHeadroomMemoryMB: map[string]float64{"g_.*": -1},
+		}, true},
 	}
 
 	for _, tt := range tests {
@@ -1483,3 +1498,271 @@ func TestFilterHasEnoughCapacity_VMInterReservationMigration(t *testing.T) {
 		})
 	}
 }
+
+func TestFilterHasEnoughCapacity_OvercommitOverrides(t *testing.T) {
+	scheme := buildTestScheme(t)
+
+	tests := []struct {
+		name          string
+		projectID     string
+		expectedHosts []string
+		filteredHosts []string
+	}{
+		{
+			// Overriding the cpu overcommit to 1.0 leaves only 8 physical cores.
+			name:          "authorized override is applied",
+			projectID:     "project-trusted",
+			expectedHosts: []string{},
+			filteredHosts: []string{"host1"},
+		},
+		{
+			// Falls back to the effective capacity of 16 cores.
+			name:          "unauthorized override is ignored",
+			projectID:     "project-A",
+			expectedHosts: []string{"host1"},
+			filteredHosts: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hv := newHypervisorWithBothCapacities("host1", "8", "16", "32Gi", "32Gi")
+			step := &FilterHasEnoughCapacity{}
+			step.Client = fake.NewClientBuilder().WithScheme(scheme).WithObjects(hv).Build()
+			step.Options = FilterHasEnoughCapacityOpts{
+				OvercommitOverrideAllowedProjects: []string{"project-trusted"},
+			}
+
+			request := newNovaRequest("instance-123", tt.projectID, "m1.large", "gp-1", 12, "8Gi", false, []string{"host1"})
+			request.Options.OvercommitOverrides = map[string]float64{"cpu": 1.0}
+
+			result, err := step.Run(slog.Default(), request)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			assertActivations(t, result.Activations, tt.expectedHosts, tt.filteredHosts)
+		})
+	}
+}
+
+func TestFilterHasEnoughCapacity_FlavorHeadroom(t *testing.T) {
+	scheme := buildTestScheme(t)
+
+	// Each host has 16 free cores and 32Gi free memory.
+	hvs := []client.Object{
+		newHypervisor("host1", "16", "0", "32Gi", "0"),
+	}
+	floatMap := func(m map[string]float64) *map[string]float64 { return &m }
+	headroom := v1alpha1.Parameters{
+		{Key: "flavorHeadroomVCPUsPercent", FloatMapValue: floatMap(map[string]float64{
+			"g_c16_m8": 10, // 16 + 2 cores
+		})},
+		{Key: "flavorHeadroomVCPUs", FloatMapValue: floatMap(map[string]float64{
+			"g_.*":      1, // 16 + 1 cores
+			"m1\\.tiny": 0, // explicit no-op override
+			"bigvm_.*":  4, // 8 + 4 cores
+		})},
+		{Key: "flavorHeadroomMemoryPercent", FloatMapValue: floatMap(map[string]float64{
+			"hana_.*":    50,        // memory * 1.5
+			"hana_c8_.*": 1_000_000, // would never fit, but sorted after hana_.*
+		})},
+		{Key: "flavorHeadroomMemoryMB", FloatMapValue: floatMap(map[string]float64{
+			"bigvm_.*": 30_000, // 8Gi + 30GB memory
+		})},
+	}
+
+	tests := []struct {
+		name          string
+		flavorName    string
+		vcpus         int
+		memory        string
+		headroom      v1alpha1.Parameters
+		expectedHosts []string
+		filteredHosts []string
+	}{
+		{
+			name:          "no headroom configured keeps behavior",
+			flavorName:    "g_c16_m8",
+			vcpus:         16,
+			memory:        "8Gi",
+			expectedHosts: []string{"host1"},
+		},
+		{
+			name:          "flavor without matching headroom fits",
+			flavorName:    "m1.large",
+			vcpus:         16,
+			memory:        "8Gi",
+			headroom:      headroom,
+			expectedHosts: []string{"host1"},
+		},
+		{
+			name:          "exact flavor name takes precedence over pattern",
+			flavorName:    "g_c16_m8",
+			vcpus:         16,
+			memory:        "8Gi",
+			headroom:      headroom,
+			filteredHosts: []string{"host1"},
+		},
+		{
+			name:          "percentage headroom on cpu filters host",
+			flavorName:    "g_c16_m16",
+			vcpus:         16,
+			memory:        "8Gi",
+			headroom:      headroom,
+			filteredHosts: []string{"host1"},
+		},
+		{
+			name:          "pattern headroom leaves enough room",
+			flavorName:    "g_c15_m8",
+			vcpus:         15,
+			memory:        "8Gi",
+			headroom:      headroom,
+			expectedHosts: []string{"host1"},
+		},
+		{
+			name:          "percentage headroom on memory filters host",
+			flavorName:    "hana_c8_m24",
+			vcpus:         8,
+			memory:        "24Gi",
+			headroom:      headroom,
+			filteredHosts: []string{"host1"},
+		},
+		{
+			name:          "first matching pattern in lexical order is used",
+			flavorName:    "hana_c8_m16",
+			vcpus:         8,
+			memory:        "16Gi",
+			headroom:      headroom,
+			expectedHosts: []string{"host1"},
+		},
+		{
+			name:          "absolute memory headroom filters host",
+			flavorName:    "bigvm_c8_m8",
+			vcpus:         8,
+			memory:        "8Gi",
+			headroom:      headroom,
+			filteredHosts: []string{"host1"},
+		},
+		{
+			name:          "escaped pattern matches flavor",
+			flavorName:    "m1.tiny",
+			vcpus:         16,
+			memory:        "32Gi",
+			headroom:      headroom,
+			expectedHosts: []string{"host1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step := &FilterHasEnoughCapacity{}
+			cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(hvs...).Build()
+			spec := v1alpha1.FilterSpec{Name: "filter_has_enough_capacity", Params: tt.headroom}
+			if err := step.Init(context.Background(), cl, spec); err != nil {
+				t.Fatalf("expected no error on init, got %v", err)
+			}
+
+			request := newNovaRequest("instance-123", "project-A", tt.flavorName, "gp-1", tt.vcpus, tt.memory, false, []string{"host1"})
+			result, err := step.Run(slog.Default(), request)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			assertActivations(t, result.Activations, tt.expectedHosts, tt.filteredHosts)
+		})
+	}
+}
+
+func TestFilterHasEnoughCapacity_ReservationDomain(t *testing.T) {
+	scheme := buildTestScheme(t)
+
+	tests := []struct {
+		name                string
+		reservationDomainID string
+		requestDomainID     string
+		expectedHosts       []string
+		filteredHosts       []string
+	}{
+		{
+			name:                "matching domain: reservation unlocked, host1 passes",
+			reservationDomainID: "domain-A",
+			requestDomainID:     "domain-A",
+			expectedHosts:       []string{"host1", "host2"},
+			filteredHosts:       []string{},
+		},
+		{
+			name:                "domain mismatch: reservation stays locked, host1 filtered",
+			reservationDomainID: "domain-A",
+			requestDomainID:     "domain-B",
+			expectedHosts:       []string{"host2"},
+			filteredHosts:       []string{"host1"},
+		},
+		{
+			name:                "request without domain: reservation stays locked, host1 filtered",
+			reservationDomainID: "domain-A",
+			requestDomainID:     "",
+			expectedHosts:       []string{"host2"},
+			filteredHosts:       []string{"host1"},
+		},
+		{
+			name:                "reservation without domain: unlocked for any domain",
+			reservationDomainID: "",
+			requestDomainID:     "domain-B",
+			expectedHosts:       []string{"host1", "host2"},
+			filteredHosts:       []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reservation := newCommittedReservation("cr-res", "host1", "project-A", "m1.large", "gp-1", "8", "16Gi", nil, nil)
+			reservation.Spec.CommittedResourceReservation.DomainID = tt.reservationDomainID
+			objects := []client.Object{
+				newHypervisor("host1", "16", "8", "32Gi", "16Gi"), // 8 CPU free after alloc, 0 with reservation
+				newHypervisor("host2", "16", "0", "32Gi", "0"),
+				reservation,
+			}
+			step := &FilterHasEnoughCapacity{}
+			step.Client = fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+
+			request := newNovaRequest("instance-123", "project-A", "m1.large", "gp-1", 4, "8Gi", false, []string{"host1", "host2"})
+			request.Context.ProjectDomainID = tt.requestDomainID
+			result, err := step.Run(slog.Default(), request)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assertActivations(t, result.Activations, tt.expectedHosts, tt.filteredHosts)
+		})
+	}
+}
+
+func TestFilterHasEnoughCapacity_Reasons(t *testing.T) {
+	scheme := buildTestScheme(t)
+	hvs := []client.Object{
+		newHypervisorWithBothCapacities("host-cpu", "8", "8", "32Gi", "32Gi"),
+		newHypervisorWithBothCapacities("host-memory", "16", "16", "4Gi", "4Gi"),
+		newHypervisorWithBothCapacities("host-fits", "16", "16", "32Gi", "32Gi"),
+	}
+	step := &FilterHasEnoughCapacity{}
+	step.Client = fake.NewClientBuilder().WithScheme(scheme).WithObjects(hvs...).Build()
+	request := newNovaRequest("instance-123", "project-A", "m1.large", "gp-1", 12, "8Gi", false,
+		[]string{"host-cpu", "host-memory", "host-fits", "host-missing"})
+
+	result, err := step.Run(slog.Default(), request)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	assertActivations(t, result.Activations, []string{"host-fits"}, []string{"host-cpu", "host-memory", "host-missing"})
+	expectedPrefixes := map[string]string{
+		"host-cpu":     "insufficient vCPUs: need 12 have 8",
+		"host-memory":  "insufficient memory: need",
+		"host-missing": "unknown capacity",
+	}
+	for host, prefix := range expectedPrefixes {
+		if reason := result.Reasons[host]; !strings.HasPrefix(reason, prefix) {
+			t.Errorf("expected reason for %s to start with %q, got %q", host, prefix, reason)
+		}
+	}
+	if _, ok := result.Reasons["host-fits"]; ok {
+		t.Errorf("expected no reason for kept host, got %q", result.Reasons["host-fits"])
+	}
+}
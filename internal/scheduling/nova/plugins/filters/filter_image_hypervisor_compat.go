@@ -0,0 +1,74 @@
+// Copyright SAP SE
+// SPDX-License-Identifier: Apache-2.0
+
+package filters
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	api "github.com/cobaltcore-dev/cortex/api/external/nova"
+	"github.com/cobaltcore-dev/cortex/internal/scheduling/lib"
+	hv1 "github.com/cobaltcore-dev/openstack-hypervisor-operator/api/v1"
+)
+
+type FilterImageHypervisorCompatStep struct {
+	lib.BaseFilter[api.ExternalSchedulerRequest, lib.EmptyFilterWeigherPipelineStepOpts]
+}
+
+// Normalize a hypervisor type given by an image property or autodiscovered
+// by libvirt. Nova treats qemu and kvm as the same virt type, since libvirt
+// reports kvm for qemu hosts with hardware acceleration.
+func normalizeHypervisorType(hvType string) string {
+	hvType = strings.ToLower(strings.TrimSpace(hvType))
+	if hvType == "kvm" {
+		return "qemu"
+	}
+	return hvType
+}
+
+// Filter out hosts whose hypervisor type doesn't match the hypervisor type
+// required by the requested image, given by the img_hv_type image property.
+// Images without this property and hosts without autodiscovered hypervisor
+// type are considered compatible, to avoid over-filtering.
+func (s *FilterImageHypervisorCompatStep) Run(traceLog *slog.Logger, request api.ExternalSchedulerRequest) (*lib.FilterWeigherPipelineStepResult, error) {
+	result := s.IncludeAllHostsFromRequest(request)
+	// Nova example: img_hv_type='qemu'
+	requested, ok := request.Spec.Data.Image.Data.Properties.Data["img_hv_type"].(string)
+	if !ok || strings.TrimSpace(requested) == "" {
+		traceLog.Debug("no hypervisor type required by image, skipping filter")
+		return result, nil
+	}
+	requested = normalizeHypervisorType(requested)
+
+	hvs := &hv1.HypervisorList{}
+	if err := s.Client.List(context.Background(), hvs); err != nil {
+		traceLog.Error("failed to list hypervisors", "error", err)
+		return nil, err
+	}
+	hvTypes := make(map[string]string, len(hvs.Items))
+	for _, hv := range hvs.Items {
+		hvTypes[hv.Name] = normalizeHypervisorType(hv.Status.DomainCapabilities.HypervisorType)
+	}
+
+	for host := range result.Activations {
+		provided := hvTypes[host]
+		if provided == "" {
+			traceLog.Info("host without known hypervisor type, keeping", "host", host)
+			continue
+		}
+		if provided != requested {
+			traceLog.Info(
+				"filtering host with hypervisor type not matching image",
+				"host", host, "want", requested, "have", provided,
+			)
+			delete(result.Activations, host)
+		}
+	}
+	return result, nil
+}
+
+func init() {
+	Index["filter_image_hypervisor_compat"] = func() NovaFilter { return &FilterImageHypervisorCompatStep{} }
+}
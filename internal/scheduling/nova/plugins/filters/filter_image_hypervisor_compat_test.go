@@ -0,0 +1,107 @@
+// Copyright SAP SE
+// SPDX-License-Identifier: Apache-2.0
+
+package filters
+
+import (
+	"log/slog"
+	"testing"
+
+	api "github.com/cobaltcore-dev/cortex/api/external/nova"
+	hv1 "github.com/cobaltcore-dev/openstack-hypervisor-operator/api/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestFilterImageHypervisorCompatStep_Run(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := hv1.AddToScheme(scheme); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	hypervisor := func(name, hvType string) *hv1.Hypervisor {
+		return &hv1.Hypervisor{
+			ObjectMeta: v1.ObjectMeta{Name: name},
+			Status: hv1.HypervisorStatus{
+				DomainCapabilities: hv1.DomainCapabilities{HypervisorType: hvType},
+			},
+		}
+	}
+	hvs := []client.Object{
+		hypervisor("host-ch", "ch"),
+		hypervisor("host-kvm", "kvm"),
+		hypervisor("host-unknown", ""),
+	}
+	request := func(properties map[string]any) api.ExternalSchedulerRequest {
+		return api.ExternalSchedulerRequest{
+			Spec: api.NovaObject[api.NovaSpec]{Data: api.NovaSpec{
+				Image: api.NovaObject[api.NovaImageMeta]{Data: api.NovaImageMeta{
+					Properties: api.NovaObject[map[string]any]{Data: properties},
+				}},
+			}},
+			Hosts: []api.ExternalSchedulerHost{
+				{ComputeHost: "host-ch"},
+				{ComputeHost: "host-kvm"},
+				{ComputeHost: "host-unknown"},
+				{ComputeHost: "host-missing"},
+			},
+		}
+	}
+
+	tests := []struct {
+		name          string
+		request       api.ExternalSchedulerRequest
+		expectedHosts []string
+	}{
+		{
+			name:          "no image properties",
+			request:       request(nil),
+			expectedHosts: []string{"host-ch", "host-kvm", "host-unknown", "host-missing"},
+		},
+		{
+			name:          "image without hypervisor type",
+			request:       request(map[string]any{"hw_disk_bus": "virtio"}),
+			expectedHosts: []string{"host-ch", "host-kvm", "host-unknown", "host-missing"},
+		},
+		{
+			name:          "image requires qemu",
+			request:       request(map[string]any{"img_hv_type": "qemu"}),
+			expectedHosts: []string{"host-kvm", "host-unknown", "host-missing"},
+		},
+		{
+			name:          "image requires kvm",
+			request:       request(map[string]any{"img_hv_type": "KVM"}),
+			expectedHosts: []string{"host-kvm", "host-unknown", "host-missing"},
+		},
+		{
+			name:          "image requires ch",
+			request:       request(map[string]any{"img_hv_type": "ch"}),
+			expectedHosts: []string{"host-ch", "host-unknown", "host-missing"},
+		},
+		{
+			name:          "image requires unsupported hypervisor type",
+			request:       request(map[string]any{"img_hv_type": "vmware"}),
+			expectedHosts: []string{"host-unknown", "host-missing"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step := &FilterImageHypervisorCompatStep{}
+			step.Client = fake.NewClientBuilder().WithScheme(scheme).WithObjects(hvs...).Build()
+			result, err := step.Run(slog.Default(), tt.request)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if len(result.Activations) != len(tt.expectedHosts) {
+				t.Errorf("expected hosts %v, got %v", tt.expectedHosts, result.Activations)
+			}
+			for _, host := range tt.expectedHosts {
+				if _, ok := result.Activations[host]; !ok {
+					t.Errorf("expected host %s to be kept", host)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright SAP SE
+// SPDX-License-Identifier: Apache-2.0
+
+package weighers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	api "github.com/cobaltcore-dev/cortex/api/external/nova"
+	"github.com/cobaltcore-dev/cortex/api/v1alpha1"
+	"github.com/cobaltcore-dev/cortex/internal/knowledge/extractor/plugins/compute"
+	"github.com/cobaltcore-dev/cortex/internal/scheduling/lib"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Options for the scheduling step, given through the
+// step config in the service yaml file.
+type KVMAvoidHotCoolingZonesStepOpts struct {
+	CoolingZoneLoadLowerBound float64 `json:"coolingZoneLoadLowerBound"` // -> mapped to ActivationLowerBound
+	CoolingZoneLoadUpperBound float64 `json:"coolingZoneLoadUpperBound"` // -> mapped to ActivationUpperBound
+
+	CoolingZoneLoadActivationLowerBound float64 `json:"coolingZoneLoadActivationLowerBound"`
+	CoolingZoneLoadActivationUpperBound float64 `json:"coolingZoneLoadActivationUpperBound"`
+}
+
+func (o KVMAvoidHotCoolingZonesStepOpts) Validate() error {
+	// Avoid zero-division during min-max scaling.
+	if o.CoolingZoneLoadLowerBound == o.CoolingZoneLoadUpperBound {
+		return errors.New("coolingZoneLoadLowerBound and coolingZoneLoadUpperBound must not be equal")
+	}
+	return nil
+}
+
+// Step to avoid hosts in cooling zones that are close to their cooling limit
+// by downvoting them. Hosts without cooling zone data are left untouched.
+type KVMAvoidHotCoolingZonesStep struct {
+	// BaseStep is a helper struct that provides common functionality for all steps.
+	lib.BaseWeigher[api.ExternalSchedulerRequest, KVMAvoidHotCoolingZonesStepOpts]
+}
+
+// Initialize the step and validate that all required knowledges are ready.
+func (s *KVMAvoidHotCoolingZonesStep) Init(ctx context.Context, client client.Client, weigher v1alpha1.WeigherSpec) error {
+	if err := s.BaseWeigher.Init(ctx, client, weigher); err != nil {
+		return err
+	}
+	if err := s.CheckKnowledges(ctx, corev1.ObjectReference{Name: "cooling-zone-load"}); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Downvote hosts located in cooling zones with a high thermal load.
+func (s *KVMAvoidHotCoolingZonesStep) Run(traceLog *slog.Logger, request api.ExternalSchedulerRequest) (*lib.FilterWeigherPipelineStepResult, error) {
+	result := s.IncludeAllHostsFromRequest(request)
+
+	result.Statistics["cooling zone load"] = s.PrepareStats(request, "%")
+
+	knowledge := &v1alpha1.Knowledge{}
+	if err := s.Client.Get(
+		context.Background(),
+		client.ObjectKey{Name: "cooling-zone-load"},
+		knowledge,
+	); err != nil {
+		return nil, err
+	}
+	coolingZoneLoads, err := v1alpha1.
+		UnboxFeatureList[compute.CoolingZoneLoad](knowledge.Status.Raw)
+	if err != nil {
+		return nil, err
+	}
+
+	// Push the VM away from hosts in hot cooling zones.
+	for _, host := range coolingZoneLoads {
+		// Only modify the weight if the host is in the scenario.
+		if _, ok := result.Activations[host.ComputeHost]; !ok {
+			continue
+		}
+		result.Activations[host.ComputeHost] = lib.MinMaxScale(
+			host.LoadPct,
+			s.Options.CoolingZoneLoadLowerBound,
+			s.Options.CoolingZoneLoadUpperBound,
+			s.Options.CoolingZoneLoadActivationLowerBound,
+			s.Options.CoolingZoneLoadActivationUpperBound,
+		)
+		result.Statistics["cooling zone load"].Hosts[host.ComputeHost] = host.LoadPct
+		traceLog.Debug("weighed host by cooling zone load",
+			"host", host.ComputeHost, "coolingZone", host.CoolingZone, "loadPct", host.LoadPct)
+	}
+	return result, nil
+}
+
+func init() {
+	Index["kvm_avoid_hot_cooling_zones"] = func() NovaWeigher { return &KVMAvoidHotCoolingZonesStep{} }
+}
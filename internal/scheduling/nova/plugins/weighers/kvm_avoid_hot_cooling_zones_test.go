@@ -0,0 +1,111 @@
+// Copyright SAP SE
+// SPDX-License-Identifier: Apache-2.0
+
+package weighers
+
+import (
+	"log/slog"
+	"testing"
+
+	api "github.com/cobaltcore-dev/cortex/api/external/nova"
+	"github.com/cobaltcore-dev/cortex/api/v1alpha1"
+	"github.com/cobaltcore-dev/cortex/internal/knowledge/extractor/plugins/compute"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestKVMAvoidHotCoolingZonesStepOpts_Validate(t *testing.T) {
+	opts := KVMAvoidHotCoolingZonesStepOpts{
+		CoolingZoneLoadLowerBound: 80,
+		CoolingZoneLoadUpperBound: 80,
+	}
+	if err := opts.Validate(); err == nil {
+		t.Error("expected error for equal bounds")
+	}
+	opts.CoolingZoneLoadUpperBound = 100
+	if err := opts.Validate(); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
+
+func TestKVMAvoidHotCoolingZonesStep_Run(t *testing.T) {
+	scheme, err := v1alpha1.SchemeBuilder.Build()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	coolingZoneLoads, err := v1alpha1.BoxFeatureList([]any{
+		&compute.CoolingZoneLoad{ComputeHost: "host1", CoolingZone: "zone-cool", LoadPct: 40},
+		&compute.CoolingZoneLoad{ComputeHost: "host2", CoolingZone: "zone-warm", LoadPct: 80},
+		&compute.CoolingZoneLoad{ComputeHost: "host3", CoolingZone: "zone-hot", LoadPct: 85},
+		&compute.CoolingZoneLoad{ComputeHost: "host4", CoolingZone: "zone-hot", LoadPct: 100},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	step := &KVMAvoidHotCoolingZonesStep{}
+	step.Options.CoolingZoneLoadLowerBound = 70
+	step.Options.CoolingZoneLoadUpperBound = 100
+	step.Options.CoolingZoneLoadActivationLowerBound = 0.0
+	step.Options.CoolingZoneLoadActivationUpperBound = -1.0
+	step.Client = fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(&v1alpha1.Knowledge{
+			ObjectMeta: metav1.ObjectMeta{Name: "cooling-zone-load"},
+			Status:     v1alpha1.KnowledgeStatus{Raw: coolingZoneLoads},
+		}).
+		Build()
+
+	tests := []struct {
+		name     string
+		request  api.ExternalSchedulerRequest
+		expected map[string]float64
+	}{
+		{
+			name: "Avoid hot cooling zones",
+			request: api.ExternalSchedulerRequest{
+				Hosts: []api.ExternalSchedulerHost{
+					{ComputeHost: "host1"},
+					{ComputeHost: "host2"},
+					{ComputeHost: "host3"},
+					{ComputeHost: "host4"},
+				},
+			},
+			expected: map[string]float64{
+				"host1": 0, // Below the lower bound.
+				"host2": -1.0 / 3,
+				"host3": -0.5,
+				"host4": -1,
+			},
+		},
+		{
+			name: "Missing data",
+			request: api.ExternalSchedulerRequest{
+				Hosts: []api.ExternalSchedulerHost{
+					{ComputeHost: "host4"},
+					{ComputeHost: "host5"}, // No data for host5
+				},
+			},
+			expected: map[string]float64{
+				"host4": -1,
+				"host5": 0, // No data but still contained in the result.
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := step.Run(slog.Default(), tt.request)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			for host, weight := range result.Activations {
+				expected := tt.expected[host]
+				if diff := weight - expected; diff > 1e-9 || diff < -1e-9 {
+					t.Errorf("expected weight for host %s to be %f, got %f", host, expected, weight)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright SAP SE
+// SPDX-License-Identifier: Apache-2.0
+
+package weighers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	api "github.com/cobaltcore-dev/cortex/api/external/nova"
+	"github.com/cobaltcore-dev/cortex/api/v1alpha1"
+	"github.com/cobaltcore-dev/cortex/internal/scheduling/lib"
+	hv1 "github.com/cobaltcore-dev/openstack-hypervisor-operator/api/v1"
+)
+
+// Options for the scheduling step, given through the
+// step config in the service yaml file.
+type KVMAvoidUpcomingMaintenanceStepOpts struct {
+	HoursUntilMaintenanceLowerBound float64 `json:"hoursUntilMaintenanceLowerBound"` // -> mapped to ActivationLowerBound
+	HoursUntilMaintenanceUpperBound float64 `json:"hoursUntilMaintenanceUpperBound"` // -> mapped to ActivationUpperBound
+
+	HoursUntilMaintenanceActivationLowerBound float64 `json:"hoursUntilMaintenanceActivationLowerBound"`
+	HoursUntilMaintenanceActivationUpperBound float64 `json:"hoursUntilMaintenanceActivationUpperBound"`
+}
+
+func (o KVMAvoidUpcomingMaintenanceStepOpts) Validate() error {
+	// Avoid zero-division during min-max scaling.
+	if o.HoursUntilMaintenanceLowerBound == o.HoursUntilMaintenanceUpperBound {
+		return errors.New("hoursUntilMaintenanceLowerBound and hoursUntilMaintenanceUpperBound must not be equal")
+	}
+	return nil
+}
+
+// Step to push long-lived instances away from hosts that are about to be
+// drained for a scheduled maintenance. Hosts already in maintenance are
+// handled by the status conditions filter, this weigher only softly penalizes
+// hosts whose maintenance window is still ahead. The closer the window, the
+// stronger the penalty. Hosts without an announced window stay neutral.
+type KVMAvoidUpcomingMaintenanceStep struct {
+	// Base weigher providing common functionality.
+	lib.BaseWeigher[api.ExternalSchedulerRequest, KVMAvoidUpcomingMaintenanceStepOpts]
+}
+
+// Downvote hosts with an upcoming maintenance window.
+func (s *KVMAvoidUpcomingMaintenanceStep) Run(traceLog *slog.Logger, request api.ExternalSchedulerRequest) (*lib.FilterWeigherPipelineStepResult, error) {
+	result := s.IncludeAllHostsFromRequest(request)
+	result.Statistics["hours until maintenance"] = s.PrepareStats(request, "h")
+
+	hvs := &hv1.HypervisorList{}
+	if err := s.Client.List(context.Background(), hvs); err != nil {
+		traceLog.Error("failed to list hypervisors", "error", err)
+		return nil, err
+	}
+
+	now := time.Now()
+	for _, hv := range hvs.Items {
+		// Only modify the weight if the host is in the scenario.
+		if _, ok := result.Activations[hv.Name]; !ok {
+			continue
+		}
+		value, ok := hv.Annotations[v1alpha1.AnnotationMaintenanceWindowStart]
+		if !ok {
+			continue
+		}
+		windowStart, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			traceLog.Warn("invalid maintenance window start, skipping",
+				"host", hv.Name, "value", value, "error", err)
+			continue
+		}
+		// Windows in the past are either stale or already reflected in the
+		// hypervisor's maintenance status, which is handled by the filters.
+		if !windowStart.After(now) {
+			continue
+		}
+		hoursUntil := windowStart.Sub(now).Hours()
+		result.Activations[hv.Name] = lib.MinMaxScale(
+			hoursUntil,
+			s.Options.HoursUntilMaintenanceLowerBound,
+			s.Options.HoursUntilMaintenanceUpperBound,
+			s.Options.HoursUntilMaintenanceActivationLowerBound,
+			s.Options.HoursUntilMaintenanceActivationUpperBound,
+		)
+		result.Statistics["hours until maintenance"].Hosts[hv.Name] = hoursUntil
+		traceLog.Info("penalizing host with upcoming maintenance",
+			"host", hv.Name, "hoursUntil", hoursUntil)
+	}
+	return result, nil
+}
+
+func init() {
+	Index["kvm_avoid_upcoming_maintenance"] = func() NovaWeigher { return &KVMAvoidUpcomingMaintenanceStep{} }
+}
@@ -0,0 +1,87 @@
+// Copyright SAP SE
+// SPDX-License-Identifier: Apache-2.0
+
+package weighers
+
+import (
+	"log/slog"
+	"testing"
+	"time"
+
+	api "github.com/cobaltcore-dev/cortex/api/external/nova"
+	"github.com/cobaltcore-dev/cortex/api/v1alpha1"
+	hv1 "github.com/cobaltcore-dev/openstack-hypervisor-operator/api/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newHypervisorWithMaintenanceWindow(name string, in *time.Duration) *hv1.Hypervisor {
+	hv := &hv1.Hypervisor{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	if in != nil {
+		hv.Annotations = map[string]string{
+			v1alpha1.AnnotationMaintenanceWindowStart: time.Now().Add(*in).Format(time.RFC3339),
+		}
+	}
+	return hv
+}
+
+func TestKVMAvoidUpcomingMaintenanceStepOpts_Validate(t *testing.T) {
+	opts := KVMAvoidUpcomingMaintenanceStepOpts{
+		HoursUntilMaintenanceLowerBound: 24,
+		HoursUntilMaintenanceUpperBound: 24,
+	}
+	if err := opts.Validate(); err == nil {
+		t.Error("expected error for equal bounds")
+	}
+	opts.HoursUntilMaintenanceUpperBound = 48
+	if err := opts.Validate(); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
+
+func TestKVMAvoidUpcomingMaintenanceStep_Run(t *testing.T) {
+	scheme := buildTestScheme(t)
+	oneHour := time.Hour
+	thirtyDays := 30 * 24 * time.Hour
+	past := -time.Hour
+
+	step := &KVMAvoidUpcomingMaintenanceStep{}
+	step.Client = fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		newHypervisorWithMaintenanceWindow("host-soon", &oneHour),
+		newHypervisorWithMaintenanceWindow("host-later", &thirtyDays),
+		newHypervisorWithMaintenanceWindow("host-past", &past),
+		newHypervisorWithMaintenanceWindow("host-none", nil),
+	).Build()
+	step.Options = KVMAvoidUpcomingMaintenanceStepOpts{
+		HoursUntilMaintenanceLowerBound:           0,
+		HoursUntilMaintenanceUpperBound:           35 * 24,
+		HoursUntilMaintenanceActivationLowerBound: -1,
+		HoursUntilMaintenanceActivationUpperBound: 0,
+	}
+	request := api.ExternalSchedulerRequest{
+		Hosts: []api.ExternalSchedulerHost{
+			{ComputeHost: "host-soon"},
+			{ComputeHost: "host-later"},
+			{ComputeHost: "host-past"},
+			{ComputeHost: "host-none"},
+		},
+	}
+
+	result, err := step.Run(slog.Default(), request)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	soon, later := result.Activations["host-soon"], result.Activations["host-later"]
+	if soon > -0.95 {
+		t.Errorf("expected heavy penalty for host-soon, got %f", soon)
+	}
+	if later >= 0 || later < -0.2 {
+		t.Errorf("expected light penalty for host-later, got %f", later)
+	}
+	if result.Activations["host-past"] != 0 {
+		t.Errorf("expected no penalty for host-past, got %f", result.Activations["host-past"])
+	}
+	if result.Activations["host-none"] != 0 {
+		t.Errorf("expected no penalty for host-none, got %f", result.Activations["host-none"])
+	}
+}
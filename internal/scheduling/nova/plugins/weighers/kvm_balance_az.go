@@ -0,0 +1,104 @@
+// Copyright SAP SE
+// SPDX-License-Identifier: Apache-2.0
+
+package weighers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	api "github.com/cobaltcore-dev/cortex/api/external/nova"
+	"github.com/cobaltcore-dev/cortex/internal/scheduling/lib"
+	hv1 "github.com/cobaltcore-dev/openstack-hypervisor-operator/api/v1"
+)
+
+type KVMBalanceAZStepOpts struct {
+	// Activation given to hosts in the availability zone with the
+	// fewest instances. Hosts in other zones get less, see the step.
+	Strength float64 `json:"strength"`
+}
+
+// Validate the options to ensure they are correct before running the weigher.
+func (o KVMBalanceAZStepOpts) Validate() error {
+	if o.Strength <= 0 {
+		return errors.New("strength must be greater than zero")
+	}
+	return nil
+}
+
+// Get the availability zone of a hypervisor from its aggregate membership.
+// Nova sets the availability_zone metadata on the aggregates that define
+// availability zones. Returns false if the hypervisor is in no such aggregate.
+func hypervisorAZ(hv hv1.Hypervisor) (string, bool) {
+	for _, aggregate := range hv.Status.Aggregates {
+		if az := aggregate.Metadata["availability_zone"]; az != "" {
+			return az, true
+		}
+	}
+	return "", false
+}
+
+// This step evens out placements across availability zones, so that new
+// instances don't pile up in a single zone.
+//
+// Hosts are boosted inversely proportional to the share of instances that
+// is currently placed in their availability zone, scaled so that hosts in
+// the zone with the fewest instances get the configured strength. Hosts
+// for which the availability zone is unknown are not weighed.
+type KVMBalanceAZStep struct {
+	// Base weigher providing common functionality.
+	lib.BaseWeigher[api.ExternalSchedulerRequest, KVMBalanceAZStepOpts]
+}
+
+// Run this weigher in the pipeline after filters have been executed.
+func (s *KVMBalanceAZStep) Run(traceLog *slog.Logger, request api.ExternalSchedulerRequest) (*lib.FilterWeigherPipelineStepResult, error) {
+	result := s.IncludeAllHostsFromRequest(request)
+	result.Statistics["az instance count"] = s.PrepareStats(request, "instances")
+
+	hvs := &hv1.HypervisorList{}
+	if err := s.Client.List(context.Background(), hvs); err != nil {
+		traceLog.Error("failed to list hypervisors", "error", err)
+		return nil, err
+	}
+	azsByHost := make(map[string]string, len(hvs.Items))
+	countsByAZ := make(map[string]int)
+	for _, hv := range hvs.Items {
+		az, ok := hypervisorAZ(hv)
+		if !ok {
+			continue
+		}
+		azsByHost[hv.Name] = az
+		countsByAZ[az] += len(hv.Status.Instances)
+	}
+	if len(countsByAZ) == 0 {
+		traceLog.Info("no availability zones found for hypervisors, skipping weigher")
+		return result, nil
+	}
+	minCount := -1
+	for _, count := range countsByAZ {
+		if minCount < 0 || count < minCount {
+			minCount = count
+		}
+	}
+
+	for host := range result.Activations {
+		az, ok := azsByHost[host]
+		if !ok {
+			traceLog.Info("availability zone of host unknown, skipping", "host", host)
+			continue
+		}
+		count := countsByAZ[az]
+		// Smoothed by one, so that empty zones don't divide by zero.
+		weight := s.Options.Strength * float64(minCount+1) / float64(count+1)
+		result.Activations[host] = weight
+		result.Statistics["az instance count"].Hosts[host] = float64(count)
+		traceLog.Info("calculated availability zone balance for host",
+			"host", host, "az", az, "count", count, "minCount", minCount, "weight", weight)
+	}
+	return result, nil
+}
+
+func init() {
+	Index["kvm_balance_az"] = func() NovaWeigher { return &KVMBalanceAZStep{} }
+}
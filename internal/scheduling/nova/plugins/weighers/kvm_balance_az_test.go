@@ -0,0 +1,165 @@
+// Copyright SAP SE
+// SPDX-License-Identifier: Apache-2.0
+
+package weighers
+
+import (
+	"log/slog"
+	"math"
+	"testing"
+
+	api "github.com/cobaltcore-dev/cortex/api/external/nova"
+	hv1 "github.com/cobaltcore-dev/openstack-hypervisor-operator/api/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newHypervisorInAZ(name, az string, count int) *hv1.Hypervisor {
+	hv := newHypervisorWithInstanceCount(name, count)
+	hv.Status.Aggregates = []hv1.Aggregate{{Name: "general"}}
+	if az != "" {
+		hv.Status.Aggregates = append(hv.Status.Aggregates, hv1.Aggregate{
+			Name:     az,
+			Metadata: map[string]string{"availability_zone": az},
+		})
+	}
+	return hv
+}
+
+func TestKVMBalanceAZStepOpts_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    KVMBalanceAZStepOpts
+		wantErr bool
+	}{
+		{
+			name:    "valid opts",
+			opts:    KVMBalanceAZStepOpts{Strength: 1},
+			wantErr: false,
+		},
+		{
+			name:    "zero strength",
+			opts:    KVMBalanceAZStepOpts{},
+			wantErr: true,
+		},
+		{
+			name:    "negative strength",
+			opts:    KVMBalanceAZStepOpts{Strength: -1},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.opts.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("expected error: %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestKVMBalanceAZStep_Run(t *testing.T) {
+	scheme := buildTestScheme(t)
+
+	tests := []struct {
+		name            string
+		hypervisors     []*hv1.Hypervisor
+		hosts           []string
+		expectedWeights map[string]float64
+	}{
+		{
+			name: "boost inversely proportional to az share",
+			hypervisors: []*hv1.Hypervisor{
+				newHypervisorInAZ("host1", "az-a", 3),
+				newHypervisorInAZ("host2", "az-a", 4),
+				newHypervisorInAZ("host3", "az-b", 1),
+				newHypervisorInAZ("host4", "az-c", 15),
+			},
+			hosts: []string{"host1", "host2", "host3", "host4"},
+			expectedWeights: map[string]float64{
+				"host1": 0.5,  // 2 * (1+1)/(7+1)
+				"host2": 0.5,  // same az as host1
+				"host3": 2,    // az with the fewest instances
+				"host4": 0.25, // 2 * (1+1)/(15+1)
+			},
+		},
+		{
+			name: "empty az gets the full boost",
+			hypervisors: []*hv1.Hypervisor{
+				newHypervisorInAZ("host1", "az-a", 0),
+				newHypervisorInAZ("host2", "az-b", 3),
+			},
+			hosts: []string{"host1", "host2"},
+			expectedWeights: map[string]float64{
+				"host1": 2,   // 2 * (0+1)/(0+1)
+				"host2": 0.5, // 2 * (0+1)/(3+1)
+			},
+		},
+		{
+			name: "unknown az - no activation",
+			hypervisors: []*hv1.Hypervisor{
+				newHypervisorInAZ("host1", "az-a", 1),
+				newHypervisorInAZ("host2", "", 1),
+				// host3 hypervisor is missing
+			},
+			hosts: []string{"host1", "host2", "host3"},
+			expectedWeights: map[string]float64{
+				"host1": 2,
+				"host2": 0,
+				"host3": 0,
+			},
+		},
+		{
+			name: "no az data - weigher skips",
+			hypervisors: []*hv1.Hypervisor{
+				newHypervisorInAZ("host1", "", 5),
+			},
+			hosts: []string{"host1"},
+			expectedWeights: map[string]float64{
+				"host1": 0,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			objects := make([]client.Object, 0, len(tt.hypervisors))
+			for _, hv := range tt.hypervisors {
+				objects = append(objects, hv)
+			}
+			request := api.ExternalSchedulerRequest{}
+			for _, host := range tt.hosts {
+				request.Hosts = append(request.Hosts, api.ExternalSchedulerHost{ComputeHost: host})
+			}
+
+			step := &KVMBalanceAZStep{}
+			step.Options = KVMBalanceAZStepOpts{Strength: 2}
+			step.Client = fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+
+			result, err := step.Run(slog.Default(), request)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			for host, expectedWeight := range tt.expectedWeights {
+				actualWeight, ok := result.Activations[host]
+				if !ok {
+					t.Errorf("expected host %s to be in activations", host)
+					continue
+				}
+				if math.Abs(actualWeight-expectedWeight) > 1e-9 {
+					t.Errorf("for host %s, expected weight %.2f, got %.2f", host, expectedWeight, actualWeight)
+				}
+			}
+		})
+	}
+}
+
+func TestKVMBalanceAZStep_IndexRegistration(t *testing.T) {
+	factory, ok := Index["kvm_balance_az"]
+	if !ok {
+		t.Fatal("kvm_balance_az not found in Index")
+	}
+	if _, ok := factory().(*KVMBalanceAZStep); !ok {
+		t.Fatalf("expected *KVMBalanceAZStep, got %T", factory())
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright SAP SE
+// SPDX-License-Identifier: Apache-2.0
+
+package weighers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+
+	api "github.com/cobaltcore-dev/cortex/api/external/nova"
+	"github.com/cobaltcore-dev/cortex/internal/scheduling/lib"
+	hv1 "github.com/cobaltcore-dev/openstack-hypervisor-operator/api/v1"
+)
+
+// Options for the scheduling step, given through the
+// step config in the service yaml file.
+type KVMInstanceGroupAntiAffinityStepOpts struct {
+	// The activation subtracted from hosts that already run at least one
+	// member of the instance group. Defaults to 1 if not set.
+	Penalty float64 `json:"penalty"`
+}
+
+func (o KVMInstanceGroupAntiAffinityStepOpts) Validate() error {
+	if o.Penalty < 0 {
+		return errors.New("penalty must not be negative")
+	}
+	return nil
+}
+
+// Step to spread instances of an anti-affinity instance group over hosts
+// without forbidding co-location. Hosts already running a member of the group
+// get a fixed penalty, independent of how many members they run. In contrast
+// to the instance group anti-affinity filter, this still allows placing the
+// instance next to other members if no other host is available, e.g. when
+// the group allows multiple servers per host. If the request carries no
+// instance group or the group has no members, all hosts stay neutral.
+type KVMInstanceGroupAntiAffinityStep struct {
+	// Base weigher providing common functionality.
+	lib.BaseWeigher[api.ExternalSchedulerRequest, KVMInstanceGroupAntiAffinityStepOpts]
+}
+
+// Downvote hosts that already run members of the same instance group.
+func (s *KVMInstanceGroupAntiAffinityStep) Run(traceLog *slog.Logger, request api.ExternalSchedulerRequest) (*lib.FilterWeigherPipelineStepResult, error) {
+	result := s.IncludeAllHostsFromRequest(request)
+	result.Statistics["group members on host"] = s.PrepareStats(request, "")
+
+	ig := request.Spec.Data.InstanceGroup
+	if ig == nil {
+		traceLog.Info("no instance group in request, skipping weigher")
+		return result, nil
+	}
+	policy := ig.Data.Policy
+	if policy != "anti-affinity" && policy != "soft-anti-affinity" {
+		traceLog.Info("instance group policy is not 'anti-affinity' or 'soft-anti-affinity', skipping weigher", "policy", policy)
+		return result, nil
+	}
+	// The instance itself may already be a member of the group, e.g. when
+	// it is resized or live-migrated. It should not repel itself.
+	members := slices.DeleteFunc(slices.Clone(ig.Data.Members), func(id string) bool {
+		return id == request.Spec.Data.InstanceUUID
+	})
+	if len(members) == 0 {
+		traceLog.Info("instance group has no other members, skipping weigher")
+		return result, nil
+	}
+
+	penalty := s.Options.Penalty
+	if penalty == 0 {
+		penalty = 1.0
+	}
+
+	hvs := &hv1.HypervisorList{}
+	if err := s.Client.List(context.Background(), hvs); err != nil {
+		traceLog.Error("failed to list hypervisors", "error", err)
+		return nil, err
+	}
+	for _, hv := range hvs.Items {
+		// Only modify the weight if the host is in the scenario.
+		if _, ok := result.Activations[hv.Name]; !ok {
+			continue
+		}
+		count := 0
+		for _, instance := range hv.Status.Instances {
+			if slices.Contains(members, instance.ID) {
+				count++
+			}
+		}
+		result.Statistics["group members on host"].Hosts[hv.Name] = float64(count)
+		if count == 0 {
+			continue
+		}
+		result.Activations[hv.Name] = -penalty
+		traceLog.Info("penalized host running instance group members",
+			"host", hv.Name, "count", count, "penalty", penalty)
+	}
+	return result, nil
+}
+
+func init() {
+	Index["kvm_instance_group_anti_affinity"] = func() NovaWeigher { return &KVMInstanceGroupAntiAffinityStep{} }
+}
@@ -0,0 +1,101 @@
+// Copyright SAP SE
+// SPDX-License-Identifier: Apache-2.0
+
+package weighers
+
+import (
+	"log/slog"
+	"testing"
+
+	api "github.com/cobaltcore-dev/cortex/api/external/nova"
+	hv1 "github.com/cobaltcore-dev/openstack-hypervisor-operator/api/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestKVMInstanceGroupAntiAffinityStepOpts_Validate(t *testing.T) {
+	if err := (KVMInstanceGroupAntiAffinityStepOpts{Penalty: -1}).Validate(); err == nil {
+		t.Error("expected error for negative penalty")
+	}
+	if err := (KVMInstanceGroupAntiAffinityStepOpts{Penalty: 0.5}).Validate(); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
+
+func TestKVMInstanceGroupAntiAffinityStep_Run(t *testing.T) {
+	scheme := buildTestScheme(t)
+	hypervisors := []*hv1.Hypervisor{
+		newHypervisorWithInstances("host1", "member-1", "member-2"),
+		newHypervisorWithInstances("host2", "member-3"),
+		newHypervisorWithInstances("host3", "other"),
+		newHypervisorWithInstances("host4", "new-instance-uuid"),
+	}
+	hosts := []string{"host1", "host2", "host3", "host4", "host5"}
+	members := []string{"member-1", "member-2", "member-3", "new-instance-uuid"}
+
+	tests := []struct {
+		name            string
+		request         api.ExternalSchedulerRequest
+		penalty         float64
+		expectedWeights map[string]float64
+	}{
+		{
+			name:    "anti-affinity - default penalty",
+			request: newInstanceGroupRequest("anti-affinity", members, hosts),
+			expectedWeights: map[string]float64{
+				"host1": -1, "host2": -1, "host3": 0, "host4": 0, "host5": 0,
+			},
+		},
+		{
+			name:    "soft-anti-affinity - configured penalty",
+			request: newInstanceGroupRequest("soft-anti-affinity", members, hosts),
+			penalty: 0.25,
+			expectedWeights: map[string]float64{
+				"host1": -0.25, "host2": -0.25, "host3": 0, "host4": 0, "host5": 0,
+			},
+		},
+		{
+			name:    "affinity policy - weigher skips",
+			request: newInstanceGroupRequest("affinity", members, hosts),
+			expectedWeights: map[string]float64{
+				"host1": 0, "host2": 0, "host3": 0, "host4": 0, "host5": 0,
+			},
+		},
+		{
+			name:    "no instance group - weigher skips",
+			request: newInstanceGroupRequest("", nil, hosts),
+			expectedWeights: map[string]float64{
+				"host1": 0, "host2": 0, "host3": 0, "host4": 0, "host5": 0,
+			},
+		},
+		{
+			name:    "only the instance itself is a member - weigher skips",
+			request: newInstanceGroupRequest("anti-affinity", []string{"new-instance-uuid"}, hosts),
+			expectedWeights: map[string]float64{
+				"host1": 0, "host2": 0, "host3": 0, "host4": 0, "host5": 0,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			objects := make([]client.Object, 0, len(hypervisors))
+			for _, hv := range hypervisors {
+				objects = append(objects, hv.DeepCopy())
+			}
+			step := &KVMInstanceGroupAntiAffinityStep{}
+			step.Client = fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+			step.Options = KVMInstanceGroupAntiAffinityStepOpts{Penalty: tt.penalty}
+
+			result, err := step.Run(slog.Default(), tt.request)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			for host, expected := range tt.expectedWeights {
+				if got := result.Activations[host]; got != expected {
+					t.Errorf("host %s: expected weight %f, got %f", host, expected, got)
+				}
+			}
+		})
+	}
+}
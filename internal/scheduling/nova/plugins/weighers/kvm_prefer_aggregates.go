@@ -0,0 +1,81 @@
+// Copyright SAP SE
+// SPDX-License-Identifier: Apache-2.0
+
+package weighers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+
+	api "github.com/cobaltcore-dev/cortex/api/external/nova"
+	"github.com/cobaltcore-dev/cortex/internal/scheduling/lib"
+	hv1 "github.com/cobaltcore-dev/openstack-hypervisor-operator/api/v1"
+)
+
+type KVMPreferAggregatesStepOpts struct {
+	// Names of the aggregates whose hosts should be preferred.
+	Aggregates []string `json:"aggregates"`
+	// Activation given to hosts in any of the preferred aggregates.
+	Boost float64 `json:"boost"`
+}
+
+// Validate the options to ensure they are correct before running the weigher.
+func (o KVMPreferAggregatesStepOpts) Validate() error {
+	if len(o.Aggregates) == 0 {
+		return errors.New("at least one aggregate must be specified")
+	}
+	if o.Boost <= 0 {
+		return errors.New("boost must be greater than zero")
+	}
+	return nil
+}
+
+// This step implements a soft affinity to the configured host aggregates,
+// e.g. to prefer dedicated hardware without hard-requiring it. Hosts that
+// are members of at least one of the preferred aggregates get the configured
+// boost, all other hosts are not weighed.
+type KVMPreferAggregatesStep struct {
+	// Base weigher providing common functionality.
+	lib.BaseWeigher[api.ExternalSchedulerRequest, KVMPreferAggregatesStepOpts]
+}
+
+// Run this weigher in the pipeline after filters have been executed.
+func (s *KVMPreferAggregatesStep) Run(traceLog *slog.Logger, request api.ExternalSchedulerRequest) (*lib.FilterWeigherPipelineStepResult, error) {
+	result := s.IncludeAllHostsFromRequest(request)
+	result.Statistics["preferred aggregate"] = s.PrepareStats(request, "bool")
+
+	hvs := &hv1.HypervisorList{}
+	if err := s.Client.List(context.Background(), hvs); err != nil {
+		traceLog.Error("failed to list hypervisors", "error", err)
+		return nil, err
+	}
+	hvsByName := make(map[string]hv1.Hypervisor, len(hvs.Items))
+	for _, hv := range hvs.Items {
+		hvsByName[hv.Name] = hv
+	}
+
+	for host := range result.Activations {
+		hv, ok := hvsByName[host]
+		if !ok {
+			traceLog.Info("host not found in hypervisor list, skipping", "host", host)
+			continue
+		}
+		preferred := slices.ContainsFunc(hv.Status.Aggregates, func(aggregate hv1.Aggregate) bool {
+			return slices.Contains(s.Options.Aggregates, aggregate.Name)
+		})
+		if !preferred {
+			result.Statistics["preferred aggregate"].Hosts[host] = 0
+			continue
+		}
+		result.Activations[host] = s.Options.Boost
+		result.Statistics["preferred aggregate"].Hosts[host] = 1
+		traceLog.Info("host is in preferred aggregate, boosting", "host", host, "boost", s.Options.Boost)
+	}
+	return result, nil
+}
+
+func init() {
+	Index["kvm_prefer_aggregates"] = func() NovaWeigher { return &KVMPreferAggregatesStep{} }
+}
@@ -0,0 +1,110 @@
+// Copyright SAP SE
+// SPDX-License-Identifier: Apache-2.0
+
+package weighers
+
+import (
+	"log/slog"
+	"testing"
+
+	api "github.com/cobaltcore-dev/cortex/api/external/nova"
+	hv1 "github.com/cobaltcore-dev/openstack-hypervisor-operator/api/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newHypervisorInAggregates(name string, aggregates ...string) *hv1.Hypervisor {
+	hv := &hv1.Hypervisor{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	for _, aggregate := range aggregates {
+		hv.Status.Aggregates = append(hv.Status.Aggregates, hv1.Aggregate{Name: aggregate})
+	}
+	return hv
+}
+
+func TestKVMPreferAggregatesStepOpts_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    KVMPreferAggregatesStepOpts
+		wantErr bool
+	}{
+		{
+			name:    "valid opts",
+			opts:    KVMPreferAggregatesStepOpts{Aggregates: []string{"dedicated"}, Boost: 1},
+			wantErr: false,
+		},
+		{
+			name:    "no aggregates",
+			opts:    KVMPreferAggregatesStepOpts{Boost: 1},
+			wantErr: true,
+		},
+		{
+			name:    "zero boost",
+			opts:    KVMPreferAggregatesStepOpts{Aggregates: []string{"dedicated"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.opts.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("expected error: %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestKVMPreferAggregatesStep_Run(t *testing.T) {
+	scheme := buildTestScheme(t)
+	hvs := []client.Object{
+		newHypervisorInAggregates("host-none"),
+		newHypervisorInAggregates("host-other", "general"),
+		newHypervisorInAggregates("host-one", "general", "dedicated"),
+		newHypervisorInAggregates("host-multiple", "dedicated", "gpu"),
+	}
+	request := api.ExternalSchedulerRequest{
+		Hosts: []api.ExternalSchedulerHost{
+			{ComputeHost: "host-none"},
+			{ComputeHost: "host-other"},
+			{ComputeHost: "host-one"},
+			{ComputeHost: "host-multiple"},
+			{ComputeHost: "host-missing"},
+		},
+	}
+
+	step := &KVMPreferAggregatesStep{}
+	step.Options = KVMPreferAggregatesStepOpts{Aggregates: []string{"dedicated", "gpu"}, Boost: 0.5}
+	step.Client = fake.NewClientBuilder().WithScheme(scheme).WithObjects(hvs...).Build()
+	result, err := step.Run(slog.Default(), request)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expectedWeights := map[string]float64{
+		"host-none":     0,   // in no aggregate
+		"host-other":    0,   // in no preferred aggregate
+		"host-one":      0.5, // in one preferred aggregate
+		"host-multiple": 0.5, // boosted once, not per aggregate
+		"host-missing":  0,   // unknown aggregate membership
+	}
+	for host, expected := range expectedWeights {
+		actual, ok := result.Activations[host]
+		if !ok {
+			t.Errorf("expected host %s to be in activations", host)
+			continue
+		}
+		if actual != expected {
+			t.Errorf("for host %s, expected weight %.1f, got %.1f", host, expected, actual)
+		}
+	}
+}
+
+func TestKVMPreferAggregatesStep_IndexRegistration(t *testing.T) {
+	factory, ok := Index["kvm_prefer_aggregates"]
+	if !ok {
+		t.Fatal("kvm_prefer_aggregates not found in Index")
+	}
+	if _, ok := factory().(*KVMPreferAggregatesStep); !ok {
+		t.Fatalf("expected *KVMPreferAggregatesStep, got %T", factory())
+	}
+}
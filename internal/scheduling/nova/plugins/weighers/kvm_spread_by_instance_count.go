@@ -0,0 +1,91 @@
+// Copyright SAP SE
+// SPDX-License-Identifier: Apache-2.0
+
+package weighers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	api "github.com/cobaltcore-dev/cortex/api/external/nova"
+	"github.com/cobaltcore-dev/cortex/internal/scheduling/lib"
+	hv1 "github.com/cobaltcore-dev/openstack-hypervisor-operator/api/v1"
+)
+
+type KVMSpreadByInstanceCountStepOpts struct {
+	// Maximum number of instances per host, by flavor class. The flavor
+	// class is given by the hw_version extra spec of the requested flavor.
+	// Requests for flavor classes not configured here are not weighed.
+	MaxInstancesPerFlavorClass map[string]int `json:"maxInstancesPerFlavorClass"`
+}
+
+// Validate the options to ensure they are correct before running the weigher.
+func (o KVMSpreadByInstanceCountStepOpts) Validate() error {
+	if len(o.MaxInstancesPerFlavorClass) == 0 {
+		return errors.New("at least one flavor class must be configured")
+	}
+	for flavorClass, maxInstances := range o.MaxInstancesPerFlavorClass {
+		if maxInstances <= 0 {
+			return fmt.Errorf("max instances for flavor class %q must be greater than zero", flavorClass)
+		}
+	}
+	return nil
+}
+
+// This step spreads instances by penalizing hosts that approach the
+// configured maximum instance count for the requested flavor class.
+//
+// The penalty grows quadratically with the host's instance count, so that
+// hosts far from the cap are barely affected while hosts close to it are
+// avoided. Hosts at or over the cap get the full penalty of -1. Hosts for
+// which the instance count is unknown are not weighed.
+type KVMSpreadByInstanceCountStep struct {
+	// Base weigher providing common functionality.
+	lib.BaseWeigher[api.ExternalSchedulerRequest, KVMSpreadByInstanceCountStepOpts]
+}
+
+// Run this weigher in the pipeline after filters have been executed.
+func (s *KVMSpreadByInstanceCountStep) Run(traceLog *slog.Logger, request api.ExternalSchedulerRequest) (*lib.FilterWeigherPipelineStepResult, error) {
+	result := s.IncludeAllHostsFromRequest(request)
+	result.Statistics["instance count"] = s.PrepareStats(request, "instances")
+
+	flavorClass := request.Spec.Data.Flavor.Data.ExtraSpecs["hw_version"]
+	maxInstances, ok := s.Options.MaxInstancesPerFlavorClass[flavorClass]
+	if !ok {
+		traceLog.Info("no max instance count configured for flavor class, skipping weigher",
+			"flavorClass", flavorClass)
+		return result, nil
+	}
+
+	hvs := &hv1.HypervisorList{}
+	if err := s.Client.List(context.Background(), hvs); err != nil {
+		traceLog.Error("failed to list hypervisors", "error", err)
+		return nil, err
+	}
+	hvsByName := make(map[string]hv1.Hypervisor, len(hvs.Items))
+	for _, hv := range hvs.Items {
+		hvsByName[hv.Name] = hv
+	}
+
+	for host := range result.Activations {
+		hv, ok := hvsByName[host]
+		if !ok {
+			traceLog.Info("host not found in hypervisor list, skipping", "host", host)
+			continue
+		}
+		count := len(hv.Status.Instances)
+		fill := min(float64(count)/float64(maxInstances), 1)
+		weight := -fill * fill
+		result.Activations[host] = weight
+		result.Statistics["instance count"].Hosts[host] = float64(count)
+		traceLog.Info("calculated instance count penalty for host",
+			"host", host, "count", count, "max", maxInstances, "weight", weight)
+	}
+	return result, nil
+}
+
+func init() {
+	Index["kvm_spread_by_instance_count"] = func() NovaWeigher { return &KVMSpreadByInstanceCountStep{} }
+}
@@ -0,0 +1,198 @@
+// Copyright SAP SE
+// SPDX-License-Identifier: Apache-2.0
+
+package weighers
+
+import (
+	"log/slog"
+	"math"
+	"strconv"
+	"testing"
+
+	api "github.com/cobaltcore-dev/cortex/api/external/nova"
+	hv1 "github.com/cobaltcore-dev/openstack-hypervisor-operator/api/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newHypervisorWithInstanceCount(name string, count int) *hv1.Hypervisor {
+	ids := make([]string, count)
+	for i := range ids {
+		ids[i] = name + "-instance-" + strconv.Itoa(i)
+	}
+	return newHypervisorWithInstances(name, ids...)
+}
+
+func newFlavorClassRequest(flavorClass string, hosts ...string) api.ExternalSchedulerRequest {
+	hostList := make([]api.ExternalSchedulerHost, len(hosts))
+	weights := make(map[string]float64, len(hosts))
+	for i, h := range hosts {
+		hostList[i] = api.ExternalSchedulerHost{ComputeHost: h}
+		weights[h] = 1.0
+	}
+	extraSpecs := map[string]string{}
+	if flavorClass != "" {
+		extraSpecs["hw_version"] = flavorClass
+	}
+	return api.ExternalSchedulerRequest{
+		Spec: api.NovaObject[api.NovaSpec]{Data: api.NovaSpec{
+			Flavor: api.NovaObject[api.NovaFlavor]{Data: api.NovaFlavor{
+				Name:       "m1.large",
+				ExtraSpecs: extraSpecs,
+			}},
+		}},
+		Hosts:   hostList,
+		Weights: weights,
+	}
+}
+
+func TestKVMSpreadByInstanceCountStepOpts_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    KVMSpreadByInstanceCountStepOpts
+		wantErr bool
+	}{
+		{
+			name:    "valid opts",
+			opts:    KVMSpreadByInstanceCountStepOpts{MaxInstancesPerFlavorClass: map[string]int{"v2": 10}},
+			wantErr: false,
+		},
+		{
+			name:    "no flavor classes",
+			opts:    KVMSpreadByInstanceCountStepOpts{},
+			wantErr: true,
+		},
+		{
+			name:    "zero max instances",
+			opts:    KVMSpreadByInstanceCountStepOpts{MaxInstancesPerFlavorClass: map[string]int{"v2": 0}},
+			wantErr: true,
+		},
+		{
+			name:    "negative max instances",
+			opts:    KVMSpreadByInstanceCountStepOpts{MaxInstancesPerFlavorClass: map[string]int{"v2": -1}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.opts.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("expected error: %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestKVMSpreadByInstanceCountStep_Run(t *testing.T) {
+	scheme := buildTestScheme(t)
+	opts := KVMSpreadByInstanceCountStepOpts{
+		MaxInstancesPerFlavorClass: map[string]int{"v2": 10},
+	}
+
+	tests := []struct {
+		name            string
+		hypervisors     []*hv1.Hypervisor
+		request         api.ExternalSchedulerRequest
+		expectedWeights map[string]float64
+	}{
+		{
+			name: "penalty grows towards the cap",
+			hypervisors: []*hv1.Hypervisor{
+				newHypervisorWithInstanceCount("host1", 0),
+				newHypervisorWithInstanceCount("host2", 5),
+				newHypervisorWithInstanceCount("host3", 9),
+			},
+			request: newFlavorClassRequest("v2", "host1", "host2", "host3"),
+			expectedWeights: map[string]float64{
+				"host1": 0,     // empty host
+				"host2": -0.25, // (5/10)^2
+				"host3": -0.81, // (9/10)^2
+			},
+		},
+		{
+			name: "full penalty at and over the cap",
+			hypervisors: []*hv1.Hypervisor{
+				newHypervisorWithInstanceCount("host1", 10),
+				newHypervisorWithInstanceCount("host2", 15),
+			},
+			request: newFlavorClassRequest("v2", "host1", "host2"),
+			expectedWeights: map[string]float64{
+				"host1": -1,
+				"host2": -1,
+			},
+		},
+		{
+			name: "unknown instance count - no activation",
+			hypervisors: []*hv1.Hypervisor{
+				newHypervisorWithInstanceCount("host1", 5),
+				// host2 hypervisor is missing
+			},
+			request: newFlavorClassRequest("v2", "host1", "host2"),
+			expectedWeights: map[string]float64{
+				"host1": -0.25,
+				"host2": 0,
+			},
+		},
+		{
+			name: "unconfigured flavor class - weigher skips",
+			hypervisors: []*hv1.Hypervisor{
+				newHypervisorWithInstanceCount("host1", 10),
+			},
+			request: newFlavorClassRequest("v1", "host1"),
+			expectedWeights: map[string]float64{
+				"host1": 0,
+			},
+		},
+		{
+			name: "no flavor class - weigher skips",
+			hypervisors: []*hv1.Hypervisor{
+				newHypervisorWithInstanceCount("host1", 10),
+			},
+			request: newFlavorClassRequest("", "host1"),
+			expectedWeights: map[string]float64{
+				"host1": 0,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			objects := make([]client.Object, 0, len(tt.hypervisors))
+			for _, hv := range tt.hypervisors {
+				objects = append(objects, hv)
+			}
+
+			step := &KVMSpreadByInstanceCountStep{}
+			step.Options = opts
+			step.Client = fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+
+			result, err := step.Run(slog.Default(), tt.request)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			for host, expectedWeight := range tt.expectedWeights {
+				actualWeight, ok := result.Activations[host]
+				if !ok {
+					t.Errorf("expected host %s to be in activations", host)
+					continue
+				}
+				if math.Abs(actualWeight-expectedWeight) > 1e-9 {
+					t.Errorf("for host %s, expected weight %.2f, got %.2f", host, expectedWeight, actualWeight)
+				}
+			}
+			if _, ok := result.Statistics["instance count"]; !ok {
+				t.Error("expected statistics to contain 'instance count'")
+			}
+		})
+	}
+}
+
+func TestKVMSpreadByInstanceCountStep_IndexRegistration(t *testing.T) {
+	factory, ok := Index["kvm_spread_by_instance_count"]
+	if !ok {
+		t.Fatal("kvm_spread_by_instance_count not found in Index")
+	}
+	if _, ok := factory().(*KVMSpreadByInstanceCountStep); !ok {
+		t.Fatalf("expected *KVMSpreadByInstanceCountStep, got %T", factory())
+	}
+}
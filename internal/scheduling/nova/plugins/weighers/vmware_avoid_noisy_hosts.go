@@ -0,0 +1,97 @@
+// Copyright SAP SE
+// SPDX-License-Identifier: Apache-2.0
+
+package weighers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	api "github.com/cobaltcore-dev/cortex/api/external/nova"
+	"github.com/cobaltcore-dev/cortex/api/v1alpha1"
+	"github.com/cobaltcore-dev/cortex/internal/knowledge/extractor/plugins/compute"
+	"github.com/cobaltcore-dev/cortex/internal/scheduling/lib"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Options for the scheduling step, given through the step config in the service yaml file.
+// Use the options contained in this struct to configure the bounds for min-max scaling.
+type VMwareAvoidNoisyHostsStepOpts struct {
+	MaxCPUOfVMsLowerBound float64 `json:"maxCPUOfVMsLowerBound"` // -> mapped to ActivationLowerBound
+	MaxCPUOfVMsUpperBound float64 `json:"maxCPUOfVMsUpperBound"` // -> mapped to ActivationUpperBound
+
+	MaxCPUOfVMsActivationLowerBound float64 `json:"maxCPUOfVMsActivationLowerBound"`
+	MaxCPUOfVMsActivationUpperBound float64 `json:"maxCPUOfVMsActivationUpperBound"`
+}
+
+func (o VMwareAvoidNoisyHostsStepOpts) Validate() error {
+	// Avoid zero-division during min-max scaling.
+	if o.MaxCPUOfVMsLowerBound == o.MaxCPUOfVMsUpperBound {
+		return errors.New("maxCPUOfVMsLowerBound and maxCPUOfVMsUpperBound must not be equal")
+	}
+	return nil
+}
+
+// Step to avoid hosts that currently run noisy VMs by downvoting them,
+// regardless of which project the noisy VMs belong to.
+type VMwareAvoidNoisyHostsStep struct {
+	// BaseStep is a helper struct that provides common functionality for all steps.
+	lib.BaseWeigher[api.ExternalSchedulerRequest, VMwareAvoidNoisyHostsStepOpts]
+}
+
+// Initialize the step and validate that all required knowledges are ready.
+func (s *VMwareAvoidNoisyHostsStep) Init(ctx context.Context, client client.Client, weigher v1alpha1.WeigherSpec) error {
+	if err := s.BaseWeigher.Init(ctx, client, weigher); err != nil {
+		return err
+	}
+	if err := s.CheckKnowledges(ctx, corev1.ObjectReference{Name: "vmware-host-noisiness"}); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Downvote hosts proportionally to the cpu usage of the noisiest VM on them.
+func (s *VMwareAvoidNoisyHostsStep) Run(traceLog *slog.Logger, request api.ExternalSchedulerRequest) (*lib.FilterWeigherPipelineStepResult, error) {
+	result := s.IncludeAllHostsFromRequest(request)
+
+	result.Statistics["avg cpu usage of vms"] = s.PrepareStats(request, "%")
+	result.Statistics["max cpu usage of vms"] = s.PrepareStats(request, "%")
+
+	knowledge := &v1alpha1.Knowledge{}
+	if err := s.Client.Get(
+		context.Background(),
+		client.ObjectKey{Name: "vmware-host-noisiness"},
+		knowledge,
+	); err != nil {
+		return nil, err
+	}
+	hostNoisiness, err := v1alpha1.
+		UnboxFeatureList[compute.VROpsHostNoisiness](knowledge.Status.Raw)
+	if err != nil {
+		return nil, err
+	}
+	for _, host := range hostNoisiness {
+		// Only modify the weight if the host is in the scenario.
+		if _, ok := result.Activations[host.ComputeHost]; !ok {
+			continue
+		}
+		result.Activations[host.ComputeHost] = lib.MinMaxScale(
+			host.MaxCPUOfVMs,
+			s.Options.MaxCPUOfVMsLowerBound,
+			s.Options.MaxCPUOfVMsUpperBound,
+			s.Options.MaxCPUOfVMsActivationLowerBound,
+			s.Options.MaxCPUOfVMsActivationUpperBound,
+		)
+		result.Statistics["avg cpu usage of vms"].Hosts[host.ComputeHost] = host.AvgCPUOfVMs
+		result.Statistics["max cpu usage of vms"].Hosts[host.ComputeHost] = host.MaxCPUOfVMs
+	}
+	return result, nil
+}
+
+func init() {
+	Index["vmware_avoid_noisy_hosts"] = func() lib.Weigher[api.ExternalSchedulerRequest] {
+		return &VMwareAvoidNoisyHostsStep{}
+	}
+}
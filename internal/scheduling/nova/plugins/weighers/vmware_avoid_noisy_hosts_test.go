@@ -0,0 +1,111 @@
+// Copyright SAP SE
+// SPDX-License-Identifier: Apache-2.0
+
+package weighers
+
+import (
+	"log/slog"
+	"testing"
+
+	api "github.com/cobaltcore-dev/cortex/api/external/nova"
+	"github.com/cobaltcore-dev/cortex/api/v1alpha1"
+	"github.com/cobaltcore-dev/cortex/internal/knowledge/extractor/plugins/compute"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestVMwareAvoidNoisyHostsStepOpts_Validate(t *testing.T) {
+	opts := VMwareAvoidNoisyHostsStepOpts{
+		MaxCPUOfVMsLowerBound: 50,
+		MaxCPUOfVMsUpperBound: 50,
+	}
+	if err := opts.Validate(); err == nil {
+		t.Error("expected error for equal bounds")
+	}
+	opts.MaxCPUOfVMsUpperBound = 100
+	if err := opts.Validate(); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
+
+func TestVMwareAvoidNoisyHostsStep_Run(t *testing.T) {
+	scheme, err := v1alpha1.SchemeBuilder.Build()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	hostNoisiness, err := v1alpha1.BoxFeatureList([]any{
+		&compute.VROpsHostNoisiness{ComputeHost: "host1", AvgCPUOfVMs: 10, MaxCPUOfVMs: 20},
+		&compute.VROpsHostNoisiness{ComputeHost: "host2", AvgCPUOfVMs: 30, MaxCPUOfVMs: 75},
+		&compute.VROpsHostNoisiness{ComputeHost: "host3", AvgCPUOfVMs: 60, MaxCPUOfVMs: 100},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	step := &VMwareAvoidNoisyHostsStep{}
+	step.Options.MaxCPUOfVMsLowerBound = 50
+	step.Options.MaxCPUOfVMsUpperBound = 100
+	step.Options.MaxCPUOfVMsActivationLowerBound = 0.0
+	step.Options.MaxCPUOfVMsActivationUpperBound = -1.0
+	step.Client = fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(&v1alpha1.Knowledge{
+			ObjectMeta: metav1.ObjectMeta{Name: "vmware-host-noisiness"},
+			Status:     v1alpha1.KnowledgeStatus{Raw: hostNoisiness},
+		}).
+		Build()
+
+	tests := []struct {
+		name     string
+		request  api.ExternalSchedulerRequest
+		expected map[string]float64
+	}{
+		{
+			name: "Avoid hosts with noisy vms",
+			request: api.ExternalSchedulerRequest{
+				Hosts: []api.ExternalSchedulerHost{
+					{ComputeHost: "host1"},
+					{ComputeHost: "host2"},
+					{ComputeHost: "host3"},
+				},
+			},
+			expected: map[string]float64{
+				"host1": 0, // Below the lower bound.
+				"host2": -0.5,
+				"host3": -1,
+			},
+		},
+		{
+			name: "Missing data",
+			request: api.ExternalSchedulerRequest{
+				Hosts: []api.ExternalSchedulerHost{
+					{ComputeHost: "host3"},
+					{ComputeHost: "host4"}, // No data for host4
+				},
+			},
+			expected: map[string]float64{
+				"host3": -1,
+				"host4": 0, // No data but still contained in the result.
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := step.Run(slog.Default(), tt.request)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if len(result.Activations) != len(tt.expected) {
+				t.Fatalf("expected %d activations, got %d", len(tt.expected), len(result.Activations))
+			}
+			for host, weight := range result.Activations {
+				expected := tt.expected[host]
+				if diff := weight - expected; diff > 1e-9 || diff < -1e-9 {
+					t.Errorf("expected weight for host %s to be %f, got %f", host, expected, weight)
+				}
+			}
+		})
+	}
+}
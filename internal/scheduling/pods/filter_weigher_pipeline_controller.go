@@ -42,6 +42,8 @@ type FilterWeigherPipelineController struct {
 	// Mutex to only allow one process at a time
 	processMu sync.Mutex
 
+	// Configuration of the History CRDs written after each decision.
+	HistoryConfig lib.HistoryConfig
 	// Monitor to pass down to all pipelines.
 	Monitor lib.FilterWeigherPipelineMonitor
 }
@@ -110,6 +112,7 @@ func (c *FilterWeigherPipelineController) ProcessNewPod(ctx context.Context, pod
 			Message: "pipeline run succeeded",
 		})
 	}
+	c.Monitor.ObserveDecision(c.PipelineLabel(decision.Spec.PipelineRef.Name), err)
 	return err
 }
 
@@ -117,10 +120,10 @@ func (c *FilterWeigherPipelineController) process(ctx context.Context, decision
 	log := ctrl.LoggerFrom(ctx)
 	startedAt := time.Now() // So we can measure sync duration.
 
-	pipeline, ok := c.Pipelines[decision.Spec.PipelineRef.Name]
+	pipeline, ok := c.GetPipeline(decision.Spec.PipelineRef.Name)
 	if !ok {
 		log.Error(nil, "pipeline not found or not ready", "pipelineName", decision.Spec.PipelineRef.Name)
-		return errors.New("pipeline not found or not ready")
+		return &lib.PipelineNotReadyError{Pipeline: decision.Spec.PipelineRef.Name}
 	}
 
 	// Check if the pod is already assigned to a node.
@@ -148,12 +151,15 @@ func (c *FilterWeigherPipelineController) process(ctx context.Context, decision
 
 	// Execute the scheduling pipeline. Options not set: pod scheduling always records history.
 	request := pods.PodPipelineRequest{Nodes: nodes.Items, Pod: *pod}
-	result, err := pipeline.Run(request)
+	result, err := pipeline.Run(ctx, request)
 	if !request.Options.SkipHistory {
 		if upsertErr := c.HistoryManager.CreateOrUpdateHistory(ctx, decision, nil, err); upsertErr != nil {
 			log.Error(upsertErr, "failed to create/update history")
 		}
 	}
+	if conf, _ := c.GetPipelineConfig(decision.Spec.PipelineRef.Name); conf.Spec.Audit {
+		lib.AuditDecision(decision, request, &result, err)
+	}
 	if err != nil {
 		log.V(1).Error(err, "failed to run scheduler pipeline")
 		return errors.New("failed to run scheduler pipeline")
@@ -190,6 +196,9 @@ func (c *FilterWeigherPipelineController) InitPipeline(
 		ctx, c.Client, p.Name,
 		filters.Index, p.Spec.Filters,
 		weighers.Index, p.Spec.Weighers,
+		p.Spec.TieBreaker,
+		p.Spec.InputWeightNormalization,
+		p.Spec.ParallelFilters,
 		c.Monitor,
 	)
 }
@@ -228,8 +237,16 @@ func (c *FilterWeigherPipelineController) handlePod() handler.EventHandler {
 
 func (c *FilterWeigherPipelineController) SetupWithManager(mgr manager.Manager, mcl *multicluster.Client) error {
 	c.Initializer = c
+	c.ObserveReadyPipelines = c.Monitor.ObserveReadyPipelines
 	c.SchedulingDomain = v1alpha1.SchedulingDomainPods
-	c.HistoryManager = lib.HistoryClient{Client: mcl, Recorder: mcl.GetEventRecorder("cortex-pods-scheduler")}
+	certainty := c.HistoryConfig.CertaintyThresholdsFor(v1alpha1.SchedulingDomainPods)
+	c.HistoryManager = lib.HistoryClient{
+		Client:           mcl,
+		Recorder:         mcl.GetEventRecorder("cortex-pods-scheduler"),
+		Certainty:        &certainty,
+		Verbosity:        c.HistoryConfig.ExplanationVerbosity,
+		MaxHistoryLength: c.HistoryConfig.MaxHistoryLength,
+	}
 	if err := mgr.Add(manager.RunnableFunc(c.InitAllPipelines)); err != nil {
 		return err
 	}
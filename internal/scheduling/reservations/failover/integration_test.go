@@ -1188,6 +1188,9 @@ func newIntegrationTestEnv(t *testing.T, vms []reservations.VM, hypervisors []*h
 			ctx, k8sClient, pipeline.Name,
 			filters.Index, pipeline.Spec.Filters,
 			weighers.Index, pipeline.Spec.Weighers,
+			pipeline.Spec.TieBreaker,
+			pipeline.Spec.InputWeightNormalization,
+			pipeline.Spec.ParallelFilters,
 			novaController.Monitor,
 		)
 		if len(result.FilterErrors) > 0 || len(result.WeigherErrors) > 0 {
@@ -1378,6 +1381,9 @@ func newIntegrationTestEnvWithTraitsFilter(t *testing.T, vms []reservations.VM,
 			ctx, k8sClient, pipeline.Name,
 			filters.Index, pipeline.Spec.Filters,
 			weighers.Index, pipeline.Spec.Weighers,
+			pipeline.Spec.TieBreaker,
+			pipeline.Spec.InputWeightNormalization,
+			pipeline.Spec.ParallelFilters,
 			novaController.Monitor,
 		)
 		if len(result.FilterErrors) > 0 || len(result.WeigherErrors) > 0 {
@@ -6,7 +6,10 @@ package conf
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"reflect"
+	"strings"
 
 	"github.com/cobaltcore-dev/cortex/api/v1alpha1"
 )
@@ -88,7 +91,53 @@ func UnmarshalParams(p *v1alpha1.Parameters, into any) error {
 	// Disallow unknown fields to catch typos and invalid parameters.
 	decoder.DisallowUnknownFields()
 	if err := decoder.Decode(into); err != nil {
-		return fmt.Errorf("failed to decode parameters into struct: %w", err)
+		return fmt.Errorf("failed to decode parameters into struct: %w", describeDecodeError(err))
 	}
 	return nil
 }
+
+// Translate errors of the json decoder into errors that refer to the
+// parameters, so that misconfigured pipeline steps can be fixed directly.
+func describeDecodeError(err error) error {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return fmt.Errorf("parameter %s must be set as %s, got a %s value: %w",
+			typeErr.Field, paramValueKind(typeErr.Type), typeErr.Value, err)
+	}
+	// The json package doesn't export a dedicated error type for this case.
+	if field, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+		return fmt.Errorf("unknown parameter %s: %w", field, err)
+	}
+	return err
+}
+
+// Get the parameter value field expected for the given go type.
+func paramValueKind(t reflect.Type) string {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return "stringValue"
+	case reflect.Bool:
+		return "boolValue"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "intValue"
+	case reflect.Float32, reflect.Float64:
+		return "floatValue"
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.String {
+			return "stringListValue"
+		}
+		return t.String()
+	case reflect.Map:
+		elem := t.Elem().Kind()
+		if t.Key().Kind() == reflect.String && (elem == reflect.Float32 || elem == reflect.Float64) {
+			return "floatMapValue"
+		}
+		return t.String()
+	default:
+		return t.String()
+	}
+}
@@ -4,6 +4,7 @@
 package conf
 
 import (
+	"reflect"
 	"strings"
 	"testing"
 
@@ -491,3 +492,67 @@ func TestUnmarshalParams_UnicodeInString(t *testing.T) {
 		t.Errorf("expected unicode preserved, got '%s'", result.Name)
 	}
 }
+
+func TestUnmarshalParams_TypeMismatchNamesParameter(t *testing.T) {
+	type TestStruct struct {
+		Hosts []string `json:"hosts"`
+	}
+
+	params := v1alpha1.Parameters{
+		{Key: "hosts", StringValue: new("host1")},
+	}
+
+	var result TestStruct
+	err := UnmarshalParams(&params, &result)
+
+	if err == nil {
+		t.Fatal("expected error for type mismatch, got nil")
+	}
+	if !strings.Contains(err.Error(), "parameter hosts must be set as stringListValue, got a string value") {
+		t.Errorf("expected error naming the parameter and expected value, got: %v", err)
+	}
+}
+
+func TestParamValueKind(t *testing.T) {
+	type name string
+	tests := []struct {
+		value    any
+		expected string
+	}{
+		{"", "stringValue"},
+		{new(true), "boolValue"},
+		{int64(0), "intValue"},
+		{0.0, "floatValue"},
+		{[]string{}, "stringListValue"},
+		{[]name{}, "stringListValue"},
+		{map[string]float64{}, "floatMapValue"},
+		{[]int{}, "[]int"},
+		{map[string]string{}, "map[string]string"},
+		{map[int]float64{}, "map[int]float64"},
+	}
+	for _, tt := range tests {
+		if got := paramValueKind(reflect.TypeOf(tt.value)); got != tt.expected {
+			t.Errorf("expected %s for %T, got %s", tt.expected, tt.value, got)
+		}
+	}
+}
+
+func TestUnmarshalParams_UnknownParameter(t *testing.T) {
+	type TestStruct struct {
+		Name string `json:"name"`
+	}
+
+	params := v1alpha1.Parameters{
+		{Key: "nmae", StringValue: new("typo")},
+	}
+
+	var result TestStruct
+	err := UnmarshalParams(&params, &result)
+
+	if err == nil {
+		t.Fatal("expected error for unknown parameter, got nil")
+	}
+	if !strings.Contains(err.Error(), `unknown parameter "nmae"`) {
+		t.Errorf("expected error naming the unknown parameter, got: %v", err)
+	}
+}
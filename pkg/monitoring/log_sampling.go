@@ -0,0 +1,191 @@
+// Copyright SAP SE
+// SPDX-License-Identifier: Apache-2.0
+
+package monitoring
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type LoggingConfig struct {
+	// Logging configuration
+	Logging LogConfig `json:"logging"`
+}
+
+// Configuration for the slog logger.
+type LogConfig struct {
+	// Optional sampling of repeated log messages. If unset, all
+	// messages are logged.
+	Sampling *LogSamplingConfig `json:"sampling,omitempty"`
+}
+
+// Configuration for sampling repeated log messages.
+type LogSamplingConfig struct {
+	// Number of records logged per message and level within each interval.
+	// Further records with the same message and level are dropped until
+	// the next interval starts. Defaults to DefaultLogSamplingFirst if not
+	// positive, since dropping all records would silence the logs.
+	First int `json:"first"`
+	// Length of the sampling interval. Defaults to 1s.
+	Interval metav1.Duration `json:"interval,omitempty"`
+}
+
+// Number of records logged per message, level and interval if the
+// configured number is not positive.
+const DefaultLogSamplingFirst = 10
+
+// Sampling state shared by all handlers derived from the same root handler,
+// so that sampling applies regardless of the attributes added to a logger.
+type logSampler struct {
+	first    int
+	interval time.Duration
+	// Function returning the current time, can be overridden for tests.
+	now func() time.Time
+	// Handler the summary of dropped records is written to.
+	root slog.Handler
+
+	mu          sync.Mutex
+	windowStart time.Time
+	counts      map[logSampleKey]int
+	dropped     int
+
+	// Closed to stop reporting the dropped records.
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+type logSampleKey struct {
+	level slog.Level
+	msg   string
+}
+
+// Context key marking records that must never be sampled.
+type unsampledKey struct{}
+
+// Mark records logged with the returned context as exempt from sampling,
+// e.g. audit records which must not be lost.
+func WithoutSampling(ctx context.Context) context.Context {
+	return context.WithValue(ctx, unsampledKey{}, true)
+}
+
+// SamplingSlogHandler wraps an slog.Handler and logs only the first n records
+// of each message and level per interval. Error records and records logged
+// with a context from WithoutSampling are never dropped.
+// After each interval with dropped records, a summary with the number of
+// dropped records is logged, until the handler is stopped.
+type SamplingSlogHandler struct {
+	sampler *logSampler
+	next    slog.Handler
+}
+
+// NewSamplingSlogHandler returns a new handler that samples repeated
+// records and delegates the remaining calls to next. The summary of dropped
+// records is reported in the background once per interval.
+func NewSamplingSlogHandler(config LogSamplingConfig, next slog.Handler) *SamplingSlogHandler {
+	first := config.First
+	if first <= 0 {
+		first = DefaultLogSamplingFirst
+	}
+	interval := config.Interval.Duration
+	if interval <= 0 {
+		interval = time.Second
+	}
+	sampler := &logSampler{
+		first:    first,
+		interval: interval,
+		now:      time.Now,
+		root:     next,
+		counts:   make(map[logSampleKey]int),
+		stop:     make(chan struct{}),
+	}
+	go sampler.reportDropped()
+	return &SamplingSlogHandler{sampler: sampler, next: next}
+}
+
+// Stop reporting the dropped records in the background, and report the
+// records dropped since the last summary.
+func (h *SamplingSlogHandler) Stop() {
+	h.sampler.stopOnce.Do(func() {
+		close(h.sampler.stop)
+		h.sampler.flush()
+	})
+}
+
+func (h *SamplingSlogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *SamplingSlogHandler) Handle(ctx context.Context, r slog.Record) error {
+	if r.Level >= slog.LevelError {
+		return h.next.Handle(ctx, r)
+	}
+	if unsampled, _ := ctx.Value(unsampledKey{}).(bool); unsampled {
+		return h.next.Handle(ctx, r)
+	}
+	if !h.sampler.sample(logSampleKey{level: r.Level, msg: r.Message}) {
+		return nil
+	}
+	return h.next.Handle(ctx, r)
+}
+
+// Count the record with the given key and report if it should be logged.
+func (s *logSampler) sample(key logSampleKey) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	if now.Sub(s.windowStart) >= s.interval {
+		s.windowStart = now
+		clear(s.counts)
+	}
+	s.counts[key]++
+	if s.counts[key] > s.first {
+		s.dropped++
+		return false
+	}
+	return true
+}
+
+// Log a summary of the dropped records once per interval, until stopped.
+func (s *logSampler) reportDropped() {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			s.flush()
+		}
+	}
+}
+
+// Log a summary of the records dropped since the last summary, if any.
+func (s *logSampler) flush() {
+	s.mu.Lock()
+	dropped := s.dropped
+	s.dropped = 0
+	s.mu.Unlock()
+	if dropped == 0 {
+		return
+	}
+	summary := slog.NewRecord(s.now(), slog.LevelWarn, "dropped repeated log messages", 0)
+	summary.AddAttrs(
+		slog.Int("dropped", dropped),
+		slog.Duration("interval", s.interval),
+	)
+	// A failing handler can't report its own error, so it is ignored.
+	_ = s.root.Handle(context.Background(), summary)
+}
+
+func (h *SamplingSlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &SamplingSlogHandler{sampler: h.sampler, next: h.next.WithAttrs(attrs)}
+}
+
+func (h *SamplingSlogHandler) WithGroup(name string) slog.Handler {
+	return &SamplingSlogHandler{sampler: h.sampler, next: h.next.WithGroup(name)}
+}
@@ -0,0 +1,172 @@
+// Copyright SAP SE
+// SPDX-License-Identifier: Apache-2.0
+
+package monitoring
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// parseRecords splits the JSON log output into records.
+func parseRecords(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var records []map[string]any
+	for line := range strings.SplitSeq(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var record map[string]any
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("failed to parse log line %q: %v", line, err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestSamplingSlogHandler(t *testing.T) {
+	var buf bytes.Buffer
+	handler := NewSamplingSlogHandler(
+		LogSamplingConfig{First: 2, Interval: metav1.Duration{Duration: time.Minute}},
+		slog.NewJSONHandler(&buf, nil),
+	)
+	now := time.Now()
+	handler.sampler.now = func() time.Time { return now }
+	// Attributes must not escape the sampling of the message.
+	logger := slog.New(handler).With("request", "req-1")
+
+	for range 5 {
+		logger.Info("scheduling request")
+	}
+	logger.Info("other message")
+	for range 3 {
+		logger.Error("scheduling failed")
+	}
+	records := parseRecords(t, &buf)
+	counts := map[string]int{}
+	for _, record := range records {
+		counts[record["msg"].(string)]++
+	}
+	if counts["scheduling request"] != 2 {
+		t.Errorf("expected 2 sampled records, got %d", counts["scheduling request"])
+	}
+	if counts["other message"] != 1 {
+		t.Errorf("expected 1 record of other message, got %d", counts["other message"])
+	}
+	if counts["scheduling failed"] != 3 {
+		t.Errorf("expected all error records, got %d", counts["scheduling failed"])
+	}
+
+	// The end of the interval logs a summary of the dropped records.
+	buf.Reset()
+	handler.sampler.flush()
+	records = parseRecords(t, &buf)
+	if len(records) != 1 || records[0]["msg"] != "dropped repeated log messages" || records[0]["dropped"] != float64(3) {
+		t.Fatalf("expected summary of 3 dropped records, got %v", records)
+	}
+	// The summary is only logged once.
+	buf.Reset()
+	handler.sampler.flush()
+	if records = parseRecords(t, &buf); len(records) != 0 {
+		t.Fatalf("expected no further summary, got %v", records)
+	}
+
+	// The next interval logs the message again.
+	now = now.Add(time.Minute)
+	logger.Info("scheduling request")
+	records = parseRecords(t, &buf)
+	if len(records) != 1 || records[0]["msg"] != "scheduling request" {
+		t.Errorf("expected record in the next interval, got %v", records)
+	}
+	handler.Stop()
+}
+
+// syncBuffer is a bytes.Buffer safe for concurrent use, since the summary
+// of dropped records is written in the background.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) Contains(s string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return strings.Contains(b.buf.String(), s)
+}
+
+func TestSamplingSlogHandler_ReportsDroppedPeriodically(t *testing.T) {
+	var buf syncBuffer
+	handler := NewSamplingSlogHandler(
+		LogSamplingConfig{First: 1, Interval: metav1.Duration{Duration: 10 * time.Millisecond}},
+		slog.NewJSONHandler(&buf, nil),
+	)
+	defer handler.Stop()
+	logger := slog.New(handler)
+	for range 3 {
+		logger.Info("scheduling request")
+	}
+	// The summary is logged without any further record.
+	deadline := time.Now().Add(5 * time.Second)
+	for !buf.Contains("dropped repeated log messages") {
+		if time.Now().After(deadline) {
+			t.Fatal("expected summary of dropped records to be logged periodically")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestSamplingSlogHandler_DefaultFirst(t *testing.T) {
+	var buf bytes.Buffer
+	handler := NewSamplingSlogHandler(
+		LogSamplingConfig{First: 0, Interval: metav1.Duration{Duration: time.Hour}},
+		slog.NewJSONHandler(&buf, nil),
+	)
+	defer handler.Stop()
+	logger := slog.New(handler)
+	for range DefaultLogSamplingFirst + 1 {
+		logger.Info("scheduling request")
+	}
+	if records := parseRecords(t, &buf); len(records) != DefaultLogSamplingFirst {
+		t.Errorf("expected %d records with the default, got %d", DefaultLogSamplingFirst, len(records))
+	}
+}
+
+func TestSamplingSlogHandler_WithoutSampling(t *testing.T) {
+	var buf bytes.Buffer
+	handler := NewSamplingSlogHandler(
+		LogSamplingConfig{First: 1, Interval: metav1.Duration{Duration: time.Minute}},
+		slog.NewJSONHandler(&buf, nil),
+	)
+	now := time.Now()
+	handler.sampler.now = func() time.Time { return now }
+	logger := slog.New(handler)
+
+	ctx := WithoutSampling(context.Background())
+	for range 5 {
+		logger.InfoContext(ctx, "audit record")
+	}
+	records := parseRecords(t, &buf)
+	if len(records) != 5 {
+		t.Errorf("expected all unsampled records, got %d", len(records))
+	}
+	// Unsampled records don't count towards the sampling of the message.
+	logger.Info("audit record")
+	if records = parseRecords(t, &buf); len(records) != 6 {
+		t.Errorf("expected the first sampled record to be logged, got %d", len(records))
+	}
+}
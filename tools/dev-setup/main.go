@@ -0,0 +1,295 @@
+// Copyright SAP SE
+// SPDX-License-Identifier: Apache-2.0
+
+// Tool to seed a local development cluster with a small, realistic set of
+// nova scheduling data, so that the external scheduler API can be exercised
+// without a connection to a real OpenStack region.
+//
+// Usage:
+//
+//	go run tools/dev-setup/main.go [flags]
+//
+// Flags:
+//
+//	--hosts=n           Number of fake hypervisors to create (default: 4)
+//	--az=name           Availability zone label of the fake hypervisors (default: qa-de-1a)
+//	--pipeline=name     Name of the seeded nova pipeline (default: dev-kvm)
+//	--cleanup           Delete the seeded resources instead of creating them
+//
+// The tool talks to the cluster of the current kubeconfig context, which
+// should be the local cluster started with tilt. Besides the hypervisors, it
+// seeds a nova pipeline and the knowledges its steps depend on, with features
+// filled in directly, so no datasource, database, or migration is needed. The
+// seeded data is enough to run filter_has_enough_capacity,
+// kvm_prefer_smaller_hosts, and kvm_avoid_hot_cooling_zones, for example:
+//
+//	curl -X POST localhost:8001/scheduler/nova/external -d @request.json
+//
+// Note: this tool is meant for local development only. Never point it to a
+// production cluster.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/cobaltcore-dev/cortex/api/v1alpha1"
+	"github.com/cobaltcore-dev/cortex/internal/knowledge/extractor/plugins/compute"
+	hv1 "github.com/cobaltcore-dev/openstack-hypervisor-operator/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+)
+
+var scheme = runtime.NewScheme()
+
+func init() {
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(v1alpha1.AddToScheme(scheme))
+	utilruntime.Must(hv1.AddToScheme(scheme))
+}
+
+// Label set on all seeded resources so they can be found again for cleanup.
+const seededLabel = "cortex.cloud/dev-setup"
+
+// Sizes of the fake hypervisors, cycled through when creating hosts so that
+// capacity filtering and the smaller-host preference have something to do.
+var hostSizes = []struct {
+	cpus, memory, cpusUsed, memoryUsed string
+}{
+	{cpus: "64", memory: "512Gi", cpusUsed: "16", memoryUsed: "128Gi"},
+	{cpus: "128", memory: "1Ti", cpusUsed: "96", memoryUsed: "768Gi"},
+	{cpus: "96", memory: "768Gi", cpusUsed: "0", memoryUsed: "0"},
+	{cpus: "32", memory: "256Gi", cpusUsed: "30", memoryUsed: "250Gi"},
+}
+
+// Load of the cooling zones the fake hypervisors are spread across, so that
+// the hot cooling zone is avoided by the seeded pipeline.
+var coolingZoneLoads = []struct {
+	zone    string
+	loadPct float64
+}{
+	{zone: "dev-cooling-zone-a", loadPct: 35},
+	{zone: "dev-cooling-zone-b", loadPct: 90},
+}
+
+func main() {
+	hosts := flag.Int("hosts", 4, "number of fake hypervisors to create")
+	az := flag.String("az", "qa-de-1a", "availability zone of the fake hypervisors")
+	pipelineName := flag.String("pipeline", "dev-kvm", "name of the seeded nova pipeline")
+	cleanup := flag.Bool("cleanup", false, "delete the seeded resources instead of creating them")
+	flag.Parse()
+
+	ctx := context.Background()
+	restConfig, err := config.GetConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to load kubeconfig: %v\n", err)
+		os.Exit(1)
+	}
+	cl, err := client.New(restConfig, client.Options{Scheme: scheme})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to create kubernetes client: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *cleanup {
+		if err := deleteSeeded(ctx, cl); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to clean up: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println("deleted all seeded resources")
+		return
+	}
+	for i := range *hosts {
+		name := fmt.Sprintf("dev-compute-%d", i)
+		if err := seedHypervisor(ctx, cl, name, *az, i); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to seed hypervisor %s: %v\n", name, err)
+			os.Exit(1)
+		}
+		fmt.Printf("seeded hypervisor %s\n", name)
+	}
+	// Knowledges must be seeded before the pipeline, so that the pipeline
+	// is ready as soon as the scheduler initializes it.
+	if err := seedCoolingZoneLoad(ctx, cl, *hosts); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to seed knowledge %s: %v\n", coolingZoneLoadKnowledge, err)
+		os.Exit(1)
+	}
+	fmt.Printf("seeded knowledge %s\n", coolingZoneLoadKnowledge)
+	if err := seedPipeline(ctx, cl, *pipelineName); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to seed pipeline %s: %v\n", *pipelineName, err)
+		os.Exit(1)
+	}
+	fmt.Printf("seeded pipeline %s\n", *pipelineName)
+}
+
+// Create the hypervisor if it doesn't exist and set its status, which
+// cannot be set on creation since it is a subresource.
+func seedHypervisor(ctx context.Context, cl client.Client, name, az string, i int) error {
+	size := hostSizes[i%len(hostSizes)]
+	hv := &hv1.Hypervisor{}
+	err := cl.Get(ctx, client.ObjectKey{Name: name}, hv)
+	switch {
+	case apierrors.IsNotFound(err):
+		hv = &hv1.Hypervisor{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+				Labels: map[string]string{
+					seededLabel:              "true",
+					corev1.LabelTopologyZone: az,
+				},
+			},
+		}
+		if err := cl.Create(ctx, hv); err != nil {
+			return err
+		}
+	case err != nil:
+		return err
+	}
+	hv.Status.Capacity = map[hv1.ResourceName]resource.Quantity{
+		hv1.ResourceCPU:    resource.MustParse(size.cpus),
+		hv1.ResourceMemory: resource.MustParse(size.memory),
+	}
+	hv.Status.EffectiveCapacity = hv.Status.Capacity
+	hv.Status.Allocation = map[hv1.ResourceName]resource.Quantity{
+		hv1.ResourceCPU:    resource.MustParse(size.cpusUsed),
+		hv1.ResourceMemory: resource.MustParse(size.memoryUsed),
+	}
+	meta.SetStatusCondition(&hv.Status.Conditions, metav1.Condition{
+		Type:    hv1.ConditionTypeReady,
+		Status:  metav1.ConditionTrue,
+		Reason:  "DevSetup",
+		Message: "seeded by the dev-setup tool",
+	})
+	return cl.Status().Update(ctx, hv)
+}
+
+// Create or update a minimal nova pipeline using the seeded hypervisors.
+func seedPipeline(ctx context.Context, cl client.Client, name string) error {
+	weights := map[string]float64{"cpu": 1, "memory": 1}
+	spec := v1alpha1.PipelineSpec{
+		SchedulingDomain: v1alpha1.SchedulingDomainNova,
+		Description:      "Local development pipeline seeded by the dev-setup tool.",
+		Type:             v1alpha1.PipelineTypeFilterWeigher,
+		Filters: []v1alpha1.FilterSpec{
+			{Name: "filter_has_enough_capacity"},
+		},
+		Weighers: []v1alpha1.WeigherSpec{
+			{
+				Name:   "kvm_prefer_smaller_hosts",
+				Params: v1alpha1.Parameters{{Key: "resourceWeights", FloatMapValue: &weights}},
+			},
+			{
+				Name: "kvm_avoid_hot_cooling_zones",
+				Params: v1alpha1.Parameters{
+					{Key: "coolingZoneLoadLowerBound", FloatValue: new(0.0)},
+					{Key: "coolingZoneLoadUpperBound", FloatValue: new(100.0)},
+					{Key: "coolingZoneLoadActivationLowerBound", FloatValue: new(0.0)},
+					{Key: "coolingZoneLoadActivationUpperBound", FloatValue: new(-1.0)},
+				},
+			},
+		},
+	}
+	pipeline := &v1alpha1.Pipeline{}
+	err := cl.Get(ctx, client.ObjectKey{Name: name}, pipeline)
+	switch {
+	case apierrors.IsNotFound(err):
+		pipeline = &v1alpha1.Pipeline{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   name,
+				Labels: map[string]string{seededLabel: "true"},
+			},
+			Spec: spec,
+		}
+		return cl.Create(ctx, pipeline)
+	case err != nil:
+		return err
+	}
+	pipeline.Spec = spec
+	return cl.Update(ctx, pipeline)
+}
+
+// Name of the knowledge kvm_avoid_hot_cooling_zones depends on.
+const coolingZoneLoadKnowledge = "cooling-zone-load"
+
+// Create or update the cooling zone load knowledge of the seeded hypervisors.
+//
+// The features are written into the status directly. The knowledge is marked
+// as just extracted with a long recency, so that the extractor leaves it
+// alone instead of trying to extract it from a datasource that doesn't exist.
+func seedCoolingZoneLoad(ctx context.Context, cl client.Client, hosts int) error {
+	features := make([]compute.CoolingZoneLoad, 0, hosts)
+	for i := range hosts {
+		load := coolingZoneLoads[i%len(coolingZoneLoads)]
+		features = append(features, compute.CoolingZoneLoad{
+			ComputeHost: fmt.Sprintf("dev-compute-%d", i),
+			CoolingZone: load.zone,
+			LoadPct:     load.loadPct,
+		})
+	}
+	raw, err := v1alpha1.BoxFeatureList(features)
+	if err != nil {
+		return err
+	}
+	spec := v1alpha1.KnowledgeSpec{
+		SchedulingDomain: v1alpha1.SchedulingDomainNova,
+		Extractor:        v1alpha1.KnowledgeExtractorSpec{Name: "cooling_zone_load_extractor"},
+		Recency:          metav1.Duration{Duration: 365 * 24 * time.Hour},
+		Description:      "Cooling zone load of the fake hypervisors seeded by the dev-setup tool.",
+	}
+	knowledge := &v1alpha1.Knowledge{}
+	err = cl.Get(ctx, client.ObjectKey{Name: coolingZoneLoadKnowledge}, knowledge)
+	switch {
+	case apierrors.IsNotFound(err):
+		knowledge = &v1alpha1.Knowledge{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   coolingZoneLoadKnowledge,
+				Labels: map[string]string{seededLabel: "true"},
+			},
+			Spec: spec,
+		}
+		if err := cl.Create(ctx, knowledge); err != nil {
+			return err
+		}
+	case err != nil:
+		return err
+	default:
+		knowledge.Spec = spec
+		if err := cl.Update(ctx, knowledge); err != nil {
+			return err
+		}
+	}
+	now := metav1.Now()
+	knowledge.Status.Raw = raw
+	knowledge.Status.RawLength = len(features)
+	knowledge.Status.LastExtracted = now
+	knowledge.Status.LastContentChange = now
+	meta.SetStatusCondition(&knowledge.Status.Conditions, metav1.Condition{
+		Type:    v1alpha1.KnowledgeConditionReady,
+		Status:  metav1.ConditionTrue,
+		Reason:  "DevSetup",
+		Message: "seeded by the dev-setup tool",
+	})
+	return cl.Status().Update(ctx, knowledge)
+}
+
+// Delete all hypervisors, knowledges, and pipelines created by this tool.
+func deleteSeeded(ctx context.Context, cl client.Client) error {
+	selector := client.MatchingLabels{seededLabel: "true"}
+	if err := cl.DeleteAllOf(ctx, &hv1.Hypervisor{}, selector); err != nil {
+		return err
+	}
+	if err := cl.DeleteAllOf(ctx, &v1alpha1.Knowledge{}, selector); err != nil {
+		return err
+	}
+	return cl.DeleteAllOf(ctx, &v1alpha1.Pipeline{}, selector)
+}
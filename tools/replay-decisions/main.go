@@ -0,0 +1,92 @@
+// Copyright SAP SE
+// SPDX-License-Identifier: Apache-2.0
+
+// Recompute the output weights and winners of stored scheduling decisions
+// from their input weights and step activations, and report the decisions
+// whose stored result differs from the recomputed one. This catches
+// unintended changes to the scoring math, e.g. in CI after a refactoring.
+//
+// The decisions are read from a JSON file, either as a list of decisions
+// or as a list object with items, such as `kubectl get decisions -o json`.
+// Nothing is read from or written to a cluster.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/cobaltcore-dev/cortex/api/v1alpha1"
+	"github.com/cobaltcore-dev/cortex/internal/scheduling/lib"
+)
+
+func readDecisions(path string) ([]v1alpha1.Decision, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var decisions []v1alpha1.Decision
+	if err := json.Unmarshal(data, &decisions); err == nil {
+		return decisions, nil
+	}
+	var list v1alpha1.DecisionList
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("parsing decisions: %w", err)
+	}
+	return list.Items, nil
+}
+
+func fatal(format string, args ...any) {
+	fmt.Fprintf(os.Stderr, "error: "+format+"\n", args...)
+	os.Exit(1)
+}
+
+func main() {
+	decisionsPath := flag.String("decisions", "", "Path to a JSON file with the stored decisions to replay")
+	flag.Parse()
+	if *decisionsPath == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	decisions, err := readDecisions(*decisionsPath)
+	if err != nil {
+		fatal("failed to read decisions: %v", err)
+	}
+	replayed, skipped, changed := 0, 0, 0
+	for _, decision := range decisions {
+		stored := decision.Status.Result
+		if stored == nil {
+			skipped++
+			continue
+		}
+		result, err := lib.ReplayDecisionResult(stored)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "warning: decision %s can't be replayed: %v\n", decision.Name, err)
+			skipped++
+			continue
+		}
+		replayed++
+		diffs := lib.DiffDecisionResults(stored, result)
+		if len(diffs) == 0 {
+			continue
+		}
+		changed++
+		before, after := "<none>", "<none>"
+		if stored.TargetHost != nil {
+			before = *stored.TargetHost
+		}
+		if result.TargetHost != nil {
+			after = *result.TargetHost
+		}
+		fmt.Printf("%s: winner %s -> %s\n", decision.Name, before, after)
+		for _, diff := range diffs {
+			fmt.Printf("  - %s\n", diff)
+		}
+	}
+	fmt.Printf("Replayed %d decisions (%d skipped without result), %d differ from the stored result.\n", replayed, skipped, changed)
+	if changed > 0 {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,365 @@
+// Copyright SAP SE
+// SPDX-License-Identifier: Apache-2.0
+
+// Replay recorded nova scheduling requests through a pipeline and report
+// which hosts won, to evaluate a pipeline change before deploying it. When
+// a second pipeline is given, both are run on the same requests and the
+// placements that differ between them are reported.
+//
+// The pipeline and the knowledges its steps depend on are read from the
+// cluster of the current (or given) kubeconfig context. The pipeline is run
+// locally, no requests are sent to a live scheduler and nothing is written
+// to the cluster.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"sort"
+	"strings"
+
+	api "github.com/cobaltcore-dev/cortex/api/external/nova"
+	"github.com/cobaltcore-dev/cortex/api/v1alpha1"
+	"github.com/cobaltcore-dev/cortex/internal/scheduling/lib"
+	"github.com/cobaltcore-dev/cortex/internal/scheduling/nova/plugins/filters"
+	"github.com/cobaltcore-dev/cortex/internal/scheduling/nova/plugins/weighers"
+	hv1 "github.com/cobaltcore-dev/openstack-hypervisor-operator/api/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+)
+
+var scheme = runtime.NewScheme()
+
+func init() {
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(v1alpha1.AddToScheme(scheme))
+	utilruntime.Must(hv1.AddToScheme(scheme))
+}
+
+// Placement distribution over all replayed requests.
+type distribution struct {
+	// Number of requests replayed through the pipeline.
+	requests int
+	// Number of requests for which the pipeline failed or found no host.
+	unplaced int
+	// Number of requests won by each host.
+	wins map[string]int
+	// Sum and count of the activations of each step, over all hosts.
+	activationSums   map[string]float64
+	activationCounts map[string]int
+	// Order in which the steps were first seen.
+	steps []string
+}
+
+func newDistribution() *distribution {
+	return &distribution{
+		wins:             make(map[string]int),
+		activationSums:   make(map[string]float64),
+		activationCounts: make(map[string]int),
+	}
+}
+
+// Add the result of a single pipeline run to the distribution.
+func (d *distribution) add(result v1alpha1.DecisionResult, err error) {
+	d.requests++
+	if err != nil || result.TargetHost == nil {
+		d.unplaced++
+	} else {
+		d.wins[*result.TargetHost]++
+	}
+	for _, step := range result.StepResults {
+		if _, ok := d.activationCounts[step.StepName]; !ok {
+			d.steps = append(d.steps, step.StepName)
+		}
+		for _, activation := range step.Activations {
+			d.activationSums[step.StepName] += activation
+		}
+		d.activationCounts[step.StepName] += len(step.Activations)
+	}
+}
+
+// Print a histogram of the winning hosts and the average step activations.
+func (d *distribution) print() {
+	fmt.Printf("Replayed %d requests, %d could not be placed.\n\n", d.requests, d.unplaced)
+
+	hosts := make([]string, 0, len(d.wins))
+	maxWins, maxHostLen := 0, 0
+	for host, wins := range d.wins {
+		hosts = append(hosts, host)
+		maxWins = max(maxWins, wins)
+		maxHostLen = max(maxHostLen, len(host))
+	}
+	sort.Slice(hosts, func(i, j int) bool {
+		if d.wins[hosts[i]] != d.wins[hosts[j]] {
+			return d.wins[hosts[i]] > d.wins[hosts[j]]
+		}
+		return hosts[i] < hosts[j]
+	})
+	const barWidth = 50
+	fmt.Println("Winning hosts:")
+	for _, host := range hosts {
+		wins := d.wins[host]
+		bar := strings.Repeat("#", max(1, wins*barWidth/maxWins))
+		percentage := 100 * float64(wins) / float64(d.requests)
+		fmt.Printf("  %-*s %6d (%5.1f%%) %s\n", maxHostLen, host, wins, percentage, bar)
+	}
+
+	maxStepLen := 0
+	for _, step := range d.steps {
+		maxStepLen = max(maxStepLen, len(step))
+	}
+	fmt.Println("\nAverage step activations:")
+	for _, step := range d.steps {
+		avg := 0.0
+		if count := d.activationCounts[step]; count > 0 {
+			avg = d.activationSums[step] / float64(count)
+		}
+		fmt.Printf("  %-*s %+.4f\n", maxStepLen, step, avg)
+	}
+}
+
+// A request for which two pipelines chose different hosts.
+type placementDiff struct {
+	// The resource that was scheduled.
+	resource string
+	// The hosts chosen by the baseline and the compared pipeline.
+	hostA, hostB string
+	// How much more weight the baseline pipeline gave to its own winner
+	// than to the winner of the compared pipeline, at the decision point.
+	// Nil if the baseline has no weight for one of the winners.
+	gap *float64
+	// Whether the baseline filtered out the winner of the compared pipeline,
+	// so that the placements differ regardless of the weights.
+	filtered bool
+}
+
+// Comparison of the placements of two pipelines on the same requests.
+type comparison struct {
+	// Number of requests replayed through both pipelines.
+	requests int
+	// Requests for which the pipelines chose different hosts.
+	diffs []placementDiff
+}
+
+// Add the results of both pipelines for a single request.
+func (c *comparison) add(resource string, resultA, resultB v1alpha1.DecisionResult) {
+	c.requests++
+	hostA, hostB := "<none>", "<none>"
+	if resultA.TargetHost != nil {
+		hostA = *resultA.TargetHost
+	}
+	if resultB.TargetHost != nil {
+		hostB = *resultB.TargetHost
+	}
+	if hostA == hostB {
+		return
+	}
+	// Use the same aggregated weights by which the decision was ordered.
+	// If the compared winner was filtered out by the baseline, it has no
+	// weight to compare against and there is no gap.
+	diff := placementDiff{resource: resource, hostA: hostA, hostB: hostB}
+	weightA, okA := resultA.AggregatedOutWeights[hostA]
+	weightB, okB := resultA.AggregatedOutWeights[hostB]
+	if okA && okB {
+		gap := weightA - weightB
+		diff.gap = &gap
+	}
+	diff.filtered = resultB.TargetHost != nil && !okB
+	c.diffs = append(c.diffs, diff)
+}
+
+// Print a summary and the per-resource diff of the placements.
+func (c *comparison) print(nameA, nameB string) {
+	changed := 0.0
+	if c.requests > 0 {
+		changed = 100 * float64(len(c.diffs)) / float64(c.requests)
+	}
+	// Placements without a gap would pull the median towards no difference.
+	gaps := make([]float64, 0, len(c.diffs))
+	filtered := 0
+	for _, diff := range c.diffs {
+		if diff.filtered {
+			filtered++
+		}
+		if diff.gap != nil {
+			gaps = append(gaps, *diff.gap)
+		}
+	}
+	sort.Float64s(gaps)
+	median := 0.0
+	if n := len(gaps); n > 0 {
+		median = gaps[n/2]
+		if n%2 == 0 {
+			median = (gaps[n/2-1] + gaps[n/2]) / 2
+		}
+	}
+	fmt.Printf("Replayed %d requests through %s and %s.\n", c.requests, nameA, nameB)
+	fmt.Printf("%d placements (%.1f%%) changed, median gap %.4f over %d placements with a gap.\n",
+		len(c.diffs), changed, median, len(gaps))
+	fmt.Printf("%d placements changed because %s filtered out the host chosen by %s.\n", filtered, nameA, nameB)
+	if len(c.diffs) == 0 {
+		return
+	}
+	fmt.Printf("\n%-36s  %-24s  %-24s  %s\n", "RESOURCE", nameA, nameB, "GAP")
+	for _, diff := range c.diffs {
+		gap := "-"
+		switch {
+		case diff.gap != nil:
+			gap = fmt.Sprintf("%.4f", *diff.gap)
+		case diff.filtered:
+			gap = "filtered"
+		}
+		fmt.Printf("%-36s  %-24s  %-24s  %s\n", diff.resource, diff.hostA, diff.hostB, gap)
+	}
+}
+
+// Fetch the nova filter-weigher pipeline with the given name from the
+// cluster and initialize it for local execution.
+func initPipeline(ctx context.Context, k8sClient client.Client, name string) (lib.FilterWeigherPipeline[api.ExternalSchedulerRequest], error) {
+	pipeline := &v1alpha1.Pipeline{}
+	if err := k8sClient.Get(ctx, client.ObjectKey{Name: name}, pipeline); err != nil {
+		return nil, fmt.Errorf("failed to get pipeline %s: %w", name, err)
+	}
+	if pipeline.Spec.SchedulingDomain != v1alpha1.SchedulingDomainNova ||
+		pipeline.Spec.Type != v1alpha1.PipelineTypeFilterWeigher {
+
+		return nil, fmt.Errorf("pipeline %s is not a nova filter-weigher pipeline", name)
+	}
+	initResult := lib.InitNewFilterWeigherPipeline(
+		ctx, k8sClient, pipeline.Name,
+		filters.Index, pipeline.Spec.Filters,
+		weighers.Index, pipeline.Spec.Weighers,
+		pipeline.Spec.TieBreaker,
+		pipeline.Spec.InputWeightNormalization,
+		pipeline.Spec.ParallelFilters,
+		lib.NewPipelineMonitor(),
+	)
+	for step, err := range initResult.FilterErrors {
+		fmt.Fprintf(os.Stderr, "warning: %s: filter %s not initialized: %v\n", name, step, err)
+	}
+	for step, err := range initResult.WeigherErrors {
+		fmt.Fprintf(os.Stderr, "warning: %s: weigher %s not initialized: %v\n", name, step, err)
+	}
+	for _, step := range append(initResult.UnknownFilters, initResult.UnknownWeighers...) {
+		fmt.Fprintf(os.Stderr, "warning: %s: unknown step %s\n", name, step)
+	}
+	return initResult.Pipeline, nil
+}
+
+// Create a kubernetes client for the specified context, or the current
+// context if none is given. The client is dry-run only, so that steps
+// can't modify the cluster during the simulation.
+func getClientForContext(contextName string) (client.Client, error) {
+	var cfg *rest.Config
+	var err error
+	if contextName == "" {
+		cfg, err = config.GetConfig()
+		if err != nil {
+			return nil, fmt.Errorf("getting default kubeconfig: %w", err)
+		}
+	} else {
+		loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+		configOverrides := &clientcmd.ConfigOverrides{CurrentContext: contextName}
+		kubeConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, configOverrides)
+		cfg, err = kubeConfig.ClientConfig()
+		if err != nil {
+			return nil, fmt.Errorf("getting kubeconfig for context %q: %w", contextName, err)
+		}
+	}
+	k8sClient, err := client.New(cfg, client.Options{Scheme: scheme})
+	if err != nil {
+		return nil, fmt.Errorf("creating client: %w", err)
+	}
+	return client.NewDryRunClient(k8sClient), nil
+}
+
+// Read a json list of recorded nova scheduling requests.
+func readRequests(path string) ([]api.ExternalSchedulerRequest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var requests []api.ExternalSchedulerRequest
+	if err := json.Unmarshal(data, &requests); err != nil {
+		return nil, fmt.Errorf("parsing requests: %w", err)
+	}
+	return requests, nil
+}
+
+func fatal(format string, args ...any) {
+	fmt.Fprintf(os.Stderr, "error: "+format+"\n", args...)
+	os.Exit(1)
+}
+
+func main() {
+	requestsPath := flag.String("distribution", "", "Path to a JSON file with a list of recorded nova scheduling requests to replay")
+	pipelineName := flag.String("pipeline", "", "Name of the filter-weigher pipeline to replay the requests through")
+	comparedName := flag.String("compare", "", "Name of a second filter-weigher pipeline to compare the placements against")
+	contextName := flag.String("context", "", "Kubernetes context to read the pipeline and knowledges from (defaults to current context)")
+	verbose := flag.Bool("verbose", false, "Show the logs of the pipeline steps")
+	flag.Parse()
+	if *requestsPath == "" || *pipelineName == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if !*verbose {
+		slog.SetDefault(slog.New(slog.DiscardHandler))
+	}
+
+	ctx := context.Background()
+	requests, err := readRequests(*requestsPath)
+	if err != nil {
+		fatal("failed to read requests: %v", err)
+	}
+	k8sClient, err := getClientForContext(*contextName)
+	if err != nil {
+		fatal("%v", err)
+	}
+	pipeline, err := initPipeline(ctx, k8sClient, *pipelineName)
+	if err != nil {
+		fatal("%v", err)
+	}
+	if *comparedName != "" {
+		compared, err := initPipeline(ctx, k8sClient, *comparedName)
+		if err != nil {
+			fatal("%v", err)
+		}
+		cmp := &comparison{}
+		for _, request := range requests {
+			// Don't let the replayed requests have side effects.
+			request.Options.ReadOnly = true
+			resource := request.Spec.Data.InstanceUUID
+			resultA, errA := pipeline.Run(ctx, request)
+			resultB, errB := compared.Run(ctx, request)
+			for _, err := range []error{errA, errB} {
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "warning: request %s failed: %v\n", resource, err)
+				}
+			}
+			cmp.add(resource, resultA, resultB)
+		}
+		cmp.print(*pipelineName, *comparedName)
+		return
+	}
+
+	dist := newDistribution()
+	for _, request := range requests {
+		// Don't let the replayed requests have side effects.
+		request.Options.ReadOnly = true
+		result, err := pipeline.Run(ctx, request)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "warning: request %s failed: %v\n", request.Spec.Data.InstanceUUID, err)
+		}
+		dist.add(result, err)
+	}
+	dist.print()
+}
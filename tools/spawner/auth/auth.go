@@ -0,0 +1,92 @@
+// Copyright SAP SE
+// SPDX-License-Identifier: Apache-2.0
+
+package auth
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/gophercloud/gophercloud/v2"
+	"github.com/gophercloud/gophercloud/v2/openstack"
+)
+
+// Compute API microversion needed to correctly fetch hypervisors.
+const computeMicroversion = "2.88"
+
+// Get the OpenStack password from OS_PASSWORD, or from the output of the
+// command in OS_PW_CMD if OS_PASSWORD is not set.
+func Password() (string, error) {
+	if password := os.Getenv("OS_PASSWORD"); password != "" {
+		return password, nil
+	}
+	pwdCmd := os.Getenv("OS_PW_CMD")
+	if pwdCmd == "" {
+		return "", errors.New("no password set in OS_PASSWORD or OS_PW_CMD env")
+	}
+	cmd := exec.Command("sh", "-c", pwdCmd) //nolint:gosec // pwdCmd is set by the operator via OS_PW_CMD env var, intentional
+	cmd.Stdin = os.Stdin
+	output, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// Authenticated OpenStack provider from which the service clients are
+// created on demand, so that only the services actually used need an
+// endpoint in the catalog of the region.
+type Clients struct {
+	Provider *gophercloud.ProviderClient
+	region   string
+}
+
+// Authenticate with the given options for the given region.
+func NewClients(ctx context.Context, authOpts gophercloud.AuthOptions, region string) (*Clients, error) {
+	provider, err := openstack.NewClient(authOpts.IdentityEndpoint)
+	if err != nil {
+		return nil, err
+	}
+	if err := openstack.Authenticate(ctx, provider, authOpts); err != nil {
+		return nil, err
+	}
+	return &Clients{Provider: provider, region: region}, nil
+}
+
+// Get the endpoint options for the given service type in the region.
+func (c *Clients) endpoint(serviceType string) gophercloud.EndpointOpts {
+	return gophercloud.EndpointOpts{Region: c.region, Type: serviceType}
+}
+
+// Create a client for the keystone identity service.
+func (c *Clients) Identity() (*gophercloud.ServiceClient, error) {
+	return openstack.NewIdentityV3(c.Provider, c.endpoint("identity"))
+}
+
+// Create a client for the nova compute service.
+func (c *Clients) Compute() (*gophercloud.ServiceClient, error) {
+	client, err := openstack.NewComputeV2(c.Provider, c.endpoint("compute"))
+	if err != nil {
+		return nil, err
+	}
+	client.Microversion = computeMicroversion
+	return client, nil
+}
+
+// Create a client for the neutron network service.
+func (c *Clients) Network() (*gophercloud.ServiceClient, error) {
+	return openstack.NewNetworkV2(c.Provider, c.endpoint("network"))
+}
+
+// Create a client for the glance image service.
+func (c *Clients) Image() (*gophercloud.ServiceClient, error) {
+	return openstack.NewImageV2(c.Provider, c.endpoint("image"))
+}
+
+// Create a client for the cinder block storage service.
+func (c *Clients) BlockStorage() (*gophercloud.ServiceClient, error) {
+	return openstack.NewBlockStorageV3(c.Provider, c.endpoint("volumev3"))
+}
@@ -7,12 +7,14 @@ import (
 	"bufio"
 	"fmt"
 	"os"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
 
 	"github.com/cobaltcore-dev/cortex/tools/spawner/defaults"
 	"github.com/cobaltcore-dev/cortex/tools/spawner/types"
+	"github.com/gophercloud/gophercloud/v2/openstack/blockstorage/v3/volumetypes"
 	"github.com/gophercloud/gophercloud/v2/openstack/compute/v2/flavors"
 	"github.com/gophercloud/gophercloud/v2/openstack/compute/v2/hypervisors"
 	"github.com/gophercloud/gophercloud/v2/openstack/identity/v3/domains"
@@ -23,6 +25,7 @@ import (
 
 type CLI interface {
 	ChooseAZ([]string) string
+	ChooseAZs([]string) []string
 	ChooseDomain([]domains.Domain) domains.Domain
 	ChooseProject([]projects.Project) projects.Project
 	ChooseFlavor([]flavors.Flavor) flavors.Flavor
@@ -31,35 +34,56 @@ type CLI interface {
 	ChooseHypervisor([]hypervisors.Hypervisor) hypervisors.Hypervisor
 	ChooseServerGroupPolicy([]string) string
 	ChooseServerGroup([]types.ServerGroup) types.ServerGroup
+	ChooseVolumeType([]volumetypes.VolumeType) volumetypes.VolumeType
+	// Ask the user for a value, answered by the env variable in non-interactive mode.
+	Ask(question, envKey, defaultValue string) string
+	// Ask the user a yes/no question, answered by the env variable in non-interactive mode.
+	Confirm(question, envKey string, defaultYes bool) bool
 }
 
 type cli struct {
 	defaults defaults.Defaults
+	// If set, choices are resolved from env variables or the defaults file
+	// instead of asking the user, and stdin is never read.
+	nonInteractive bool
 }
 
-func NewCLI(d defaults.Defaults) CLI {
-	return &cli{defaults: d}
+func NewCLI(d defaults.Defaults, nonInteractive bool) CLI {
+	return &cli{defaults: d, nonInteractive: nonInteractive}
 }
 
 func (c *cli) ChooseAZ(azs []string) string {
 	f := func(az string) string {
 		return az
 	}
-	return choose(c.defaults, "WS_AVAILABILITY_ZONE", "📂 Availability Zones", azs, f)
+	return choose(c, "OS_AVAILABILITY_ZONE", "WS_AVAILABILITY_ZONE", "📂 Availability Zones", azs, f, plain)
+}
+
+func (c *cli) ChooseAZs(azs []string) []string {
+	f := func(az string) string {
+		return az
+	}
+	return chooseMany(c, "OS_AVAILABILITY_ZONES", "WS_SPREAD_AZS", "📂 Availability Zones (comma-separated)", azs, f, plain)
 }
 
 func (c *cli) ChooseDomain(ds []domains.Domain) domains.Domain {
 	f := func(d domains.Domain) string {
 		return d.Name
 	}
-	return choose(c.defaults, "WS_DOMAIN", "📂 Domains", ds, f)
+	ids := func(d domains.Domain) []string {
+		return []string{d.Name, d.ID}
+	}
+	return choose(c, "OS_DOMAIN", "WS_DOMAIN", "📂 Domains", ds, f, ids)
 }
 
 func (c *cli) ChooseProject(ps []projects.Project) projects.Project {
 	f := func(p projects.Project) string {
 		return p.Name
 	}
-	return choose(c.defaults, "WS_PROJECT", "📂 Projects", ps, f)
+	ids := func(p projects.Project) []string {
+		return []string{p.Name, p.ID}
+	}
+	return choose(c, "OS_PROJECT", "WS_PROJECT", "📂 Projects", ps, f, ids)
 }
 
 func (c *cli) ChooseFlavor(fs []flavors.Flavor) flavors.Flavor {
@@ -70,21 +94,27 @@ func (c *cli) ChooseFlavor(fs []flavors.Flavor) flavors.Flavor {
 		}
 		return o
 	}
-	return choose(c.defaults, "WS_FLAVOR", "📂 Flavors", fs, f)
+	ids := func(f flavors.Flavor) []string {
+		return []string{f.Name, f.ID}
+	}
+	return choose(c, "OS_FLAVOR", "WS_FLAVOR", "📂 Flavors", fs, f, ids)
 }
 
 func (c *cli) ChooseImage(is []images.Image) images.Image {
 	f := func(i images.Image) string {
 		return fmt.Sprintf("%s (%s) id:%s", i.Name, i.Status, i.ID[:5])
 	}
-	return choose(c.defaults, "WS_IMAGE", "📂 Images", is, f)
+	ids := func(i images.Image) []string {
+		return []string{i.Name, i.ID}
+	}
+	return choose(c, "OS_IMAGE", "WS_IMAGE", "📂 Images", is, f, ids)
 }
 
 func (c *cli) ChooseHypervisorType(ts []string) string {
 	f := func(t string) string {
 		return t
 	}
-	return choose(c.defaults, "WS_HYPERVISOR_TYPE", "📂 Hypervisor Types", ts, f)
+	return choose(c, "OS_HYPERVISOR_TYPE", "WS_HYPERVISOR_TYPE", "📂 Hypervisor Types", ts, f, plain)
 }
 
 func (c *cli) ChooseHypervisor(hs []hypervisors.Hypervisor) hypervisors.Hypervisor {
@@ -92,41 +122,160 @@ func (c *cli) ChooseHypervisor(hs []hypervisors.Hypervisor) hypervisors.Hypervis
 		// Host, type and first 5 characters of the id.
 		return fmt.Sprintf("%s (%s) id:%s", h.Service.Host, h.HypervisorType, h.ID[:5])
 	}
-	return choose(c.defaults, "WS_HYPERVISOR", "📂 Hypervisors", hs, f)
+	ids := func(h hypervisors.Hypervisor) []string {
+		return []string{h.Service.Host, h.HypervisorHostname, h.ID}
+	}
+	return choose(c, "OS_HYPERVISOR", "WS_HYPERVISOR", "📂 Hypervisors", hs, f, ids)
 }
 
 func (c *cli) ChooseServerGroupPolicy(ps []string) string {
 	f := func(p string) string {
 		return p
 	}
-	return choose(c.defaults, "WS_SERVER_GROUP_POLICY", "📂 Server Group Policies", ps, f)
+	return choose(c, "OS_SERVER_GROUP_POLICY", "WS_SERVER_GROUP_POLICY", "📂 Server Group Policies", ps, f, plain)
 }
 
 func (c *cli) ChooseServerGroup(sgs []types.ServerGroup) types.ServerGroup {
 	f := func(sg types.ServerGroup) string {
 		return fmt.Sprintf("%s (%s) id:%s", sg.Name, sg.Policy, sg.ID[:5])
 	}
-	return choose(c.defaults, "WS_SERVER_GROUP", "📂 Server Groups", sgs, f)
+	ids := func(sg types.ServerGroup) []string {
+		return []string{sg.Name, sg.ID}
+	}
+	return choose(c, "OS_SERVER_GROUP", "WS_SERVER_GROUP", "📂 Server Groups", sgs, f, ids)
+}
+
+func (c *cli) ChooseVolumeType(vts []volumetypes.VolumeType) volumetypes.VolumeType {
+	f := func(vt volumetypes.VolumeType) string {
+		return vt.Name
+	}
+	ids := func(vt volumetypes.VolumeType) []string {
+		return []string{vt.Name, vt.ID}
+	}
+	return choose(c, "OS_BOOT_VOLUME_TYPE", "WS_BOOT_VOLUME_TYPE", "📂 Volume Types", vts, f, ids)
+}
+
+func (c *cli) Ask(question, envKey, defaultValue string) string {
+	if c.nonInteractive {
+		value := strings.TrimSpace(os.Getenv(envKey))
+		if value == "" {
+			value = defaultValue
+		}
+		fmt.Printf("❓ %s \033[1;34m%s\033[0m\n", question, value)
+		return value
+	}
+	fmt.Printf("❓ %s [default: \033[1;34m%s\033[0m]: ", question, defaultValue)
+	reader := bufio.NewReader(os.Stdin)
+	input := must.Return(reader.ReadString('\n'))
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return defaultValue
+	}
+	return input
+}
+
+func (c *cli) Confirm(question, envKey string, defaultYes bool) bool {
+	defaultAnswer := "N"
+	if defaultYes {
+		defaultAnswer = "y"
+	}
+	var answer string
+	if c.nonInteractive {
+		answer = strings.TrimSpace(os.Getenv(envKey))
+		if answer == "" {
+			answer = defaultAnswer
+		}
+		fmt.Printf("❓ %s \033[1;34m%s\033[0m\n", question, answer)
+	} else {
+		fmt.Printf("❓ %s [y/N, default: \033[1;34m%s\033[0m]: ", question, defaultAnswer)
+		reader := bufio.NewReader(os.Stdin)
+		answer = strings.TrimSpace(must.Return(reader.ReadString('\n')))
+		if answer == "" {
+			answer = defaultAnswer
+		}
+	}
+	return strings.EqualFold(answer, "y")
+}
+
+// Identifiers of an option that is only known by its plain string value.
+func plain(s string) []string {
+	return []string{s}
+}
+
+// Resolve the options matching the given value. An exact match of the
+// displayname wins, otherwise all options that have the value as one of their
+// identifiers (e.g. name or ID) are returned.
+func resolve[T any](ts []T, displayname func(T) string, identifiers func(T) []string, value string) []T {
+	for _, t := range ts {
+		if displayname(t) == value {
+			return []T{t}
+		}
+	}
+	var matches []T
+	for _, t := range ts {
+		if slices.Contains(identifiers(t), value) {
+			matches = append(matches, t)
+		}
+	}
+	return matches
+}
+
+// Resolve exactly one option non-interactively, or exit if the value doesn't
+// match any option or matches more than one.
+func resolveOne[T any](header, envKey string, ts []T, displayname func(T) string, identifiers func(T) []string, value string) T {
+	matches := resolve(ts, displayname, identifiers, value)
+	if len(matches) == 0 {
+		fail("%s: no option matches '%s' (set %s to a name or ID)", header, value, envKey)
+	}
+	if len(matches) > 1 {
+		names := make([]string, len(matches))
+		for i, t := range matches {
+			names[i] = displayname(t)
+		}
+		fail("%s: '%s' is ambiguous, it matches %v (set %s to an ID)", header, value, names, envKey)
+	}
+	return matches[0]
+}
+
+// Get the value to resolve non-interactively, from the env variable or the
+// defaults file, or exit if neither is set.
+func nonInteractiveValue(d defaults.Defaults, envKey, defaultKey, header string) string {
+	value := strings.TrimSpace(os.Getenv(envKey))
+	if value == "" {
+		value = d.GetDefault(defaultKey)
+	}
+	if value == "" {
+		fail("%s: no choice given in non-interactive mode (set %s or %s in the defaults file)", header, envKey, defaultKey)
+	}
+	return value
+}
+
+// Print the error and exit. Used in non-interactive mode, where we can't ask
+// the user to correct the input.
+func fail(format string, args ...any) {
+	fmt.Printf("🚫 "+format+"\n", args...)
+	os.Exit(1)
 }
 
 // Choose asks the user to choose one of the given options.
-// The user can choose by index or by name. The user can also choose the default value.
-// If the user chooses to input a name, the mapping is done by the displayname function.
+// The user can choose by index, by displayname, or by one of the identifiers
+// (e.g. name or ID) of an option. The user can also choose the default value.
+// In non-interactive mode, the choice is resolved from the env variable or the
+// default value instead, without reading stdin.
 func choose[T any](
-	d defaults.Defaults,
+	c *cli,
+	envKey string,
 	defaultKey string,
 	header string,
 	ts []T,
 	displayname func(T) string,
+	identifiers func(T) []string,
 ) T {
 
+	d := c.defaults
 	sort.Slice(ts, func(i, j int) bool {
 		return displayname(ts[i]) < displayname(ts[j])
 	})
-	fmt.Printf("🔍 %s\n", header)
-	for i, t := range ts {
-		fmt.Printf("   - [\033[1;34m%d\033[0m] \033[1;34m%s\033[0m\n", i, displayname(t))
-	}
 	tByName := make(map[string]T)
 	for _, t := range ts {
 		tByName[displayname(t)] = t
@@ -134,6 +283,16 @@ func choose[T any](
 	if len(ts) != len(tByName) {
 		panic("displayname is not unique")
 	}
+	if c.nonInteractive {
+		value := nonInteractiveValue(d, envKey, defaultKey, header)
+		t := resolveOne(header, envKey, ts, displayname, identifiers, value)
+		fmt.Printf("🔍 %s: \033[1;34m%s\033[0m\n", header, displayname(t))
+		return t
+	}
+	fmt.Printf("🔍 %s\n", header)
+	for i, t := range ts {
+		fmt.Printf("   - [\033[1;34m%d\033[0m] \033[1;34m%s\033[0m\n", i, displayname(t))
+	}
 	var defaultChoice = d.GetDefault(defaultKey)
 	var defaultChoicePresent bool
 	if _, ok := tByName[defaultChoice]; ok {
@@ -153,9 +312,94 @@ func choose[T any](
 	var t T
 	if i, err := strconv.Atoi(input); err == nil {
 		t = ts[i]
-	} else {
-		t = tByName[input]
+	} else if matches := resolve(ts, displayname, identifiers, input); len(matches) == 1 {
+		t = matches[0]
 	}
 	d.SetDefault(defaultKey, displayname(t))
 	return t
 }
+
+// ChooseMany asks the user to choose one or more of the given options.
+// The user can choose by a comma-separated list of indices, displaynames or
+// identifiers, or choose the default value. Duplicate choices are ignored.
+// In non-interactive mode, the choices are resolved from the env variable or
+// the default value instead, without reading stdin.
+func chooseMany[T any](
+	c *cli,
+	envKey string,
+	defaultKey string,
+	header string,
+	ts []T,
+	displayname func(T) string,
+	identifiers func(T) []string,
+) []T {
+
+	d := c.defaults
+	sort.Slice(ts, func(i, j int) bool {
+		return displayname(ts[i]) < displayname(ts[j])
+	})
+	tByName := make(map[string]T)
+	for _, t := range ts {
+		tByName[displayname(t)] = t
+	}
+	if len(ts) != len(tByName) {
+		panic("displayname is not unique")
+	}
+	if c.nonInteractive {
+		value := nonInteractiveValue(d, envKey, defaultKey, header)
+		var chosen []T
+		var names []string
+		for part := range strings.SplitSeq(value, ",") {
+			t := resolveOne(header, envKey, ts, displayname, identifiers, strings.TrimSpace(part))
+			if slices.Contains(names, displayname(t)) {
+				continue
+			}
+			chosen = append(chosen, t)
+			names = append(names, displayname(t))
+		}
+		fmt.Printf("🔍 %s: \033[1;34m%s\033[0m\n", header, strings.Join(names, ","))
+		return chosen
+	}
+	fmt.Printf("🔍 %s\n", header)
+	for i, t := range ts {
+		fmt.Printf("   - [\033[1;34m%d\033[0m] \033[1;34m%s\033[0m\n", i, displayname(t))
+	}
+	var defaultChoice = d.GetDefault(defaultKey)
+	var defaultChoicePresent = defaultChoice != ""
+	for name := range strings.SplitSeq(defaultChoice, ",") {
+		if _, ok := tByName[name]; !ok {
+			defaultChoicePresent = false
+		}
+	}
+	if defaultChoicePresent {
+		fmt.Printf("📥 Indices or names [default: \033[1;34m%s\033[0m]: ", defaultChoice)
+	} else {
+		fmt.Printf("📥 Indices or names: ")
+	}
+	reader := bufio.NewReader(os.Stdin)
+	input := must.Return(reader.ReadString('\n'))
+	input = strings.TrimSpace(input)
+	if input == "" {
+		input = defaultChoice
+	}
+	var chosen []T
+	var names []string
+	for part := range strings.SplitSeq(input, ",") {
+		part = strings.TrimSpace(part)
+		var t T
+		if i, err := strconv.Atoi(part); err == nil {
+			t = ts[i]
+		} else if matches := resolve(ts, displayname, identifiers, part); len(matches) == 1 {
+			t = matches[0]
+		} else {
+			continue
+		}
+		if slices.Contains(names, displayname(t)) {
+			continue
+		}
+		chosen = append(chosen, t)
+		names = append(names, displayname(t))
+	}
+	d.SetDefault(defaultKey, strings.Join(names, ","))
+	return chosen
+}
@@ -4,25 +4,28 @@
 package main
 
 import (
-	"bufio"
 	"context"
+	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
 	"html/template"
 	"math/rand"
 	"os"
-	"os/exec"
+	"os/signal"
 	"slices"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/cobaltcore-dev/cortex/tools/spawner/auth"
 	"github.com/cobaltcore-dev/cortex/tools/spawner/cli"
 	"github.com/cobaltcore-dev/cortex/tools/spawner/defaults"
 	"github.com/cobaltcore-dev/cortex/tools/spawner/types"
 	"github.com/gophercloud/gophercloud/v2"
-	"github.com/gophercloud/gophercloud/v2/openstack"
 	"github.com/gophercloud/gophercloud/v2/openstack/blockstorage/v3/volumes"
+	"github.com/gophercloud/gophercloud/v2/openstack/blockstorage/v3/volumetypes"
 	"github.com/gophercloud/gophercloud/v2/openstack/compute/v2/aggregates"
 	"github.com/gophercloud/gophercloud/v2/openstack/compute/v2/flavors"
 	"github.com/gophercloud/gophercloud/v2/openstack/compute/v2/hypervisors"
@@ -37,20 +40,42 @@ import (
 	"github.com/sapcc/go-bits/must"
 )
 
+// Where the private key of the spawned VMs' keypair is stored.
+const sshKeyPath = "tools/spawner/ssh.pem"
+
 func main() {
+	dryRunFlag := flag.Bool("dry-run", false, "preview all actions without creating or deleting anything")
+	reportPath := flag.String("report", "", "write a JSON report of all spawned VMs to the given path")
+	cleanupFlag := flag.Bool("cleanup", false, "only delete the resources created by previous runs, without spawning new VMs")
+	nonInteractiveFlag := flag.Bool("non-interactive", false, "answer all prompts from env variables and the defaults file instead of stdin")
+	flag.Parse()
+	// Dry-run mode can also be enabled through OS_DRY_RUN=1.
+	dryRun := *dryRunFlag || os.Getenv("OS_DRY_RUN") == "1"
+	// Spread the VMs round-robin across multiple availability zones.
+	spreadAZs := os.Getenv("OS_SPREAD_AZS") == "1"
+	// Reuse an existing keypair if its private key is still available locally,
+	// so that ssh access to VMs spawned by previous runs keeps working.
+	reuseKeypair := os.Getenv("OS_REUSE_KEYPAIR") == "1"
+	// Non-interactive mode can also be enabled through OS_NONINTERACTIVE=1.
+	nonInteractive := *nonInteractiveFlag || os.Getenv("OS_NONINTERACTIVE") == "1"
+	// Cleanup mode can also be enabled through OS_CLEANUP=1.
+	cleanup := *cleanupFlag || os.Getenv("OS_CLEANUP") == "1"
+	plan := types.NewDryRunPlan()
+	if dryRun {
+		fmt.Println("🧪 Running in dry-run mode, no resources will be created or deleted")
+	}
+
 	ctx := context.Background()
 	def := defaults.NewDefaults("tools/spawner/defaults.json")
-	cli := cli.NewCLI(def)
+	cli := cli.NewCLI(def, nonInteractive)
 
 	// Get the number of vms to spawn from the user.
-	fmt.Printf("❓ Number of VMs to spawn [default: \033[1;34m1\033[0m]: ")
-	reader := bufio.NewReader(os.Stdin)
-	input := must.Return(reader.ReadString('\n'))
-	input = strings.TrimSpace(input)
-	if input == "" {
-		input = "1"
+	vmsToSpawn := 0
+	if cleanup {
+		fmt.Println("🧹 Running in cleanup mode, only existing resources will be deleted")
+	} else {
+		vmsToSpawn = must.Return(strconv.Atoi(cli.Ask("Number of VMs to spawn", "OS_VM_COUNT", "1")))
 	}
-	vmsToSpawn := must.Return(strconv.Atoi(input))
 
 	// Prefix for the vms and network.
 	prefix := os.Getenv("OS_PREFIX")
@@ -58,34 +83,11 @@ func main() {
 		prefix = "cortex-workload-spawner"
 	}
 
-	// Some endpoint opts.
-	region := os.Getenv("OS_REGION_NAME")
-	computeEO := gophercloud.EndpointOpts{Region: region, Type: "compute"}
-	imageEO := gophercloud.EndpointOpts{Region: region, Type: "image"}
-	networkEO := gophercloud.EndpointOpts{Region: region, Type: "network"}
-	keystoneEO := gophercloud.EndpointOpts{Region: region, Type: "identity"}
-	blockstorageEO := gophercloud.EndpointOpts{Region: region, Type: "volumev3"}
-
 	// Authenticate with the admin project.
 	fmt.Printf("🔄 Resolving openstack endpoints and logging into admin project ...")
-
-	// Use OS_PASSWORD or OS_PWD_CMD to get the password.
-	password := os.Getenv("OS_PASSWORD")
-
-	if password == "" {
-		// Use OS_PW_CMD to get the password.
-		pwdCmd := os.Getenv("OS_PW_CMD")
-		if pwdCmd == "" {
-			panic("No password set in OS_PASSWORD or OS_PW_CMD env.")
-		}
-
-		cmd := exec.Command("sh", "-c", pwdCmd) //nolint:gosec // pwdCmd is set by the operator via OS_PW_CMD env var, intentional
-		cmd.Stdin = os.Stdin
-		output := must.Return(cmd.Output())
-		password = strings.TrimSpace(string(output))
-	}
-
-	adminAuth := gophercloud.AuthOptions{
+	region := os.Getenv("OS_REGION_NAME")
+	password := must.Return(auth.Password())
+	admin := must.Return(auth.NewClients(ctx, gophercloud.AuthOptions{
 		IdentityEndpoint: os.Getenv("OS_AUTH_URL"),
 		Username:         os.Getenv("OS_USERNAME"),
 		DomainName:       os.Getenv("OS_USER_DOMAIN_NAME"),
@@ -95,13 +97,10 @@ func main() {
 			ProjectName: os.Getenv("OS_PROJECT_NAME"),
 			DomainName:  os.Getenv("OS_PROJECT_DOMAIN_NAME"),
 		},
-	}
-	adminProvider := must.Return(openstack.NewClient(adminAuth.IdentityEndpoint))
-	must.Succeed(openstack.Authenticate(ctx, adminProvider, adminAuth))
-	adminKeystone := must.Return(openstack.NewIdentityV3(adminProvider, keystoneEO))
-	adminNova := must.Return(openstack.NewComputeV2(adminProvider, computeEO))
-	adminNova.Microversion = "2.88" // Needed to correctly fetch hypervisors.
-	adminGlance := must.Return(openstack.NewImageV2(adminProvider, imageEO))
+	}, region))
+	adminKeystone := must.Return(admin.Identity())
+	adminNova := must.Return(admin.Compute())
+	adminGlance := must.Return(admin.Image())
 	fmt.Printf(" ✅ Done!\n")
 
 	// Get all domains and let the user choose one.
@@ -118,22 +117,78 @@ func main() {
 
 	// Authenticate with that project.
 	fmt.Printf("🔄 Logging into project %s ...", project.Name)
-	projectAuth := gophercloud.AuthOptions{
+	projectClients := must.Return(auth.NewClients(ctx, gophercloud.AuthOptions{
 		IdentityEndpoint: os.Getenv("OS_AUTH_URL"),
 		Username:         os.Getenv("OS_USERNAME"),
 		DomainID:         project.DomainID,
 		Password:         password,
 		AllowReauth:      true,
 		Scope:            &gophercloud.AuthScope{ProjectID: project.ID},
-	}
-	projectProvider := must.Return(openstack.NewClient(projectAuth.IdentityEndpoint))
-	must.Succeed(openstack.Authenticate(ctx, projectProvider, projectAuth))
-	projectCompute := must.Return(openstack.NewComputeV2(projectProvider, computeEO))
-	projectCompute.Microversion = "2.88" // Needed to correctly fetch hypervisors.
-	projectNetwork := must.Return(openstack.NewNetworkV2(projectProvider, networkEO))
-	projectCinder := must.Return(openstack.NewBlockStorageV3(projectProvider, blockstorageEO))
+	}, region))
+	projectProvider := projectClients.Provider
+	projectCompute := must.Return(projectClients.Compute())
+	projectNetwork := must.Return(projectClients.Network())
+	projectCinder := must.Return(projectClients.BlockStorage())
 	fmt.Printf(" ✅ Done!\n")
 
+	// Resolve the flavor and image before any resources are deleted, so that
+	// an unusable combination doesn't leave the project half torn down.
+	var flavor flavors.Flavor
+	var image images.Image
+	bootVolumeSize := 16 // 16GB boot volume should be sufficient for most OSes
+	if !cleanup {
+		// Get flavors.
+		fmt.Println("🔄 Looking up flavors to use")
+		floPublic := flavors.ListOpts{AccessType: flavors.PublicAccess}
+		flavorPagesPublic := must.Return(flavors.ListDetail(adminNova, floPublic).AllPages(ctx))
+		flavorsAll := must.Return(flavors.ExtractFlavors(flavorPagesPublic))
+		floPrivate := flavors.ListOpts{AccessType: flavors.PrivateAccess}
+		flavorPagesPrivate := must.Return(flavors.ListDetail(adminNova, floPrivate).AllPages(ctx))
+		// Add flavors that are not in the public list.
+		for _, f1 := range must.Return(flavors.ExtractFlavors(flavorPagesPrivate)) {
+			if !slices.ContainsFunc(flavorsAll, func(f2 flavors.Flavor) bool { return f1.ID == f2.ID }) {
+				flavorsAll = append(flavorsAll, f1)
+			}
+		}
+		flavor = cli.ChooseFlavor(flavorsAll)
+
+		// Get a suitable image.
+		fmt.Println("🔄 Looking up image to use")
+		ilo := images.ListOpts{Status: images.ImageStatusActive, Visibility: images.ImageVisibilityPublic}
+		imagePages := must.Return(images.List(adminGlance, ilo).AllPages(ctx))
+		imagesAll := must.Return(images.ExtractImages(imagePages))
+		image = cli.ChooseImage(imagesAll)
+
+		// Resolve the size of the boot volumes. Env vars take precedence over
+		// the defaults file, which takes precedence over the built-in default.
+		fmt.Println("🔄 Resolving boot volume size")
+		bootVolumeSizeStr := os.Getenv("OS_BOOT_VOLUME_SIZE")
+		if bootVolumeSizeStr == "" {
+			bootVolumeSizeStr = def.GetDefault("WS_BOOT_VOLUME_SIZE")
+		}
+		if bootVolumeSizeStr != "" {
+			bootVolumeSize = must.Return(strconv.Atoi(bootVolumeSizeStr))
+		}
+		// Check that the flavor and image fit together before deleting or spawning
+		// anything, since nova only rejects incompatible combinations on the
+		// create call.
+		for {
+			mismatches := types.FlavorImageMismatches(flavor, image, bootVolumeSize)
+			if len(mismatches) == 0 {
+				break
+			}
+			for _, m := range mismatches {
+				fmt.Printf("🚫 %s\n", m)
+			}
+			// Choosing again would only resolve the same flavor and image.
+			if nonInteractive || !cli.Confirm("Choose another flavor and image?", "", true) {
+				os.Exit(1)
+			}
+			flavor = cli.ChooseFlavor(flavorsAll)
+			image = cli.ChooseImage(imagesAll)
+		}
+	}
+
 	// Delete existing vms.
 	fmt.Println("🔄 Looking up existing VMs")
 	serverPages := must.Return(servers.List(projectCompute, nil).AllPages(ctx))
@@ -147,13 +202,14 @@ func main() {
 			serversToDeleteNames = append(serversToDeleteNames, s.Name)
 		}
 	}
-	if len(serversToDelete) > 0 {
+	if len(serversToDelete) > 0 && dryRun {
+		for _, s := range serversToDelete {
+			plan.Delete("VM", s.Name)
+		}
+	} else if len(serversToDelete) > 0 {
 		// Get manual input to delete the vm.
-		fmt.Printf("❓ Delete existing VMs %v? [y/N, default: \033[1;34my\033[0m]: ", serversToDeleteNames)
-		serverReader := bufio.NewReader(os.Stdin)
-		serverInput := must.Return(serverReader.ReadString('\n'))
-		serverInput = strings.TrimSpace(serverInput)
-		if serverInput == "y" || serverInput == "" {
+		question := fmt.Sprintf("Delete existing VMs %v?", serversToDeleteNames)
+		if cli.Confirm(question, "OS_DELETE_VMS", true) {
 			var wg sync.WaitGroup
 			for _, s := range serversToDelete {
 				wg.Go(func() {
@@ -192,13 +248,14 @@ func main() {
 			volumesToDeleteNames = append(volumesToDeleteNames, v.Name)
 		}
 	}
-	if len(volumesToDelete) > 0 {
+	if len(volumesToDelete) > 0 && dryRun {
+		for _, v := range volumesToDelete {
+			plan.Delete("volume", v.Name)
+		}
+	} else if len(volumesToDelete) > 0 {
 		// Get manual input to delete the volumes.
-		fmt.Printf("❓ Delete existing volumes %v? [y/N, default: \033[1;34my\033[0m]: ", volumesToDeleteNames)
-		volumeReader := bufio.NewReader(os.Stdin)
-		volumeInput := must.Return(volumeReader.ReadString('\n'))
-		volumeInput = strings.TrimSpace(volumeInput)
-		if volumeInput == "y" || volumeInput == "" {
+		question := fmt.Sprintf("Delete existing volumes %v?", volumesToDeleteNames)
+		if cli.Confirm(question, "OS_DELETE_VOLUMES", true) {
 			var wg sync.WaitGroup
 			for _, v := range volumesToDelete {
 				wg.Go(func() {
@@ -224,93 +281,16 @@ func main() {
 		}
 	}
 
-	if vmsToSpawn <= 0 {
+	if vmsToSpawn <= 0 && !cleanup {
+		if dryRun {
+			plan.PrintSummary()
+		}
 		fmt.Println("🎉 Done! - Not spawning VMs.")
 		return
 	}
 
-	fmt.Printf("❓ Spawn on specific host [default: \033[1;34mn\033[0m]: ")
-	reader = bufio.NewReader(os.Stdin)
-	input = must.Return(reader.ReadString('\n'))
-	input = strings.TrimSpace(input)
-	if input == "" {
-		input = "n"
-	}
-	var hypervisor *hypervisors.Hypervisor
-	var az = ""
-	aggregatePages := must.Return(aggregates.List(adminNova).AllPages(ctx))
-	aggregatesAll := must.Return(aggregates.ExtractAggregates(aggregatePages))
-	if input == "y" {
-		// List all hypervisors with the given type.
-		fmt.Println("🔄 Looking up hypervisors")
-		withServers := true
-		hlo := hypervisors.ListOpts{WithServers: &withServers}
-		hypervisorPages := must.Return(hypervisors.List(adminNova, hlo).AllPages(ctx))
-		hypervisorsAll := must.Return(hypervisors.ExtractHypervisors(hypervisorPages))
-		hypervisorTypes := []string{}
-		for _, h := range hypervisorsAll {
-			if !slices.Contains(hypervisorTypes, h.HypervisorType) {
-				hypervisorTypes = append(hypervisorTypes, h.HypervisorType)
-			}
-		}
-		hypervisorType := cli.ChooseHypervisorType(hypervisorTypes)
-		var hypervisorsFiltered []hypervisors.Hypervisor
-		for _, h := range hypervisorsAll {
-			if h.Status == "enabled" && h.State == "up" && h.HypervisorType == hypervisorType {
-				hypervisorsFiltered = append(hypervisorsFiltered, h)
-			}
-		}
-		h := cli.ChooseHypervisor(hypervisorsFiltered)
-		hypervisor = &h
-		// Resolve the availability zones of the hypervisor.
-		fmt.Printf("🔄 Resolving availability zone of host %s\n", hypervisor.Service.Host)
-		for _, a := range aggregatesAll {
-			if a.AvailabilityZone == "" {
-				continue
-			}
-			if slices.Contains(a.Hosts, hypervisor.Service.Host) {
-				az = a.AvailabilityZone
-			}
-			if az != "" {
-				break
-			}
-		}
-		fmt.Printf("🗺️ Using availability zone '%s'\n", az)
-	} else {
-		// Let the user choose an az.
-		azs := []string{}
-		for _, a := range aggregatesAll {
-			if !slices.Contains(azs, a.AvailabilityZone) && a.AvailabilityZone != "" {
-				azs = append(azs, a.AvailabilityZone)
-			}
-		}
-		az = cli.ChooseAZ(azs)
-		fmt.Printf("🗺️ Using availability zone '%s'\n", az)
-	}
-
-	// Get flavors.
-	fmt.Println("🔄 Looking up flavors to use")
-	floPublic := flavors.ListOpts{AccessType: flavors.PublicAccess}
-	flavorPagesPublic := must.Return(flavors.ListDetail(adminNova, floPublic).AllPages(ctx))
-	flavorsAll := must.Return(flavors.ExtractFlavors(flavorPagesPublic))
-	floPrivate := flavors.ListOpts{AccessType: flavors.PrivateAccess}
-	flavorPagesPrivate := must.Return(flavors.ListDetail(adminNova, floPrivate).AllPages(ctx))
-	// Add flavors that are not in the public list.
-	for _, f1 := range must.Return(flavors.ExtractFlavors(flavorPagesPrivate)) {
-		if !slices.ContainsFunc(flavorsAll, func(f2 flavors.Flavor) bool { return f1.ID == f2.ID }) {
-			flavorsAll = append(flavorsAll, f1)
-		}
-	}
-	flavor := cli.ChooseFlavor(flavorsAll)
-
-	// Get a suitable image.
-	fmt.Println("🔄 Looking up image to use")
-	ilo := images.ListOpts{Status: images.ImageStatusActive, Visibility: images.ImageVisibilityPublic}
-	imagePages := must.Return(images.List(adminGlance, ilo).AllPages(ctx))
-	imagesAll := must.Return(images.ExtractImages(imagePages))
-	image := cli.ChooseImage(imagesAll)
-
-	// Create the necessary network in the target availability zone.
+	// Look up the network, and delete it if requested. In cleanup mode, the
+	// network is deleted by default.
 	networkName := prefix + "-network"
 	subnetworkName := networkName + "-subnet"
 	fmt.Println("🔄 Looking up networks to use")
@@ -326,51 +306,36 @@ func main() {
 	}
 	var network *networks.Network
 	if len(networksAll) == 1 {
-		fmt.Printf("❓ Delete existing network %s [y/N, default: \033[1;34mN\033[0m]: ", networkName)
-		reader = bufio.NewReader(os.Stdin)
-		input = must.Return(reader.ReadString('\n'))
-		input = strings.TrimSpace(input)
-		if input == "y" {
+		if cli.Confirm("Delete existing network "+networkName+"?", "OS_DELETE_NETWORK", cleanup) {
 			// Delete the subnets.
 			fmt.Printf("🔄 Looking up subnets in network %s\n", networkName)
 			slo := subnets.ListOpts{NetworkID: networksAll[0].ID}
 			subnetPages := must.Return(subnets.List(projectNetwork, slo).AllPages(ctx))
 			subnetsAll := must.Return(subnets.ExtractSubnets(subnetPages))
 			for _, s := range subnetsAll {
+				if dryRun {
+					plan.Delete("subnet", s.ID)
+					continue
+				}
 				fmt.Printf("🧨 Deleting subnet %s\n", s.ID)
 				result := subnets.Delete(ctx, projectNetwork, s.ID)
 				must.Succeed(result.Err)
 				fmt.Printf("💥 Deleted subnet %s\n", s.ID)
 			}
 			// Delete the network.
-			fmt.Printf("🧨 Deleting network %s\n", networkName)
-			result := networks.Delete(ctx, projectNetwork, networksAll[0].ID)
-			must.Succeed(result.Err)
-			fmt.Printf("💥 Deleted network %s\n", networkName)
+			if dryRun {
+				plan.Delete("network", networkName)
+			} else {
+				fmt.Printf("🧨 Deleting network %s\n", networkName)
+				result := networks.Delete(ctx, projectNetwork, networksAll[0].ID)
+				must.Succeed(result.Err)
+				fmt.Printf("💥 Deleted network %s\n", networkName)
+			}
 			networksAll = nil
 		}
 	}
-	if len(networksAll) == 1 {
-		network = &networksAll[0]
-		fmt.Printf("🛜 Using network %s\n", networkName)
-	}
-	if len(networksAll) == 0 {
-		fmt.Printf("🆕 Creating network %s\n", networkName)
-		no := networks.CreateOpts{
-			Name: networkName,
-		}
-		network = must.Return(networks.Create(ctx, projectNetwork, no).Extract())
-		res := subnets.Create(ctx, projectNetwork, subnets.CreateOpts{
-			NetworkID: network.ID,
-			Name:      subnetworkName,
-			IPVersion: 4,
-			CIDR:      "10.180.1.0/16",
-		})
-		must.Succeed(res.Err)
-		fmt.Printf("🛜 Using new network %s\n", networkName)
-	}
 
-	// Create an ssh key pair in case we want to login later on.
+	// Look up existing keypairs, and delete them unless they should be reused.
 	fmt.Println("🔄 Looking up existing keypairs")
 	keyName := prefix + "-key"
 	kplo := keypairs.ListOpts{}
@@ -382,21 +347,27 @@ func main() {
 			keypairsFiltered = append(keypairsFiltered, kp)
 		}
 	}
+	reusingKeypair := false
+	if reuseKeypair && !cleanup && len(keypairsFiltered) > 0 {
+		if _, err := os.Stat(sshKeyPath); err == nil {
+			fmt.Printf("♻️ Reusing existing keypair %s with local key %s\n", keyName, sshKeyPath)
+			reusingKeypair = true
+		} else {
+			fmt.Printf("⚠️ Local key %s not found, the keypair %s has to be recreated\n", sshKeyPath, keyName)
+		}
+	}
 	// Delete all existing keypairs with the same name.
-	if len(keypairsFiltered) > 0 {
-		fmt.Printf("❓ Delete existing keypairs %v? [y/N, default: \033[1;34my\033[0m]: ", keyName)
-		reader = bufio.NewReader(os.Stdin)
-		input = must.Return(reader.ReadString('\n'))
-		input = strings.TrimSpace(input)
-		if input == "" {
-			input = "y"
-		}
-		if input != "y" {
+	if len(keypairsFiltered) > 0 && !reusingKeypair {
+		if !cli.Confirm("Delete existing keypairs "+keyName+"?", "OS_DELETE_KEYPAIRS", true) {
 			fmt.Println("🚫 Aborted")
 			return
 		}
 		var wg sync.WaitGroup
 		for _, kp := range keypairsFiltered {
+			if dryRun {
+				plan.Delete("keypair", kp.Name)
+				continue
+			}
 			wg.Go(func() {
 				fmt.Printf("🧨 Deleting keypair %s\n", kp.Name)
 				result := keypairs.Delete(ctx, projectCompute, kp.Name, keypairs.DeleteOpts{})
@@ -405,13 +376,10 @@ func main() {
 			})
 		}
 		wg.Wait()
-		fmt.Println("🧨 Deleted all existing keypairs")
+		if !dryRun {
+			fmt.Println("🧨 Deleted all existing keypairs")
+		}
 	}
-	// Create a new keypair.
-	fmt.Printf("🆕 Creating keypair %s\n", keyName)
-	kpo := keypairs.CreateOpts{Name: keyName}
-	keypair := must.Return(keypairs.Create(ctx, projectCompute, kpo).Extract())
-	fmt.Printf("🛜 Using keypair %s\n", keyName)
 
 	// Check if there are existing server groups and check if the user wants to delete them.
 	fmt.Println("🔄 Looking up existing server groups")
@@ -421,17 +389,13 @@ func main() {
 	}
 	_ = must.Return(projectCompute.Get(ctx, projectCompute.Endpoint+"/os-server-groups", &getServerGroupsResponse, nil))
 	if len(getServerGroupsResponse.ServerGroups) > 0 {
-		fmt.Printf("❓ Delete existing server groups with name prefix %s [y/N, default: \033[1;34my\033[0m]: ", prefix)
-		reader = bufio.NewReader(os.Stdin)
-		input = must.Return(reader.ReadString('\n'))
-		input = strings.TrimSpace(input)
-		if input == "" {
-			input = "y"
-		}
-		if input == "y" {
+		question := fmt.Sprintf("Delete existing server groups with name prefix %s?", prefix)
+		if cli.Confirm(question, "OS_DELETE_SERVER_GROUPS", true) {
 			var wg sync.WaitGroup
 			for _, sg := range getServerGroupsResponse.ServerGroups {
-				if strings.HasPrefix(sg.Name, prefix) {
+				if strings.HasPrefix(sg.Name, prefix) && dryRun {
+					plan.Delete("server group", sg.Name)
+				} else if strings.HasPrefix(sg.Name, prefix) {
 					wg.Go(func() {
 						fmt.Printf("🧨 Deleting server group %s\n", sg.Name)
 						_ = must.Return(projectCompute.Delete(ctx, projectCompute.Endpoint+"/os-server-groups/"+sg.ID, nil))
@@ -440,10 +404,195 @@ func main() {
 				}
 			}
 			wg.Wait()
-			fmt.Println("🧨 Deleted all existing server groups")
+			if !dryRun {
+				fmt.Println("🧨 Deleted all existing server groups")
+			}
+		}
+	}
+
+	if cleanup {
+		if dryRun {
+			plan.PrintSummary()
+		}
+		fmt.Printf("🎉 Done! - Cleaned up resources with prefix %s.\n", prefix)
+		return
+	}
+
+	spawnOnHost := cli.Confirm("Spawn on specific host?", "OS_SPAWN_ON_HOST", false)
+	var hypervisor *hypervisors.Hypervisor
+	// Availability zones to spawn the VMs in, assigned round-robin.
+	var azs []string
+	aggregatePages := must.Return(aggregates.List(adminNova).AllPages(ctx))
+	aggregatesAll := must.Return(aggregates.ExtractAggregates(aggregatePages))
+	if spawnOnHost {
+		// List all hypervisors with the given type.
+		fmt.Println("🔄 Looking up hypervisors")
+		withServers := true
+		hlo := hypervisors.ListOpts{WithServers: &withServers}
+		hypervisorPages := must.Return(hypervisors.List(adminNova, hlo).AllPages(ctx))
+		hypervisorsAll := must.Return(hypervisors.ExtractHypervisors(hypervisorPages))
+		hypervisorTypes := []string{}
+		for _, h := range hypervisorsAll {
+			if !slices.Contains(hypervisorTypes, h.HypervisorType) {
+				hypervisorTypes = append(hypervisorTypes, h.HypervisorType)
+			}
+		}
+		hypervisorType := cli.ChooseHypervisorType(hypervisorTypes)
+		var hypervisorsFiltered []hypervisors.Hypervisor
+		for _, h := range hypervisorsAll {
+			if h.Status == "enabled" && h.State == "up" && h.HypervisorType == hypervisorType {
+				hypervisorsFiltered = append(hypervisorsFiltered, h)
+			}
+		}
+		h := cli.ChooseHypervisor(hypervisorsFiltered)
+		hypervisor = &h
+		// Resolve the availability zones of the hypervisor.
+		fmt.Printf("🔄 Resolving availability zone of host %s\n", hypervisor.Service.Host)
+		var az = ""
+		for _, a := range aggregatesAll {
+			if a.AvailabilityZone == "" {
+				continue
+			}
+			if slices.Contains(a.Hosts, hypervisor.Service.Host) {
+				az = a.AvailabilityZone
+			}
+			if az != "" {
+				break
+			}
+		}
+		fmt.Printf("🗺️ Using availability zone '%s'\n", az)
+		azs = []string{az}
+	} else {
+		// Let the user choose an az, or multiple azs to spread the VMs across.
+		azsAll := []string{}
+		for _, a := range aggregatesAll {
+			if !slices.Contains(azsAll, a.AvailabilityZone) && a.AvailabilityZone != "" {
+				azsAll = append(azsAll, a.AvailabilityZone)
+			}
+		}
+		if spreadAZs {
+			azs = cli.ChooseAZs(azsAll)
+		}
+		if len(azs) == 0 {
+			azs = []string{cli.ChooseAZ(azsAll)}
+		}
+		fmt.Printf("🗺️ Using availability zone(s) '%s'\n", strings.Join(azs, "', '"))
+	}
+
+	// Resolve the type of the boot volumes, in the same order as the size.
+	fmt.Println("🔄 Resolving boot volume type")
+	bootVolumeTypeName := os.Getenv("OS_BOOT_VOLUME_TYPE")
+	if bootVolumeTypeName == "" {
+		bootVolumeTypeName = def.GetDefault("WS_BOOT_VOLUME_TYPE")
+	}
+	if bootVolumeTypeName == "" {
+		bootVolumeTypeName = "nfs"
+	}
+	volumeTypePages := must.Return(volumetypes.List(projectCinder, volumetypes.ListOpts{}).AllPages(ctx))
+	volumeTypesAll := must.Return(volumetypes.ExtractVolumeTypes(volumeTypePages))
+	if !slices.ContainsFunc(volumeTypesAll, func(vt volumetypes.VolumeType) bool { return vt.Name == bootVolumeTypeName }) {
+		fmt.Printf("⚠️ Volume type '%s' not found in this region\n", bootVolumeTypeName)
+		bootVolumeTypeName = cli.ChooseVolumeType(volumeTypesAll).Name
+	}
+	fmt.Printf("💾 Using %dGB boot volumes of type '%s'\n", bootVolumeSize, bootVolumeTypeName)
+
+	// Resolve how long to wait for each VM to become active. Accepts a
+	// duration (e.g. "10m") or a plain number of seconds.
+	spawnTimeout := 300 * time.Second
+	if spawnTimeoutStr := os.Getenv("OS_SPAWN_TIMEOUT"); spawnTimeoutStr != "" {
+		if d, err := time.ParseDuration(spawnTimeoutStr); err == nil {
+			spawnTimeout = d
+		} else {
+			spawnTimeout = time.Duration(must.Return(strconv.Atoi(spawnTimeoutStr))) * time.Second
+		}
+	}
+
+	// Resolve the subnets to create in the network. Env vars take precedence
+	// over the defaults file, which takes precedence over the built-in default.
+	subnetIPVersion := os.Getenv("OS_SUBNET_IP_VERSION")
+	if subnetIPVersion == "" {
+		subnetIPVersion = def.GetDefault("WS_SUBNET_IP_VERSION")
+	}
+	if subnetIPVersion == "" {
+		subnetIPVersion = "4"
+	}
+	subnetCIDRv4 := os.Getenv("OS_SUBNET_CIDR")
+	if subnetCIDRv4 == "" {
+		subnetCIDRv4 = def.GetDefault("WS_SUBNET_CIDR")
+	}
+	if subnetCIDRv4 == "" {
+		subnetCIDRv4 = "10.180.0.0/16"
+	}
+	subnetCIDRv6 := os.Getenv("OS_SUBNET_CIDR_V6")
+	if subnetCIDRv6 == "" {
+		subnetCIDRv6 = def.GetDefault("WS_SUBNET_CIDR_V6")
+	}
+	if subnetCIDRv6 == "" {
+		subnetCIDRv6 = "fd00:180::/64"
+	}
+	subnetSpecs, err := types.ParseSubnetSpecs(subnetIPVersion, subnetCIDRv4, subnetCIDRv6)
+	if err != nil {
+		fmt.Printf("🚫 Invalid subnet configuration: %v\n", err)
+		return
+	}
+	// Name of the subnet, suffixed for the IPv6 subnet of a dual-stack network.
+	subnetName := func(spec types.SubnetSpec) string {
+		if spec.IPVersion == gophercloud.IPv6 && len(subnetSpecs) > 1 {
+			return subnetworkName + "-v6"
+		}
+		return subnetworkName
+	}
+
+	// Create the necessary network if it doesn't exist (anymore).
+	if len(networksAll) == 1 {
+		network = &networksAll[0]
+		fmt.Printf("🛜 Using network %s\n", networkName)
+	}
+	if len(networksAll) == 0 && dryRun {
+		plan.Create("network", networkName)
+		for _, spec := range subnetSpecs {
+			plan.Create("subnet", subnetName(spec)+" with cidr "+spec.CIDR)
+		}
+		network = &networks.Network{ID: "<new network>", Name: networkName}
+	} else if len(networksAll) == 0 {
+		fmt.Printf("🆕 Creating network %s\n", networkName)
+		no := networks.CreateOpts{
+			Name: networkName,
 		}
+		network = must.Return(networks.Create(ctx, projectNetwork, no).Extract())
+		for _, spec := range subnetSpecs {
+			fmt.Printf("🆕 Creating IPv%d subnet %s with cidr %s\n", spec.IPVersion, subnetName(spec), spec.CIDR)
+			so := subnets.CreateOpts{
+				NetworkID: network.ID,
+				Name:      subnetName(spec),
+				IPVersion: spec.IPVersion,
+				CIDR:      spec.CIDR,
+			}
+			if spec.IPVersion == gophercloud.IPv6 {
+				// Hand out addresses through neutron's dhcp agent, since the
+				// network isn't attached to a router that could send RAs.
+				so.IPv6AddressMode = "dhcpv6-stateful"
+			}
+			subnet := must.Return(subnets.Create(ctx, projectNetwork, so).Extract())
+			network.Subnets = append(network.Subnets, subnet.ID)
+		}
+		fmt.Printf("🛜 Using new network %s\n", networkName)
 	}
 
+	// Create a new keypair.
+	var keypair *keypairs.KeyPair
+	switch {
+	case reusingKeypair:
+		// Nothing to do, the keypair and its local key are kept.
+	case dryRun:
+		plan.Create("keypair", keyName)
+	default:
+		fmt.Printf("🆕 Creating keypair %s\n", keyName)
+		kpo := keypairs.CreateOpts{Name: keyName}
+		keypair = must.Return(keypairs.Create(ctx, projectCompute, kpo).Extract())
+	}
+	fmt.Printf("🛜 Using keypair %s\n", keyName)
+
 	var selectedServerGroupID string
 
 	// Get the server groups again and check if the user wants to use an existing one or create a new one.
@@ -451,43 +600,40 @@ func main() {
 	_ = must.Return(projectCompute.Get(ctx, projectCompute.Endpoint+"/os-server-groups", &getServerGroupsResponse, nil))
 	if len(getServerGroupsResponse.ServerGroups) > 0 {
 		// Ask the user if they want to use an existing server group.
-		fmt.Printf("❓ Use existing server group for affinity rules? [y/N, default: \033[1;34mN\033[0m]: ")
-		reader = bufio.NewReader(os.Stdin)
-		input = must.Return(reader.ReadString('\n'))
-		input = strings.TrimSpace(input)
-		if input == "y" {
+		if cli.Confirm("Use existing server group for affinity rules?", "OS_USE_SERVER_GROUP", false) {
 			selectedServerGroupID = cli.ChooseServerGroup(getServerGroupsResponse.ServerGroups).ID
 		}
 	}
 	// If the user doesn't want to use an existing server group, ask if they want to create a new one.
 	if selectedServerGroupID == "" {
-		fmt.Printf("❓ Create a server group for affinity rules? [y/N, default: \033[1;34mN\033[0m]: ")
-		reader = bufio.NewReader(os.Stdin)
-		input = must.Return(reader.ReadString('\n'))
-		input = strings.TrimSpace(input)
-		if input == "y" {
+		if cli.Confirm("Create a server group for affinity rules?", "OS_CREATE_SERVER_GROUP", false) {
 			policies := []string{"anti-affinity", "affinity", "soft-anti-affinity", "soft-affinity"}
 			policy := cli.ChooseServerGroupPolicy(policies)
 			serverGroupName := prefix + "-server-group"
-			fmt.Printf("🆕 Creating server group %s with policy %s\n", serverGroupName, policy)
-			createServerGroupRequest := struct {
-				ServerGroup struct {
-					Name   string `json:"name"`
-					Policy string `json:"policy"`
-					// For simplicity, we don't include rules for now.
-				} `json:"server_group"`
-			}{}
-			createServerGroupRequest.ServerGroup.Name = serverGroupName
-			createServerGroupRequest.ServerGroup.Policy = policy
-			var createServerGroupResponse struct {
-				ServerGroup struct {
-					ID string `json:"id"`
-				} `json:"server_group"`
+			if dryRun {
+				plan.Create("server group", serverGroupName+" with policy "+policy)
+				selectedServerGroupID = "<new server group>"
+			} else {
+				fmt.Printf("🆕 Creating server group %s with policy %s\n", serverGroupName, policy)
+				createServerGroupRequest := struct {
+					ServerGroup struct {
+						Name   string `json:"name"`
+						Policy string `json:"policy"`
+						// For simplicity, we don't include rules for now.
+					} `json:"server_group"`
+				}{}
+				createServerGroupRequest.ServerGroup.Name = serverGroupName
+				createServerGroupRequest.ServerGroup.Policy = policy
+				var createServerGroupResponse struct {
+					ServerGroup struct {
+						ID string `json:"id"`
+					} `json:"server_group"`
+				}
+				_ = must.Return(projectCompute.Post(ctx, projectCompute.Endpoint+"/os-server-groups", &createServerGroupRequest, &createServerGroupResponse, &gophercloud.RequestOpts{
+					OkCodes: []int{200, 201, 202},
+				}))
+				selectedServerGroupID = createServerGroupResponse.ServerGroup.ID
 			}
-			_ = must.Return(projectCompute.Post(ctx, projectCompute.Endpoint+"/os-server-groups", &createServerGroupRequest, &createServerGroupResponse, &gophercloud.RequestOpts{
-				OkCodes: []int{200, 201, 202},
-			}))
-			selectedServerGroupID = createServerGroupResponse.ServerGroup.ID
 		}
 	}
 	if selectedServerGroupID != "" {
@@ -500,38 +646,94 @@ func main() {
 	tmpl, err := template.ParseFiles("tools/spawner/script.sh.tpl")
 	must.Succeed(err)
 
-	// Spawn new VMs.
+	// Spawn new VMs. An interrupt aborts waiting for the VMs cleanly,
+	// so that the summary is still printed.
+	spawnCtx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+	summary := types.NewSpawnSummary()
 	var wg sync.WaitGroup
+	var vmsPerAZLock sync.Mutex
+	vmsPerAZ := make(map[string]int, len(azs))
 	for i := range vmsToSpawn {
+		az := azs[i%len(azs)]
 		wg.Go(func() {
 			//nolint:gosec // We don't care if the id is cryptographically secure.
 			name := fmt.Sprintf("%s-%05d", prefix, rand.Intn(100000))
+			vm := types.SpawnedVM{
+				Name:             name,
+				FlavorID:         flavor.ID,
+				ImageID:          image.ID,
+				AvailabilityZone: az,
+			}
+			if hypervisor != nil {
+				vm.Host = hypervisor.Service.Host
+			}
 			var scriptBuilder strings.Builder
 			must.Succeed(tmpl.Execute(&scriptBuilder, map[string]any{
 				"VCPUs": flavor.VCPUs,
 				"RAM":   flavor.RAM * 1_000,
 			}))
 
+			baseMsg := fmt.Sprintf(
+				"... (%d/%d) Spawning VM %s on %s with flavor %s, image %s ",
+				i+1, vmsToSpawn, name, az, image.Name, flavor.Name,
+			)
+			// Record a VM that failed while waiting for an OpenStack resource,
+			// telling timeouts and interrupts apart from actual errors.
+			recordFailure := func(waitingFor string, err error) {
+				switch {
+				case errors.Is(err, context.DeadlineExceeded):
+					fmt.Printf("%s⏱️ %s not ready within %s\n", baseMsg, waitingFor, spawnTimeout)
+					vm.Status, vm.Error = types.SpawnOutcomeTimedOut, fmt.Sprintf("%s not ready within %s", waitingFor, spawnTimeout)
+				case errors.Is(err, context.Canceled):
+					fmt.Printf("%s🛑 Aborted waiting for %s\n", baseMsg, waitingFor)
+					vm.Status, vm.Error = types.SpawnOutcomeAborted, err.Error()
+				default:
+					fmt.Printf("%s🚫 Error while waiting for %s: %s\n", baseMsg, waitingFor, err)
+					vm.Status, vm.Error = types.SpawnOutcomeError, err.Error()
+				}
+				summary.Record(vm)
+			}
+
 			var so keypairs.CreateOptsExt
-			fmt.Println("💾 Creating boot volume for server")
 			// Create a boot volume for zero-disk flavors
 			volumeName := name + "-boot-volume"
-			bootVolume := must.Return(volumes.Create(ctx, projectCinder, volumes.CreateOpts{
-				Size:             16, // 16GB boot volume should be sufficient for most OSes
-				Name:             volumeName,
-				ImageID:          image.ID,
-				AvailabilityZone: az,
-				VolumeType:       "nfs",
-			}, nil).Extract())
-
-			// Wait for volume to be available
-			for {
-				vol, err := volumes.Get(ctx, projectCinder, bootVolume.ID).Extract()
+			bootVolume := &volumes.Volume{ID: "<new boot volume>", Name: volumeName}
+			if dryRun {
+				plan.Create("volume", volumeName)
+			} else {
+				fmt.Println("💾 Creating boot volume for server")
+				var err error
+				bootVolume, err = volumes.Create(spawnCtx, projectCinder, volumes.CreateOpts{
+					Size:             bootVolumeSize,
+					Name:             volumeName,
+					ImageID:          image.ID,
+					AvailabilityZone: az,
+					VolumeType:       bootVolumeTypeName,
+				}, nil).Extract()
 				if err != nil {
-					break
+					if spawnCtx.Err() != nil {
+						err = spawnCtx.Err()
+					}
+					recordFailure("boot volume creation", err)
+					return
 				}
-				if vol.Status == "available" {
-					break
+				// Wait for the volume to become available.
+				waitCtx, cancel := context.WithTimeout(spawnCtx, spawnTimeout)
+				defer cancel()
+				err = pollWithBackoff(waitCtx, func(ctx context.Context) (bool, error) {
+					vol, err := volumes.Get(ctx, projectCinder, bootVolume.ID).Extract()
+					if err != nil {
+						return false, err
+					}
+					if vol.Status == "error" {
+						return false, errors.New("boot volume entered error state")
+					}
+					return vol.Status == "available", nil
+				})
+				if err != nil {
+					recordFailure("boot volume", err)
+					return
 				}
 			}
 
@@ -558,43 +760,112 @@ func main() {
 				CreateOptsBuilder: sco,
 			}
 			ho := servers.SchedulerHintOpts{Group: selectedServerGroupID}
-			serverCreateResult, err := servers.Create(ctx, projectCompute, so, ho).Extract()
-			baseMsg := fmt.Sprintf(
-				"... (%d/%d) Spawning VM %s on %s with flavor %s, image %s ",
-				i+1, vmsToSpawn, name, az, image.Name, flavor.Name,
-			)
+			if dryRun {
+				plan.Create("VM", name)
+				// Show the exact request body that would have been submitted.
+				body := must.Return(so.ToServerCreateMap())
+				fmt.Printf("🧪 [dry-run] Create opts for VM %s:\n%s\n", name, must.Return(json.MarshalIndent(body, "", "  ")))
+				vmsPerAZLock.Lock()
+				vmsPerAZ[az]++
+				vmsPerAZLock.Unlock()
+				vm.Status = types.SpawnOutcomePlanned
+				summary.Record(vm)
+				return
+			}
+			serverCreateResult, err := servers.Create(spawnCtx, projectCompute, so, ho).Extract()
 			if err != nil {
-				fmt.Printf("%s🚫 Error: %s\n", baseMsg, err)
+				if spawnCtx.Err() != nil {
+					err = spawnCtx.Err()
+				}
+				recordFailure("VM creation", err)
 				return
 			}
-			// Wait for the instance to become active.
-			for {
-				time.Sleep(1 * time.Second)
+			vm.ID = serverCreateResult.ID
+			// Wait for the instance to become active, backing off exponentially
+			// between polls until the spawn timeout is reached.
+			waitCtx, cancel := context.WithTimeout(spawnCtx, spawnTimeout)
+			defer cancel()
+			err = pollWithBackoff(waitCtx, func(ctx context.Context) (bool, error) {
 				s, err := servers.Get(ctx, projectCompute, serverCreateResult.ID).Extract()
 				if err != nil {
-					fmt.Printf("%s🚫 Error while waiting for server to become active: %s\n", baseMsg, err)
-					break
-				}
-				if s.Status == "ACTIVE" {
-					fmt.Printf("%s✅ VM is active\n", baseMsg)
-					break
+					return false, err
 				}
 				if s.Status == "ERROR" {
 					// Get additional error details from the server's fault message if available.
-					fmt.Printf("%s🚫 VM entered error state, fault: %s (%s)\n", baseMsg, s.Fault.Message, s.Fault.Details)
-					break
+					return false, fmt.Errorf("VM entered error state, fault: %s (%s)", s.Fault.Message, s.Fault.Details)
 				}
+				if s.Status != "ACTIVE" {
+					return false, nil
+				}
+				vm.Host = s.Host
+				return true, nil
+			})
+			if err != nil {
+				recordFailure("VM", err)
+				return
 			}
+			fmt.Printf("%s✅ VM is active\n", baseMsg)
+			vm.Status = types.SpawnOutcomeActive
+			summary.Record(vm)
+			vmsPerAZLock.Lock()
+			vmsPerAZ[az]++
+			vmsPerAZLock.Unlock()
 		})
 	}
 	wg.Wait()
 
+	if !dryRun {
+		summary.PrintSummary()
+	}
+	fmt.Println("🗺️ VMs per availability zone (active, or planned in dry-run mode):")
+	for _, az := range azs {
+		fmt.Printf("   - %s: %d\n", az, vmsPerAZ[az])
+	}
+
+	if *reportPath != "" {
+		fmt.Printf("📝 Writing report to %s\n", *reportPath)
+		must.Succeed(summary.WriteReport(*reportPath, keyName, network.ID, network.Subnets))
+	}
+
+	if dryRun {
+		plan.PrintSummary()
+		fmt.Println("🎉 Done! - Dry run, nothing was changed.")
+		return
+	}
+
 	// Write the keypair to a file, so the user can ssh into the vms.
-	fmt.Println("📝 Writing keypair to ssh.pem", keyName)
-	must.Succeed(os.WriteFile("tools/spawner/ssh.pem", []byte(keypair.PrivateKey), 0600))
+	if keypair != nil {
+		fmt.Println("📝 Writing keypair to ssh.pem", keyName)
+		must.Succeed(os.WriteFile(sshKeyPath, []byte(keypair.PrivateKey), 0600))
+	}
 	fmt.Println("🔑 Add the following ssh key to your ssh agent:")
 	fmt.Println("💲 eval $(ssh-agent -s) && ssh-add tools/spawner/ssh.pem")
 	fmt.Printf("📝 To ssh into your VMs, create a new router that assigns the subnet %s to a floating IP network. Then assign a floating IP to your VM.\n", subnetworkName)
 
 	fmt.Println("🎉 Done!")
 }
+
+// Poll until done, backing off exponentially between polls. Returns the
+// first error of the poll, or the context error once the context is done.
+func pollWithBackoff(ctx context.Context, poll func(context.Context) (bool, error)) error {
+	interval := 1 * time.Second
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+		interval = min(2*interval, 30*time.Second)
+		done, err := poll(ctx)
+		if ctx.Err() != nil {
+			// Report the timeout or abort on the next iteration.
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if done {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright SAP SE
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// Records which resources the spawner would create or delete when running
+// in dry-run mode, so a summary can be printed at the end.
+type DryRunPlan struct {
+	mu      sync.Mutex
+	creates map[string]int
+	deletes map[string]int
+}
+
+func NewDryRunPlan() *DryRunPlan {
+	return &DryRunPlan{creates: map[string]int{}, deletes: map[string]int{}}
+}
+
+// Record that a resource of the given kind would be created.
+func (p *DryRunPlan) Create(kind, name string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.creates[kind]++
+	fmt.Printf("🧪 [dry-run] Would create %s %s\n", kind, name)
+}
+
+// Record that a resource of the given kind would be deleted.
+func (p *DryRunPlan) Delete(kind, name string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.deletes[kind]++
+	fmt.Printf("🧪 [dry-run] Would delete %s %s\n", kind, name)
+}
+
+// Print the counts per resource type.
+func (p *DryRunPlan) PrintSummary() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	fmt.Println("🧪 [dry-run] Summary, nothing was changed:")
+	for _, kv := range []struct {
+		action string
+		counts map[string]int
+	}{{"delete", p.deletes}, {"create", p.creates}} {
+		if len(kv.counts) == 0 {
+			fmt.Printf("   would %s: nothing\n", kv.action)
+			continue
+		}
+		kinds := make([]string, 0, len(kv.counts))
+		for kind := range kv.counts {
+			kinds = append(kinds, kind)
+		}
+		slices.Sort(kinds)
+		for _, kind := range kinds {
+			fmt.Printf("   would %s: %d %s(s)\n", kv.action, kv.counts[kind], kind)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+// Copyright SAP SE
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"fmt"
+
+	"github.com/gophercloud/gophercloud/v2/openstack/compute/v2/flavors"
+	"github.com/gophercloud/gophercloud/v2/openstack/image/v2/images"
+)
+
+// Check if the image can be booted with the flavor from a boot volume of the
+// given size, and describe every requirement of the image that isn't met.
+// Since the VMs boot from volume, nova checks the minimum disk size of the
+// image against the boot volume and not against the root disk of the flavor.
+func FlavorImageMismatches(flavor flavors.Flavor, image images.Image, bootVolumeSizeGB int) []string {
+	var mismatches []string
+	if image.MinRAMMegabytes > flavor.RAM {
+		mismatches = append(mismatches, fmt.Sprintf(
+			"Flavor %s has %dMB RAM, but image %s requires at least %dMB",
+			flavor.Name, flavor.RAM, image.Name, image.MinRAMMegabytes,
+		))
+	}
+	if image.MinDiskGigabytes > bootVolumeSizeGB {
+		mismatches = append(mismatches, fmt.Sprintf(
+			"Boot volume size %dGB is smaller than the minimum disk size %dGB of image %s",
+			bootVolumeSizeGB, image.MinDiskGigabytes, image.Name,
+		))
+	}
+	return mismatches
+}
@@ -0,0 +1,129 @@
+// Copyright SAP SE
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Outcome of waiting for a spawned VM.
+type SpawnOutcome string
+
+const (
+	// The VM became active.
+	SpawnOutcomeActive SpawnOutcome = "active"
+	// The VM could not be created or entered the error state.
+	SpawnOutcomeError SpawnOutcome = "error"
+	// The VM did not become active or errored within the spawn timeout.
+	SpawnOutcomeTimedOut SpawnOutcome = "timed out"
+	// Waiting for the VM was aborted, e.g. by an interrupt.
+	SpawnOutcomeAborted SpawnOutcome = "aborted"
+	// The VM would have been spawned, but the spawner ran in dry-run mode.
+	SpawnOutcomePlanned SpawnOutcome = "planned"
+)
+
+// A VM the spawner attempted to spawn.
+type SpawnedVM struct {
+	// Name of the VM.
+	Name string `json:"name"`
+	// ID of the VM, if it was created.
+	ID string `json:"id,omitempty"`
+	// ID of the flavor used for the VM.
+	FlavorID string `json:"flavorID"`
+	// ID of the image the boot volume was created from.
+	ImageID string `json:"imageID"`
+	// Availability zone the VM was spawned in.
+	AvailabilityZone string `json:"availabilityZone"`
+	// Host the VM was requested on or landed on, if known.
+	Host string `json:"host,omitempty"`
+	// Final status of the VM.
+	Status SpawnOutcome `json:"status"`
+	// Error message if the VM did not become active.
+	Error string `json:"error,omitempty"`
+}
+
+// Machine-readable report of a spawner run.
+type SpawnReport struct {
+	// Name of the keypair injected into the VMs.
+	KeyName string `json:"keyName"`
+	// ID of the network the VMs were attached to.
+	NetworkID string `json:"networkID"`
+	// IDs of the subnets in the network.
+	SubnetIDs []string `json:"subnetIDs"`
+	// All VMs the spawner attempted to spawn.
+	VMs []SpawnedVM `json:"vms"`
+}
+
+// Collects the outcome of each spawned VM, so a summary can be printed
+// once all VMs are done.
+type SpawnSummary struct {
+	mu  sync.Mutex
+	vms []SpawnedVM
+}
+
+func NewSpawnSummary() *SpawnSummary {
+	return &SpawnSummary{}
+}
+
+// Record the final status of a VM.
+func (s *SpawnSummary) Record(vm SpawnedVM) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.vms = append(s.vms, vm)
+}
+
+// Write a JSON report of all recorded VMs to the given path.
+func (s *SpawnSummary) WriteReport(path, keyName, networkID string, subnetIDs []string) error {
+	s.mu.Lock()
+	vms := slices.Clone(s.vms)
+	s.mu.Unlock()
+	slices.SortFunc(vms, func(a, b SpawnedVM) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	report := SpawnReport{
+		KeyName:   keyName,
+		NetworkID: networkID,
+		SubnetIDs: subnetIDs,
+		VMs:       vms,
+	}
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// Print the number of VMs per outcome, listing the VMs that didn't become active.
+func (s *SpawnSummary) PrintSummary() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	outcomes := map[SpawnOutcome][]string{}
+	for _, vm := range s.vms {
+		outcomes[vm.Status] = append(outcomes[vm.Status], vm.Name)
+	}
+	fmt.Println("📊 Spawn summary:")
+	for _, outcome := range []SpawnOutcome{
+		SpawnOutcomeActive,
+		SpawnOutcomeError,
+		SpawnOutcomeTimedOut,
+		SpawnOutcomeAborted,
+	} {
+		names := slices.Sorted(slices.Values(outcomes[outcome]))
+		if len(names) == 0 {
+			continue
+		}
+		fmt.Printf("   - %s: %d\n", outcome, len(names))
+		if outcome == SpawnOutcomeActive {
+			continue
+		}
+		for _, name := range names {
+			fmt.Printf("      %s\n", name)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright SAP SE
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/gophercloud/gophercloud/v2"
+)
+
+// Subnet to create in the network of the spawned VMs.
+type SubnetSpec struct {
+	IPVersion gophercloud.IPVersion
+	CIDR      string
+}
+
+// Resolve the subnets to create for the given ip version, which is either
+// "4", "6" or "dual" for a dual-stack network with one subnet per version.
+func ParseSubnetSpecs(ipVersion, cidrV4, cidrV6 string) ([]SubnetSpec, error) {
+	var versions []gophercloud.IPVersion
+	switch ipVersion {
+	case "4":
+		versions = []gophercloud.IPVersion{gophercloud.IPv4}
+	case "6":
+		versions = []gophercloud.IPVersion{gophercloud.IPv6}
+	case "dual":
+		versions = []gophercloud.IPVersion{gophercloud.IPv4, gophercloud.IPv6}
+	default:
+		return nil, fmt.Errorf("unsupported ip version '%s', expected 4, 6 or dual", ipVersion)
+	}
+	specs := make([]SubnetSpec, 0, len(versions))
+	for _, version := range versions {
+		cidr := cidrV4
+		if version == gophercloud.IPv6 {
+			cidr = cidrV6
+		}
+		if err := validateCIDR(cidr, version); err != nil {
+			return nil, err
+		}
+		specs = append(specs, SubnetSpec{IPVersion: version, CIDR: cidr})
+	}
+	return specs, nil
+}
+
+// Check that the cidr is a network of the given ip version, without any
+// host bits set (e.g. 10.180.1.0/16 instead of 10.180.0.0/16).
+func validateCIDR(cidr string, version gophercloud.IPVersion) error {
+	ip, ipNet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return fmt.Errorf("invalid cidr '%s': %w", cidr, err)
+	}
+	isIPv4 := ip.To4() != nil
+	if isIPv4 != (version == gophercloud.IPv4) {
+		return fmt.Errorf("cidr '%s' is not an IPv%d network", cidr, version)
+	}
+	if !ip.Equal(ipNet.IP) {
+		return fmt.Errorf("cidr '%s' has host bits set, did you mean '%s'?", cidr, ipNet)
+	}
+	return nil
+}